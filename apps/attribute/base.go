@@ -0,0 +1,26 @@
+package attribute
+
+import (
+	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/infra/gin"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	repo := newRepository(db)
+	svc := newService(repo)
+	handler := newHandler(svc)
+
+	router.GET("/attributes", handler.GetListAttributes)
+
+	adminRoute := router.Group("/admin/attributes")
+	{
+		adminRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		adminRoute.POST("", handler.CreateAttribute)
+		adminRoute.PUT("/:code", handler.UpdateAttribute)
+		adminRoute.DELETE("/:code", handler.DeleteAttribute)
+	}
+}
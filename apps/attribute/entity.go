@@ -0,0 +1,362 @@
+package attribute
+
+import (
+	"Ecommerce-basic/infra/response"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+type AttributeType uint8
+
+const (
+	AttributeType_String  AttributeType = 1
+	AttributeType_Number  AttributeType = 2
+	AttributeType_Enum    AttributeType = 3
+	AttributeType_Boolean AttributeType = 4
+
+	ATTRIBUTE_STRING  string = "STRING"
+	ATTRIBUTE_NUMBER  string = "NUMBER"
+	ATTRIBUTE_ENUM    string = "ENUM"
+	ATTRIBUTE_BOOLEAN string = "BOOLEAN"
+	ATTRIBUTE_UNKNOWN string = "UNKNOWN"
+
+	// panjang maksimal nilai atribut STRING
+	maxStringValueLength = 255
+)
+
+var (
+	MappingAttributeType = map[AttributeType]string{
+		AttributeType_String:  ATTRIBUTE_STRING,
+		AttributeType_Number:  ATTRIBUTE_NUMBER,
+		AttributeType_Enum:    ATTRIBUTE_ENUM,
+		AttributeType_Boolean: ATTRIBUTE_BOOLEAN,
+	}
+
+	attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+)
+
+func ParseAttributeType(name string) (attributeType AttributeType, err error) {
+	for attributeType, typeName := range MappingAttributeType {
+		if typeName == strings.ToUpper(name) {
+			return attributeType, nil
+		}
+	}
+	return attributeType, response.ErrAttributeTypeInvalid
+}
+
+// Attribute adalah definisi atribut produk yang diatur admin, contoh: weight (NUMBER, gram) atau material (ENUM)
+type Attribute struct {
+	Id        int            `db:"id"`
+	Code      string         `db:"code"`
+	Name      string         `db:"name"`
+	Type      AttributeType  `db:"type"`
+	Unit      string         `db:"unit"`
+	Options   pq.StringArray `db:"options"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func NewAttributeFromCreateRequest(req CreateAttributeRequestPayload) Attribute {
+	// tipe yang tidak dikenal tetap 0 dan ditolak oleh Validate
+	attributeType, _ := ParseAttributeType(req.Type)
+
+	return Attribute{
+		Code:      strings.TrimSpace(req.Code),
+		Name:      strings.TrimSpace(req.Name),
+		Type:      attributeType,
+		Unit:      strings.TrimSpace(req.Unit),
+		Options:   req.Options,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// ApplyUpdate code dan type tidak bisa diubah karena sudah dipakai nilai di produk
+func (a *Attribute) ApplyUpdate(req UpdateAttributeRequestPayload) {
+	a.Name = strings.TrimSpace(req.Name)
+	a.Unit = strings.TrimSpace(req.Unit)
+	a.Options = req.Options
+	a.UpdatedAt = time.Now()
+}
+
+func (a Attribute) Validate() (err error) {
+	if !attributeCodePattern.MatchString(a.Code) {
+		return response.ErrAttributeCodeInvalid
+	}
+	if a.Name == "" {
+		return response.ErrAttributeNameRequired
+	}
+	if _, ok := MappingAttributeType[a.Type]; !ok {
+		return response.ErrAttributeTypeInvalid
+	}
+	return a.ValidateOptions()
+}
+
+// ValidateOptions opsi hanya untuk ENUM dan wajib unik, unit hanya untuk NUMBER
+func (a Attribute) ValidateOptions() (err error) {
+	if a.Unit != "" && a.Type != AttributeType_Number {
+		return response.ErrAttributeOptionsInvalid
+	}
+
+	if a.Type != AttributeType_Enum {
+		if len(a.Options) > 0 {
+			return response.ErrAttributeOptionsInvalid
+		}
+		return
+	}
+
+	if len(a.Options) == 0 {
+		return response.ErrAttributeOptionsInvalid
+	}
+	seen := map[string]bool{}
+	for _, option := range a.Options {
+		if option == "" || seen[option] {
+			return response.ErrAttributeOptionsInvalid
+		}
+		seen[option] = true
+	}
+	return
+}
+
+// RemovedOptions opsi lama yang tidak ada lagi setelah update, dipakai untuk memeriksa apakah masih dipakai produk
+func (a Attribute) RemovedOptions(previous []string) (removed []string) {
+	current := map[string]bool{}
+	for _, option := range a.Options {
+		current[option] = true
+	}
+	for _, option := range previous {
+		if !current[option] {
+			removed = append(removed, option)
+		}
+	}
+	return
+}
+
+func (a Attribute) GetType() string {
+	attributeType, ok := MappingAttributeType[a.Type]
+	if !ok {
+		return ATTRIBUTE_UNKNOWN
+	}
+	return attributeType
+}
+
+// ParseValue memvalidasi satu nilai JSON sesuai tipe atribut
+func (a Attribute) ParseValue(raw json.RawMessage) (value interface{}, err error) {
+	switch a.Type {
+	case AttributeType_String, AttributeType_Enum:
+		var text string
+		if err = json.Unmarshal(raw, &text); err != nil {
+			return nil, response.ErrAttributeValueInvalid
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || utf8.RuneCountInString(text) > maxStringValueLength {
+			return nil, response.ErrAttributeValueInvalid
+		}
+		if a.Type == AttributeType_Enum && !a.hasOption(text) {
+			return nil, response.ErrAttributeValueInvalid
+		}
+		return text, nil
+	case AttributeType_Number:
+		var number float64
+		if err = json.Unmarshal(raw, &number); err != nil {
+			return nil, response.ErrAttributeValueInvalid
+		}
+		return number, nil
+	case AttributeType_Boolean:
+		var flag bool
+		if err = json.Unmarshal(raw, &flag); err != nil {
+			return nil, response.ErrAttributeValueInvalid
+		}
+		return flag, nil
+	}
+	return nil, response.ErrAttributeValueInvalid
+}
+
+func (a Attribute) hasOption(value string) bool {
+	for _, option := range a.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Values adalah nilai atribut per produk berdasarkan code, disimpan sebagai JSONB di kolom products.attributes
+type Values map[string]interface{}
+
+func (v *Values) Scan(src interface{}) (err error) {
+	switch data := src.(type) {
+	case nil:
+		*v = Values{}
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	}
+	return errors.New("unsupported attribute values type")
+}
+
+func (v Values) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(v)
+}
+
+// Specification adalah nilai atribut produk beserta definisinya, untuk ditampilkan di detail produk
+type Specification struct {
+	Attribute
+	Value interface{}
+}
+
+// Specifications mengurutkan nilai sesuai urutan definisi, nilai yang definisinya sudah tidak ada dilewati
+func (v Values) Specifications(definitions []Attribute) (specifications []Specification) {
+	for _, definition := range definitions {
+		value, ok := v[definition.Code]
+		if !ok {
+			continue
+		}
+		specifications = append(specifications, Specification{Attribute: definition, Value: value})
+	}
+	return
+}
+
+// ParseValues memvalidasi semua nilai terhadap definisinya, code yang tidak dikenal ditolak
+func ParseValues(definitions []Attribute, raw map[string]json.RawMessage) (values Values, err error) {
+	byCode := mapByCode(definitions)
+
+	values = Values{}
+	for code, value := range raw {
+		definition, ok := byCode[code]
+		if !ok {
+			return nil, response.ErrAttributeValueInvalid
+		}
+		if values[code], err = definition.ParseValue(value); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// Filter adalah filter GET /products untuk satu atribut, Values untuk salah satu dari beberapa nilai
+// dan Min / Max untuk rentang atribut NUMBER
+type Filter struct {
+	Code   string
+	Values []string
+	Min    *float64
+	Max    *float64
+}
+
+// NewFilters mengubah query attr[code], attr_min[code] dan attr_max[code] menjadi filter yang sudah divalidasi
+func NewFilters(definitions []Attribute, equals map[string]string, min map[string]string, max map[string]string) (filters []Filter, err error) {
+	byCode := mapByCode(definitions)
+
+	filtersByCode := map[string]*Filter{}
+	filter := func(code string) (*Filter, Attribute, error) {
+		definition, ok := byCode[code]
+		if !ok {
+			return nil, definition, response.ErrAttributeFilterInvalid
+		}
+		if filtersByCode[code] == nil {
+			filtersByCode[code] = &Filter{Code: code}
+		}
+		return filtersByCode[code], definition, nil
+	}
+
+	for code, value := range equals {
+		f, definition, err := filter(code)
+		if err != nil {
+			return nil, err
+		}
+
+		switch definition.Type {
+		case AttributeType_Number:
+			number, err := parseNumber(value)
+			if err != nil {
+				return nil, err
+			}
+			f.Min, f.Max = &number, &number
+		case AttributeType_Boolean:
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, response.ErrAttributeFilterInvalid
+			}
+			f.Values = []string{strconv.FormatBool(flag)}
+		default:
+			for _, option := range strings.Split(value, ",") {
+				if option = strings.TrimSpace(option); option != "" {
+					f.Values = append(f.Values, option)
+				}
+			}
+			if len(f.Values) == 0 {
+				return nil, response.ErrAttributeFilterInvalid
+			}
+		}
+	}
+
+	for bound, values := range map[string]map[string]string{"min": min, "max": max} {
+		for code, value := range values {
+			f, definition, err := filter(code)
+			if err != nil {
+				return nil, err
+			}
+			if definition.Type != AttributeType_Number {
+				return nil, response.ErrAttributeFilterInvalid
+			}
+
+			number, err := parseNumber(value)
+			if err != nil {
+				return nil, err
+			}
+			if bound == "min" {
+				f.Min = &number
+			} else {
+				f.Max = &number
+			}
+		}
+	}
+
+	for _, f := range filtersByCode {
+		if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+			return nil, response.ErrAttributeFilterInvalid
+		}
+		filters = append(filters, *f)
+	}
+
+	// urutan tetap supaya query yang sama menghasilkan SQL yang sama
+	sortFilters(filters)
+	return
+}
+
+func parseNumber(value string) (number float64, err error) {
+	number, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, response.ErrAttributeFilterInvalid
+	}
+	return
+}
+
+func sortFilters(filters []Filter) {
+	for i := 1; i < len(filters); i++ {
+		for j := i; j > 0 && filters[j].Code < filters[j-1].Code; j-- {
+			filters[j], filters[j-1] = filters[j-1], filters[j]
+		}
+	}
+}
+
+func mapByCode(definitions []Attribute) map[string]Attribute {
+	byCode := make(map[string]Attribute, len(definitions))
+	for _, definition := range definitions {
+		byCode[definition.Code] = definition
+	}
+	return byCode
+}
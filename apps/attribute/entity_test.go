@@ -0,0 +1,165 @@
+package attribute
+
+import (
+	"Ecommerce-basic/infra/response"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAttribute(t *testing.T) {
+	t.Run("number with unit", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "weight", Name: "Weight", Type: "number", Unit: "gram"})
+
+		require.Nil(t, attribute.Validate())
+		require.Equal(t, ATTRIBUTE_NUMBER, attribute.GetType())
+	})
+	t.Run("enum", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "material", Name: "Material", Type: ATTRIBUTE_ENUM, Options: []string{"cotton", "linen"}})
+
+		require.Nil(t, attribute.Validate())
+	})
+	t.Run("code invalid", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "Weight", Name: "Weight", Type: ATTRIBUTE_NUMBER})
+
+		require.Equal(t, response.ErrAttributeCodeInvalid, attribute.Validate())
+	})
+	t.Run("name required", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "weight", Name: " ", Type: ATTRIBUTE_NUMBER})
+
+		require.Equal(t, response.ErrAttributeNameRequired, attribute.Validate())
+	})
+	t.Run("type invalid", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "weight", Name: "Weight", Type: "date"})
+
+		require.Equal(t, response.ErrAttributeTypeInvalid, attribute.Validate())
+		require.Equal(t, ATTRIBUTE_UNKNOWN, attribute.GetType())
+	})
+	t.Run("enum without options", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "material", Name: "Material", Type: ATTRIBUTE_ENUM})
+
+		require.Equal(t, response.ErrAttributeOptionsInvalid, attribute.Validate())
+	})
+	t.Run("enum duplicate options", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "material", Name: "Material", Type: ATTRIBUTE_ENUM, Options: []string{"cotton", "cotton"}})
+
+		require.Equal(t, response.ErrAttributeOptionsInvalid, attribute.Validate())
+	})
+	t.Run("options for non enum", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "color", Name: "Color", Type: ATTRIBUTE_STRING, Options: []string{"red"}})
+
+		require.Equal(t, response.ErrAttributeOptionsInvalid, attribute.Validate())
+	})
+	t.Run("unit for non number", func(t *testing.T) {
+		attribute := NewAttributeFromCreateRequest(CreateAttributeRequestPayload{Code: "color", Name: "Color", Type: ATTRIBUTE_STRING, Unit: "cm"})
+
+		require.Equal(t, response.ErrAttributeOptionsInvalid, attribute.Validate())
+	})
+}
+
+func TestRemovedOptions(t *testing.T) {
+	attribute := Attribute{Type: AttributeType_Enum, Options: []string{"cotton", "wool"}}
+
+	require.Equal(t, []string{"linen"}, attribute.RemovedOptions([]string{"cotton", "linen"}))
+	require.Empty(t, attribute.RemovedOptions([]string{"cotton"}))
+}
+
+var definitions = []Attribute{
+	{Code: "color", Name: "Color", Type: AttributeType_String},
+	{Code: "material", Name: "Material", Type: AttributeType_Enum, Options: []string{"cotton", "linen"}},
+	{Code: "waterproof", Name: "Waterproof", Type: AttributeType_Boolean},
+	{Code: "weight", Name: "Weight", Type: AttributeType_Number, Unit: "gram"},
+}
+
+func TestParseValues(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		values, err := ParseValues(definitions, map[string]json.RawMessage{
+			"color":      json.RawMessage(`" navy "`),
+			"material":   json.RawMessage(`"linen"`),
+			"waterproof": json.RawMessage(`true`),
+			"weight":     json.RawMessage(`250.5`),
+		})
+
+		require.Nil(t, err)
+		require.Equal(t, Values{"color": "navy", "material": "linen", "waterproof": true, "weight": 250.5}, values)
+	})
+	t.Run("unknown code", func(t *testing.T) {
+		_, err := ParseValues(definitions, map[string]json.RawMessage{"size": json.RawMessage(`"XL"`)})
+
+		require.Equal(t, response.ErrAttributeValueInvalid, err)
+	})
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := ParseValues(definitions, map[string]json.RawMessage{"weight": json.RawMessage(`"heavy"`)})
+
+		require.Equal(t, response.ErrAttributeValueInvalid, err)
+	})
+	t.Run("enum option not allowed", func(t *testing.T) {
+		_, err := ParseValues(definitions, map[string]json.RawMessage{"material": json.RawMessage(`"wool"`)})
+
+		require.Equal(t, response.ErrAttributeValueInvalid, err)
+	})
+	t.Run("empty string", func(t *testing.T) {
+		_, err := ParseValues(definitions, map[string]json.RawMessage{"color": json.RawMessage(`"  "`)})
+
+		require.Equal(t, response.ErrAttributeValueInvalid, err)
+	})
+}
+
+func TestValuesScan(t *testing.T) {
+	var values Values
+
+	require.Nil(t, values.Scan([]byte(`{"weight": 250, "waterproof": false}`)))
+	require.Equal(t, Values{"weight": 250.0, "waterproof": false}, values)
+
+	specifications := values.Specifications(definitions)
+	require.Len(t, specifications, 2)
+	require.Equal(t, "waterproof", specifications[0].Code)
+	require.Equal(t, "weight", specifications[1].Code)
+	require.Equal(t, "gram", specifications[1].Unit)
+}
+
+func TestNewFilters(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		filters, err := NewFilters(definitions,
+			map[string]string{"material": "cotton, linen", "waterproof": "1"},
+			map[string]string{"weight": "100"},
+			map[string]string{"weight": "500"},
+		)
+
+		require.Nil(t, err)
+		require.Len(t, filters, 3)
+		require.Equal(t, "material", filters[0].Code)
+		require.Equal(t, []string{"cotton", "linen"}, filters[0].Values)
+		require.Equal(t, []string{"true"}, filters[1].Values)
+		require.Equal(t, 100.0, *filters[2].Min)
+		require.Equal(t, 500.0, *filters[2].Max)
+	})
+	t.Run("number equality", func(t *testing.T) {
+		filters, err := NewFilters(definitions, map[string]string{"weight": "250"}, nil, nil)
+
+		require.Nil(t, err)
+		require.Equal(t, 250.0, *filters[0].Min)
+		require.Equal(t, 250.0, *filters[0].Max)
+	})
+	t.Run("unknown code", func(t *testing.T) {
+		_, err := NewFilters(definitions, map[string]string{"size": "XL"}, nil, nil)
+
+		require.Equal(t, response.ErrAttributeFilterInvalid, err)
+	})
+	t.Run("range for non number", func(t *testing.T) {
+		_, err := NewFilters(definitions, nil, map[string]string{"color": "1"}, nil)
+
+		require.Equal(t, response.ErrAttributeFilterInvalid, err)
+	})
+	t.Run("min greater than max", func(t *testing.T) {
+		_, err := NewFilters(definitions, nil, map[string]string{"weight": "500"}, map[string]string{"weight": "100"})
+
+		require.Equal(t, response.ErrAttributeFilterInvalid, err)
+	})
+	t.Run("boolean invalid", func(t *testing.T) {
+		_, err := NewFilters(definitions, map[string]string{"waterproof": "maybe"}, nil, nil)
+
+		require.Equal(t, response.ErrAttributeFilterInvalid, err)
+	})
+}
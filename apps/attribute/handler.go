@@ -0,0 +1,131 @@
+package attribute
+
+import (
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/infra/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	svc service
+}
+
+func newHandler(svc service) handler {
+	return handler{
+		svc: svc,
+	}
+}
+
+func (h handler) GetListAttributes(c *gin.Context) {
+	attributes, err := h.svc.ListAttributes(c.Request.Context())
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get list attributes success"),
+		infragin.WithPayload(NewAttributeListResponse(attributes)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) CreateAttribute(c *gin.Context) {
+	var req CreateAttributeRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	attribute, err := h.svc.CreateAttribute(c.Request.Context(), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("create attribute success"),
+		infragin.WithPayload(attribute.ToAttributeResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) UpdateAttribute(c *gin.Context) {
+	var req UpdateAttributeRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	attribute, err := h.svc.UpdateAttribute(c.Request.Context(), c.Param("code"), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("update attribute success"),
+		infragin.WithPayload(attribute.ToAttributeResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) DeleteAttribute(c *gin.Context) {
+	if err := h.svc.DeleteAttribute(c.Request.Context(), c.Param("code")); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("delete attribute success"),
+	)
+	resp.Send(c)
+}
@@ -0,0 +1,112 @@
+package attribute
+
+import (
+	"Ecommerce-basic/infra/response"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+const selectAttributeSQL = `
+	SELECT
+		id, code, name, type, unit, options, created_at, updated_at
+	FROM product_attributes
+`
+
+// GetAttributes dipakai juga oleh modul product untuk memvalidasi nilai dan filter atribut
+func GetAttributes(ctx context.Context, db sqlx.QueryerContext) (attributes []Attribute, err error) {
+	query := selectAttributeSQL + `
+		ORDER BY code ASC
+	`
+
+	err = sqlx.SelectContext(ctx, db, &attributes, query)
+	return
+}
+
+func (r repository) GetAttributes(ctx context.Context) (attributes []Attribute, err error) {
+	return GetAttributes(ctx, r.db)
+}
+
+func (r repository) GetAttributeByCode(ctx context.Context, code string) (attribute Attribute, err error) {
+	query := selectAttributeSQL + `
+		WHERE code=$1
+	`
+
+	err = r.db.GetContext(ctx, &attribute, query, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrAttributeNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) CreateAttribute(ctx context.Context, attribute Attribute) (err error) {
+	query := `
+		INSERT INTO product_attributes (
+			code, name, type, unit, options, created_at, updated_at
+		) VALUES (
+			:code, :name, :type, :unit, :options, :created_at, :updated_at
+		)
+	`
+
+	_, err = r.db.NamedExecContext(ctx, query, attribute)
+	return
+}
+
+func (r repository) UpdateAttribute(ctx context.Context, attribute Attribute) (err error) {
+	query := `
+		UPDATE product_attributes
+		SET name=:name, unit=:unit, options=:options, updated_at=:updated_at
+		WHERE code=:code
+	`
+
+	_, err = r.db.NamedExecContext(ctx, query, attribute)
+	return
+}
+
+func (r repository) DeleteAttribute(ctx context.Context, code string) (err error) {
+	query := `
+		DELETE FROM product_attributes
+		WHERE code=$1
+	`
+
+	_, err = r.db.ExecContext(ctx, query, code)
+	return
+}
+
+// IsAttributeUsed produk yang sudah dihapus ikut dihitung karena masih bisa di-restore
+func (r repository) IsAttributeUsed(ctx context.Context, code string) (used bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM products WHERE attributes ->> $1 IS NOT NULL
+		)
+	`
+
+	err = r.db.GetContext(ctx, &used, query, code)
+	return
+}
+
+func (r repository) IsAttributeOptionUsed(ctx context.Context, code string, options []string) (used bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM products WHERE attributes ->> $1 = ANY($2)
+		)
+	`
+
+	err = r.db.GetContext(ctx, &used, query, code, pq.Array(options))
+	return
+}
@@ -0,0 +1,15 @@
+package attribute
+
+type CreateAttributeRequestPayload struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Unit    string   `json:"unit"`
+	Options []string `json:"options"`
+}
+
+type UpdateAttributeRequestPayload struct {
+	Name    string   `json:"name"`
+	Unit    string   `json:"unit"`
+	Options []string `json:"options"`
+}
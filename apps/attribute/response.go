@@ -0,0 +1,27 @@
+package attribute
+
+type AttributeResponse struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Unit    string   `json:"unit,omitempty"`
+	Options []string `json:"options,omitempty"`
+}
+
+func (a Attribute) ToAttributeResponse() AttributeResponse {
+	return AttributeResponse{
+		Code:    a.Code,
+		Name:    a.Name,
+		Type:    a.GetType(),
+		Unit:    a.Unit,
+		Options: a.Options,
+	}
+}
+
+func NewAttributeListResponse(attributes []Attribute) []AttributeResponse {
+	resp := []AttributeResponse{}
+	for _, attribute := range attributes {
+		resp = append(resp, attribute.ToAttributeResponse())
+	}
+	return resp
+}
@@ -0,0 +1,110 @@
+package attribute
+
+import (
+	"Ecommerce-basic/infra/response"
+	"context"
+)
+
+type Repository interface {
+	AttributeRepository
+	ProductRepository
+}
+
+type AttributeRepository interface {
+	GetAttributes(ctx context.Context) (attributes []Attribute, err error)
+	GetAttributeByCode(ctx context.Context, code string) (attribute Attribute, err error)
+	CreateAttribute(ctx context.Context, attribute Attribute) (err error)
+	UpdateAttribute(ctx context.Context, attribute Attribute) (err error)
+	DeleteAttribute(ctx context.Context, code string) (err error)
+}
+
+type ProductRepository interface {
+	IsAttributeUsed(ctx context.Context, code string) (used bool, err error)
+	IsAttributeOptionUsed(ctx context.Context, code string, options []string) (used bool, err error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func newService(repo Repository) service {
+	return service{
+		repo: repo,
+	}
+}
+
+func (s service) ListAttributes(ctx context.Context) (attributes []Attribute, err error) {
+	attributes, err = s.repo.GetAttributes(ctx)
+	if err != nil {
+		return
+	}
+	if len(attributes) == 0 {
+		attributes = []Attribute{}
+	}
+	return
+}
+
+func (s service) CreateAttribute(ctx context.Context, req CreateAttributeRequestPayload) (attribute Attribute, err error) {
+	attribute = NewAttributeFromCreateRequest(req)
+	if err = attribute.Validate(); err != nil {
+		return
+	}
+
+	_, err = s.repo.GetAttributeByCode(ctx, attribute.Code)
+	if err == nil {
+		return attribute, response.ErrAttributeAlreadyExists
+	}
+	if err != response.ErrAttributeNotFound {
+		return
+	}
+
+	if err = s.repo.CreateAttribute(ctx, attribute); err != nil {
+		return
+	}
+	return s.repo.GetAttributeByCode(ctx, attribute.Code)
+}
+
+// UpdateAttribute opsi ENUM yang dihapus tidak boleh masih dipakai produk
+func (s service) UpdateAttribute(ctx context.Context, code string, req UpdateAttributeRequestPayload) (attribute Attribute, err error) {
+	attribute, err = s.repo.GetAttributeByCode(ctx, code)
+	if err != nil {
+		return
+	}
+
+	previous := attribute.Options
+	attribute.ApplyUpdate(req)
+	if err = attribute.Validate(); err != nil {
+		return
+	}
+
+	if removed := attribute.RemovedOptions(previous); len(removed) > 0 {
+		used, err := s.repo.IsAttributeOptionUsed(ctx, code, removed)
+		if err != nil {
+			return attribute, err
+		}
+		if used {
+			return attribute, response.ErrAttributeInUse
+		}
+	}
+
+	if err = s.repo.UpdateAttribute(ctx, attribute); err != nil {
+		return
+	}
+	return s.repo.GetAttributeByCode(ctx, code)
+}
+
+func (s service) DeleteAttribute(ctx context.Context, code string) (err error) {
+	if _, err = s.repo.GetAttributeByCode(ctx, code); err != nil {
+		return
+	}
+
+	used, err := s.repo.IsAttributeUsed(ctx, code)
+	if err != nil {
+		return
+	}
+	if used {
+		return response.ErrAttributeInUse
+	}
+
+	return s.repo.DeleteAttribute(ctx, code)
+}
@@ -0,0 +1,70 @@
+package attribute
+
+import (
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo)
+}
+
+func TestAttributeLifecycle(t *testing.T) {
+	ctx := context.Background()
+	code := fmt.Sprintf("material_%d", time.Now().UnixNano())
+
+	attribute, err := svc.CreateAttribute(ctx, CreateAttributeRequestPayload{
+		Code:    code,
+		Name:    "Material",
+		Type:    ATTRIBUTE_ENUM,
+		Options: []string{"cotton", "linen"},
+	})
+	require.Nil(t, err)
+	require.NotZero(t, attribute.Id)
+
+	t.Run("already exists", func(t *testing.T) {
+		_, err := svc.CreateAttribute(ctx, CreateAttributeRequestPayload{Code: code, Name: "Material", Type: ATTRIBUTE_STRING})
+		require.Equal(t, response.ErrAttributeAlreadyExists, err)
+	})
+	t.Run("update options", func(t *testing.T) {
+		attribute, err := svc.UpdateAttribute(ctx, code, UpdateAttributeRequestPayload{Name: "Bahan", Options: []string{"cotton", "wool"}})
+		require.Nil(t, err)
+		require.Equal(t, "Bahan", attribute.Name)
+		require.Equal(t, []string{"cotton", "wool"}, []string(attribute.Options))
+	})
+	t.Run("listed", func(t *testing.T) {
+		attributes, err := svc.ListAttributes(ctx)
+		require.Nil(t, err)
+
+		found := false
+		for _, attribute := range attributes {
+			found = found || attribute.Code == code
+		}
+		require.True(t, found)
+	})
+	t.Run("delete", func(t *testing.T) {
+		require.Nil(t, svc.DeleteAttribute(ctx, code))
+		require.Equal(t, response.ErrAttributeNotFound, svc.DeleteAttribute(ctx, code))
+	})
+}
@@ -1,18 +1,46 @@
 package auth
 
 import (
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/infra/mailer"
+
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 )
 
 func Init(router *gin.Engine, db *sqlx.DB) {
-	repo := newRepository(db)
-	svc := newService(repo)
+	repo := newCachedRepository(newRepository(db))
+	svc := newService(repo, mailer.FromConfig())
 	handler := newHandler(svc)
 
+	// token akun yang sudah dihapus ditolak CheckAuth di semua modul
+	infragin.SetSessionChecker(newSessionChecker(repo))
+
 	authRouter := router.Group("auth")
 	{
 		authRouter.POST("register", handler.register)
 		authRouter.POST("login", handler.login)
+		authRouter.POST("verify", handler.verifyEmail)
+		authRouter.POST("forgot-password", handler.forgotPassword)
+		authRouter.POST("reset-password", handler.resetPassword)
+
+		meRouter := authRouter.Group("me")
+		meRouter.Use(infragin.CheckAuth())
+		{
+			meRouter.GET("", handler.getProfile)
+			meRouter.PATCH("", handler.updateProfile)
+			meRouter.PUT("password", handler.changePassword)
+			meRouter.PUT("email", handler.changeEmail)
+			meRouter.POST("verify-email", handler.resendVerification)
+			meRouter.DELETE("", handler.deleteAccount)
+		}
+	}
+
+	adminRouter := router.Group("/admin/lockouts")
+	{
+		adminRouter.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(ROLE_Admin)}))
+
+		adminRouter.GET("", handler.getLockouts)
+		adminRouter.DELETE("/:id", handler.clearLockout)
 	}
 }
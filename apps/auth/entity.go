@@ -25,6 +25,15 @@ type AuthEntity struct {
 	Role      Role      `db:"role"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+
+	// profil yang diubah sendiri oleh pengguna lewat /auth/me
+	DisplayName string `db:"display_name"`
+	Phone       string `db:"phone"`
+	AvatarURL   string `db:"avatar_url"`
+
+	// nil berarti email belum diverifikasi, contoh: setelah email diganti
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	DeletedAt       *time.Time `db:"deleted_at"`
 }
 
 func NewFromRegisterRequest(req RegisterRequestPayload) AuthEntity {
@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"Ecommerce-basic/infra/gin"
 	"Ecommerce-basic/infra/response"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -64,6 +67,7 @@ func (h handler) login(c *gin.Context) {
 		return
 	}
 
+	req.ClientIP = c.ClientIP()
 	token, err := h.svc.login(c.Request.Context(), req)
 	if err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
@@ -86,3 +90,342 @@ func (h handler) login(c *gin.Context) {
 		"access_token": token,
 	})
 }
+
+func (h handler) getProfile(c *gin.Context) {
+	model, err := h.svc.profile(c.Request.Context(), c.GetString("PUBLIC_ID"))
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get profile success"),
+		infragin.WithPayload(NewProfileResponse(model)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) updateProfile(c *gin.Context) {
+	var req UpdateProfileRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	model, err := h.svc.updateProfile(c.Request.Context(), c.GetString("PUBLIC_ID"), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("update profile success"),
+		infragin.WithPayload(NewProfileResponse(model)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) changePassword(c *gin.Context) {
+	var req ChangePasswordRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.changePassword(c.Request.Context(), c.GetString("PUBLIC_ID"), req); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("change password success"),
+	)
+	resp.Send(c)
+}
+
+func (h handler) changeEmail(c *gin.Context) {
+	var req ChangeEmailRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	model, err := h.svc.changeEmail(c.Request.Context(), c.GetString("PUBLIC_ID"), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("change email success, please verify the new email"),
+		infragin.WithPayload(NewProfileResponse(model)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) deleteAccount(c *gin.Context) {
+	var req DeleteAccountRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.deleteAccount(c.Request.Context(), c.GetString("PUBLIC_ID"), req); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("delete account success"),
+	)
+	resp.Send(c)
+}
+
+func (h handler) verifyEmail(c *gin.Context) {
+	var req VerifyEmailRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.verifyEmail(c.Request.Context(), req); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("verify email success"),
+	)
+	resp.Send(c)
+}
+
+// forgotPassword selalu berhasil untuk email yang valid, terdaftar atau tidak
+func (h handler) forgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.forgotPassword(c.Request.Context(), req); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("if the email is registered, a reset link has been sent"),
+	)
+	resp.Send(c)
+}
+
+func (h handler) resetPassword(c *gin.Context) {
+	var req ResetPasswordRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.resetPassword(c.Request.Context(), req); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("reset password success"),
+	)
+	resp.Send(c)
+}
+
+func (h handler) resendVerification(c *gin.Context) {
+	if err := h.svc.resendVerification(c.Request.Context(), c.GetString("PUBLIC_ID")); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("verification email sent"),
+	)
+	resp.Send(c)
+}
+
+func (h handler) getLockouts(c *gin.Context) {
+	var req ListLockoutRequestPayload
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	failures, err := h.svc.lockouts(c.Request.Context(), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get lockouts success"),
+		infragin.WithPayload(NewLockoutListResponse(failures, time.Now())),
+	)
+	resp.Send(c)
+}
+
+func (h handler) clearLockout(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid lockout ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.clearLockout(c.Request.Context(), id); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("clear lockout success"),
+	)
+	resp.Send(c)
+}
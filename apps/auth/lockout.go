@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"Ecommerce-basic/internal"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type LockoutScope string
+
+const (
+	LOCKOUT_Account LockoutScope = "ACCOUNT"
+	LOCKOUT_IP      LockoutScope = "IP"
+)
+
+// nilai default jika app.auth tidak diatur
+const (
+	defaultMaxLoginAttempts      = 5
+	defaultMaxLoginAttemptsPerIP = 20
+	defaultLockoutBase           = time.Minute
+	defaultLockoutMax            = time.Hour
+	defaultFailureWindow         = 15 * time.Minute
+)
+
+// LoginFailure hitungan login gagal per email (termasuk email yang tidak terdaftar) atau per IP
+type LoginFailure struct {
+	Id           int          `db:"id"`
+	Scope        LockoutScope `db:"scope"`
+	Subject      string       `db:"subject"`
+	Failures     int          `db:"failures"`
+	LockedUntil  *time.Time   `db:"locked_until"`
+	LastFailedAt time.Time    `db:"last_failed_at"`
+}
+
+func (f LoginFailure) IsLocked(now time.Time) bool {
+	return f.LockedUntil != nil && f.LockedUntil.After(now)
+}
+
+// LockoutPolicy kunci pertama selama Base setelah MaxAttempts kali gagal,
+// setiap gagal berikutnya lamanya berlipat dua sampai Max
+type LockoutPolicy struct {
+	MaxAttempts int
+	Base        time.Duration
+	Max         time.Duration
+	Window      time.Duration
+}
+
+// LockDuration nol jika jumlah gagal belum mencapai MaxAttempts
+func (p LockoutPolicy) LockDuration(failures int) time.Duration {
+	if failures < p.MaxAttempts {
+		return 0
+	}
+
+	duration := p.Base
+	for i := p.MaxAttempts; i < failures && duration < p.Max; i++ {
+		duration *= 2
+	}
+	return min(duration, p.Max)
+}
+
+// WindowStart hitungan gagal yang terakhir gagal / terkunci sebelum waktu ini dimulai lagi dari nol
+func (p LockoutPolicy) WindowStart(now time.Time) time.Time {
+	return now.Add(-p.Window)
+}
+
+func accountLockoutPolicy() LockoutPolicy {
+	policy := lockoutPolicy()
+	policy.MaxAttempts = defaultMaxLoginAttempts
+	if attempts := config.Cfg.App.Auth.MaxLoginAttempts; attempts > 0 {
+		policy.MaxAttempts = attempts
+	}
+	return policy
+}
+
+func ipLockoutPolicy() LockoutPolicy {
+	policy := lockoutPolicy()
+	policy.MaxAttempts = defaultMaxLoginAttemptsPerIP
+	if attempts := config.Cfg.App.Auth.MaxLoginAttemptsPerIP; attempts > 0 {
+		policy.MaxAttempts = attempts
+	}
+	return policy
+}
+
+func lockoutPolicy() LockoutPolicy {
+	policy := LockoutPolicy{
+		Base:   defaultLockoutBase,
+		Max:    defaultLockoutMax,
+		Window: defaultFailureWindow,
+	}
+	if base := config.Cfg.App.Auth.LockoutBase; base > 0 {
+		policy.Base = base
+	}
+	if max := config.Cfg.App.Auth.LockoutMax; max > 0 {
+		policy.Max = max
+	}
+	if window := config.Cfg.App.Auth.FailureWindow; window > 0 {
+		policy.Window = window
+	}
+	return policy
+}
+
+// lockoutSubject email dengan huruf besar / kecil berbeda dihitung sebagai email yang sama
+func lockoutSubject(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// dummyPasswordHash hash dengan cost yang sama seperti EncryptPassword, dibandingkan untuk email yang
+// tidak terdaftar supaya lama responsnya sama dengan password yang salah
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+		dummyHash = string(hash)
+	})
+	return dummyHash
+}
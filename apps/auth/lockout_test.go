@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLockoutPolicy(t *testing.T) {
+	policy := LockoutPolicy{MaxAttempts: 5, Base: time.Minute, Max: 10 * time.Minute, Window: 15 * time.Minute}
+
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{failures: 1, expected: 0},
+		{failures: 4, expected: 0},
+		{failures: 5, expected: time.Minute},
+		{failures: 6, expected: 2 * time.Minute},
+		{failures: 7, expected: 4 * time.Minute},
+		{failures: 8, expected: 8 * time.Minute},
+		{failures: 9, expected: 10 * time.Minute},
+		{failures: 1000, expected: 10 * time.Minute},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, policy.LockDuration(test.failures), "failures %d", test.failures)
+	}
+
+	now := time.Now()
+	require.Equal(t, now.Add(-15*time.Minute), policy.WindowStart(now))
+}
+
+func TestLoginFailureIsLocked(t *testing.T) {
+	now := time.Now()
+	until := now.Add(time.Minute)
+
+	require.False(t, LoginFailure{Failures: 3}.IsLocked(now))
+	require.True(t, LoginFailure{LockedUntil: &until}.IsLocked(now))
+	require.False(t, LoginFailure{LockedUntil: &until}.IsLocked(until))
+}
+
+func TestLockoutDefaults(t *testing.T) {
+	require.Equal(t, "user@mail.com", lockoutSubject("  User@Mail.com "))
+	require.Greater(t, ipLockoutPolicy().MaxAttempts, accountLockoutPolicy().MaxAttempts)
+
+	// cost sama dengan EncryptPassword supaya email yang tidak terdaftar tidak lebih cepat
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash()))
+	require.Nil(t, err)
+	auth := AuthEntity{Password: "mysecretpassword"}
+	require.Nil(t, auth.EncryptPassword(0))
+	expected, _ := bcrypt.Cost([]byte(auth.Password))
+	require.Equal(t, expected, cost)
+}
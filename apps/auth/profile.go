@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/response"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxDisplayNameLength = 100
+	maxAvatarURLLength   = 500
+
+	// domain .invalid tidak pernah bisa menerima email (RFC 2606)
+	anonymizedEmailDomain = "deleted.invalid"
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+// ApplyProfile field yang nil tidak diubah, string kosong menghapus nilainya
+func (a *AuthEntity) ApplyProfile(req UpdateProfileRequestPayload, now time.Time) (err error) {
+	if req.DisplayName != nil {
+		displayName := strings.TrimSpace(*req.DisplayName)
+		if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+			return response.ErrDisplayNameInvalid
+		}
+		a.DisplayName = displayName
+	}
+
+	if req.Phone != nil {
+		// spasi dan tanda hubung hanya format tampilan
+		phone := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(*req.Phone))
+		if phone != "" && !phonePattern.MatchString(phone) {
+			return response.ErrPhoneInvalid
+		}
+		a.Phone = phone
+	}
+
+	if req.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*req.AvatarURL)
+		if avatarURL != "" {
+			if err = ValidateAvatarURL(avatarURL); err != nil {
+				return
+			}
+		}
+		a.AvatarURL = avatarURL
+	}
+
+	a.UpdatedAt = now
+	return
+}
+
+func ValidateAvatarURL(avatarURL string) (err error) {
+	if len(avatarURL) > maxAvatarURLLength {
+		return response.ErrAvatarURLInvalid
+	}
+
+	parsed, err := url.Parse(avatarURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return response.ErrAvatarURLInvalid
+	}
+	return nil
+}
+
+// VerifyPassword password yang salah selalu menjadi ErrPasswordNotMatch
+func (a AuthEntity) VerifyPassword(plain string) (err error) {
+	if a.VerifyPasswordFromEncrypted(plain) != nil {
+		return response.ErrPasswordNotMatch
+	}
+	return
+}
+
+// ChangePassword password lama harus benar dan password baru harus berbeda
+func (a *AuthEntity) ChangePassword(currentPassword string, newPassword string, salt int, now time.Time) (err error) {
+	if err = a.VerifyPassword(currentPassword); err != nil {
+		return
+	}
+
+	next := AuthEntity{Password: newPassword}
+	if err = next.ValidatePassword(); err != nil {
+		return
+	}
+	if a.VerifyPasswordFromEncrypted(newPassword) == nil {
+		return response.ErrPasswordUnchanged
+	}
+	if err = next.EncryptPassword(salt); err != nil {
+		return
+	}
+
+	a.Password = next.Password
+	a.UpdatedAt = now
+	return
+}
+
+// ChangeEmail email baru harus diverifikasi ulang
+func (a *AuthEntity) ChangeEmail(email string, now time.Time) (err error) {
+	next := AuthEntity{Email: strings.TrimSpace(email)}
+	if err = next.ValidateEmail(); err != nil {
+		return
+	}
+	if next.Email == a.Email {
+		return response.ErrEmailAlreadyUsed
+	}
+
+	a.Email = next.Email
+	a.EmailVerifiedAt = nil
+	a.UpdatedAt = now
+	return
+}
+
+func (a AuthEntity) IsEmailVerified() bool {
+	return a.EmailVerifiedAt != nil
+}
+
+func (a AuthEntity) IsDeleted() bool {
+	return a.DeletedAt != nil
+}
+
+// Anonymize menghapus data pribadi akun. public_id tetap sama supaya riwayat transaksi tetap utuh,
+// password dikosongkan sehingga akun tidak bisa login lagi
+func (a *AuthEntity) Anonymize(now time.Time) {
+	a.Email = "deleted-" + a.PublicId.String() + "@" + anonymizedEmailDomain
+	a.Password = ""
+	a.DisplayName = ""
+	a.Phone = ""
+	a.AvatarURL = ""
+	a.EmailVerifiedAt = nil
+	a.DeletedAt = &now
+	a.UpdatedAt = now
+}
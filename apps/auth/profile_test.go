@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/response"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestApplyProfile(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		model := AuthEntity{DisplayName: "Lama", Phone: "08123456789"}
+		err := model.ApplyProfile(UpdateProfileRequestPayload{
+			DisplayName: ptr("  Fathur  "),
+			AvatarURL:   ptr("https://cdn.example.com/avatar.png"),
+		}, now)
+		require.Nil(t, err)
+		require.Equal(t, "Fathur", model.DisplayName)
+		require.Equal(t, "08123456789", model.Phone)
+		require.Equal(t, "https://cdn.example.com/avatar.png", model.AvatarURL)
+		require.Equal(t, now, model.UpdatedAt)
+	})
+	t.Run("empty string clears value", func(t *testing.T) {
+		model := AuthEntity{Phone: "08123456789"}
+		require.Nil(t, model.ApplyProfile(UpdateProfileRequestPayload{Phone: ptr("")}, now))
+		require.Equal(t, "", model.Phone)
+	})
+	t.Run("phone is normalized", func(t *testing.T) {
+		model := AuthEntity{}
+		require.Nil(t, model.ApplyProfile(UpdateProfileRequestPayload{Phone: ptr("+62 812-3456-789")}, now))
+		require.Equal(t, "+628123456789", model.Phone)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			req UpdateProfileRequestPayload
+			err error
+		}{
+			{UpdateProfileRequestPayload{DisplayName: ptr(strings.Repeat("a", 101))}, response.ErrDisplayNameInvalid},
+			{UpdateProfileRequestPayload{Phone: ptr("12345")}, response.ErrPhoneInvalid},
+			{UpdateProfileRequestPayload{Phone: ptr("0812abc6789")}, response.ErrPhoneInvalid},
+			{UpdateProfileRequestPayload{AvatarURL: ptr("javascript:alert(1)")}, response.ErrAvatarURLInvalid},
+			{UpdateProfileRequestPayload{AvatarURL: ptr("/avatar.png")}, response.ErrAvatarURLInvalid},
+		} {
+			model := AuthEntity{}
+			require.Equal(t, tc.err, model.ApplyProfile(tc.req, now))
+		}
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	model := AuthEntity{Password: "mysecretpassword"}
+	require.Nil(t, model.EncryptPassword(10))
+
+	t.Run("wrong current password", func(t *testing.T) {
+		next := model
+		require.Equal(t, response.ErrPasswordNotMatch, next.ChangePassword("salah", "newpassword", 10, now))
+	})
+	t.Run("new password too short", func(t *testing.T) {
+		next := model
+		require.Equal(t, response.ErrPasswordInvalidLength, next.ChangePassword("mysecretpassword", "abc", 10, now))
+	})
+	t.Run("same password", func(t *testing.T) {
+		next := model
+		require.Equal(t, response.ErrPasswordUnchanged, next.ChangePassword("mysecretpassword", "mysecretpassword", 10, now))
+	})
+	t.Run("success", func(t *testing.T) {
+		next := model
+		require.Nil(t, next.ChangePassword("mysecretpassword", "newpassword", 10, now))
+		require.Nil(t, next.VerifyPassword("newpassword"))
+		require.Equal(t, response.ErrPasswordNotMatch, next.VerifyPassword("mysecretpassword"))
+	})
+}
+
+func TestChangeEmail(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	verifiedAt := now.Add(-time.Hour)
+
+	model := AuthEntity{Email: "lama@mail.com", EmailVerifiedAt: &verifiedAt}
+	require.Equal(t, response.ErrEmailInvalid, model.ChangeEmail("bukan-email", now))
+	require.Equal(t, response.ErrEmailAlreadyUsed, model.ChangeEmail("lama@mail.com", now))
+
+	require.Nil(t, model.ChangeEmail(" baru@mail.com ", now))
+	require.Equal(t, "baru@mail.com", model.Email)
+	require.False(t, model.IsEmailVerified())
+}
+
+func TestAnonymize(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	publicId := uuid.New()
+	model := AuthEntity{
+		Email:       "user@mail.com",
+		PublicId:    publicId,
+		Password:    "hash",
+		DisplayName: "Fathur",
+		Phone:       "08123456789",
+		AvatarURL:   "https://cdn.example.com/avatar.png",
+	}
+
+	model.Anonymize(now)
+	require.Equal(t, "deleted-"+publicId.String()+"@deleted.invalid", model.Email)
+	require.Equal(t, publicId, model.PublicId)
+	require.Empty(t, model.Password)
+	require.Empty(t, model.DisplayName)
+	require.Empty(t, model.Phone)
+	require.Empty(t, model.AvatarURL)
+	require.True(t, model.IsDeleted())
+
+	// password kosong tidak pernah cocok dengan password apa pun
+	require.Equal(t, response.ErrPasswordNotMatch, model.VerifyPassword(""))
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -49,8 +50,9 @@ func (r repository) GetAuthByEmail(ctx context.Context, email string) (model Aut
 	query := `
 		SELECT 
 			id, email, password, role, created_at, updated_at, public_id
+			, display_name, phone, avatar_url, email_verified_at, deleted_at
 		FROM auth
-		WHERE email=$1
+		WHERE email=$1 AND deleted_at IS NULL
 	`
 
 	err = r.db.GetContext(ctx, &model, query, email)
@@ -64,3 +66,176 @@ func (r repository) GetAuthByEmail(ctx context.Context, email string) (model Aut
 
 	return
 }
+
+func (r repository) GetAuthByPublicId(ctx context.Context, publicId string) (model AuthEntity, err error) {
+	query := `
+		SELECT
+			id, email, password, role, created_at, updated_at, public_id
+			, display_name, phone, avatar_url, email_verified_at, deleted_at
+		FROM auth
+		WHERE public_id=$1 AND deleted_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &model, query, publicId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrAuthIsNotExists
+		}
+		return
+	}
+	return
+}
+
+// UpdateAuth menyimpan email, password dan profil, akun yang sudah dihapus tidak bisa diubah lagi
+func (r repository) UpdateAuth(ctx context.Context, model AuthEntity) (err error) {
+	query := `
+		UPDATE auth
+		SET email=:email, password=:password, display_name=:display_name, phone=:phone, avatar_url=:avatar_url
+			, email_verified_at=:email_verified_at, deleted_at=:deleted_at, updated_at=:updated_at
+		WHERE public_id=:public_id AND deleted_at IS NULL
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		return response.ErrAuthIsNotExists
+	}
+	return
+}
+
+// CreateAuthToken token lama yang belum dipakai dengan tujuan yang sama ikut dicabut,
+// hanya link di email terakhir yang berlaku
+func (r repository) CreateAuthToken(ctx context.Context, model AuthToken) (err error) {
+	query := `
+		WITH revoked AS (
+			UPDATE auth_tokens
+			SET used_at=:created_at
+			WHERE auth_public_id=:auth_public_id AND purpose=:purpose AND used_at IS NULL
+		)
+		INSERT INTO auth_tokens (
+			auth_public_id, purpose, token_hash, email, expires_at, created_at
+		) VALUES (
+			:auth_public_id, :purpose, :token_hash, :email, :expires_at, :created_at
+		)
+	`
+
+	_, err = r.db.NamedExecContext(ctx, query, model)
+	return
+}
+
+// ConsumeAuthToken menandai token terpakai dalam satu query, token yang sama tidak bisa dipakai dua kali
+// walaupun dikirim bersamaan
+func (r repository) ConsumeAuthToken(ctx context.Context, purpose TokenPurpose, tokenHash string) (model AuthToken, err error) {
+	query := `
+		UPDATE auth_tokens
+		SET used_at=NOW()
+		WHERE token_hash=$1 AND purpose=$2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, auth_public_id, purpose, token_hash, email, expires_at, used_at, created_at
+	`
+
+	err = r.db.GetContext(ctx, &model, query, tokenHash, purpose)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrTokenInvalid
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetLoginFailures(ctx context.Context, accountSubject string, ip string) (models []LoginFailure, err error) {
+	query := `
+		SELECT id, scope, subject, failures, locked_until, last_failed_at
+		FROM auth_login_failures
+		WHERE (scope=$1 AND subject=$2) OR (scope=$3 AND subject=$4)
+	`
+
+	err = r.db.SelectContext(ctx, &models, query, LOCKOUT_Account, accountSubject, LOCKOUT_IP, ip)
+	return
+}
+
+// RecordLoginFailure menambah hitungan gagal secara atomik, hitungan yang terakhir gagal / terkunci
+// sebelum windowStart dimulai lagi dari satu
+func (r repository) RecordLoginFailure(ctx context.Context, scope LockoutScope, subject string, now time.Time, windowStart time.Time) (model LoginFailure, err error) {
+	query := `
+		INSERT INTO auth_login_failures (scope, subject, failures, last_failed_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, subject) DO UPDATE
+		SET failures = CASE
+				WHEN GREATEST(auth_login_failures.last_failed_at, COALESCE(auth_login_failures.locked_until, auth_login_failures.last_failed_at)) < $4 THEN 1
+				ELSE auth_login_failures.failures + 1
+			END
+			, locked_until = CASE
+				WHEN GREATEST(auth_login_failures.last_failed_at, COALESCE(auth_login_failures.locked_until, auth_login_failures.last_failed_at)) < $4 THEN NULL
+				ELSE auth_login_failures.locked_until
+			END
+			, last_failed_at = $3
+		RETURNING id, scope, subject, failures, locked_until, last_failed_at
+	`
+
+	err = r.db.GetContext(ctx, &model, query, scope, subject, now, windowStart)
+	return
+}
+
+func (r repository) LockLoginFailure(ctx context.Context, id int, lockedUntil time.Time) (err error) {
+	query := `
+		UPDATE auth_login_failures
+		SET locked_until=$2
+		WHERE id=$1
+	`
+
+	_, err = r.db.ExecContext(ctx, query, id, lockedUntil)
+	return
+}
+
+func (r repository) ClearLoginFailure(ctx context.Context, scope LockoutScope, subject string) (err error) {
+	query := `
+		DELETE FROM auth_login_failures
+		WHERE scope=$1 AND subject=$2
+	`
+
+	_, err = r.db.ExecContext(ctx, query, scope, subject)
+	return
+}
+
+// ListLoginFailures yang masih terkunci atau masih dihitung, subject kosong berarti semua
+func (r repository) ListLoginFailures(ctx context.Context, subject string, now time.Time, windowStart time.Time) (models []LoginFailure, err error) {
+	query := `
+		SELECT id, scope, subject, failures, locked_until, last_failed_at
+		FROM auth_login_failures
+		WHERE (locked_until > $1 OR last_failed_at >= $2)
+			AND ($3 = '' OR subject = $3)
+		ORDER BY last_failed_at DESC
+	`
+
+	err = r.db.SelectContext(ctx, &models, query, now, windowStart, subject)
+	return
+}
+
+func (r repository) DeleteLoginFailure(ctx context.Context, id int) (err error) {
+	query := `
+		DELETE FROM auth_login_failures
+		WHERE id=$1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+	return
+}
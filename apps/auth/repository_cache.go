@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"Ecommerce-basic/internal/log"
+	"context"
+)
+
+// cachedRepository menambahkan cache GetAuthByEmail tanpa mengubah service,
+// akun yang diubah lewat repository ini langsung dihapus dari cache lewat tag supaya
+// semua cache yang di-Subscribe ke email akun ikut dihapus
+type cachedRepository struct {
+	Repository
+
+	auths *cache.Loader[AuthEntity]
+}
+
+// newCachedRepository mengembalikan repo apa adanya jika app.cache.driver none
+func newCachedRepository(repo Repository) Repository {
+	auths := cache.FromConfig[AuthEntity]("auth:email")
+	if auths == nil {
+		return repo
+	}
+	return newCachedRepositoryWithLoader(repo, auths)
+}
+
+func newCachedRepositoryWithLoader(repo Repository, auths *cache.Loader[AuthEntity]) cachedRepository {
+	cache.Subscribe(cache.TAG_AUTH_EMAIL, auths)
+
+	return cachedRepository{
+		Repository: repo,
+		auths:      auths,
+	}
+}
+
+func (r cachedRepository) GetAuthByEmail(ctx context.Context, email string) (model AuthEntity, err error) {
+	return r.auths.Load(ctx, email, func(ctx context.Context) (AuthEntity, error) {
+		return r.Repository.GetAuthByEmail(ctx, email)
+	})
+}
+
+func (r cachedRepository) CreateAuth(ctx context.Context, model AuthEntity) (err error) {
+	if err = r.Repository.CreateAuth(ctx, model); err != nil {
+		return
+	}
+	r.invalidate(ctx, model.Email)
+	return
+}
+
+// UpdateAuth email lama dicari sebelum diubah supaya cache email lama juga dihapus
+func (r cachedRepository) UpdateAuth(ctx context.Context, model AuthEntity) (err error) {
+	previous, getErr := r.Repository.GetAuthByPublicId(ctx, model.PublicId.String())
+
+	if err = r.Repository.UpdateAuth(ctx, model); err != nil {
+		return
+	}
+
+	emails := []string{model.Email}
+	if getErr == nil && previous.Email != model.Email {
+		emails = append(emails, previous.Email)
+	}
+	r.invalidate(ctx, emails...)
+
+	// akun yang dihapus, token lamanya ditolak CheckAuth
+	if err := cache.Invalidate(ctx, cache.TAG_AUTH_PUBLIC_ID, model.PublicId.String()); err != nil {
+		log.Log.Errorf(ctx, "[cachedRepository, Invalidate] with error detail %v", err.Error())
+	}
+	return
+}
+
+// invalidate akun sudah tersimpan, cache yang gagal dihapus hilang setelah TTL
+func (r cachedRepository) invalidate(ctx context.Context, emails ...string) {
+	if err := cache.Invalidate(ctx, cache.TAG_AUTH_EMAIL, emails...); err != nil {
+		log.Log.Errorf(ctx, "[cachedRepository, Invalidate] with error detail %v", err.Error())
+	}
+}
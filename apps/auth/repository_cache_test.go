@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRepository struct {
+	auths map[string]AuthEntity
+	reads int
+}
+
+func (r *stubRepository) GetAuthByEmail(ctx context.Context, email string) (AuthEntity, error) {
+	r.reads++
+	auth, ok := r.auths[email]
+	if !ok {
+		return AuthEntity{}, response.ErrNotFound
+	}
+	return auth, nil
+}
+
+func (r *stubRepository) CreateAuth(ctx context.Context, model AuthEntity) error {
+	r.auths[model.Email] = model
+	return nil
+}
+
+func (r *stubRepository) GetAuthByPublicId(ctx context.Context, publicId string) (AuthEntity, error) {
+	for _, auth := range r.auths {
+		if auth.PublicId.String() == publicId && auth.DeletedAt == nil {
+			return auth, nil
+		}
+	}
+	return AuthEntity{}, response.ErrAuthIsNotExists
+}
+
+func (r *stubRepository) UpdateAuth(ctx context.Context, model AuthEntity) error {
+	for email, auth := range r.auths {
+		if auth.PublicId == model.PublicId {
+			delete(r.auths, email)
+		}
+	}
+	r.auths[model.Email] = model
+	return nil
+}
+
+func (r *stubRepository) CreateAuthToken(ctx context.Context, model AuthToken) error {
+	return nil
+}
+
+func (r *stubRepository) ConsumeAuthToken(ctx context.Context, purpose TokenPurpose, tokenHash string) (AuthToken, error) {
+	return AuthToken{}, response.ErrTokenInvalid
+}
+
+func (r *stubRepository) GetLoginFailures(ctx context.Context, accountSubject string, ip string) ([]LoginFailure, error) {
+	return nil, nil
+}
+
+func (r *stubRepository) RecordLoginFailure(ctx context.Context, scope LockoutScope, subject string, now time.Time, windowStart time.Time) (LoginFailure, error) {
+	return LoginFailure{Scope: scope, Subject: subject, Failures: 1, LastFailedAt: now}, nil
+}
+
+func (r *stubRepository) LockLoginFailure(ctx context.Context, id int, lockedUntil time.Time) error {
+	return nil
+}
+
+func (r *stubRepository) ClearLoginFailure(ctx context.Context, scope LockoutScope, subject string) error {
+	return nil
+}
+
+func (r *stubRepository) ListLoginFailures(ctx context.Context, subject string, now time.Time, windowStart time.Time) ([]LoginFailure, error) {
+	return nil, nil
+}
+
+func (r *stubRepository) DeleteLoginFailure(ctx context.Context, id int) error {
+	return response.ErrNotFound
+}
+
+func TestCachedRepository(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubRepository{auths: map[string]AuthEntity{}}
+	repo := newCachedRepositoryWithLoader(stub, cache.NewLoader[AuthEntity](cache.NewLRU[AuthEntity](10), time.Minute))
+
+	// email belum terdaftar tidak di-cache, register langsung terlihat oleh login
+	_, err := repo.GetAuthByEmail(ctx, "user@mail.com")
+	require.Equal(t, response.ErrNotFound, err)
+
+	created := AuthEntity{Email: "user@mail.com", PublicId: uuid.New(), Password: "hash", Role: ROLE_User}
+	require.Nil(t, repo.CreateAuth(ctx, created))
+
+	for range 2 {
+		auth, err := repo.GetAuthByEmail(ctx, "user@mail.com")
+		require.Nil(t, err)
+		require.Equal(t, created, auth)
+	}
+	require.Equal(t, 2, stub.reads)
+
+	// email lama tidak boleh masih bisa dipakai login dari cache
+	changed := created
+	changed.Email = "new@mail.com"
+	require.Nil(t, repo.UpdateAuth(ctx, changed))
+
+	_, err = repo.GetAuthByEmail(ctx, "user@mail.com")
+	require.Equal(t, response.ErrNotFound, err)
+	auth, err := repo.GetAuthByEmail(ctx, "new@mail.com")
+	require.Nil(t, err)
+	require.Equal(t, changed, auth)
+
+	// perubahan dari luar repository ini, contoh: instance lain lewat Redis
+	stub.auths["new@mail.com"] = AuthEntity{Email: "new@mail.com", PublicId: changed.PublicId, Password: "other", Role: ROLE_User}
+	require.Nil(t, cache.Invalidate(ctx, cache.TAG_AUTH_EMAIL, "new@mail.com"))
+
+	auth, err = repo.GetAuthByEmail(ctx, "new@mail.com")
+	require.Nil(t, err)
+	require.Equal(t, "other", auth.Password)
+}
@@ -8,4 +8,44 @@ type RegisterRequestPayload struct {
 type LoginRequestPayload struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	ClientIP string `json:"-"`
+}
+
+// UpdateProfileRequestPayload field yang tidak dikirim tidak diubah, string kosong menghapus nilainya
+type UpdateProfileRequestPayload struct {
+	DisplayName *string `json:"display_name"`
+	Phone       *string `json:"phone"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+type ChangePasswordRequestPayload struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailRequestPayload struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type DeleteAccountRequestPayload struct {
+	Password string `json:"password"`
+}
+
+type VerifyEmailRequestPayload struct {
+	Token string `json:"token"`
+}
+
+type ForgotPasswordRequestPayload struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequestPayload struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type ListLockoutRequestPayload struct {
+	// email atau IP, kosong berarti semua
+	Subject string `form:"subject"`
 }
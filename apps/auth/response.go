@@ -0,0 +1,53 @@
+package auth
+
+import "time"
+
+type ProfileResponse struct {
+	PublicId      string    `json:"public_id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	DisplayName   string    `json:"display_name"`
+	Phone         string    `json:"phone"`
+	AvatarURL     string    `json:"avatar_url"`
+	Role          string    `json:"role"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewProfileResponse(model AuthEntity) ProfileResponse {
+	return ProfileResponse{
+		PublicId:      model.PublicId.String(),
+		Email:         model.Email,
+		EmailVerified: model.IsEmailVerified(),
+		DisplayName:   model.DisplayName,
+		Phone:         model.Phone,
+		AvatarURL:     model.AvatarURL,
+		Role:          string(model.Role),
+		CreatedAt:     model.CreatedAt,
+	}
+}
+
+type LockoutResponse struct {
+	Id           int        `json:"id"`
+	Scope        string     `json:"scope"`
+	Subject      string     `json:"subject"`
+	Failures     int        `json:"failures"`
+	Locked       bool       `json:"locked"`
+	LockedUntil  *time.Time `json:"locked_until"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+}
+
+func NewLockoutListResponse(failures []LoginFailure, now time.Time) []LockoutResponse {
+	lockouts := make([]LockoutResponse, 0, len(failures))
+	for _, failure := range failures {
+		lockouts = append(lockouts, LockoutResponse{
+			Id:           failure.Id,
+			Scope:        string(failure.Scope),
+			Subject:      failure.Subject,
+			Failures:     failure.Failures,
+			Locked:       failure.IsLocked(now),
+			LockedUntil:  failure.LockedUntil,
+			LastFailedAt: failure.LastFailedAt,
+		})
+	}
+	return lockouts
+}
@@ -1,24 +1,39 @@
 package auth
 
 import (
+	"Ecommerce-basic/infra/mailer"
 	"Ecommerce-basic/infra/response"
 	"Ecommerce-basic/internal"
+	infraLog "Ecommerce-basic/internal/log"
 	"context"
-	"log"
+	"strings"
+	"time"
 )
 
 type Repository interface {
 	GetAuthByEmail(ctx context.Context, email string) (model AuthEntity, err error)
 	CreateAuth(ctx context.Context, model AuthEntity) (err error)
+	GetAuthByPublicId(ctx context.Context, publicId string) (model AuthEntity, err error)
+	UpdateAuth(ctx context.Context, model AuthEntity) (err error)
+	CreateAuthToken(ctx context.Context, model AuthToken) (err error)
+	ConsumeAuthToken(ctx context.Context, purpose TokenPurpose, tokenHash string) (model AuthToken, err error)
+	GetLoginFailures(ctx context.Context, accountSubject string, ip string) (models []LoginFailure, err error)
+	RecordLoginFailure(ctx context.Context, scope LockoutScope, subject string, now time.Time, windowStart time.Time) (model LoginFailure, err error)
+	LockLoginFailure(ctx context.Context, id int, lockedUntil time.Time) (err error)
+	ClearLoginFailure(ctx context.Context, scope LockoutScope, subject string) (err error)
+	ListLoginFailures(ctx context.Context, subject string, now time.Time, windowStart time.Time) (models []LoginFailure, err error)
+	DeleteLoginFailure(ctx context.Context, id int) (err error)
 }
 
 type service struct {
-	repo Repository
+	repo   Repository
+	mailer mailer.Mailer
 }
 
-func newService(repo Repository) service {
+func newService(repo Repository, mailer mailer.Mailer) service {
 	return service{
-		repo: repo,
+		repo:   repo,
+		mailer: mailer,
 	}
 }
 
@@ -44,9 +59,19 @@ func (s service) register(ctx context.Context, req RegisterRequestPayload) (err
 		return response.ErrEmailAlreadyUsed
 	}
 
-	return s.repo.CreateAuth(ctx, authEntity)
+	if err = s.repo.CreateAuth(ctx, authEntity); err != nil {
+		return
+	}
+
+	// akun sudah dibuat, email yang gagal dikirim bisa diminta lagi lewat /auth/me/verify-email
+	if err := s.sendToken(ctx, authEntity, TOKEN_VerifyEmail); err != nil {
+		infraLog.Log.Errorf(ctx, "[register, sendToken] with error detail %v", err.Error())
+	}
+	return
 }
 
+// login email yang tidak terdaftar dan password yang salah sama-sama menjadi ErrInvalidCredentials
+// dengan lama proses yang sama, keduanya dihitung sebagai login gagal per email dan per IP
 func (s service) login(ctx context.Context, req LoginRequestPayload) (token string, err error) {
 	authEntity := NewFromLoginRequest(req)
 
@@ -57,17 +82,313 @@ func (s service) login(ctx context.Context, req LoginRequestPayload) (token stri
 		return
 	}
 
+	now := time.Now()
+	subject := lockoutSubject(authEntity.Email)
+	if err = s.checkLockout(ctx, subject, req.ClientIP, now); err != nil {
+		return
+	}
+
 	model, err := s.repo.GetAuthByEmail(ctx, authEntity.Email)
-	if err != nil {
-		log.Println("error when try to GetAuthByEmail with deail", err.Error())
+	if err != nil && err != response.ErrNotFound {
+		infraLog.Log.Errorf(ctx, "[login, GetAuthByEmail] with error detail %v", err.Error())
 		return
 	}
 
-	if err = authEntity.VerifyPasswordFromPlain(model.Password); err != nil {
-		err = response.ErrPasswordNotMatch
+	// email yang tidak terdaftar tetap menjalankan bcrypt
+	password := model.Password
+	if !model.IsExists() {
+		password = dummyPasswordHash()
+	}
+	if err = authEntity.VerifyPasswordFromPlain(password); err != nil || !model.IsExists() {
+		s.recordLoginFailure(ctx, subject, req.ClientIP, now)
+		err = response.ErrInvalidCredentials
+		return
+	}
+
+	if err := s.repo.ClearLoginFailure(ctx, LOCKOUT_Account, subject); err != nil {
+		infraLog.Log.Errorf(ctx, "[login, ClearLoginFailure] with error detail %v", err.Error())
+	}
+
+	if config.Cfg.App.Auth.RequireVerifiedEmail && !model.IsEmailVerified() {
+		err = response.ErrEmailNotVerified
 		return
 	}
 
 	token, err = model.GenerateToken(config.Cfg.App.Encryption.JWTSecret)
 	return
 }
+
+// checkLockout email atau IP yang sedang terkunci ditolak sebelum password diperiksa
+func (s service) checkLockout(ctx context.Context, subject string, ip string, now time.Time) (err error) {
+	failures, err := s.repo.GetLoginFailures(ctx, subject, ip)
+	if err != nil {
+		infraLog.Log.Errorf(ctx, "[checkLockout, GetLoginFailures] with error detail %v", err.Error())
+		return
+	}
+
+	for _, failure := range failures {
+		if failure.IsLocked(now) {
+			return response.ErrTooManyLoginAttempts
+		}
+	}
+	return
+}
+
+// recordLoginFailure error hanya dicatat, respons login tetap ErrInvalidCredentials
+func (s service) recordLoginFailure(ctx context.Context, subject string, ip string, now time.Time) {
+	s.recordFailure(ctx, LOCKOUT_Account, subject, accountLockoutPolicy(), now)
+	if ip != "" {
+		s.recordFailure(ctx, LOCKOUT_IP, ip, ipLockoutPolicy(), now)
+	}
+}
+
+func (s service) recordFailure(ctx context.Context, scope LockoutScope, subject string, policy LockoutPolicy, now time.Time) {
+	failure, err := s.repo.RecordLoginFailure(ctx, scope, subject, now, policy.WindowStart(now))
+	if err != nil {
+		infraLog.Log.Errorf(ctx, "[recordFailure, RecordLoginFailure] with error detail %v", err.Error())
+		return
+	}
+
+	duration := policy.LockDuration(failure.Failures)
+	if duration == 0 {
+		return
+	}
+	if err = s.repo.LockLoginFailure(ctx, failure.Id, now.Add(duration)); err != nil {
+		infraLog.Log.Errorf(ctx, "[recordFailure, LockLoginFailure] with error detail %v", err.Error())
+		return
+	}
+	infraLog.Log.Infof(ctx, "[recordFailure] %v %v locked for %v after %d failed logins", scope, subject, duration, failure.Failures)
+}
+
+// lockouts email dan IP yang masih terkunci atau masih punya hitungan gagal
+func (s service) lockouts(ctx context.Context, req ListLockoutRequestPayload) (failures []LoginFailure, err error) {
+	now := time.Now()
+	failures, err = s.repo.ListLoginFailures(ctx, lockoutSubject(req.Subject), now, lockoutPolicy().WindowStart(now))
+	if err != nil {
+		return
+	}
+	if len(failures) == 0 {
+		failures = []LoginFailure{}
+	}
+	return
+}
+
+func (s service) clearLockout(ctx context.Context, id int) (err error) {
+	return s.repo.DeleteLoginFailure(ctx, id)
+}
+
+func (s service) profile(ctx context.Context, publicId string) (model AuthEntity, err error) {
+	return s.repo.GetAuthByPublicId(ctx, publicId)
+}
+
+func (s service) updateProfile(ctx context.Context, publicId string, req UpdateProfileRequestPayload) (model AuthEntity, err error) {
+	model, err = s.repo.GetAuthByPublicId(ctx, publicId)
+	if err != nil {
+		return
+	}
+
+	if err = model.ApplyProfile(req, time.Now()); err != nil {
+		return
+	}
+
+	if err = s.repo.UpdateAuth(ctx, model); err != nil {
+		infraLog.Log.Errorf(ctx, "[updateProfile, UpdateAuth] with error detail %v", err.Error())
+		return
+	}
+	return
+}
+
+func (s service) changePassword(ctx context.Context, publicId string, req ChangePasswordRequestPayload) (err error) {
+	model, err := s.repo.GetAuthByPublicId(ctx, publicId)
+	if err != nil {
+		return
+	}
+
+	if err = model.ChangePassword(req.CurrentPassword, req.NewPassword, int(config.Cfg.App.Encryption.Salt), time.Now()); err != nil {
+		return
+	}
+
+	if err = s.repo.UpdateAuth(ctx, model); err != nil {
+		infraLog.Log.Errorf(ctx, "[changePassword, UpdateAuth] with error detail %v", err.Error())
+		return
+	}
+	return
+}
+
+// changeEmail email baru belum terverifikasi sampai pengguna memverifikasinya lagi
+func (s service) changeEmail(ctx context.Context, publicId string, req ChangeEmailRequestPayload) (model AuthEntity, err error) {
+	model, err = s.repo.GetAuthByPublicId(ctx, publicId)
+	if err != nil {
+		return
+	}
+
+	if err = model.VerifyPassword(req.Password); err != nil {
+		return
+	}
+	if err = model.ChangeEmail(req.Email, time.Now()); err != nil {
+		return
+	}
+
+	existing, err := s.repo.GetAuthByEmail(ctx, model.Email)
+	if err != nil && err != response.ErrNotFound {
+		return
+	}
+	if existing.IsExists() {
+		return AuthEntity{}, response.ErrEmailAlreadyUsed
+	}
+
+	if err = s.repo.UpdateAuth(ctx, model); err != nil {
+		infraLog.Log.Errorf(ctx, "[changeEmail, UpdateAuth] with error detail %v", err.Error())
+		return
+	}
+
+	if err := s.sendToken(ctx, model, TOKEN_VerifyEmail); err != nil {
+		infraLog.Log.Errorf(ctx, "[changeEmail, sendToken] with error detail %v", err.Error())
+	}
+	return
+}
+
+// deleteAccount menganonimkan akun, transaksi tetap tersimpan dengan public_id yang sama
+func (s service) deleteAccount(ctx context.Context, publicId string, req DeleteAccountRequestPayload) (err error) {
+	model, err := s.repo.GetAuthByPublicId(ctx, publicId)
+	if err != nil {
+		return
+	}
+
+	if err = model.VerifyPassword(req.Password); err != nil {
+		return
+	}
+	model.Anonymize(time.Now())
+
+	if err = s.repo.UpdateAuth(ctx, model); err != nil {
+		infraLog.Log.Errorf(ctx, "[deleteAccount, UpdateAuth] with error detail %v", err.Error())
+		return
+	}
+	return
+}
+
+// resendVerification mengirim ulang link verifikasi, link sebelumnya tidak berlaku lagi
+func (s service) resendVerification(ctx context.Context, publicId string) (err error) {
+	model, err := s.repo.GetAuthByPublicId(ctx, publicId)
+	if err != nil {
+		return
+	}
+	if model.IsEmailVerified() {
+		return response.ErrEmailAlreadyVerified
+	}
+
+	if err = s.sendToken(ctx, model, TOKEN_VerifyEmail); err != nil {
+		infraLog.Log.Errorf(ctx, "[resendVerification, sendToken] with error detail %v", err.Error())
+		return
+	}
+	return
+}
+
+func (s service) verifyEmail(ctx context.Context, req VerifyEmailRequestPayload) (err error) {
+	model, err := s.consumeToken(ctx, TOKEN_VerifyEmail, req.Token)
+	if err != nil {
+		return
+	}
+
+	if err = model.VerifyEmail(time.Now()); err != nil {
+		return
+	}
+
+	if err = s.repo.UpdateAuth(ctx, model); err != nil {
+		infraLog.Log.Errorf(ctx, "[verifyEmail, UpdateAuth] with error detail %v", err.Error())
+		return
+	}
+	return
+}
+
+// forgotPassword tidak memberi tahu apakah email terdaftar, email yang tidak terdaftar tetap berhasil
+// tanpa mengirim apa pun
+func (s service) forgotPassword(ctx context.Context, req ForgotPasswordRequestPayload) (err error) {
+	authEntity := AuthEntity{Email: strings.TrimSpace(req.Email)}
+	if err = authEntity.ValidateEmail(); err != nil {
+		return
+	}
+
+	model, err := s.repo.GetAuthByEmail(ctx, authEntity.Email)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil
+		}
+		return
+	}
+
+	if err := s.sendToken(ctx, model, TOKEN_ResetPassword); err != nil {
+		infraLog.Log.Errorf(ctx, "[forgotPassword, sendToken] with error detail %v", err.Error())
+	}
+	return nil
+}
+
+func (s service) resetPassword(ctx context.Context, req ResetPasswordRequestPayload) (err error) {
+	// password divalidasi sebelum token dipakai supaya token tidak hangus karena password salah format
+	if err = (AuthEntity{Password: req.NewPassword}).ValidatePassword(); err != nil {
+		return
+	}
+
+	model, err := s.consumeToken(ctx, TOKEN_ResetPassword, req.Token)
+	if err != nil {
+		return
+	}
+
+	if err = model.ResetPassword(req.NewPassword, int(config.Cfg.App.Encryption.Salt), time.Now()); err != nil {
+		return
+	}
+
+	if err = s.repo.UpdateAuth(ctx, model); err != nil {
+		infraLog.Log.Errorf(ctx, "[resetPassword, UpdateAuth] with error detail %v", err.Error())
+		return
+	}
+	return
+}
+
+// consumeToken token yang tidak ada, kedaluwarsa, sudah dipakai, atau akunnya sudah dihapus / ganti email
+// semuanya menjadi ErrTokenInvalid
+func (s service) consumeToken(ctx context.Context, purpose TokenPurpose, plain string) (model AuthEntity, err error) {
+	if strings.TrimSpace(plain) == "" {
+		return AuthEntity{}, response.ErrTokenInvalid
+	}
+
+	token, err := s.repo.ConsumeAuthToken(ctx, purpose, HashToken(plain))
+	if err != nil {
+		return
+	}
+
+	model, err = s.repo.GetAuthByPublicId(ctx, token.AuthPublicId.String())
+	if err != nil {
+		if err == response.ErrAuthIsNotExists {
+			err = response.ErrTokenInvalid
+		}
+		return
+	}
+
+	if !token.IsFor(model) {
+		return AuthEntity{}, response.ErrTokenInvalid
+	}
+	return
+}
+
+// sendToken membuat token baru lalu mengirim link-nya ke email akun
+func (s service) sendToken(ctx context.Context, model AuthEntity, purpose TokenPurpose) (err error) {
+	template, path, ttl := "verify_email", "/verify-email", verifyTokenTTL()
+	if purpose == TOKEN_ResetPassword {
+		template, path, ttl = "reset_password", "/reset-password", resetTokenTTL()
+	}
+
+	token, plain, err := NewAuthToken(model, purpose, ttl, time.Now())
+	if err != nil {
+		return
+	}
+	if err = s.repo.CreateAuthToken(ctx, token); err != nil {
+		return
+	}
+
+	msg, err := mailer.Render(template, model.Email, newTokenMailData(model, path, plain, ttl))
+	if err != nil {
+		return
+	}
+	return s.mailer.Send(ctx, msg)
+}
@@ -2,18 +2,25 @@ package auth
 
 import (
 	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/mailer"
 	"Ecommerce-basic/infra/response"
 	config "Ecommerce-basic/internal"
 	"context"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
-var svc service
+var (
+	svc    service
+	outbox *mailer.MemoryOutbox
+)
 
 func init() {
 	filename := "../../cmd/api/config.yaml"
@@ -28,7 +35,8 @@ func init() {
 	}
 
 	repo := newRepository(db)
-	svc = newService(repo)
+	outbox = mailer.NewMemoryOutbox()
+	svc = newService(repo, outbox)
 }
 
 func TestRegister_Success(t *testing.T) {
@@ -79,3 +87,228 @@ func TestLogin_Success(t *testing.T) {
 	log.Println(token)
 
 }
+
+func TestProfile(t *testing.T) {
+	ctx := context.Background()
+	email := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+	require.Nil(t, svc.register(ctx, RegisterRequestPayload{Email: email, Password: "mysecretpassword"}))
+
+	registered, err := svc.repo.GetAuthByEmail(ctx, email)
+	require.Nil(t, err)
+	publicId := registered.PublicId.String()
+
+	t.Run("update profile", func(t *testing.T) {
+		model, err := svc.updateProfile(ctx, publicId, UpdateProfileRequestPayload{DisplayName: ptr("Fathur"), Phone: ptr("08123456789")})
+		require.Nil(t, err)
+		require.Equal(t, "Fathur", model.DisplayName)
+
+		model, err = svc.profile(ctx, publicId)
+		require.Nil(t, err)
+		require.Equal(t, "Fathur", model.DisplayName)
+		require.Equal(t, "08123456789", model.Phone)
+	})
+	t.Run("change password", func(t *testing.T) {
+		err := svc.changePassword(ctx, publicId, ChangePasswordRequestPayload{CurrentPassword: "mysecretpassword", NewPassword: "newpassword"})
+		require.Nil(t, err)
+
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "mysecretpassword"})
+		require.Equal(t, response.ErrInvalidCredentials, err)
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "newpassword"})
+		require.Nil(t, err)
+	})
+	t.Run("change email to used email", func(t *testing.T) {
+		other := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+		require.Nil(t, svc.register(ctx, RegisterRequestPayload{Email: other, Password: "mysecretpassword"}))
+
+		_, err := svc.changeEmail(ctx, publicId, ChangeEmailRequestPayload{Email: other, Password: "newpassword"})
+		require.Equal(t, response.ErrEmailAlreadyUsed, err)
+	})
+	t.Run("change email", func(t *testing.T) {
+		newEmail := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+		model, err := svc.changeEmail(ctx, publicId, ChangeEmailRequestPayload{Email: newEmail, Password: "newpassword"})
+		require.Nil(t, err)
+		require.False(t, model.IsEmailVerified())
+
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "newpassword"})
+		require.Equal(t, response.ErrInvalidCredentials, err)
+		email = newEmail
+	})
+	t.Run("delete account", func(t *testing.T) {
+		err := svc.deleteAccount(ctx, publicId, DeleteAccountRequestPayload{Password: "salah"})
+		require.Equal(t, response.ErrPasswordNotMatch, err)
+
+		require.Nil(t, svc.deleteAccount(ctx, publicId, DeleteAccountRequestPayload{Password: "newpassword"}))
+
+		_, err = svc.profile(ctx, publicId)
+		require.Equal(t, response.ErrAuthIsNotExists, err)
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "newpassword"})
+		require.Equal(t, response.ErrInvalidCredentials, err)
+
+		// email bisa dipakai lagi untuk akun baru
+		require.Nil(t, svc.register(ctx, RegisterRequestPayload{Email: email, Password: "mysecretpassword"}))
+	})
+}
+
+// tokenFromOutbox mengambil token dari link email terakhir yang dikirim ke alamat tersebut
+func tokenFromOutbox(t *testing.T, email string) string {
+	msg, ok := outbox.Last(email)
+	require.True(t, ok)
+
+	for _, line := range strings.Split(msg.Text, "\n") {
+		if link, err := url.Parse(strings.TrimSpace(line)); err == nil && link.Query().Get("token") != "" {
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatalf("no token link in email to %v", email)
+	return ""
+}
+
+func TestEmailVerification(t *testing.T) {
+	ctx := context.Background()
+	email := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+	require.Nil(t, svc.register(ctx, RegisterRequestPayload{Email: email, Password: "mysecretpassword"}))
+
+	registered, err := svc.repo.GetAuthByEmail(ctx, email)
+	require.Nil(t, err)
+	require.False(t, registered.IsEmailVerified())
+	publicId := registered.PublicId.String()
+
+	t.Run("resend revokes previous link", func(t *testing.T) {
+		first := tokenFromOutbox(t, email)
+		require.Nil(t, svc.resendVerification(ctx, publicId))
+		require.NotEqual(t, first, tokenFromOutbox(t, email))
+
+		err := svc.verifyEmail(ctx, VerifyEmailRequestPayload{Token: first})
+		require.Equal(t, response.ErrTokenInvalid, err)
+	})
+	t.Run("verify once", func(t *testing.T) {
+		token := tokenFromOutbox(t, email)
+		require.Nil(t, svc.verifyEmail(ctx, VerifyEmailRequestPayload{Token: token}))
+
+		model, err := svc.profile(ctx, publicId)
+		require.Nil(t, err)
+		require.True(t, model.IsEmailVerified())
+
+		err = svc.verifyEmail(ctx, VerifyEmailRequestPayload{Token: token})
+		require.Equal(t, response.ErrTokenInvalid, err)
+		require.Equal(t, response.ErrEmailAlreadyVerified, svc.resendVerification(ctx, publicId))
+	})
+	t.Run("link for old email is invalid after change email", func(t *testing.T) {
+		newEmail := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+		_, err := svc.changeEmail(ctx, publicId, ChangeEmailRequestPayload{Email: newEmail, Password: "mysecretpassword"})
+		require.Nil(t, err)
+		token := tokenFromOutbox(t, newEmail)
+
+		other := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+		_, err = svc.changeEmail(ctx, publicId, ChangeEmailRequestPayload{Email: other, Password: "mysecretpassword"})
+		require.Nil(t, err)
+
+		err = svc.verifyEmail(ctx, VerifyEmailRequestPayload{Token: token})
+		require.Equal(t, response.ErrTokenInvalid, err)
+	})
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	email := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+	require.Nil(t, svc.register(ctx, RegisterRequestPayload{Email: email, Password: "mysecretpassword"}))
+
+	t.Run("unknown email still succeeds", func(t *testing.T) {
+		unknown := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+		require.Nil(t, svc.forgotPassword(ctx, ForgotPasswordRequestPayload{Email: unknown}))
+
+		_, ok := outbox.Last(unknown)
+		require.False(t, ok)
+	})
+	t.Run("reset once", func(t *testing.T) {
+		require.Nil(t, svc.forgotPassword(ctx, ForgotPasswordRequestPayload{Email: email}))
+		token := tokenFromOutbox(t, email)
+
+		// password salah format tidak menghanguskan token
+		err := svc.resetPassword(ctx, ResetPasswordRequestPayload{Token: token, NewPassword: "123"})
+		require.Equal(t, response.ErrPasswordInvalidLength, err)
+
+		require.Nil(t, svc.resetPassword(ctx, ResetPasswordRequestPayload{Token: token, NewPassword: "resetpassword"}))
+		err = svc.resetPassword(ctx, ResetPasswordRequestPayload{Token: token, NewPassword: "otherpassword"})
+		require.Equal(t, response.ErrTokenInvalid, err)
+
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "mysecretpassword"})
+		require.Equal(t, response.ErrInvalidCredentials, err)
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "resetpassword"})
+		require.Nil(t, err)
+
+		model, err := svc.repo.GetAuthByEmail(ctx, email)
+		require.Nil(t, err)
+		require.True(t, model.IsEmailVerified())
+	})
+	t.Run("verify token cannot reset password", func(t *testing.T) {
+		require.Nil(t, svc.register(ctx, RegisterRequestPayload{Email: "x" + email, Password: "mysecretpassword"}))
+		token := tokenFromOutbox(t, "x"+email)
+
+		err := svc.resetPassword(ctx, ResetPasswordRequestPayload{Token: token, NewPassword: "resetpassword"})
+		require.Equal(t, response.ErrTokenInvalid, err)
+	})
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	email := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+	require.Nil(t, svc.register(ctx, RegisterRequestPayload{Email: email, Password: "mysecretpassword"}))
+	attempts := accountLockoutPolicy().MaxAttempts
+
+	t.Run("unknown email and wrong password look the same", func(t *testing.T) {
+		unknown := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+		_, unknownErr := svc.login(ctx, LoginRequestPayload{Email: unknown, Password: "mysecretpassword"})
+		_, wrongErr := svc.login(ctx, LoginRequestPayload{Email: email, Password: "wrongpassword"})
+		require.Equal(t, response.ErrInvalidCredentials, unknownErr)
+		require.Equal(t, unknownErr, wrongErr)
+	})
+	t.Run("success resets account failures", func(t *testing.T) {
+		_, err := svc.login(ctx, LoginRequestPayload{Email: email, Password: "mysecretpassword"})
+		require.Nil(t, err)
+
+		failures, err := svc.lockouts(ctx, ListLockoutRequestPayload{Subject: email})
+		require.Nil(t, err)
+		require.Empty(t, failures)
+	})
+	t.Run("locked account rejects correct password", func(t *testing.T) {
+		for range attempts {
+			_, err := svc.login(ctx, LoginRequestPayload{Email: strings.ToUpper(email), Password: "wrongpassword"})
+			require.Equal(t, response.ErrInvalidCredentials, err)
+		}
+
+		_, err := svc.login(ctx, LoginRequestPayload{Email: email, Password: "mysecretpassword"})
+		require.Equal(t, response.ErrTooManyLoginAttempts, err)
+
+		failures, err := svc.lockouts(ctx, ListLockoutRequestPayload{Subject: email})
+		require.Nil(t, err)
+		require.Len(t, failures, 1)
+		require.Equal(t, attempts, failures[0].Failures)
+		require.True(t, failures[0].IsLocked(time.Now()))
+
+		require.Nil(t, svc.clearLockout(ctx, failures[0].Id))
+		require.Equal(t, response.ErrNotFound, svc.clearLockout(ctx, failures[0].Id))
+
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "mysecretpassword"})
+		require.Nil(t, err)
+	})
+	t.Run("locked ip rejects other accounts", func(t *testing.T) {
+		ip := "203.0.113." + fmt.Sprint(time.Now().UnixNano()%250+1)
+		defer func() {
+			failures, _ := svc.lockouts(ctx, ListLockoutRequestPayload{Subject: ip})
+			for _, failure := range failures {
+				svc.clearLockout(ctx, failure.Id)
+			}
+		}()
+
+		for range ipLockoutPolicy().MaxAttempts {
+			unknown := fmt.Sprintf("%v@gmail.com", uuid.NewString())
+			svc.login(ctx, LoginRequestPayload{Email: unknown, Password: "wrongpassword", ClientIP: ip})
+		}
+
+		_, err := svc.login(ctx, LoginRequestPayload{Email: email, Password: "mysecretpassword", ClientIP: ip})
+		require.Equal(t, response.ErrTooManyLoginAttempts, err)
+		_, err = svc.login(ctx, LoginRequestPayload{Email: email, Password: "mysecretpassword"})
+		require.Nil(t, err)
+	})
+}
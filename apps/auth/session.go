@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"Ecommerce-basic/infra/response"
+	"context"
+)
+
+// sessionChecker dipakai CheckAuth supaya token akun yang sudah dihapus langsung ditolak,
+// hasilnya di-cache per public_id dan dihapus lewat tag setelah akun diubah
+type sessionChecker struct {
+	repo   Repository
+	active *cache.Loader[bool]
+}
+
+// newSessionChecker langsung membaca database jika app.cache.driver none
+func newSessionChecker(repo Repository) sessionChecker {
+	active := cache.FromConfig[bool]("auth:session")
+	if active != nil {
+		cache.Subscribe(cache.TAG_AUTH_PUBLIC_ID, active)
+	}
+
+	return sessionChecker{
+		repo:   repo,
+		active: active,
+	}
+}
+
+func (c sessionChecker) IsActive(ctx context.Context, publicId string) (active bool, err error) {
+	if c.active == nil {
+		return c.isActive(ctx, publicId)
+	}
+	return c.active.Load(ctx, publicId, func(ctx context.Context) (bool, error) {
+		return c.isActive(ctx, publicId)
+	})
+}
+
+// isActive GetAuthByPublicId tidak mengembalikan akun yang sudah dihapus
+func (c sessionChecker) isActive(ctx context.Context, publicId string) (active bool, err error) {
+	if _, err = c.repo.GetAuthByPublicId(ctx, publicId); err != nil {
+		if err == response.ErrAuthIsNotExists {
+			return false, nil
+		}
+		return
+	}
+	return true, nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionChecker(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubRepository{auths: map[string]AuthEntity{}}
+	repo := newCachedRepositoryWithLoader(stub, cache.NewLoader[AuthEntity](cache.NewLRU[AuthEntity](10), time.Minute))
+
+	active := cache.NewLoader[bool](cache.NewLRU[bool](10), time.Minute)
+	cache.Subscribe(cache.TAG_AUTH_PUBLIC_ID, active)
+	checker := sessionChecker{repo: stub, active: active}
+
+	auth := AuthEntity{Email: "user@mail.com", PublicId: uuid.New(), Role: ROLE_User}
+	require.Nil(t, repo.CreateAuth(ctx, auth))
+
+	ok, err := checker.IsActive(ctx, auth.PublicId.String())
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// akun dihapus setelah hasil aktif sudah di-cache
+	now := time.Now()
+	auth.DeletedAt = &now
+	require.Nil(t, repo.UpdateAuth(ctx, auth))
+
+	ok, err = checker.IsActive(ctx, auth.PublicId.String())
+	require.Nil(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TokenPurpose string
+
+const (
+	TOKEN_VerifyEmail   TokenPurpose = "VERIFY_EMAIL"
+	TOKEN_ResetPassword TokenPurpose = "RESET_PASSWORD"
+)
+
+const (
+	defaultVerifyTokenTTL = 24 * time.Hour
+	defaultResetTokenTTL  = time.Hour
+	defaultLinkBaseURL    = "http://localhost:3000"
+
+	tokenBytes = 32
+)
+
+// AuthToken token sekali pakai untuk link di email. Yang disimpan hanya hash-nya,
+// token aslinya hanya ada di email yang dikirim ke pengguna
+type AuthToken struct {
+	Id           int          `db:"id"`
+	AuthPublicId uuid.UUID    `db:"auth_public_id"`
+	Purpose      TokenPurpose `db:"purpose"`
+	TokenHash    string       `db:"token_hash"`
+
+	// email tujuan token, token tidak berlaku lagi jika email akun sudah diganti
+	Email     string     `db:"email"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// NewAuthToken membuat token acak, plain dikirim lewat email dan tidak pernah disimpan
+func NewAuthToken(auth AuthEntity, purpose TokenPurpose, ttl time.Duration, now time.Time) (token AuthToken, plain string, err error) {
+	b := make([]byte, tokenBytes)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	plain = base64.RawURLEncoding.EncodeToString(b)
+
+	token = AuthToken{
+		AuthPublicId: auth.PublicId,
+		Purpose:      purpose,
+		TokenHash:    HashToken(plain),
+		Email:        auth.Email,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}
+	return
+}
+
+// HashToken sha256 hex, token sudah acak 256 bit sehingga tidak perlu bcrypt dan bisa dicari langsung
+func HashToken(plain string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(plain)))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsFor token hanya berlaku untuk akun dan email yang sama dengan saat token dibuat
+func (t AuthToken) IsFor(auth AuthEntity) bool {
+	return t.AuthPublicId == auth.PublicId && t.Email == auth.Email
+}
+
+// VerifyEmail email yang sudah diverifikasi tidak diubah waktunya
+func (a *AuthEntity) VerifyEmail(now time.Time) (err error) {
+	if a.IsEmailVerified() {
+		return response.ErrEmailAlreadyVerified
+	}
+	a.EmailVerifiedAt = &now
+	a.UpdatedAt = now
+	return
+}
+
+// ResetPassword tanpa password lama, pemilik token sudah membuktikan bisa membuka email akun.
+// Email ikut dianggap terverifikasi karena link reset dikirim ke email tersebut
+func (a *AuthEntity) ResetPassword(newPassword string, salt int, now time.Time) (err error) {
+	next := AuthEntity{Password: newPassword}
+	if err = next.ValidatePassword(); err != nil {
+		return
+	}
+	if err = next.EncryptPassword(salt); err != nil {
+		return
+	}
+
+	a.Password = next.Password
+	if !a.IsEmailVerified() {
+		a.EmailVerifiedAt = &now
+	}
+	a.UpdatedAt = now
+	return
+}
+
+// tokenMailData data untuk template verify_email dan reset_password
+type tokenMailData struct {
+	Name      string
+	Email     string
+	Link      string
+	ExpiresIn string
+}
+
+func newTokenMailData(auth AuthEntity, path string, plain string, ttl time.Duration) tokenMailData {
+	return tokenMailData{
+		Name:      auth.DisplayName,
+		Email:     auth.Email,
+		Link:      tokenLink(path, plain),
+		ExpiresIn: ttl.String(),
+	}
+}
+
+// tokenLink contoh: http://localhost:3000/verify-email?token=...
+func tokenLink(path string, plain string) string {
+	return strings.TrimRight(linkBaseURL(), "/") + path + "?token=" + url.QueryEscape(plain)
+}
+
+func verifyTokenTTL() time.Duration {
+	if ttl := config.Cfg.App.Auth.VerifyTokenTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultVerifyTokenTTL
+}
+
+func resetTokenTTL() time.Duration {
+	if ttl := config.Cfg.App.Auth.ResetTokenTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultResetTokenTTL
+}
+
+func linkBaseURL() string {
+	if base := config.Cfg.App.Auth.LinkBaseURL; base != "" {
+		return base
+	}
+	return defaultLinkBaseURL
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"Ecommerce-basic/infra/response"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuthToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	auth := AuthEntity{PublicId: uuid.New(), Email: "user@mail.com"}
+
+	token, plain, err := NewAuthToken(auth, TOKEN_VerifyEmail, time.Hour, now)
+	require.Nil(t, err)
+	require.Len(t, plain, 43)
+	require.Equal(t, url.QueryEscape(plain), plain)
+	require.Len(t, token.TokenHash, 64)
+	require.NotContains(t, token.TokenHash, plain)
+	require.Equal(t, HashToken(plain), token.TokenHash)
+	require.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+	require.True(t, token.IsFor(auth))
+
+	_, other, _ := NewAuthToken(auth, TOKEN_VerifyEmail, time.Hour, now)
+	require.NotEqual(t, plain, other)
+
+	t.Run("email changed", func(t *testing.T) {
+		changed := auth
+		changed.Email = "other@mail.com"
+		require.False(t, token.IsFor(changed))
+	})
+	t.Run("other account", func(t *testing.T) {
+		require.False(t, token.IsFor(AuthEntity{PublicId: uuid.New(), Email: auth.Email}))
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	now := time.Now()
+	auth := AuthEntity{}
+
+	require.Nil(t, auth.VerifyEmail(now))
+	require.True(t, auth.IsEmailVerified())
+	require.Equal(t, response.ErrEmailAlreadyVerified, auth.VerifyEmail(now.Add(time.Hour)))
+	require.Equal(t, now, *auth.EmailVerifiedAt)
+}
+
+func TestResetPassword(t *testing.T) {
+	now := time.Now()
+	auth := AuthEntity{Password: "mysecretpassword"}
+	require.Nil(t, auth.EncryptPassword(4))
+
+	require.Equal(t, response.ErrPasswordInvalidLength, auth.ResetPassword("123", 4, now))
+	require.False(t, auth.IsEmailVerified())
+
+	require.Nil(t, auth.ResetPassword("resetpassword", 4, now))
+	require.Nil(t, auth.VerifyPassword("resetpassword"))
+	require.True(t, auth.IsEmailVerified())
+}
+
+func TestTokenLink(t *testing.T) {
+	link, err := url.Parse(tokenLink("/reset-password", "a-b_c"))
+	require.Nil(t, err)
+	require.Equal(t, "/reset-password", link.Path)
+	require.Equal(t, "a-b_c", link.Query().Get("token"))
+}
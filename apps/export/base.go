@@ -0,0 +1,36 @@
+package export
+
+import (
+	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/infra/gin"
+	"context"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	repo := newRepository(db)
+	svc := newService(repo)
+	handler := newHandler(svc)
+
+	exportRoute := router.Group("/admin/exports")
+	{
+		exportRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		exportRoute.GET("/products", handler.ExportProducts)
+		exportRoute.GET("/transactions", handler.ExportTransactions)
+	}
+}
+
+// Export dipakai oleh cmd/export untuk membuat export terjadwal tanpa HTTP server
+func Export(ctx context.Context, db *sqlx.DB, resource string, req ExportRequestPayload, w io.Writer) (err error) {
+	filter, err := NewExportFilterFromRequest(req)
+	if err != nil {
+		return
+	}
+
+	svc := newService(newRepository(db))
+	return svc.Export(ctx, resource, filter, w)
+}
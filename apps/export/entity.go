@@ -0,0 +1,163 @@
+package export
+
+import (
+	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/infra/response"
+	"strconv"
+	"time"
+)
+
+const (
+	FORMAT_CSV   string = "csv"
+	FORMAT_JSONL string = "jsonl"
+	FORMAT_XLSX  string = "xlsx"
+
+	RESOURCE_PRODUCTS     string = "products"
+	RESOURCE_TRANSACTIONS string = "transactions"
+
+	// format tanggal pendek, "to" dianggap sampai akhir hari tersebut
+	dateLayout = "2006-01-02"
+)
+
+var (
+	MappingContentType = map[string]string{
+		FORMAT_CSV:   "text/csv",
+		FORMAT_JSONL: "application/x-ndjson",
+		FORMAT_XLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}
+)
+
+// Record adalah satu baris export, urutan Values harus sama dengan Columns
+type Record interface {
+	Columns() []string
+	Values() []interface{}
+}
+
+type ExportFilter struct {
+	Format string
+	From   *time.Time
+	To     *time.Time
+	Status *string
+}
+
+type ProductRecord struct {
+	Id        int       `db:"id"`
+	SKU       string    `db:"sku"`
+	Name      string    `db:"name"`
+	Stock     int       `db:"stock"`
+	Price     int       `db:"price"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type TransactionRecord struct {
+	Id           int                           `db:"id"`
+	UserPublicId string                        `db:"user_public_id"`
+	ProductId    int                           `db:"product_id"`
+	ProductSKU   string                        `db:"product_sku"`
+	ProductName  string                        `db:"product_name"`
+	ProductPrice int                           `db:"product_price"`
+	Amount       int                           `db:"amount"`
+	SubTotal     int                           `db:"sub_total"`
+	PlatformFee  int                           `db:"platform_fee"`
+	GrandTotal   int                           `db:"grand_total"`
+	Status       transaction.TransactionStatus `db:"status"`
+	CreatedAt    time.Time                     `db:"created_at"`
+	UpdatedAt    time.Time                     `db:"updated_at"`
+}
+
+func NewExportFilterFromRequest(req ExportRequestPayload) (filter ExportFilter, err error) {
+	filter.Format = req.Format
+	if filter.Format == "" {
+		filter.Format = FORMAT_CSV
+	}
+
+	if req.From != "" {
+		from, err := parseDate(req.From, false)
+		if err != nil {
+			return ExportFilter{}, response.ErrDateRangeInvalid
+		}
+		filter.From = &from
+	}
+
+	if req.To != "" {
+		to, err := parseDate(req.To, true)
+		if err != nil {
+			return ExportFilter{}, response.ErrDateRangeInvalid
+		}
+		filter.To = &to
+	}
+
+	if req.Status != "" {
+		status, ok := statusCode(req.Status)
+		if !ok {
+			return ExportFilter{}, response.ErrTransactionStatusInvalid
+		}
+		filter.Status = &status
+	}
+
+	err = filter.Validate()
+	return
+}
+
+func (f ExportFilter) Validate() (err error) {
+	if _, ok := MappingContentType[f.Format]; !ok {
+		return response.ErrExportFormatInvalid
+	}
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return response.ErrDateRangeInvalid
+	}
+	return
+}
+
+// parseDate menerima RFC3339 atau YYYY-MM-DD
+func parseDate(value string, endOfDay bool) (date time.Time, err error) {
+	date, err = time.Parse(time.RFC3339, value)
+	if err == nil {
+		return
+	}
+
+	date, err = time.Parse(dateLayout, value)
+	if err != nil {
+		return
+	}
+
+	if endOfDay {
+		date = date.Add(24*time.Hour - time.Nanosecond)
+	}
+	return
+}
+
+// status transaksi disimpan sebagai kode, contoh: COMPLETED menjadi "20"
+func statusCode(name string) (code string, ok bool) {
+	for status, statusName := range transaction.MappingTransactionStatus {
+		if statusName == name {
+			return strconv.Itoa(int(status)), true
+		}
+	}
+	return "", false
+}
+
+func (p ProductRecord) Columns() []string {
+	return []string{"id", "sku", "name", "stock", "price", "created_at", "updated_at"}
+}
+
+func (p ProductRecord) Values() []interface{} {
+	return []interface{}{p.Id, p.SKU, p.Name, p.Stock, p.Price, p.CreatedAt, p.UpdatedAt}
+}
+
+func (t TransactionRecord) Columns() []string {
+	return []string{
+		"id", "user_public_id", "product_id", "product_sku", "product_name", "product_price",
+		"amount", "sub_total", "platform_fee", "grand_total", "status", "created_at", "updated_at",
+	}
+}
+
+func (t TransactionRecord) Values() []interface{} {
+	status := transaction.Transaction{Status: t.Status}.GetStatus()
+
+	return []interface{}{
+		t.Id, t.UserPublicId, t.ProductId, t.ProductSKU, t.ProductName, t.ProductPrice,
+		t.Amount, t.SubTotal, t.PlatformFee, t.GrandTotal, status, t.CreatedAt, t.UpdatedAt,
+	}
+}
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExportFilter(t *testing.T) {
+	t.Run("default format", func(t *testing.T) {
+		filter, err := NewExportFilterFromRequest(ExportRequestPayload{})
+		require.Nil(t, err)
+		require.Equal(t, FORMAT_CSV, filter.Format)
+		require.Nil(t, filter.From)
+		require.Nil(t, filter.Status)
+	})
+	t.Run("date only includes the whole last day", func(t *testing.T) {
+		filter, err := NewExportFilterFromRequest(ExportRequestPayload{
+			From: "2024-01-01",
+			To:   "2024-01-31",
+		})
+		require.Nil(t, err)
+		require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+		require.Equal(t, time.Date(2024, 1, 31, 23, 59, 59, 999_999_999, time.UTC), *filter.To)
+	})
+	t.Run("status name to code", func(t *testing.T) {
+		filter, err := NewExportFilterFromRequest(ExportRequestPayload{Status: "COMPLETED"})
+		require.Nil(t, err)
+		require.Equal(t, "20", *filter.Status)
+	})
+	t.Run("format invalid", func(t *testing.T) {
+		_, err := NewExportFilterFromRequest(ExportRequestPayload{Format: "pdf"})
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrExportFormatInvalid, err)
+	})
+	t.Run("date range invalid", func(t *testing.T) {
+		_, err := NewExportFilterFromRequest(ExportRequestPayload{From: "2024-02-01", To: "2024-01-01"})
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrDateRangeInvalid, err)
+	})
+	t.Run("date invalid", func(t *testing.T) {
+		_, err := NewExportFilterFromRequest(ExportRequestPayload{From: "kemarin"})
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrDateRangeInvalid, err)
+	})
+	t.Run("status invalid", func(t *testing.T) {
+		_, err := NewExportFilterFromRequest(ExportRequestPayload{Status: "PAID"})
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrTransactionStatusInvalid, err)
+	})
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal/log"
+	"bufio"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	svc service
+}
+
+func newHandler(svc service) handler {
+	return handler{
+		svc: svc,
+	}
+}
+
+func (h handler) ExportProducts(c *gin.Context) {
+	h.export(c, RESOURCE_PRODUCTS)
+}
+
+func (h handler) ExportTransactions(c *gin.Context) {
+	h.export(c, RESOURCE_TRANSACTIONS)
+}
+
+func (h handler) export(c *gin.Context, resource string) {
+	var req ExportRequestPayload
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	filter, err := NewExportFilterFromRequest(req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	// buffer supaya error di awal (contoh: koneksi database) masih bisa dikirim sebagai JSON
+	output := bufio.NewWriterSize(c.Writer, 64*1024)
+	filename := fmt.Sprintf("%s-%s.%s", resource, time.Now().Format("20060102-150405"), filter.Format)
+
+	c.Header("Content-Type", MappingContentType[filter.Format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	err = h.svc.Export(c.Request.Context(), resource, filter, output)
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			resp := infragin.NewResponse(
+				infragin.WithMessage(err.Error()),
+				infragin.WithError(response.ErrorGeneral),
+			)
+			resp.Send(c)
+			return
+		}
+
+		// response sudah terkirim sebagian, hanya bisa dicatat
+		log.Log.Errorf(c.Request.Context(), "[export] %s stopped with error detail %v", resource, err.Error())
+		return
+	}
+
+	if err = output.Flush(); err != nil {
+		log.Log.Errorf(c.Request.Context(), "[export] %s flush with error detail %v", resource, err.Error())
+	}
+}
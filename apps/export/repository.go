@@ -0,0 +1,88 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// jumlah baris yang diambil setiap FETCH dari server-side cursor
+const fetchSize = 500
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+func (r repository) StreamProducts(ctx context.Context, filter ExportFilter, fn func(record ProductRecord) error) (err error) {
+	query := `
+		SELECT
+			id, sku, name, stock, price, created_at, updated_at
+		FROM products
+		WHERE deleted_at IS NULL
+			AND ($1::timestamp IS NULL OR created_at >= $1)
+			AND ($2::timestamp IS NULL OR created_at <= $2)
+		ORDER BY id ASC
+	`
+
+	return streamWithCursor(ctx, r.db, query, []interface{}{filter.From, filter.To}, fn)
+}
+
+func (r repository) StreamTransactions(ctx context.Context, filter ExportFilter, fn func(record TransactionRecord) error) (err error) {
+	query := `
+		SELECT
+			id, user_public_id, product_id
+			, COALESCE(product_snapshot->>'sku', '') AS product_sku
+			, COALESCE(product_snapshot->>'name', '') AS product_name
+			, product_price, amount, sub_total, platform_fee
+			, grand_total, status, created_at, updated_at
+		FROM transactions
+		WHERE ($1::timestamp IS NULL OR created_at >= $1)
+			AND ($2::timestamp IS NULL OR created_at <= $2)
+			AND ($3::varchar IS NULL OR status = $3)
+		ORDER BY id ASC
+	`
+
+	return streamWithCursor(ctx, r.db, query, []interface{}{filter.From, filter.To, filter.Status}, fn)
+}
+
+// streamWithCursor membaca hasil query lewat DECLARE CURSOR dan FETCH bertahap
+// sehingga tabel yang besar tidak pernah dimuat sekaligus ke memory.
+func streamWithCursor[T any](ctx context.Context, db *sqlx.DB, query string, args []interface{}, fn func(record T) error) (err error) {
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return
+	}
+
+	// cursor otomatis ditutup ketika transaksi selesai
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "DECLARE export_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM export_cursor", fetchSize)
+	for {
+		var records []T
+		if err = tx.SelectContext(ctx, &records, fetch); err != nil {
+			return
+		}
+
+		for _, record := range records {
+			if err = fn(record); err != nil {
+				return
+			}
+		}
+
+		if len(records) < fetchSize {
+			return
+		}
+	}
+}
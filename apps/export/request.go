@@ -0,0 +1,8 @@
+package export
+
+type ExportRequestPayload struct {
+	Format string `form:"format"`
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Status string `form:"status"`
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"Ecommerce-basic/internal/log"
+	"context"
+	"io"
+)
+
+type Repository interface {
+	StreamProducts(ctx context.Context, filter ExportFilter, fn func(record ProductRecord) error) (err error)
+	StreamTransactions(ctx context.Context, filter ExportFilter, fn func(record TransactionRecord) error) (err error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func newService(repo Repository) service {
+	return service{
+		repo: repo,
+	}
+}
+
+func (s service) ExportProducts(ctx context.Context, filter ExportFilter, w io.Writer) (err error) {
+	writer, err := NewWriter(filter.Format, w, RESOURCE_PRODUCTS, ProductRecord{}.Columns())
+	if err != nil {
+		return
+	}
+
+	count := 0
+	err = s.repo.StreamProducts(ctx, filter, func(record ProductRecord) error {
+		count++
+		return writer.Write(record)
+	})
+	if err != nil {
+		log.Log.Errorf(ctx, "[ExportProducts, StreamProducts] with error detail %v", err.Error())
+		return
+	}
+
+	log.Log.Infof(ctx, "exported %d products as %s", count, filter.Format)
+	return writer.Close()
+}
+
+func (s service) ExportTransactions(ctx context.Context, filter ExportFilter, w io.Writer) (err error) {
+	writer, err := NewWriter(filter.Format, w, RESOURCE_TRANSACTIONS, TransactionRecord{}.Columns())
+	if err != nil {
+		return
+	}
+
+	count := 0
+	err = s.repo.StreamTransactions(ctx, filter, func(record TransactionRecord) error {
+		count++
+		return writer.Write(record)
+	})
+	if err != nil {
+		log.Log.Errorf(ctx, "[ExportTransactions, StreamTransactions] with error detail %v", err.Error())
+		return
+	}
+
+	log.Log.Infof(ctx, "exported %d transactions as %s", count, filter.Format)
+	return writer.Close()
+}
+
+// Export memilih resource yang akan diexport, dipakai oleh handler dan cmd/export
+func (s service) Export(ctx context.Context, resource string, filter ExportFilter, w io.Writer) (err error) {
+	if resource == RESOURCE_TRANSACTIONS {
+		return s.ExportTransactions(ctx, filter, w)
+	}
+	return s.ExportProducts(ctx, filter, w)
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/internal"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo)
+}
+
+func TestExportProducts_Success(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := svc.ExportProducts(context.Background(), ExportFilter{Format: FORMAT_CSV}, &buffer)
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(buffer.String(), "id,sku,name"))
+}
+
+func TestExportTransactions_Success(t *testing.T) {
+	var buffer bytes.Buffer
+
+	filter, err := NewExportFilterFromRequest(ExportRequestPayload{Format: FORMAT_JSONL, Status: "CREATED"})
+	require.Nil(t, err)
+
+	err = svc.ExportTransactions(context.Background(), filter, &buffer)
+	require.Nil(t, err)
+
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line != "" {
+			require.Contains(t, line, `"status":"CREATED"`)
+		}
+	}
+}
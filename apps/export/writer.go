@@ -0,0 +1,221 @@
+package export
+
+import (
+	"Ecommerce-basic/infra/response"
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writer menulis record satu per satu tanpa menyimpan seluruh data di memory.
+// Header ditulis ketika writer dibuat sehingga export kosong tetap memiliki kolom.
+type Writer interface {
+	Write(record Record) (err error)
+	Close() (err error)
+}
+
+func NewWriter(format string, w io.Writer, sheetName string, columns []string) (writer Writer, err error) {
+	switch format {
+	case FORMAT_CSV:
+		return newCSVWriter(w, columns)
+	case FORMAT_JSONL:
+		return &jsonlWriter{writer: bufio.NewWriter(w), columns: columns}, nil
+	case FORMAT_XLSX:
+		return newXLSXWriter(w, sheetName, columns)
+	}
+	return nil, response.ErrExportFormatInvalid
+}
+
+func formatValue(value interface{}) (text string, numeric bool) {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case time.Time:
+		return v.Format(time.RFC3339), false
+	case string:
+		return v, false
+	}
+	return fmt.Sprintf("%v", value), false
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []string) (writer *csvWriter, err error) {
+	writer = &csvWriter{writer: csv.NewWriter(w)}
+	err = writer.writer.Write(columns)
+	return
+}
+
+func (c *csvWriter) Write(record Record) (err error) {
+	values := record.Values()
+	line := make([]string, len(values))
+	for i, value := range values {
+		line[i], _ = formatValue(value)
+	}
+	return c.writer.Write(line)
+}
+
+func (c *csvWriter) Close() (err error) {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type jsonlWriter struct {
+	writer  *bufio.Writer
+	columns []string
+}
+
+// Write menjaga urutan kolom, berbeda dengan json.Marshal dari map
+func (j *jsonlWriter) Write(record Record) (err error) {
+	values := record.Values()
+
+	j.writer.WriteByte('{')
+	for i, column := range j.columns {
+		if i > 0 {
+			j.writer.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(column)
+		value := values[i]
+		if date, ok := value.(time.Time); ok {
+			value = date.Format(time.RFC3339)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		j.writer.Write(key)
+		j.writer.WriteByte(':')
+		j.writer.Write(encoded)
+	}
+	j.writer.WriteString("}\n")
+	return
+}
+
+func (j *jsonlWriter) Close() (err error) {
+	return j.writer.Flush()
+}
+
+// xlsxWriter menulis workbook minimal dengan satu sheet dan inline string,
+// sheet ditulis terakhir di dalam zip supaya baris bisa di-stream.
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+func newXLSXWriter(w io.Writer, sheetName string, columns []string) (writer *xlsxWriter, err error) {
+	zipWriter := zip.NewWriter(w)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapeXML(sheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, file := range files {
+		entry, err := zipWriter.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(entry, file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return
+	}
+
+	writer = &xlsxWriter{
+		zip:   zipWriter,
+		sheet: bufio.NewWriter(sheet),
+	}
+	if _, err = writer.sheet.WriteString(xlsxSheetHeader); err != nil {
+		return
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	err = writer.writeRow(header)
+	return
+}
+
+func (x *xlsxWriter) Write(record Record) (err error) {
+	return x.writeRow(record.Values())
+}
+
+func (x *xlsxWriter) writeRow(values []interface{}) (err error) {
+	x.sheet.WriteString("<row>")
+	for _, value := range values {
+		text, numeric := formatValue(value)
+		if numeric {
+			fmt.Fprintf(x.sheet, "<c><v>%s</v></c>", text)
+			continue
+		}
+		fmt.Fprintf(x.sheet, `<c t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, escapeXML(text))
+	}
+	_, err = x.sheet.WriteString("</row>")
+	return
+}
+
+func (x *xlsxWriter) Close() (err error) {
+	if _, err = x.sheet.WriteString(xlsxSheetFooter); err != nil {
+		return
+	}
+	if err = x.sheet.Flush(); err != nil {
+		return
+	}
+	return x.zip.Close()
+}
+
+func escapeXML(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"Ecommerce-basic/apps/transaction"
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var createdAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestCSVWriter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer, err := NewWriter(FORMAT_CSV, &buffer, RESOURCE_PRODUCTS, ProductRecord{}.Columns())
+	require.Nil(t, err)
+	require.Nil(t, writer.Write(ProductRecord{Id: 1, SKU: "BJ-01", Name: "Baju, Baru", Stock: 10, Price: 100_000, CreatedAt: createdAt, UpdatedAt: createdAt}))
+	require.Nil(t, writer.Close())
+
+	expected := "id,sku,name,stock,price,created_at,updated_at\n" +
+		"1,BJ-01,\"Baju, Baru\",10,100000,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z\n"
+	require.Equal(t, expected, buffer.String())
+}
+
+func TestCSVWriter_Empty(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer, err := NewWriter(FORMAT_CSV, &buffer, RESOURCE_PRODUCTS, ProductRecord{}.Columns())
+	require.Nil(t, err)
+	require.Nil(t, writer.Close())
+
+	require.Equal(t, "id,sku,name,stock,price,created_at,updated_at\n", buffer.String())
+}
+
+func TestJSONLWriter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer, err := NewWriter(FORMAT_JSONL, &buffer, RESOURCE_TRANSACTIONS, TransactionRecord{}.Columns())
+	require.Nil(t, err)
+	require.Nil(t, writer.Write(TransactionRecord{
+		Id:           1,
+		UserPublicId: "user-1",
+		ProductSKU:   "BJ-01",
+		Amount:       2,
+		GrandTotal:   201_000,
+		Status:       transaction.TransactionStatus_Completed,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}))
+	require.Nil(t, writer.Close())
+
+	expected := `{"id":1,"user_public_id":"user-1","product_id":0,"product_sku":"BJ-01","product_name":"","product_price":0,` +
+		`"amount":2,"sub_total":0,"platform_fee":0,"grand_total":201000,"status":"COMPLETED",` +
+		`"created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z"}` + "\n"
+	require.Equal(t, expected, buffer.String())
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer, err := NewWriter(FORMAT_XLSX, &buffer, RESOURCE_PRODUCTS, ProductRecord{}.Columns())
+	require.Nil(t, err)
+	require.Nil(t, writer.Write(ProductRecord{Id: 1, SKU: "BJ-01", Name: "Baju & Celana", Stock: 10, Price: 100_000, CreatedAt: createdAt}))
+	require.Nil(t, writer.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.Nil(t, err)
+
+	files := map[string]string{}
+	for _, file := range reader.File {
+		content, err := file.Open()
+		require.Nil(t, err)
+		data, err := io.ReadAll(content)
+		require.Nil(t, err)
+		files[file.Name] = string(data)
+	}
+
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files, "xl/workbook.xml")
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="products"`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	require.Contains(t, sheet, `<c t="inlineStr"><is><t xml:space="preserve">sku</t></is></c>`)
+	require.Contains(t, sheet, `<c t="inlineStr"><is><t xml:space="preserve">Baju &amp; Celana</t></is></c>`)
+	require.Contains(t, sheet, `<c><v>100000</v></c>`)
+	require.Contains(t, sheet, `</sheetData></worksheet>`)
+}
@@ -0,0 +1,158 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal/log"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AlertType uint8
+
+const (
+	AlertType_LowStock    AlertType = 1
+	AlertType_BackInStock AlertType = 2
+
+	ALERT_LOW_STOCK     string = "LOW_STOCK"
+	ALERT_BACK_IN_STOCK string = "BACK_IN_STOCK"
+	ALERT_UNKNOWN       string = "UNKNOWN"
+)
+
+var (
+	MappingAlertType = map[AlertType]string{
+		AlertType_LowStock:    ALERT_LOW_STOCK,
+		AlertType_BackInStock: ALERT_BACK_IN_STOCK,
+	}
+)
+
+// StockAlert ditulis di transaksi database yang sama dengan perubahan stok (outbox),
+// lalu dikirim oleh dispatcher setelah commit sehingga perubahan yang di-rollback tidak memicu alert.
+type StockAlert struct {
+	Id           int        `db:"id"`
+	ProductId    int        `db:"product_id"`
+	Type         AlertType  `db:"type"`
+	StockBefore  int        `db:"stock_before"`
+	StockAfter   int        `db:"stock_after"`
+	Threshold    int        `db:"threshold"`
+	CreatedAt    time.Time  `db:"created_at"`
+	DispatchedAt *time.Time `db:"dispatched_at"`
+
+	// diisi dari tabel products saat alert diambil dispatcher
+	SKU          string `db:"sku"`
+	Name         string `db:"name"`
+	CurrentStock int    `db:"current_stock"`
+}
+
+// NewStockAlerts mengembalikan alert jika movement melewati reorder threshold (turun)
+// atau membuat produk yang habis tersedia lagi
+func NewStockAlerts(movement StockMovement, threshold int) (alerts []StockAlert) {
+	before := movement.StockBefore()
+	after := movement.StockAfter
+
+	if before > threshold && after <= threshold {
+		alerts = append(alerts, newStockAlert(movement.ProductId, AlertType_LowStock, before, after, threshold))
+	}
+	if before <= 0 && after > 0 {
+		alerts = append(alerts, newStockAlert(movement.ProductId, AlertType_BackInStock, before, after, threshold))
+	}
+	return
+}
+
+func newStockAlert(productId int, alertType AlertType, before int, after int, threshold int) StockAlert {
+	return StockAlert{
+		ProductId:   productId,
+		Type:        alertType,
+		StockBefore: before,
+		StockAfter:  after,
+		Threshold:   threshold,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func (a StockAlert) GetType() string {
+	alertType, ok := MappingAlertType[a.Type]
+	if !ok {
+		return ALERT_UNKNOWN
+	}
+	return alertType
+}
+
+// IsStale true jika produk sudah habis lagi sebelum alert back in stock sempat dikirim
+func (a StockAlert) IsStale() bool {
+	return a.Type == AlertType_BackInStock && a.CurrentStock <= 0
+}
+
+// StockSubscription adalah permintaan pembeli untuk diberi tahu ketika produk tersedia lagi
+type StockSubscription struct {
+	Id           int        `db:"id"`
+	PublicId     string     `db:"public_id"`
+	ProductId    int        `db:"product_id"`
+	UserPublicId string     `db:"user_public_id"`
+	CreatedAt    time.Time  `db:"created_at"`
+	NotifiedAt   *time.Time `db:"notified_at"`
+
+	SKU  string `db:"sku"`
+	Name string `db:"name"`
+}
+
+func NewStockSubscription(product Product, userPublicId string) StockSubscription {
+	return StockSubscription{
+		PublicId:     uuid.NewString(),
+		ProductId:    product.Id,
+		UserPublicId: userPublicId,
+		CreatedAt:    time.Now(),
+		SKU:          product.SKU,
+		Name:         product.Name,
+	}
+}
+
+// ValidateSubscribe hanya produk yang habis yang bisa di-subscribe
+func ValidateSubscribe(product Product) (err error) {
+	if product.Stock > 0 {
+		return response.ErrProductInStock
+	}
+	return
+}
+
+func ValidateReorderThreshold(threshold int) (err error) {
+	if threshold < 0 {
+		return response.ErrReorderThresholdInvalid
+	}
+	return
+}
+
+// LowStockProduct adalah satu baris laporan produk yang stoknya di bawah atau sama dengan reorder threshold
+type LowStockProduct struct {
+	ProductId        int    `db:"product_id"`
+	SKU              string `db:"sku"`
+	Name             string `db:"name"`
+	Stock            int    `db:"stock"`
+	ReorderThreshold int    `db:"reorder_threshold"`
+	Subscribers      int    `db:"subscribers"`
+}
+
+func (p LowStockProduct) Shortage() int {
+	return p.ReorderThreshold - p.Stock
+}
+
+// AlertEmitter mengirim alert ke admin dan notifikasi ke pembeli.
+// Error membuat alert tetap pending dan dicoba lagi pada dispatch berikutnya.
+type AlertEmitter interface {
+	EmitLowStock(ctx context.Context, alert StockAlert) (err error)
+	EmitBackInStock(ctx context.Context, alert StockAlert, subscription StockSubscription) (err error)
+}
+
+// LogAlertEmitter menulis alert ke log, dipakai sampai ada channel notifikasi lain
+type LogAlertEmitter struct{}
+
+func (LogAlertEmitter) EmitLowStock(ctx context.Context, alert StockAlert) (err error) {
+	log.Log.Infof(ctx, "[EmitLowStock] product %s (%s) stock %d is at or below reorder threshold %d", alert.SKU, alert.Name, alert.StockAfter, alert.Threshold)
+	return
+}
+
+func (LogAlertEmitter) EmitBackInStock(ctx context.Context, alert StockAlert, subscription StockSubscription) (err error) {
+	log.Log.Infof(ctx, "[EmitBackInStock] notify user %s product %s (%s) is back in stock", subscription.UserPublicId, alert.SKU, alert.Name)
+	return
+}
@@ -0,0 +1,79 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStockAlerts(t *testing.T) {
+	t.Run("crossing threshold", func(t *testing.T) {
+		// 8 -> 4 dengan threshold 5
+		movement := NewStockMovement(1, MovementType_Sale, -4, 4, REASON_ORDER)
+
+		alerts := NewStockAlerts(movement, 5)
+		require.Len(t, alerts, 1)
+		require.Equal(t, ALERT_LOW_STOCK, alerts[0].GetType())
+		require.Equal(t, 8, alerts[0].StockBefore)
+		require.Equal(t, 4, alerts[0].StockAfter)
+	})
+	t.Run("already below threshold", func(t *testing.T) {
+		movement := NewStockMovement(1, MovementType_Sale, -1, 3, REASON_ORDER)
+
+		require.Empty(t, NewStockAlerts(movement, 5))
+	})
+	t.Run("sold out with default threshold", func(t *testing.T) {
+		movement := NewStockMovement(1, MovementType_Sale, -2, 0, REASON_ORDER)
+
+		alerts := NewStockAlerts(movement, 0)
+		require.Len(t, alerts, 1)
+		require.Equal(t, AlertType_LowStock, alerts[0].Type)
+	})
+	t.Run("back in stock", func(t *testing.T) {
+		movement := NewStockMovement(1, MovementType_Restock, 10, 10, REASON_RESTOCK)
+
+		alerts := NewStockAlerts(movement, 5)
+		require.Len(t, alerts, 1)
+		require.Equal(t, ALERT_BACK_IN_STOCK, alerts[0].GetType())
+	})
+	t.Run("restock above zero", func(t *testing.T) {
+		movement := NewStockMovement(1, MovementType_Restock, 10, 12, REASON_RESTOCK)
+
+		require.Empty(t, NewStockAlerts(movement, 5))
+	})
+	t.Run("back in stock but still low", func(t *testing.T) {
+		movement := NewStockMovement(1, MovementType_Cancellation, 2, 2, REASON_ORDER_CANCELLED)
+
+		alerts := NewStockAlerts(movement, 5)
+		require.Len(t, alerts, 1)
+		require.Equal(t, AlertType_BackInStock, alerts[0].Type)
+	})
+}
+
+func TestStockAlertIsStale(t *testing.T) {
+	alert := StockAlert{Type: AlertType_BackInStock, CurrentStock: 0}
+	require.True(t, alert.IsStale())
+
+	alert.CurrentStock = 3
+	require.False(t, alert.IsStale())
+
+	lowStock := StockAlert{Type: AlertType_LowStock, CurrentStock: 0}
+	require.False(t, lowStock.IsStale())
+}
+
+func TestValidateSubscribe(t *testing.T) {
+	require.Nil(t, ValidateSubscribe(Product{Stock: 0}))
+	require.Equal(t, response.ErrProductInStock, ValidateSubscribe(Product{Stock: 1}))
+}
+
+func TestValidateReorderThreshold(t *testing.T) {
+	require.Nil(t, ValidateReorderThreshold(0))
+	require.Nil(t, ValidateReorderThreshold(10))
+	require.Equal(t, response.ErrReorderThresholdInvalid, ValidateReorderThreshold(-1))
+}
+
+func TestLowStockShortage(t *testing.T) {
+	product := LowStockProduct{Stock: 2, ReorderThreshold: 5}
+	require.Equal(t, 3, product.Shortage())
+}
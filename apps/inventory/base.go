@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/internal"
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// interval default sweeper dan dispatcher jika tidak diatur di config
+const (
+	defaultReservationSweepInterval = time.Minute
+	defaultAlertDispatchInterval    = time.Minute
+)
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	repo := newCachedRepository(newRepository(db))
+	svc := newService(repo)
+	handler := newHandler(svc)
+
+	stockRoute := router.Group("/products")
+	{
+		stockRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		stockRoute.POST("/:id/stock-adjustments", handler.CreateStockAdjustment)
+		stockRoute.GET("/:id/stock-movements", handler.GetStockMovements)
+		stockRoute.PUT("/:id/reorder-threshold", handler.UpdateReorderThreshold)
+	}
+
+	inventoryRoute := router.Group("/admin/inventory")
+	{
+		inventoryRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		inventoryRoute.GET("/reconciliation", handler.GetStockReconciliation)
+		inventoryRoute.GET("/warehouses", handler.GetWarehouses)
+		inventoryRoute.GET("/reservations/metrics", handler.GetReservationMetrics)
+		inventoryRoute.GET("/low-stock", handler.GetLowStockProducts)
+	}
+
+	subscriptionRoute := router.Group("/stock-subscriptions")
+	{
+		subscriptionRoute.Use(infragin.CheckAuth())
+
+		subscriptionRoute.POST("", handler.CreateStockSubscription)
+		subscriptionRoute.GET("", handler.GetStockSubscriptions)
+		subscriptionRoute.DELETE("/:id", handler.DeleteStockSubscription)
+	}
+
+	reservationRoute := router.Group("/reservations")
+	{
+		reservationRoute.Use(infragin.CheckAuth())
+
+		reservationRoute.POST("", handler.CreateReservation)
+		reservationRoute.GET("/:id", handler.GetReservation)
+		reservationRoute.DELETE("/:id", handler.ReleaseReservation)
+	}
+}
+
+// StartReservationSweeper menjalankan sweeper hold yang kedaluwarsa di background sampai ctx dibatalkan
+func StartReservationSweeper(ctx context.Context, db *sqlx.DB) {
+	interval := config.Cfg.App.Inventory.ReservationSweepInterval
+	if interval <= 0 {
+		interval = defaultReservationSweepInterval
+	}
+
+	svc := newService(newRepository(db))
+	go svc.runReservationSweeper(ctx, interval)
+}
+
+// StartStockAlertDispatcher mengirim alert stok lewat emitter di background sampai ctx dibatalkan
+func StartStockAlertDispatcher(ctx context.Context, db *sqlx.DB, emitter AlertEmitter) {
+	interval := config.Cfg.App.Inventory.AlertDispatchInterval
+	if interval <= 0 {
+		interval = defaultAlertDispatchInterval
+	}
+
+	svc := newService(newRepository(db))
+	if emitter != nil {
+		svc.emitter = emitter
+	}
+	go svc.runStockAlertDispatcher(ctx, interval)
+}
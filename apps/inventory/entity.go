@@ -0,0 +1,214 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"time"
+)
+
+type MovementType uint8
+
+const (
+	MovementType_Sale         MovementType = 1
+	MovementType_Cancellation MovementType = 2
+	MovementType_Restock      MovementType = 3
+	MovementType_Adjustment   MovementType = 4
+	MovementType_Import       MovementType = 5
+
+	MOVEMENT_SALE         string = "SALE"
+	MOVEMENT_CANCELLATION string = "CANCELLATION"
+	MOVEMENT_RESTOCK      string = "RESTOCK"
+	MOVEMENT_ADJUSTMENT   string = "ADJUSTMENT"
+	MOVEMENT_IMPORT       string = "IMPORT"
+	MOVEMENT_UNKNOWN      string = "UNKNOWN"
+)
+
+var (
+	MappingMovementType = map[MovementType]string{
+		MovementType_Sale:         MOVEMENT_SALE,
+		MovementType_Cancellation: MOVEMENT_CANCELLATION,
+		MovementType_Restock:      MOVEMENT_RESTOCK,
+		MovementType_Adjustment:   MOVEMENT_ADJUSTMENT,
+		MovementType_Import:       MOVEMENT_IMPORT,
+	}
+)
+
+// reason code untuk penyesuaian manual oleh admin
+const (
+	REASON_RESTOCK     string = "RESTOCK"
+	REASON_RETURNED    string = "RETURNED"
+	REASON_FOUND       string = "FOUND"
+	REASON_DAMAGED     string = "DAMAGED"
+	REASON_LOST        string = "LOST"
+	REASON_STOCK_COUNT string = "STOCK_COUNT"
+)
+
+// reason code yang diisi oleh sistem
+const (
+	REASON_INITIAL_STOCK   string = "INITIAL_STOCK"
+	REASON_PRODUCT_UPDATE  string = "PRODUCT_UPDATE"
+	REASON_ORDER           string = "ORDER"
+	REASON_ORDER_CANCELLED string = "ORDER_CANCELLED"
+	REASON_IMPORT          string = "IMPORT"
+	REASON_LICENSE_KEYS    string = "LICENSE_KEYS"
+)
+
+const (
+	REFERENCE_TRANSACTION string = "transaction"
+	REFERENCE_IMPORT_JOB  string = "import_job"
+)
+
+type adjustmentReason struct {
+	Type MovementType
+	// 1 hanya boleh menambah stok, -1 hanya boleh mengurangi, 0 keduanya
+	Sign int
+}
+
+var adjustmentReasons = map[string]adjustmentReason{
+	REASON_RESTOCK:     {Type: MovementType_Restock, Sign: 1},
+	REASON_RETURNED:    {Type: MovementType_Adjustment, Sign: 1},
+	REASON_FOUND:       {Type: MovementType_Adjustment, Sign: 1},
+	REASON_DAMAGED:     {Type: MovementType_Adjustment, Sign: -1},
+	REASON_LOST:        {Type: MovementType_Adjustment, Sign: -1},
+	REASON_STOCK_COUNT: {Type: MovementType_Adjustment},
+}
+
+// StockMovement adalah satu baris ledger stok yang tidak pernah diubah atau dihapus.
+// Quantity bertanda: positif menambah stok, negatif mengurangi stok.
+type StockMovement struct {
+	Id            int          `db:"id"`
+	ProductId     int          `db:"product_id"`
+	WarehouseId   int          `db:"warehouse_id"`
+	Type          MovementType `db:"type"`
+	Quantity      int          `db:"quantity"`
+	StockAfter    int          `db:"stock_after"`
+	ReasonCode    string       `db:"reason_code"`
+	Note          string       `db:"note"`
+	ReferenceType *string      `db:"reference_type"`
+	ReferenceId   *int         `db:"reference_id"`
+	ActorPublicId string       `db:"actor_public_id"`
+	CreatedAt     time.Time    `db:"created_at"`
+}
+
+func NewStockMovement(productId int, movementType MovementType, quantity int, stockAfter int, reasonCode string) StockMovement {
+	return StockMovement{
+		ProductId:  productId,
+		Type:       movementType,
+		Quantity:   quantity,
+		StockAfter: stockAfter,
+		ReasonCode: reasonCode,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func NewStockMovementFromAdjustmentRequest(productId int, req StockAdjustmentRequestPayload) StockMovement {
+	movement := NewStockMovement(productId, adjustmentReasons[req.ReasonCode].Type, req.Quantity, 0, req.ReasonCode)
+	movement.WarehouseId = req.WarehouseId
+	movement.Note = req.Note
+	movement.ActorPublicId = req.UserPublicId
+	return movement
+}
+
+func (m *StockMovement) WithReference(referenceType string, referenceId int) *StockMovement {
+	m.ReferenceType = &referenceType
+	m.ReferenceId = &referenceId
+	return m
+}
+
+func (m *StockMovement) WithWarehouse(warehouseId int) *StockMovement {
+	m.WarehouseId = warehouseId
+	return m
+}
+
+func (m *StockMovement) WithActor(actorPublicId string) *StockMovement {
+	m.ActorPublicId = actorPublicId
+	return m
+}
+
+// ValidateAdjustment memeriksa reason code dan arah quantity untuk penyesuaian manual
+func (m StockMovement) ValidateAdjustment() (err error) {
+	reason, ok := adjustmentReasons[m.ReasonCode]
+	if !ok {
+		return response.ErrReasonCodeInvalid
+	}
+	if m.Quantity == 0 || m.Quantity*reason.Sign < 0 {
+		return response.ErrStockAdjustmentInvalid
+	}
+	return
+}
+
+// Apply menghitung stok setelah movement, stok tidak boleh negatif
+func (m *StockMovement) Apply(currentStock int) (err error) {
+	stockAfter := currentStock + m.Quantity
+	if stockAfter < 0 {
+		return response.ErrStockNegative
+	}
+	m.StockAfter = stockAfter
+	return
+}
+
+// StockBefore adalah stok produk sebelum movement dicatat
+func (m StockMovement) StockBefore() int {
+	return m.StockAfter - m.Quantity
+}
+
+func (m StockMovement) GetType() string {
+	movementType, ok := MappingMovementType[m.Type]
+	if !ok {
+		return MOVEMENT_UNKNOWN
+	}
+	return movementType
+}
+
+func (m StockMovement) CursorKey() pagination.Key {
+	return pagination.Key{Id: m.Id}
+}
+
+// StockReconciliation membandingkan kolom stock dengan jumlah quantity di ledger dan total stok gudang
+type StockReconciliation struct {
+	ProductId      int    `db:"product_id"`
+	SKU            string `db:"sku"`
+	Name           string `db:"name"`
+	Stock          int    `db:"stock"`
+	LedgerStock    int    `db:"ledger_stock"`
+	WarehouseStock int    `db:"warehouse_stock"`
+}
+
+func (r StockReconciliation) Difference() int {
+	return r.Stock - r.LedgerStock
+}
+
+func (r StockReconciliation) WarehouseDifference() int {
+	return r.Stock - r.WarehouseStock
+}
+
+func (r StockReconciliation) IsBalanced() bool {
+	return r.Difference() == 0 && r.WarehouseDifference() == 0
+}
+
+// productStatusPublished sama dengan product.ProductStatus_Published,
+// inventory tidak bisa import product karena product sudah import inventory
+const productStatusPublished = 10
+
+type Product struct {
+	Id     int    `db:"id"`
+	SKU    string `db:"sku"`
+	Name   string `db:"name"`
+	Stock  int    `db:"stock"`
+	Status int    `db:"status"`
+
+	ReorderThreshold int `db:"reorder_threshold"`
+}
+
+func (p Product) IsExists() bool {
+	return p.Id != 0
+}
+
+// ValidateSellable stok hanya bisa ditahan untuk produk PUBLISHED, sama seperti checkout.
+// produk yang sudah dihapus tidak pernah sampai ke sini karena query mengabaikan deleted_at
+func (p Product) ValidateSellable() (err error) {
+	if p.Status != productStatusPublished {
+		return response.ErrProductNotPublished
+	}
+	return
+}
@@ -0,0 +1,90 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAdjustment(t *testing.T) {
+	t.Run("restock", func(t *testing.T) {
+		movement := NewStockMovementFromAdjustmentRequest(1, StockAdjustmentRequestPayload{Quantity: 10, ReasonCode: REASON_RESTOCK})
+
+		require.Nil(t, movement.ValidateAdjustment())
+		require.Equal(t, MovementType_Restock, movement.Type)
+		require.Equal(t, MOVEMENT_RESTOCK, movement.GetType())
+	})
+	t.Run("stock count can go both ways", func(t *testing.T) {
+		up := NewStockMovementFromAdjustmentRequest(1, StockAdjustmentRequestPayload{Quantity: 3, ReasonCode: REASON_STOCK_COUNT})
+		down := NewStockMovementFromAdjustmentRequest(1, StockAdjustmentRequestPayload{Quantity: -3, ReasonCode: REASON_STOCK_COUNT})
+
+		require.Nil(t, up.ValidateAdjustment())
+		require.Nil(t, down.ValidateAdjustment())
+		require.Equal(t, MovementType_Adjustment, down.Type)
+	})
+	t.Run("reason code invalid", func(t *testing.T) {
+		movement := NewStockMovementFromAdjustmentRequest(1, StockAdjustmentRequestPayload{Quantity: 1, ReasonCode: REASON_ORDER})
+
+		err := movement.ValidateAdjustment()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrReasonCodeInvalid, err)
+	})
+	t.Run("quantity zero", func(t *testing.T) {
+		movement := NewStockMovementFromAdjustmentRequest(1, StockAdjustmentRequestPayload{ReasonCode: REASON_STOCK_COUNT})
+
+		err := movement.ValidateAdjustment()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrStockAdjustmentInvalid, err)
+	})
+	t.Run("damaged must decrease stock", func(t *testing.T) {
+		movement := NewStockMovementFromAdjustmentRequest(1, StockAdjustmentRequestPayload{Quantity: 2, ReasonCode: REASON_DAMAGED})
+
+		err := movement.ValidateAdjustment()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrStockAdjustmentInvalid, err)
+	})
+	t.Run("restock must increase stock", func(t *testing.T) {
+		movement := NewStockMovementFromAdjustmentRequest(1, StockAdjustmentRequestPayload{Quantity: -2, ReasonCode: REASON_RESTOCK})
+
+		err := movement.ValidateAdjustment()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrStockAdjustmentInvalid, err)
+	})
+}
+
+func TestApplyMovement(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		movement := NewStockMovement(1, MovementType_Adjustment, -4, 0, REASON_LOST)
+
+		require.Nil(t, movement.Apply(10))
+		require.Equal(t, 6, movement.StockAfter)
+	})
+	t.Run("stock negative", func(t *testing.T) {
+		movement := NewStockMovement(1, MovementType_Adjustment, -11, 0, REASON_LOST)
+
+		err := movement.Apply(10)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrStockNegative, err)
+	})
+}
+
+func TestStockReconciliation(t *testing.T) {
+	balanced := StockReconciliation{Stock: 10, LedgerStock: 10, WarehouseStock: 10}
+	require.True(t, balanced.IsBalanced())
+
+	mismatched := StockReconciliation{Stock: 10, LedgerStock: 7, WarehouseStock: 10}
+	require.False(t, mismatched.IsBalanced())
+	require.Equal(t, 3, mismatched.Difference())
+
+	warehouseMismatched := StockReconciliation{Stock: 10, LedgerStock: 10, WarehouseStock: 12}
+	require.False(t, warehouseMismatched.IsBalanced())
+	require.Equal(t, -2, warehouseMismatched.WarehouseDifference())
+}
+
+func TestProductValidateSellable(t *testing.T) {
+	require.Nil(t, Product{Status: productStatusPublished}.ValidateSellable())
+	for _, status := range []int{1, 20} {
+		require.Equal(t, response.ErrProductNotPublished, Product{Status: status}.ValidateSellable())
+	}
+}
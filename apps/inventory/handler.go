@@ -0,0 +1,422 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/infra/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	svc service
+}
+
+func newHandler(svc service) handler {
+	return handler{
+		svc: svc,
+	}
+}
+
+func (h handler) CreateStockAdjustment(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req StockAdjustmentRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+	req.UserPublicId = c.GetString("PUBLIC_ID")
+
+	movement, err := h.svc.AdjustStock(c.Request.Context(), productId, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("adjust stock success"),
+		infragin.WithPayload(movement.ToStockMovementResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetStockMovements(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req ListStockMovementRequestPayload
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	movements, meta, err := h.svc.StockMovements(c.Request.Context(), productId, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get stock movements success"),
+		infragin.WithPayload(NewStockMovementListResponse(movements)),
+		infragin.WithMeta(meta),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetStockReconciliation(c *gin.Context) {
+	mismatches, checked, err := h.svc.Reconcile(c.Request.Context())
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	meta := StockReconciliationMetaResponse{
+		Checked:    checked,
+		Mismatched: len(mismatches),
+		Balanced:   len(mismatches) == 0,
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("stock reconciliation success"),
+		infragin.WithPayload(NewStockReconciliationListResponse(mismatches)),
+		infragin.WithMeta(meta),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetWarehouses(c *gin.Context) {
+	warehouses, err := h.svc.Warehouses(c.Request.Context())
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get warehouses success"),
+		infragin.WithPayload(NewWarehouseListResponse(warehouses)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) CreateReservation(c *gin.Context) {
+	var req CreateReservationRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+	req.UserPublicId = c.GetString("PUBLIC_ID")
+
+	reservation, err := h.svc.Reserve(c.Request.Context(), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("create reservation success"),
+		infragin.WithPayload(reservation.ToReservationResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetReservation(c *gin.Context) {
+	reservation, err := h.svc.Reservation(c.Request.Context(), c.Param("id"), c.GetString("PUBLIC_ID"))
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get reservation success"),
+		infragin.WithPayload(reservation.ToReservationResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) ReleaseReservation(c *gin.Context) {
+	if err := h.svc.ReleaseReservation(c.Request.Context(), c.Param("id"), c.GetString("PUBLIC_ID")); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("release reservation success"),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetReservationMetrics(c *gin.Context) {
+	snapshot, activeHolds, activeQuantity, err := h.svc.ReservationMetrics(c.Request.Context())
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	metrics := ReservationMetricsResponse{
+		Sweeps:          snapshot.Sweeps,
+		ExpiredHolds:    snapshot.ExpiredHolds,
+		ExpiredQuantity: snapshot.ExpiredQuantity,
+		FailedSweeps:    snapshot.FailedSweeps,
+		LastSweepAt:     snapshot.LastSweepAt,
+		ActiveHolds:     activeHolds,
+		ActiveQuantity:  activeQuantity,
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get reservation metrics success"),
+		infragin.WithPayload(metrics),
+	)
+	resp.Send(c)
+}
+
+func (h handler) UpdateReorderThreshold(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req UpdateReorderThresholdRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.UpdateReorderThreshold(c.Request.Context(), productId, req); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("update reorder threshold success"),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetLowStockProducts(c *gin.Context) {
+	products, err := h.svc.LowStockProducts(c.Request.Context())
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get low stock products success"),
+		infragin.WithPayload(NewLowStockProductListResponse(products)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) CreateStockSubscription(c *gin.Context) {
+	var req CreateStockSubscriptionRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+	req.UserPublicId = c.GetString("PUBLIC_ID")
+
+	subscription, err := h.svc.SubscribeBackInStock(c.Request.Context(), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("create stock subscription success"),
+		infragin.WithPayload(subscription.ToStockSubscriptionResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetStockSubscriptions(c *gin.Context) {
+	subscriptions, err := h.svc.StockSubscriptions(c.Request.Context(), c.GetString("PUBLIC_ID"))
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get stock subscriptions success"),
+		infragin.WithPayload(NewStockSubscriptionListResponse(subscriptions)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) DeleteStockSubscription(c *gin.Context) {
+	if err := h.svc.UnsubscribeBackInStock(c.Request.Context(), c.Param("id"), c.GetString("PUBLIC_ID")); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("delete stock subscription success"),
+	)
+	resp.Send(c)
+}
@@ -0,0 +1,638 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+func (r repository) Begin(ctx context.Context) (tx *sqlx.Tx, err error) {
+	tx, err = r.db.BeginTxx(ctx, &sql.TxOptions{})
+	return
+}
+
+func (repository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Commit()
+}
+
+func (repository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Rollback()
+}
+
+// GetProductByIdForUpdateWithTx mengunci baris produk sampai transaksi selesai
+func (r repository) GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, stock
+		FROM products
+		WHERE id=$1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &product, query, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetProductById(ctx context.Context, productId int) (product Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, stock
+		FROM products
+		WHERE id=$1 AND deleted_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &product, query, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetProductBySkuForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, sku string) (product Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, stock, status
+		FROM products
+		WHERE sku=$1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &product, query, sku)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetProductBySku(ctx context.Context, sku string) (product Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, stock, reorder_threshold
+		FROM products
+		WHERE sku=$1 AND deleted_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &product, query, sku)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) UpdateReorderThreshold(ctx context.Context, productId int, threshold int) (err error) {
+	query := `
+		UPDATE products
+		SET reorder_threshold=$2, updated_at=NOW(), version=version + 1
+		WHERE id=$1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, productId, threshold)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+	return
+}
+
+func (r repository) UpdateProductStockWithTx(ctx context.Context, tx *sqlx.Tx, product Product) (err error) {
+	query := `
+		UPDATE products
+		SET stock=:stock, updated_at=NOW(), version=version + 1
+		WHERE id=:id
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, product)
+	return
+}
+
+func (r repository) RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement StockMovement) (err error) {
+	return RecordStockMovementWithTx(ctx, tx, movement)
+}
+
+func (r repository) GetWarehouses(ctx context.Context) (warehouses []Warehouse, err error) {
+	query := `
+		SELECT
+			id, code, name, city, latitude, longitude, priority, is_active, created_at
+		FROM warehouses
+		ORDER BY priority, id
+	`
+
+	err = r.db.SelectContext(ctx, &warehouses, query)
+	return
+}
+
+func (r repository) GetWarehouseById(ctx context.Context, id int) (warehouse Warehouse, err error) {
+	query := `
+		SELECT
+			id, code, name, city, latitude, longitude, priority, is_active, created_at
+		FROM warehouses
+		WHERE id=$1
+	`
+
+	err = r.db.GetContext(ctx, &warehouse, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrWarehouseNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetWarehouseStocksWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (stocks []WarehouseStock, err error) {
+	return GetWarehouseStocksWithTx(ctx, tx, productId)
+}
+
+func (r repository) GetStockMovementsByProductId(ctx context.Context, productId int, page pagination.Request) (movements []StockMovement, err error) {
+	operator, direction := page.Order(true)
+	query := fmt.Sprintf(`
+		SELECT
+			id, product_id, warehouse_id, type, quantity, stock_after
+			, reason_code, note, reference_type, reference_id
+			, actor_public_id, created_at
+		FROM stock_movements
+		WHERE product_id=$1
+			AND ($2 = 0 OR id %s $2)
+		ORDER BY id %s
+		LIMIT $3
+	`, operator, direction)
+
+	cursorId := 0
+	if page.Cursor != nil {
+		cursorId = page.Cursor.Id
+	}
+
+	err = r.db.SelectContext(ctx, &movements, query, productId, cursorId, page.Limit())
+	return
+}
+
+// GetStockReconciliations menjumlahkan ledger dan stok gudang per produk untuk dibandingkan dengan kolom stock
+func (r repository) GetStockReconciliations(ctx context.Context) (reconciliations []StockReconciliation, err error) {
+	query := `
+		SELECT
+			p.id AS product_id, p.sku, p.name, p.stock
+			, COALESCE(m.ledger_stock, 0) AS ledger_stock
+			, COALESCE(ws.warehouse_stock, 0) AS warehouse_stock
+		FROM products p
+		LEFT JOIN (
+			SELECT product_id, SUM(quantity) AS ledger_stock
+			FROM stock_movements
+			GROUP BY product_id
+		) m ON m.product_id = p.id
+		LEFT JOIN (
+			SELECT product_id, SUM(stock) AS warehouse_stock
+			FROM warehouse_stocks
+			GROUP BY product_id
+		) ws ON ws.product_id = p.id
+		WHERE p.deleted_at IS NULL
+		ORDER BY p.id
+	`
+
+	err = r.db.SelectContext(ctx, &reconciliations, query)
+	return
+}
+
+func (r repository) CreateReservationWithTx(ctx context.Context, tx *sqlx.Tx, reservation Reservation) (id int, err error) {
+	query := `
+		INSERT INTO stock_reservations (
+			public_id, product_id, user_public_id, quantity
+			, status, expires_at, created_at, updated_at
+		) VALUES (
+			:public_id, :product_id, :user_public_id, :quantity
+			, :status, :expires_at, :created_at, :updated_at
+		)
+		RETURNING id
+	`
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	err = stmt.GetContext(ctx, &id, reservation)
+	return
+}
+
+func (r repository) GetReservationByPublicId(ctx context.Context, publicId string) (reservation Reservation, err error) {
+	query := `
+		SELECT
+			id, public_id, product_id, user_public_id, quantity
+			, status, expires_at, transaction_id, created_at, updated_at
+		FROM stock_reservations
+		WHERE public_id=$1
+	`
+
+	err = r.db.GetContext(ctx, &reservation, query, publicId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrReservationNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetReservationByPublicIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, publicId string) (reservation Reservation, err error) {
+	return GetReservationByPublicIdForUpdateWithTx(ctx, tx, publicId)
+}
+
+func (r repository) UpdateReservationStatusWithTx(ctx context.Context, tx *sqlx.Tx, reservation Reservation) (err error) {
+	return UpdateReservationStatusWithTx(ctx, tx, reservation)
+}
+
+func (r repository) GetReservedStockWithTx(ctx context.Context, tx *sqlx.Tx, productId int, excludeReservationId int) (reserved int, err error) {
+	return GetReservedStockWithTx(ctx, tx, productId, excludeReservationId)
+}
+
+// ExpireReservations menandai semua hold yang lewat waktu sebagai EXPIRED dan mengembalikan jumlahnya
+func (r repository) ExpireReservations(ctx context.Context, now time.Time) (expiredHolds int, expiredQuantity int, err error) {
+	query := `
+		WITH expired AS (
+			UPDATE stock_reservations
+			SET status=$1, updated_at=$2
+			WHERE status=$3 AND expires_at <= $2
+			RETURNING quantity
+		)
+		SELECT COUNT(*), COALESCE(SUM(quantity), 0) FROM expired
+	`
+
+	err = r.db.QueryRowxContext(ctx, query, ReservationStatus_Expired, now, ReservationStatus_Active).Scan(&expiredHolds, &expiredQuantity)
+	return
+}
+
+func (r repository) GetActiveReservationTotals(ctx context.Context) (activeHolds int, activeQuantity int, err error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(quantity), 0)
+		FROM stock_reservations
+		WHERE status=$1 AND expires_at > NOW()
+	`
+
+	err = r.db.QueryRowxContext(ctx, query, ReservationStatus_Active).Scan(&activeHolds, &activeQuantity)
+	return
+}
+
+// GetLowStockProducts mengambil produk yang stoknya sudah di bawah atau sama dengan reorder threshold
+func (r repository) GetLowStockProducts(ctx context.Context) (products []LowStockProduct, err error) {
+	query := `
+		SELECT
+			p.id AS product_id, p.sku, p.name, p.stock, p.reorder_threshold
+			, COUNT(s.id) AS subscribers
+		FROM products p
+		LEFT JOIN stock_subscriptions s ON s.product_id = p.id AND s.notified_at IS NULL
+		WHERE p.deleted_at IS NULL AND p.stock <= p.reorder_threshold
+		GROUP BY p.id
+		ORDER BY p.stock, p.id
+	`
+
+	err = r.db.SelectContext(ctx, &products, query)
+	return
+}
+
+// CreateStockSubscription bersifat idempotent, subscription yang masih menunggu dikembalikan apa adanya
+func (r repository) CreateStockSubscription(ctx context.Context, subscription StockSubscription) (created StockSubscription, err error) {
+	query := `
+		INSERT INTO stock_subscriptions (
+			public_id, product_id, user_public_id, created_at
+		) VALUES (
+			:public_id, :product_id, :user_public_id, :created_at
+		)
+		ON CONFLICT (product_id, user_public_id) WHERE notified_at IS NULL
+		DO NOTHING
+	`
+
+	if _, err = r.db.NamedExecContext(ctx, query, subscription); err != nil {
+		return
+	}
+
+	query = `
+		SELECT
+			s.id, s.public_id, s.product_id, s.user_public_id, s.created_at, s.notified_at
+			, p.sku, p.name
+		FROM stock_subscriptions s
+		JOIN products p ON p.id = s.product_id
+		WHERE s.product_id=$1 AND s.user_public_id=$2 AND s.notified_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &created, query, subscription.ProductId, subscription.UserPublicId)
+	return
+}
+
+func (r repository) GetStockSubscriptionsByUserPublicId(ctx context.Context, userPublicId string) (subscriptions []StockSubscription, err error) {
+	query := `
+		SELECT
+			s.id, s.public_id, s.product_id, s.user_public_id, s.created_at, s.notified_at
+			, p.sku, p.name
+		FROM stock_subscriptions s
+		JOIN products p ON p.id = s.product_id
+		WHERE s.user_public_id=$1 AND s.notified_at IS NULL
+		ORDER BY s.id DESC
+	`
+
+	err = r.db.SelectContext(ctx, &subscriptions, query, userPublicId)
+	return
+}
+
+func (r repository) DeleteStockSubscription(ctx context.Context, publicId string, userPublicId string) (err error) {
+	query := `
+		DELETE FROM stock_subscriptions
+		WHERE public_id=$1 AND user_public_id=$2 AND notified_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, publicId, userPublicId)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		return response.ErrStockSubscriptionNotFound
+	}
+	return
+}
+
+// GetPendingStockAlertsForUpdateWithTx mengunci alert yang belum dikirim, SKIP LOCKED supaya dispatcher lain tidak menunggu
+func (r repository) GetPendingStockAlertsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, limit int) (alerts []StockAlert, err error) {
+	query := `
+		SELECT
+			a.id, a.product_id, a.type, a.stock_before, a.stock_after, a.threshold
+			, a.created_at, a.dispatched_at
+			, p.sku, p.name, p.stock AS current_stock
+		FROM stock_alerts a
+		JOIN products p ON p.id = a.product_id
+		WHERE a.dispatched_at IS NULL
+		ORDER BY a.id
+		LIMIT $1
+		FOR UPDATE OF a SKIP LOCKED
+	`
+
+	err = tx.SelectContext(ctx, &alerts, query, limit)
+	return
+}
+
+func (r repository) GetPendingStockSubscriptionsWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (subscriptions []StockSubscription, err error) {
+	query := `
+		SELECT
+			id, public_id, product_id, user_public_id, created_at, notified_at
+		FROM stock_subscriptions
+		WHERE product_id=$1 AND notified_at IS NULL
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED
+	`
+
+	err = tx.SelectContext(ctx, &subscriptions, query, productId)
+	return
+}
+
+func (r repository) MarkStockSubscriptionNotifiedWithTx(ctx context.Context, tx *sqlx.Tx, id int, notifiedAt time.Time) (err error) {
+	query := `
+		UPDATE stock_subscriptions
+		SET notified_at=$2
+		WHERE id=$1
+	`
+
+	_, err = tx.ExecContext(ctx, query, id, notifiedAt)
+	return
+}
+
+func (r repository) MarkStockAlertDispatchedWithTx(ctx context.Context, tx *sqlx.Tx, id int, dispatchedAt time.Time) (err error) {
+	query := `
+		UPDATE stock_alerts
+		SET dispatched_at=$2
+		WHERE id=$1
+	`
+
+	_, err = tx.ExecContext(ctx, query, id, dispatchedAt)
+	return
+}
+
+// GetReservedStockWithTx menjumlahkan hold aktif yang belum lewat waktu, kecuali excludeReservationId.
+// Dipakai juga oleh modul transaction saat checkout.
+func GetReservedStockWithTx(ctx context.Context, tx *sqlx.Tx, productId int, excludeReservationId int) (reserved int, err error) {
+	query := `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM stock_reservations
+		WHERE product_id=$1 AND status=$2 AND expires_at > NOW() AND id <> $3
+	`
+
+	err = tx.GetContext(ctx, &reserved, query, productId, ReservationStatus_Active, excludeReservationId)
+	return
+}
+
+func GetReservationByPublicIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, publicId string) (reservation Reservation, err error) {
+	query := `
+		SELECT
+			id, public_id, product_id, user_public_id, quantity
+			, status, expires_at, transaction_id, created_at, updated_at
+		FROM stock_reservations
+		WHERE public_id=$1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &reservation, query, publicId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrReservationNotFound
+		}
+		return
+	}
+	return
+}
+
+func UpdateReservationStatusWithTx(ctx context.Context, tx *sqlx.Tx, reservation Reservation) (err error) {
+	query := `
+		UPDATE stock_reservations
+		SET status=:status, transaction_id=:transaction_id, updated_at=:updated_at
+		WHERE id=:id
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, reservation)
+	return
+}
+
+// RecordStockMovementWithTx dipakai juga oleh modul product, transaction dan productimport
+// supaya ledger dan stok per gudang selalu ditulis di transaksi database yang sama dengan perubahan stok.
+// Penambahan tanpa gudang dicatat di gudang utama (prioritas tertinggi), pengurangan tanpa gudang
+// dibagi ke beberapa gudang. Alert stok dihitung sekali dari movement asalnya.
+func RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement StockMovement) (err error) {
+	movements, err := warehouseMovementsWithTx(ctx, tx, movement)
+	if err != nil {
+		return
+	}
+
+	for _, warehouseMovement := range movements {
+		if err = recordWarehouseMovementWithTx(ctx, tx, warehouseMovement); err != nil {
+			return
+		}
+	}
+
+	return recordStockAlertsWithTx(ctx, tx, movement)
+}
+
+// warehouseMovementsWithTx pengurangan tanpa gudang dibagi ke gudang yang masih punya stok lewat SpreadStockDecrease
+func warehouseMovementsWithTx(ctx context.Context, tx *sqlx.Tx, movement StockMovement) (movements []StockMovement, err error) {
+	if movement.WarehouseId != 0 {
+		return []StockMovement{movement}, nil
+	}
+
+	if movement.Quantity >= 0 {
+		if movement.WarehouseId, err = getPrimaryWarehouseIdWithTx(ctx, tx); err != nil {
+			return
+		}
+		return []StockMovement{movement}, nil
+	}
+
+	stocks, err := GetWarehouseStocksWithTx(ctx, tx, movement.ProductId)
+	if err != nil {
+		return
+	}
+	return SpreadStockDecrease(movement, stocks)
+}
+
+func recordWarehouseMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement StockMovement) (err error) {
+	query := `
+		INSERT INTO warehouse_stocks (
+			warehouse_id, product_id, stock, updated_at
+		) VALUES (
+			$1, $2, $3, NOW()
+		)
+		ON CONFLICT (warehouse_id, product_id)
+		DO UPDATE SET stock = warehouse_stocks.stock + EXCLUDED.stock, updated_at = NOW()
+		RETURNING stock
+	`
+
+	var warehouseStock int
+	if err = tx.GetContext(ctx, &warehouseStock, query, movement.WarehouseId, movement.ProductId, movement.Quantity); err != nil {
+		return
+	}
+	if warehouseStock < 0 {
+		return response.ErrStockNegative
+	}
+
+	query = `
+		INSERT INTO stock_movements (
+			product_id, warehouse_id, type, quantity, stock_after
+			, reason_code, note, reference_type, reference_id
+			, actor_public_id, created_at
+		) VALUES (
+			:product_id, :warehouse_id, :type, :quantity, :stock_after
+			, :reason_code, :note, :reference_type, :reference_id
+			, :actor_public_id, :created_at
+		)
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, movement)
+	return
+}
+
+// recordStockAlertsWithTx menulis alert ke outbox jika movement melewati reorder threshold.
+// Alert back in stock hanya ditulis jika ada pembeli yang menunggu.
+func recordStockAlertsWithTx(ctx context.Context, tx *sqlx.Tx, movement StockMovement) (err error) {
+	var threshold int
+	query := `
+		SELECT reorder_threshold
+		FROM products
+		WHERE id=$1
+	`
+	if err = tx.GetContext(ctx, &threshold, query, movement.ProductId); err != nil {
+		return
+	}
+
+	query = `
+		INSERT INTO stock_alerts (
+			product_id, type, stock_before, stock_after, threshold, created_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE $2 <> $7 OR EXISTS (
+			SELECT 1 FROM stock_subscriptions
+			WHERE product_id = $1 AND notified_at IS NULL
+		)
+	`
+	for _, alert := range NewStockAlerts(movement, threshold) {
+		_, err = tx.ExecContext(ctx, query,
+			alert.ProductId, alert.Type, alert.StockBefore, alert.StockAfter, alert.Threshold, alert.CreatedAt,
+			AlertType_BackInStock,
+		)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// GetWarehouseStocksWithTx mengembalikan stok produk di setiap gudang aktif, termasuk gudang dengan stok 0.
+// Pemanggil harus sudah mengunci baris produk agar stok gudang tidak berubah sampai transaksi selesai.
+func GetWarehouseStocksWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (stocks []WarehouseStock, err error) {
+	query := `
+		SELECT
+			w.id AS warehouse_id, $1::int AS product_id, COALESCE(ws.stock, 0) AS stock
+			, w.code AS warehouse_code, w.name AS warehouse_name, w.city
+			, w.latitude, w.longitude, w.priority
+		FROM warehouses w
+		LEFT JOIN warehouse_stocks ws ON ws.warehouse_id = w.id AND ws.product_id = $1
+		WHERE w.is_active
+		ORDER BY w.priority, w.id
+	`
+
+	err = tx.SelectContext(ctx, &stocks, query, productId)
+	return
+}
+
+func getPrimaryWarehouseIdWithTx(ctx context.Context, tx *sqlx.Tx) (id int, err error) {
+	query := `
+		SELECT id
+		FROM warehouses
+		WHERE is_active
+		ORDER BY priority, id
+		LIMIT 1
+	`
+
+	err = tx.GetContext(ctx, &id, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNoWarehouseAvailable
+		}
+		return
+	}
+	return
+}
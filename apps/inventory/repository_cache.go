@@ -0,0 +1,58 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"context"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// cachedRepository menghapus cache produk berdasarkan SKU setelah stok diubah lewat adjustment.
+// Reservasi tidak mengubah produk, tetapi mengubah stok tersedia di daftar produk publik
+type cachedRepository struct {
+	Repository
+
+	pending  *cache.PendingKeys[*sqlx.Tx]
+	reserved *cache.PendingKeys[*sqlx.Tx]
+}
+
+func newCachedRepository(repo Repository) cachedRepository {
+	return cachedRepository{
+		Repository: repo,
+		pending:    cache.NewPendingKeys[*sqlx.Tx](cache.TAG_PRODUCT_SKU),
+		reserved:   cache.NewPendingKeys[*sqlx.Tx](cache.TAG_PRODUCT_CATALOG),
+	}
+}
+
+func (r cachedRepository) UpdateProductStockWithTx(ctx context.Context, tx *sqlx.Tx, product Product) (err error) {
+	r.pending.Add(tx, product.SKU)
+	return r.Repository.UpdateProductStockWithTx(ctx, tx, product)
+}
+
+func (r cachedRepository) CreateReservationWithTx(ctx context.Context, tx *sqlx.Tx, reservation Reservation) (id int, err error) {
+	r.reserved.Add(tx, strconv.Itoa(reservation.ProductId))
+	return r.Repository.CreateReservationWithTx(ctx, tx, reservation)
+}
+
+func (r cachedRepository) UpdateReservationStatusWithTx(ctx context.Context, tx *sqlx.Tx, reservation Reservation) (err error) {
+	r.reserved.Add(tx, strconv.Itoa(reservation.ProductId))
+	return r.Repository.UpdateReservationStatusWithTx(ctx, tx, reservation)
+}
+
+func (r cachedRepository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	if err = r.Repository.Commit(ctx, tx); err != nil {
+		r.pending.Discard(tx)
+		r.reserved.Discard(tx)
+		return
+	}
+	r.pending.Flush(ctx, tx)
+	r.reserved.Flush(ctx, tx)
+	return
+}
+
+func (r cachedRepository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	r.pending.Discard(tx)
+	r.reserved.Discard(tx)
+	return r.Repository.Rollback(ctx, tx)
+}
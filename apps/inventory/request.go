@@ -0,0 +1,29 @@
+package inventory
+
+type StockAdjustmentRequestPayload struct {
+	WarehouseId  int    `json:"warehouse_id"`
+	Quantity     int    `json:"quantity"`
+	ReasonCode   string `json:"reason_code"`
+	Note         string `json:"note"`
+	UserPublicId string `json:"-"`
+}
+
+type ListStockMovementRequestPayload struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size"`
+}
+
+type UpdateReorderThresholdRequestPayload struct {
+	ReorderThreshold int `json:"reorder_threshold"`
+}
+
+type CreateStockSubscriptionRequestPayload struct {
+	ProductSKU   string `json:"product_sku"`
+	UserPublicId string `json:"-"`
+}
+
+type CreateReservationRequestPayload struct {
+	ProductSKU   string `json:"product_sku"`
+	Amount       int    `json:"amount"`
+	UserPublicId string `json:"-"`
+}
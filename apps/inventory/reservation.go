@@ -0,0 +1,193 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/response"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReservationStatus uint8
+
+const (
+	ReservationStatus_Active    ReservationStatus = 1
+	ReservationStatus_Converted ReservationStatus = 10
+	ReservationStatus_Expired   ReservationStatus = 20
+	ReservationStatus_Released  ReservationStatus = 30
+
+	RESERVATION_ACTIVE    string = "ACTIVE"
+	RESERVATION_CONVERTED string = "CONVERTED"
+	RESERVATION_EXPIRED   string = "EXPIRED"
+	RESERVATION_RELEASED  string = "RELEASED"
+	RESERVATION_UNKNOWN   string = "UNKNOWN"
+)
+
+var (
+	MappingReservationStatus = map[ReservationStatus]string{
+		ReservationStatus_Active:    RESERVATION_ACTIVE,
+		ReservationStatus_Converted: RESERVATION_CONVERTED,
+		ReservationStatus_Expired:   RESERVATION_EXPIRED,
+		ReservationStatus_Released:  RESERVATION_RELEASED,
+	}
+)
+
+const DefaultReservationTTL = 15 * time.Minute
+
+// Reservation menahan stok produk untuk satu pembeli sampai ExpiresAt
+type Reservation struct {
+	Id            int               `db:"id"`
+	PublicId      string            `db:"public_id"`
+	ProductId     int               `db:"product_id"`
+	UserPublicId  string            `db:"user_public_id"`
+	Quantity      int               `db:"quantity"`
+	Status        ReservationStatus `db:"status"`
+	ExpiresAt     time.Time         `db:"expires_at"`
+	TransactionId *int              `db:"transaction_id"`
+	CreatedAt     time.Time         `db:"created_at"`
+	UpdatedAt     time.Time         `db:"updated_at"`
+}
+
+func NewReservationFromRequest(productId int, req CreateReservationRequestPayload, ttl time.Duration) Reservation {
+	if ttl <= 0 {
+		ttl = DefaultReservationTTL
+	}
+
+	now := time.Now()
+	return Reservation{
+		PublicId:     uuid.NewString(),
+		ProductId:    productId,
+		UserPublicId: req.UserPublicId,
+		Quantity:     req.Amount,
+		Status:       ReservationStatus_Active,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func (r Reservation) Validate() (err error) {
+	if r.Quantity <= 0 {
+		return response.ErrAmountInvalid
+	}
+	return
+}
+
+// ValidateAvailable memastikan stok yang belum di-reserve cukup untuk reservation ini
+func (r Reservation) ValidateAvailable(stock int, reserved int) (err error) {
+	if Available(stock, reserved) < r.Quantity {
+		return response.ErrAmountGreaterThanAvailable
+	}
+	return
+}
+
+func (r Reservation) IsActive(now time.Time) bool {
+	return r.Status == ReservationStatus_Active && now.Before(r.ExpiresAt)
+}
+
+func (r Reservation) IsOwnedBy(userPublicId string) bool {
+	return r.UserPublicId == userPublicId
+}
+
+// ValidateConvert dipanggil saat checkout memakai reservation
+func (r Reservation) ValidateConvert(userPublicId string, productId int, quantity int, now time.Time) (err error) {
+	// reservation milik user lain diperlakukan seperti tidak ada
+	if !r.IsOwnedBy(userPublicId) || r.ProductId != productId {
+		return response.ErrReservationNotFound
+	}
+	if err = r.ValidateActive(now); err != nil {
+		return
+	}
+	if r.Quantity != quantity {
+		return response.ErrReservationAmountMismatch
+	}
+	return
+}
+
+func (r Reservation) ValidateActive(now time.Time) (err error) {
+	if r.Status == ReservationStatus_Expired || (r.Status == ReservationStatus_Active && !now.Before(r.ExpiresAt)) {
+		return response.ErrReservationExpired
+	}
+	if r.Status != ReservationStatus_Active {
+		return response.ErrReservationNotActive
+	}
+	return
+}
+
+func (r *Reservation) Convert(transactionId int) {
+	r.Status = ReservationStatus_Converted
+	r.TransactionId = &transactionId
+	r.UpdatedAt = time.Now()
+}
+
+func (r *Reservation) Release() {
+	r.Status = ReservationStatus_Released
+	r.UpdatedAt = time.Now()
+}
+
+func (r Reservation) GetStatus() string {
+	status, ok := MappingReservationStatus[r.Status]
+	if !ok {
+		return RESERVATION_UNKNOWN
+	}
+	return status
+}
+
+// ReservedStock adalah total reservation aktif per produk
+type ReservedStock struct {
+	ProductId int `db:"product_id"`
+	Reserved  int `db:"reserved"`
+}
+
+// Available adalah stok yang masih bisa dibeli atau di-reserve
+func Available(stock int, reserved int) int {
+	available := stock - reserved
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// ReservationMetrics dihitung oleh sweeper dan dibaca oleh endpoint admin
+type ReservationMetrics struct {
+	sweeps          atomic.Int64
+	expiredHolds    atomic.Int64
+	expiredQuantity atomic.Int64
+	failedSweeps    atomic.Int64
+	lastSweepAt     atomic.Int64
+}
+
+type ReservationMetricsSnapshot struct {
+	Sweeps          int64
+	ExpiredHolds    int64
+	ExpiredQuantity int64
+	FailedSweeps    int64
+	LastSweepAt     *time.Time
+}
+
+var reservationMetrics = &ReservationMetrics{}
+
+func (m *ReservationMetrics) RecordSweep(expiredHolds int, expiredQuantity int, at time.Time) {
+	m.sweeps.Add(1)
+	m.expiredHolds.Add(int64(expiredHolds))
+	m.expiredQuantity.Add(int64(expiredQuantity))
+	m.lastSweepAt.Store(at.UnixNano())
+}
+
+func (m *ReservationMetrics) RecordFailedSweep() {
+	m.failedSweeps.Add(1)
+}
+
+func (m *ReservationMetrics) Snapshot() ReservationMetricsSnapshot {
+	snapshot := ReservationMetricsSnapshot{
+		Sweeps:          m.sweeps.Load(),
+		ExpiredHolds:    m.expiredHolds.Load(),
+		ExpiredQuantity: m.expiredQuantity.Load(),
+		FailedSweeps:    m.failedSweeps.Load(),
+	}
+	if lastSweepAt := m.lastSweepAt.Load(); lastSweepAt != 0 {
+		at := time.Unix(0, lastSweepAt)
+		snapshot.LastSweepAt = &at
+	}
+	return snapshot
+}
@@ -0,0 +1,96 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReservation(t *testing.T) {
+	t.Run("default ttl", func(t *testing.T) {
+		reservation := NewReservationFromRequest(1, CreateReservationRequestPayload{Amount: 2, UserPublicId: "user-1"}, 0)
+
+		require.Nil(t, reservation.Validate())
+		require.NotEmpty(t, reservation.PublicId)
+		require.Equal(t, RESERVATION_ACTIVE, reservation.GetStatus())
+		require.WithinDuration(t, time.Now().Add(DefaultReservationTTL), reservation.ExpiresAt, time.Second)
+	})
+	t.Run("amount invalid", func(t *testing.T) {
+		reservation := NewReservationFromRequest(1, CreateReservationRequestPayload{Amount: 0}, time.Minute)
+
+		require.Equal(t, response.ErrAmountInvalid, reservation.Validate())
+	})
+	t.Run("not enough available stock", func(t *testing.T) {
+		reservation := NewReservationFromRequest(1, CreateReservationRequestPayload{Amount: 3}, time.Minute)
+
+		require.Nil(t, reservation.ValidateAvailable(10, 7))
+		require.Equal(t, response.ErrAmountGreaterThanAvailable, reservation.ValidateAvailable(10, 8))
+	})
+}
+
+func TestValidateConvertReservation(t *testing.T) {
+	now := time.Now()
+	reservation := Reservation{
+		Id:           1,
+		ProductId:    5,
+		UserPublicId: "user-1",
+		Quantity:     2,
+		Status:       ReservationStatus_Active,
+		ExpiresAt:    now.Add(time.Minute),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		require.Nil(t, reservation.ValidateConvert("user-1", 5, 2, now))
+	})
+	t.Run("other user", func(t *testing.T) {
+		require.Equal(t, response.ErrReservationNotFound, reservation.ValidateConvert("user-2", 5, 2, now))
+	})
+	t.Run("other product", func(t *testing.T) {
+		require.Equal(t, response.ErrReservationNotFound, reservation.ValidateConvert("user-1", 6, 2, now))
+	})
+	t.Run("amount mismatch", func(t *testing.T) {
+		require.Equal(t, response.ErrReservationAmountMismatch, reservation.ValidateConvert("user-1", 5, 3, now))
+	})
+	t.Run("expired", func(t *testing.T) {
+		require.Equal(t, response.ErrReservationExpired, reservation.ValidateConvert("user-1", 5, 2, now.Add(time.Minute)))
+	})
+	t.Run("already converted", func(t *testing.T) {
+		converted := reservation
+		converted.Convert(10)
+
+		require.Equal(t, RESERVATION_CONVERTED, converted.GetStatus())
+		require.Equal(t, 10, *converted.TransactionId)
+		require.Equal(t, response.ErrReservationNotActive, converted.ValidateConvert("user-1", 5, 2, now))
+	})
+	t.Run("released", func(t *testing.T) {
+		released := reservation
+		released.Release()
+
+		require.False(t, released.IsActive(now))
+		require.Equal(t, response.ErrReservationNotActive, released.ValidateActive(now))
+	})
+}
+
+func TestAvailable(t *testing.T) {
+	require.Equal(t, 7, Available(10, 3))
+	require.Equal(t, 0, Available(3, 5))
+}
+
+func TestReservationMetrics(t *testing.T) {
+	metrics := &ReservationMetrics{}
+	require.Nil(t, metrics.Snapshot().LastSweepAt)
+
+	at := time.Now()
+	metrics.RecordSweep(2, 5, at)
+	metrics.RecordSweep(1, 1, at)
+	metrics.RecordFailedSweep()
+
+	snapshot := metrics.Snapshot()
+	require.Equal(t, int64(2), snapshot.Sweeps)
+	require.Equal(t, int64(3), snapshot.ExpiredHolds)
+	require.Equal(t, int64(6), snapshot.ExpiredQuantity)
+	require.Equal(t, int64(1), snapshot.FailedSweeps)
+	require.Equal(t, at.UnixNano(), snapshot.LastSweepAt.UnixNano())
+}
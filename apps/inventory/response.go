@@ -0,0 +1,214 @@
+package inventory
+
+import "time"
+
+type StockMovementResponse struct {
+	Id            int       `json:"id"`
+	ProductId     int       `json:"product_id"`
+	WarehouseId   int       `json:"warehouse_id"`
+	Type          string    `json:"type"`
+	Quantity      int       `json:"quantity"`
+	StockAfter    int       `json:"stock_after"`
+	ReasonCode    string    `json:"reason_code"`
+	Note          string    `json:"note,omitempty"`
+	ReferenceType *string   `json:"reference_type,omitempty"`
+	ReferenceId   *int      `json:"reference_id,omitempty"`
+	ActorPublicId string    `json:"actor_public_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type StockReconciliationResponse struct {
+	ProductId   int    `json:"product_id"`
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Stock       int    `json:"stock"`
+	LedgerStock int    `json:"ledger_stock"`
+	Difference  int    `json:"difference"`
+
+	WarehouseStock      int `json:"warehouse_stock"`
+	WarehouseDifference int `json:"warehouse_difference"`
+}
+
+type StockReconciliationMetaResponse struct {
+	Checked    int  `json:"checked"`
+	Mismatched int  `json:"mismatched"`
+	Balanced   bool `json:"balanced"`
+}
+
+func (m StockMovement) ToStockMovementResponse() StockMovementResponse {
+	return StockMovementResponse{
+		Id:            m.Id,
+		ProductId:     m.ProductId,
+		WarehouseId:   m.WarehouseId,
+		Type:          m.GetType(),
+		Quantity:      m.Quantity,
+		StockAfter:    m.StockAfter,
+		ReasonCode:    m.ReasonCode,
+		Note:          m.Note,
+		ReferenceType: m.ReferenceType,
+		ReferenceId:   m.ReferenceId,
+		ActorPublicId: m.ActorPublicId,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+func NewStockMovementListResponse(movements []StockMovement) []StockMovementResponse {
+	resp := []StockMovementResponse{}
+	for _, movement := range movements {
+		resp = append(resp, movement.ToStockMovementResponse())
+	}
+	return resp
+}
+
+func NewStockReconciliationListResponse(reconciliations []StockReconciliation) []StockReconciliationResponse {
+	resp := []StockReconciliationResponse{}
+	for _, r := range reconciliations {
+		resp = append(resp, StockReconciliationResponse{
+			ProductId:   r.ProductId,
+			SKU:         r.SKU,
+			Name:        r.Name,
+			Stock:       r.Stock,
+			LedgerStock: r.LedgerStock,
+			Difference:  r.Difference(),
+
+			WarehouseStock:      r.WarehouseStock,
+			WarehouseDifference: r.WarehouseDifference(),
+		})
+	}
+	return resp
+}
+
+type WarehouseResponse struct {
+	Id        int     `json:"id"`
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	City      string  `json:"city"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Priority  int     `json:"priority"`
+	IsActive  bool    `json:"is_active"`
+}
+
+func NewWarehouseListResponse(warehouses []Warehouse) []WarehouseResponse {
+	resp := []WarehouseResponse{}
+	for _, w := range warehouses {
+		resp = append(resp, WarehouseResponse{
+			Id:        w.Id,
+			Code:      w.Code,
+			Name:      w.Name,
+			City:      w.City,
+			Latitude:  w.Latitude,
+			Longitude: w.Longitude,
+			Priority:  w.Priority,
+			IsActive:  w.IsActive,
+		})
+	}
+	return resp
+}
+
+// StockLocationResponse adalah stok produk di satu gudang, dipakai di response produk
+type StockLocationResponse struct {
+	WarehouseId   int    `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	City          string `json:"city"`
+	Stock         int    `json:"stock"`
+}
+
+func NewStockLocationListResponse(stocks []WarehouseStock) []StockLocationResponse {
+	resp := []StockLocationResponse{}
+	for _, stock := range stocks {
+		resp = append(resp, StockLocationResponse{
+			WarehouseId:   stock.WarehouseId,
+			WarehouseCode: stock.WarehouseCode,
+			WarehouseName: stock.WarehouseName,
+			City:          stock.City,
+			Stock:         stock.Stock,
+		})
+	}
+	return resp
+}
+
+type ReservationResponse struct {
+	Id            string    `json:"id"`
+	ProductId     int       `json:"product_id"`
+	Amount        int       `json:"amount"`
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	TransactionId *int      `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (r Reservation) ToReservationResponse() ReservationResponse {
+	return ReservationResponse{
+		Id:            r.PublicId,
+		ProductId:     r.ProductId,
+		Amount:        r.Quantity,
+		Status:        r.GetStatus(),
+		ExpiresAt:     r.ExpiresAt,
+		TransactionId: r.TransactionId,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+type ReservationMetricsResponse struct {
+	Sweeps          int64      `json:"sweeps"`
+	ExpiredHolds    int64      `json:"expired_holds"`
+	ExpiredQuantity int64      `json:"expired_quantity"`
+	FailedSweeps    int64      `json:"failed_sweeps"`
+	LastSweepAt     *time.Time `json:"last_sweep_at"`
+	ActiveHolds     int        `json:"active_holds"`
+	ActiveQuantity  int        `json:"active_quantity"`
+}
+
+type LowStockProductResponse struct {
+	ProductId        int    `json:"product_id"`
+	SKU              string `json:"sku"`
+	Name             string `json:"name"`
+	Stock            int    `json:"stock"`
+	ReorderThreshold int    `json:"reorder_threshold"`
+	Shortage         int    `json:"shortage"`
+	Subscribers      int    `json:"subscribers"`
+}
+
+func NewLowStockProductListResponse(products []LowStockProduct) []LowStockProductResponse {
+	resp := []LowStockProductResponse{}
+	for _, p := range products {
+		resp = append(resp, LowStockProductResponse{
+			ProductId:        p.ProductId,
+			SKU:              p.SKU,
+			Name:             p.Name,
+			Stock:            p.Stock,
+			ReorderThreshold: p.ReorderThreshold,
+			Shortage:         p.Shortage(),
+			Subscribers:      p.Subscribers,
+		})
+	}
+	return resp
+}
+
+type StockSubscriptionResponse struct {
+	Id         string    `json:"id"`
+	ProductId  int       `json:"product_id"`
+	ProductSKU string    `json:"product_sku"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (s StockSubscription) ToStockSubscriptionResponse() StockSubscriptionResponse {
+	return StockSubscriptionResponse{
+		Id:         s.PublicId,
+		ProductId:  s.ProductId,
+		ProductSKU: s.SKU,
+		Name:       s.Name,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+func NewStockSubscriptionListResponse(subscriptions []StockSubscription) []StockSubscriptionResponse {
+	resp := []StockSubscriptionResponse{}
+	for _, s := range subscriptions {
+		resp = append(resp, s.ToStockSubscriptionResponse())
+	}
+	return resp
+}
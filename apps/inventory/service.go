@@ -0,0 +1,427 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"Ecommerce-basic/internal/log"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	InventoryDBRepository
+	StockMovementRepository
+	WarehouseRepository
+	ReservationRepository
+	ProductRepository
+	AlertRepository
+}
+
+type InventoryDBRepository interface {
+	Begin(ctx context.Context) (tx *sqlx.Tx, err error)
+	Rollback(ctx context.Context, tx *sqlx.Tx) (err error)
+	Commit(ctx context.Context, tx *sqlx.Tx) (err error)
+}
+
+type StockMovementRepository interface {
+	RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement StockMovement) (err error)
+	GetStockMovementsByProductId(ctx context.Context, productId int, page pagination.Request) (movements []StockMovement, err error)
+	GetStockReconciliations(ctx context.Context) (reconciliations []StockReconciliation, err error)
+}
+
+type WarehouseRepository interface {
+	GetWarehouses(ctx context.Context) (warehouses []Warehouse, err error)
+	GetWarehouseById(ctx context.Context, id int) (warehouse Warehouse, err error)
+}
+
+type ReservationRepository interface {
+	CreateReservationWithTx(ctx context.Context, tx *sqlx.Tx, reservation Reservation) (id int, err error)
+	GetReservationByPublicId(ctx context.Context, publicId string) (reservation Reservation, err error)
+	GetReservationByPublicIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, publicId string) (reservation Reservation, err error)
+	UpdateReservationStatusWithTx(ctx context.Context, tx *sqlx.Tx, reservation Reservation) (err error)
+	GetReservedStockWithTx(ctx context.Context, tx *sqlx.Tx, productId int, excludeReservationId int) (reserved int, err error)
+	ExpireReservations(ctx context.Context, now time.Time) (expiredHolds int, expiredQuantity int, err error)
+	GetActiveReservationTotals(ctx context.Context) (activeHolds int, activeQuantity int, err error)
+}
+
+type AlertRepository interface {
+	UpdateReorderThreshold(ctx context.Context, productId int, threshold int) (err error)
+	GetLowStockProducts(ctx context.Context) (products []LowStockProduct, err error)
+	CreateStockSubscription(ctx context.Context, subscription StockSubscription) (created StockSubscription, err error)
+	GetStockSubscriptionsByUserPublicId(ctx context.Context, userPublicId string) (subscriptions []StockSubscription, err error)
+	DeleteStockSubscription(ctx context.Context, publicId string, userPublicId string) (err error)
+	GetPendingStockAlertsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, limit int) (alerts []StockAlert, err error)
+	GetPendingStockSubscriptionsWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (subscriptions []StockSubscription, err error)
+	MarkStockSubscriptionNotifiedWithTx(ctx context.Context, tx *sqlx.Tx, id int, notifiedAt time.Time) (err error)
+	MarkStockAlertDispatchedWithTx(ctx context.Context, tx *sqlx.Tx, id int, dispatchedAt time.Time) (err error)
+}
+
+type ProductRepository interface {
+	GetProductById(ctx context.Context, productId int) (product Product, err error)
+	GetProductBySku(ctx context.Context, sku string) (product Product, err error)
+	GetProductBySkuForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, sku string) (product Product, err error)
+	GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error)
+	UpdateProductStockWithTx(ctx context.Context, tx *sqlx.Tx, product Product) (err error)
+}
+
+// jumlah alert yang dikirim dalam satu kali dispatch
+const alertDispatchBatchSize = 100
+
+type service struct {
+	repo           Repository
+	reservationTTL time.Duration
+	emitter        AlertEmitter
+}
+
+func newService(repo Repository) service {
+	return service{
+		repo:           repo,
+		reservationTTL: config.Cfg.App.Inventory.ReservationTTL,
+		emitter:        LogAlertEmitter{},
+	}
+}
+
+// AdjustStock mengubah stok produk secara manual dan mencatatnya di ledger
+func (s service) AdjustStock(ctx context.Context, productId int, req StockAdjustmentRequestPayload) (movement StockMovement, err error) {
+	movement = NewStockMovementFromAdjustmentRequest(productId, req)
+	if err = movement.ValidateAdjustment(); err != nil {
+		return
+	}
+
+	if movement.WarehouseId != 0 {
+		if _, err = s.repo.GetWarehouseById(ctx, movement.WarehouseId); err != nil {
+			return
+		}
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	product, err := s.repo.GetProductByIdForUpdateWithTx(ctx, tx, productId)
+	if err != nil {
+		return
+	}
+
+	if err = movement.Apply(product.Stock); err != nil {
+		return
+	}
+
+	product.Stock = movement.StockAfter
+	if err = s.repo.UpdateProductStockWithTx(ctx, tx, product); err != nil {
+		return
+	}
+
+	if err = s.repo.RecordStockMovementWithTx(ctx, tx, movement); err != nil {
+		return
+	}
+
+	err = s.repo.Commit(ctx, tx)
+	return
+}
+
+func (s service) StockMovements(ctx context.Context, productId int, req ListStockMovementRequestPayload) (movements []StockMovement, meta pagination.Meta, err error) {
+	secret := config.Cfg.App.Encryption.CursorSecret
+
+	page, err := pagination.NewRequest(req.Cursor, req.Size, "", secret)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.repo.GetProductById(ctx, productId); err != nil {
+		return
+	}
+
+	movements, err = s.repo.GetStockMovementsByProductId(ctx, productId, page)
+	if err != nil {
+		return
+	}
+
+	movements, meta = pagination.Paginate(movements, page, StockMovement.CursorKey, secret)
+	if len(movements) == 0 {
+		movements = []StockMovement{}
+	}
+	return
+}
+
+// Reconcile mengembalikan produk yang stoknya tidak sama dengan jumlah ledger
+func (s service) Reconcile(ctx context.Context) (mismatches []StockReconciliation, checked int, err error) {
+	reconciliations, err := s.repo.GetStockReconciliations(ctx)
+	if err != nil {
+		return
+	}
+
+	mismatches = []StockReconciliation{}
+	for _, r := range reconciliations {
+		if r.IsBalanced() {
+			continue
+		}
+		log.Log.Errorf(ctx, "[Reconcile] product %d stock %d does not match ledger %d or warehouse stock %d", r.ProductId, r.Stock, r.LedgerStock, r.WarehouseStock)
+		mismatches = append(mismatches, r)
+	}
+	return mismatches, len(reconciliations), nil
+}
+
+func (s service) Warehouses(ctx context.Context) (warehouses []Warehouse, err error) {
+	return s.repo.GetWarehouses(ctx)
+}
+
+// Reserve menahan stok untuk pembeli selama reservationTTL
+func (s service) Reserve(ctx context.Context, req CreateReservationRequestPayload) (reservation Reservation, err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	// lock baris produk supaya dua reservation tidak mengambil stok yang sama
+	product, err := s.repo.GetProductBySkuForUpdateWithTx(ctx, tx, req.ProductSKU)
+	if err != nil {
+		return
+	}
+	if err = product.ValidateSellable(); err != nil {
+		return
+	}
+
+	reservation = NewReservationFromRequest(product.Id, req, s.reservationTTL)
+	if err = reservation.Validate(); err != nil {
+		return
+	}
+
+	reserved, err := s.repo.GetReservedStockWithTx(ctx, tx, product.Id, 0)
+	if err != nil {
+		return
+	}
+	if err = reservation.ValidateAvailable(product.Stock, reserved); err != nil {
+		return
+	}
+
+	if reservation.Id, err = s.repo.CreateReservationWithTx(ctx, tx, reservation); err != nil {
+		return
+	}
+
+	err = s.repo.Commit(ctx, tx)
+	return
+}
+
+func (s service) Reservation(ctx context.Context, publicId string, userPublicId string) (reservation Reservation, err error) {
+	reservation, err = s.repo.GetReservationByPublicId(ctx, publicId)
+	if err != nil {
+		return
+	}
+	if !reservation.IsOwnedBy(userPublicId) {
+		return Reservation{}, response.ErrReservationNotFound
+	}
+	return
+}
+
+// ReleaseReservation dipanggil ketika pembeli membatalkan checkout sebelum hold habis
+func (s service) ReleaseReservation(ctx context.Context, publicId string, userPublicId string) (err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	reservation, err := s.repo.GetReservationByPublicIdForUpdateWithTx(ctx, tx, publicId)
+	if err != nil {
+		return
+	}
+	if !reservation.IsOwnedBy(userPublicId) {
+		return response.ErrReservationNotFound
+	}
+	if err = reservation.ValidateActive(time.Now()); err != nil {
+		return
+	}
+
+	reservation.Release()
+	if err = s.repo.UpdateReservationStatusWithTx(ctx, tx, reservation); err != nil {
+		return
+	}
+
+	return s.repo.Commit(ctx, tx)
+}
+
+// SweepExpiredReservations mengubah hold yang lewat waktu menjadi EXPIRED dan mencatat metrics
+// Cache daftar produk tidak dihapus di sini, stok tersedia dihitung dari expires_at saat dibaca
+// sehingga response yang sudah di-cache hanya menampilkan stok tersedia lebih kecil paling lama selama cache_ttl
+func (s service) SweepExpiredReservations(ctx context.Context) (expiredHolds int, expiredQuantity int, err error) {
+	now := time.Now()
+
+	expiredHolds, expiredQuantity, err = s.repo.ExpireReservations(ctx, now)
+	if err != nil {
+		reservationMetrics.RecordFailedSweep()
+		log.Log.Errorf(ctx, "[SweepExpiredReservations] with error detail %v", err.Error())
+		return
+	}
+
+	reservationMetrics.RecordSweep(expiredHolds, expiredQuantity, now)
+	if expiredHolds > 0 {
+		log.Log.Infof(ctx, "[SweepExpiredReservations] expired %d holds with %d items", expiredHolds, expiredQuantity)
+	}
+	return
+}
+
+// runReservationSweeper berjalan sampai ctx dibatalkan
+func (s service) runReservationSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SweepExpiredReservations(ctx)
+		}
+	}
+}
+
+func (s service) ReservationMetrics(ctx context.Context) (snapshot ReservationMetricsSnapshot, activeHolds int, activeQuantity int, err error) {
+	activeHolds, activeQuantity, err = s.repo.GetActiveReservationTotals(ctx)
+	if err != nil {
+		return
+	}
+	return reservationMetrics.Snapshot(), activeHolds, activeQuantity, nil
+}
+
+func (s service) UpdateReorderThreshold(ctx context.Context, productId int, req UpdateReorderThresholdRequestPayload) (err error) {
+	if err = ValidateReorderThreshold(req.ReorderThreshold); err != nil {
+		return
+	}
+	return s.repo.UpdateReorderThreshold(ctx, productId, req.ReorderThreshold)
+}
+
+func (s service) LowStockProducts(ctx context.Context) (products []LowStockProduct, err error) {
+	products, err = s.repo.GetLowStockProducts(ctx)
+	if err != nil {
+		return
+	}
+	if len(products) == 0 {
+		products = []LowStockProduct{}
+	}
+	return
+}
+
+// SubscribeBackInStock mendaftarkan pembeli untuk diberi tahu saat produk yang habis tersedia lagi
+func (s service) SubscribeBackInStock(ctx context.Context, req CreateStockSubscriptionRequestPayload) (subscription StockSubscription, err error) {
+	product, err := s.repo.GetProductBySku(ctx, req.ProductSKU)
+	if err != nil {
+		return
+	}
+
+	if err = ValidateSubscribe(product); err != nil {
+		return
+	}
+
+	return s.repo.CreateStockSubscription(ctx, NewStockSubscription(product, req.UserPublicId))
+}
+
+func (s service) StockSubscriptions(ctx context.Context, userPublicId string) (subscriptions []StockSubscription, err error) {
+	subscriptions, err = s.repo.GetStockSubscriptionsByUserPublicId(ctx, userPublicId)
+	if err != nil {
+		return
+	}
+	if len(subscriptions) == 0 {
+		subscriptions = []StockSubscription{}
+	}
+	return
+}
+
+func (s service) UnsubscribeBackInStock(ctx context.Context, publicId string, userPublicId string) (err error) {
+	return s.repo.DeleteStockSubscription(ctx, publicId, userPublicId)
+}
+
+// DispatchStockAlerts mengirim alert yang sudah di-commit lewat emitter.
+// Alert yang gagal dikirim tetap pending dan dicoba lagi pada dispatch berikutnya.
+func (s service) DispatchStockAlerts(ctx context.Context) (dispatched int, err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	alerts, err := s.repo.GetPendingStockAlertsForUpdateWithTx(ctx, tx, alertDispatchBatchSize)
+	if err != nil {
+		log.Log.Errorf(ctx, "[DispatchStockAlerts, GetPendingStockAlerts] with error detail %v", err.Error())
+		return
+	}
+
+	for _, alert := range alerts {
+		sent, err := s.dispatchStockAlertWithTx(ctx, tx, alert)
+		if err != nil {
+			log.Log.Errorf(ctx, "[DispatchStockAlerts, dispatchStockAlert] alert %d with error detail %v", alert.Id, err.Error())
+			return dispatched, err
+		}
+		if !sent {
+			continue
+		}
+
+		if err = s.repo.MarkStockAlertDispatchedWithTx(ctx, tx, alert.Id, time.Now()); err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+
+	err = s.repo.Commit(ctx, tx)
+	return
+}
+
+// dispatchStockAlertWithTx mengembalikan sent false jika emitter gagal, error hanya untuk kegagalan database
+func (s service) dispatchStockAlertWithTx(ctx context.Context, tx *sqlx.Tx, alert StockAlert) (sent bool, err error) {
+	switch alert.Type {
+	case AlertType_LowStock:
+		if emitErr := s.emitter.EmitLowStock(ctx, alert); emitErr != nil {
+			log.Log.Errorf(ctx, "[dispatchStockAlert, EmitLowStock] alert %d with error detail %v", alert.Id, emitErr.Error())
+			return false, nil
+		}
+		return true, nil
+
+	case AlertType_BackInStock:
+		// stok sudah habis lagi, subscription tetap menunggu restock berikutnya
+		if alert.IsStale() {
+			return true, nil
+		}
+
+		subscriptions, err := s.repo.GetPendingStockSubscriptionsWithTx(ctx, tx, alert.ProductId)
+		if err != nil {
+			return false, err
+		}
+
+		sent = true
+		for _, subscription := range subscriptions {
+			if emitErr := s.emitter.EmitBackInStock(ctx, alert, subscription); emitErr != nil {
+				log.Log.Errorf(ctx, "[dispatchStockAlert, EmitBackInStock] subscription %d with error detail %v", subscription.Id, emitErr.Error())
+				sent = false
+				continue
+			}
+			if err = s.repo.MarkStockSubscriptionNotifiedWithTx(ctx, tx, subscription.Id, time.Now()); err != nil {
+				return false, err
+			}
+		}
+		return sent, nil
+	}
+
+	// tipe yang tidak dikenal tidak akan pernah bisa dikirim
+	return true, nil
+}
+
+// runStockAlertDispatcher berjalan sampai ctx dibatalkan
+func (s service) runStockAlertDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.DispatchStockAlerts(ctx)
+		}
+	}
+}
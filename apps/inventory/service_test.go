@@ -0,0 +1,127 @@
+package inventory
+
+import (
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo)
+}
+
+func TestAdjustStock(t *testing.T) {
+	productId := 1
+
+	t.Run("success", func(t *testing.T) {
+		before, err := svc.repo.GetProductById(context.Background(), productId)
+		require.Nil(t, err)
+
+		req := StockAdjustmentRequestPayload{Quantity: 5, ReasonCode: REASON_RESTOCK, Note: "supplier A"}
+		movement, err := svc.AdjustStock(context.Background(), productId, req)
+		require.Nil(t, err)
+		require.Equal(t, before.Stock+5, movement.StockAfter)
+
+		movements, _, err := svc.StockMovements(context.Background(), productId, ListStockMovementRequestPayload{Size: 1})
+		require.Nil(t, err)
+		require.Len(t, movements, 1)
+		require.Equal(t, REASON_RESTOCK, movements[0].ReasonCode)
+		require.Equal(t, 5, movements[0].Quantity)
+	})
+	t.Run("stock negative", func(t *testing.T) {
+		before, err := svc.repo.GetProductById(context.Background(), productId)
+		require.Nil(t, err)
+
+		req := StockAdjustmentRequestPayload{Quantity: -(before.Stock + 1), ReasonCode: REASON_LOST}
+		_, err = svc.AdjustStock(context.Background(), productId, req)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrStockNegative, err)
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	_, checked, err := svc.Reconcile(context.Background())
+	require.Nil(t, err)
+	require.NotZero(t, checked)
+}
+
+func TestReservation(t *testing.T) {
+	product, err := svc.repo.GetProductById(context.Background(), 1)
+	require.Nil(t, err)
+
+	req := CreateReservationRequestPayload{ProductSKU: product.SKU, Amount: 1, UserPublicId: "reservation-test"}
+
+	t.Run("reserve and release", func(t *testing.T) {
+		reservation, err := svc.Reserve(context.Background(), req)
+		require.Nil(t, err)
+		require.Equal(t, RESERVATION_ACTIVE, reservation.GetStatus())
+
+		err = svc.ReleaseReservation(context.Background(), reservation.PublicId, req.UserPublicId)
+		require.Nil(t, err)
+
+		released, err := svc.Reservation(context.Background(), reservation.PublicId, req.UserPublicId)
+		require.Nil(t, err)
+		require.Equal(t, RESERVATION_RELEASED, released.GetStatus())
+	})
+	t.Run("other user", func(t *testing.T) {
+		reservation, err := svc.Reserve(context.Background(), req)
+		require.Nil(t, err)
+
+		err = svc.ReleaseReservation(context.Background(), reservation.PublicId, "someone-else")
+		require.Equal(t, response.ErrReservationNotFound, err)
+	})
+	t.Run("sweep", func(t *testing.T) {
+		_, _, err := svc.SweepExpiredReservations(context.Background())
+		require.Nil(t, err)
+	})
+}
+
+func TestStockAlerts(t *testing.T) {
+	t.Run("reorder threshold invalid", func(t *testing.T) {
+		err := svc.UpdateReorderThreshold(context.Background(), 1, UpdateReorderThresholdRequestPayload{ReorderThreshold: -1})
+		require.Equal(t, response.ErrReorderThresholdInvalid, err)
+	})
+	t.Run("low stock report", func(t *testing.T) {
+		product, err := svc.repo.GetProductById(context.Background(), 1)
+		require.Nil(t, err)
+
+		err = svc.UpdateReorderThreshold(context.Background(), 1, UpdateReorderThresholdRequestPayload{ReorderThreshold: product.Stock})
+		require.Nil(t, err)
+
+		products, err := svc.LowStockProducts(context.Background())
+		require.Nil(t, err)
+		require.NotEmpty(t, products)
+	})
+	t.Run("subscribe product in stock", func(t *testing.T) {
+		product, err := svc.repo.GetProductById(context.Background(), 1)
+		require.Nil(t, err)
+		if product.Stock == 0 {
+			t.Skip("product is out of stock")
+		}
+
+		_, err = svc.SubscribeBackInStock(context.Background(), CreateStockSubscriptionRequestPayload{ProductSKU: product.SKU, UserPublicId: "subscription-test"})
+		require.Equal(t, response.ErrProductInStock, err)
+	})
+	t.Run("dispatch", func(t *testing.T) {
+		_, err := svc.DispatchStockAlerts(context.Background())
+		require.Nil(t, err)
+	})
+}
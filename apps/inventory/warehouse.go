@@ -0,0 +1,233 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/response"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Warehouse struct {
+	Id        int       `db:"id"`
+	Code      string    `db:"code"`
+	Name      string    `db:"name"`
+	City      string    `db:"city"`
+	Latitude  float64   `db:"latitude"`
+	Longitude float64   `db:"longitude"`
+	Priority  int       `db:"priority"`
+	IsActive  bool      `db:"is_active"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// WarehouseStock adalah stok satu produk di satu gudang beserta data gudangnya
+type WarehouseStock struct {
+	WarehouseId   int     `db:"warehouse_id"`
+	ProductId     int     `db:"product_id"`
+	Stock         int     `db:"stock"`
+	WarehouseCode string  `db:"warehouse_code"`
+	WarehouseName string  `db:"warehouse_name"`
+	City          string  `db:"city"`
+	Latitude      float64 `db:"latitude"`
+	Longitude     float64 `db:"longitude"`
+	Priority      int     `db:"priority"`
+}
+
+// Location tujuan pengiriman, koordinat opsional
+type Location struct {
+	City      string
+	Latitude  *float64
+	Longitude *float64
+}
+
+func (l Location) HasCoordinate() bool {
+	return l.Latitude != nil && l.Longitude != nil
+}
+
+type AllocationRequest struct {
+	ProductId   int
+	Amount      int
+	Destination Location
+}
+
+const (
+	ALLOCATION_NEAREST    string = "nearest"
+	ALLOCATION_MOST_STOCK string = "most_stock"
+	ALLOCATION_PRIORITY   string = "priority"
+)
+
+// AllocationStrategy memilih satu gudang yang memenuhi seluruh order
+type AllocationStrategy interface {
+	Name() string
+	Allocate(req AllocationRequest, stocks []WarehouseStock) (allocated WarehouseStock, err error)
+}
+
+var allocationStrategies = map[string]AllocationStrategy{
+	ALLOCATION_NEAREST:    NearestWarehouse{},
+	ALLOCATION_MOST_STOCK: MostStock{},
+	ALLOCATION_PRIORITY:   FixedPriority{},
+}
+
+// NewAllocationStrategy mengembalikan strategy berdasarkan nama di config, default nearest
+func NewAllocationStrategy(name string) (strategy AllocationStrategy, err error) {
+	if name == "" {
+		name = ALLOCATION_NEAREST
+	}
+
+	strategy, ok := allocationStrategies[name]
+	if !ok {
+		return nil, response.ErrAllocationStrategyInvalid
+	}
+	return
+}
+
+// RegisterAllocationStrategy menambah strategy baru, misalnya dari modul lain
+func RegisterAllocationStrategy(strategy AllocationStrategy) {
+	allocationStrategies[strategy.Name()] = strategy
+}
+
+// candidates hanya gudang yang stoknya cukup, diurutkan berdasarkan prioritas lalu id
+func candidates(req AllocationRequest, stocks []WarehouseStock) (result []WarehouseStock, err error) {
+	for _, stock := range stocks {
+		if stock.Stock >= req.Amount {
+			result = append(result, stock)
+		}
+	}
+	if len(result) == 0 {
+		return nil, response.ErrNoWarehouseAvailable
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority < result[j].Priority
+		}
+		return result[i].WarehouseId < result[j].WarehouseId
+	})
+	return
+}
+
+// NearestWarehouse memilih gudang terdekat dari alamat pengiriman.
+// Tanpa koordinat, gudang di kota yang sama dianggap paling dekat, selebihnya mengikuti prioritas.
+type NearestWarehouse struct{}
+
+func (NearestWarehouse) Name() string {
+	return ALLOCATION_NEAREST
+}
+
+func (NearestWarehouse) Allocate(req AllocationRequest, stocks []WarehouseStock) (allocated WarehouseStock, err error) {
+	result, err := candidates(req, stocks)
+	if err != nil {
+		return
+	}
+
+	distance := func(stock WarehouseStock) float64 {
+		if req.Destination.HasCoordinate() {
+			return haversine(*req.Destination.Latitude, *req.Destination.Longitude, stock.Latitude, stock.Longitude)
+		}
+		if req.Destination.City != "" && strings.EqualFold(req.Destination.City, stock.City) {
+			return 0
+		}
+		return math.Inf(1)
+	}
+
+	allocated = result[0]
+	nearest := distance(allocated)
+	for _, stock := range result[1:] {
+		if d := distance(stock); d < nearest {
+			allocated, nearest = stock, d
+		}
+	}
+	return
+}
+
+// MostStock memilih gudang dengan stok terbanyak agar stok antar gudang tetap seimbang
+type MostStock struct{}
+
+func (MostStock) Name() string {
+	return ALLOCATION_MOST_STOCK
+}
+
+func (MostStock) Allocate(req AllocationRequest, stocks []WarehouseStock) (allocated WarehouseStock, err error) {
+	result, err := candidates(req, stocks)
+	if err != nil {
+		return
+	}
+
+	allocated = result[0]
+	for _, stock := range result[1:] {
+		if stock.Stock > allocated.Stock {
+			allocated = stock
+		}
+	}
+	return
+}
+
+// FixedPriority selalu memilih gudang dengan prioritas tertinggi (angka terkecil) yang stoknya cukup
+type FixedPriority struct{}
+
+func (FixedPriority) Name() string {
+	return ALLOCATION_PRIORITY
+}
+
+func (FixedPriority) Allocate(req AllocationRequest, stocks []WarehouseStock) (allocated WarehouseStock, err error) {
+	result, err := candidates(req, stocks)
+	if err != nil {
+		return
+	}
+	return result[0], nil
+}
+
+const earthRadiusKm = 6371
+
+// SpreadStockDecrease membagi pengurangan stok tanpa gudang (contoh: update produk atau import) ke gudang
+// berdasarkan prioritas, sehingga pengurangan tidak gagal hanya karena stok gudang utama kurang.
+// stock_after setiap bagian adalah total stok produk setelah bagian tersebut
+func SpreadStockDecrease(movement StockMovement, stocks []WarehouseStock) (movements []StockMovement, err error) {
+	sorted := append([]WarehouseStock(nil), stocks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].WarehouseId < sorted[j].WarehouseId
+	})
+
+	remaining := -movement.Quantity
+	stockAfter := movement.StockAfter + remaining
+	for _, stock := range sorted {
+		if remaining == 0 {
+			break
+		}
+		if stock.Stock <= 0 {
+			continue
+		}
+
+		taken := min(stock.Stock, remaining)
+		remaining -= taken
+		stockAfter -= taken
+
+		part := movement
+		part.WarehouseId = stock.WarehouseId
+		part.Quantity = -taken
+		part.StockAfter = stockAfter
+		movements = append(movements, part)
+	}
+
+	if remaining > 0 {
+		return nil, response.ErrStockNegative
+	}
+	return
+}
+
+// haversine menghitung jarak dua koordinat dalam kilometer
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadian := func(degree float64) float64 {
+		return degree * math.Pi / 180
+	}
+
+	dLat := toRadian(lat2 - lat1)
+	dLon := toRadian(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadian(lat1))*math.Cos(toRadian(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
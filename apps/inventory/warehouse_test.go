@@ -0,0 +1,116 @@
+package inventory
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var warehouseStocks = []WarehouseStock{
+	{WarehouseId: 1, WarehouseCode: "JKT", City: "Jakarta", Latitude: -6.2088, Longitude: 106.8456, Priority: 1, Stock: 5},
+	{WarehouseId: 2, WarehouseCode: "SBY", City: "Surabaya", Latitude: -7.2575, Longitude: 112.7521, Priority: 2, Stock: 20},
+}
+
+func TestNearestWarehouse(t *testing.T) {
+	t.Run("by coordinate", func(t *testing.T) {
+		// Malang lebih dekat ke Surabaya
+		lat, lon := -7.9666, 112.6326
+		req := AllocationRequest{Amount: 2, Destination: Location{Latitude: &lat, Longitude: &lon}}
+
+		allocated, err := NearestWarehouse{}.Allocate(req, warehouseStocks)
+		require.Nil(t, err)
+		require.Equal(t, "SBY", allocated.WarehouseCode)
+	})
+	t.Run("by city", func(t *testing.T) {
+		req := AllocationRequest{Amount: 2, Destination: Location{City: "surabaya"}}
+
+		allocated, err := NearestWarehouse{}.Allocate(req, warehouseStocks)
+		require.Nil(t, err)
+		require.Equal(t, "SBY", allocated.WarehouseCode)
+	})
+	t.Run("unknown destination falls back to priority", func(t *testing.T) {
+		req := AllocationRequest{Amount: 2, Destination: Location{City: "Medan"}}
+
+		allocated, err := NearestWarehouse{}.Allocate(req, warehouseStocks)
+		require.Nil(t, err)
+		require.Equal(t, "JKT", allocated.WarehouseCode)
+	})
+	t.Run("nearest without enough stock is skipped", func(t *testing.T) {
+		req := AllocationRequest{Amount: 10, Destination: Location{City: "Jakarta"}}
+
+		allocated, err := NearestWarehouse{}.Allocate(req, warehouseStocks)
+		require.Nil(t, err)
+		require.Equal(t, "SBY", allocated.WarehouseCode)
+	})
+}
+
+func TestMostStock(t *testing.T) {
+	allocated, err := MostStock{}.Allocate(AllocationRequest{Amount: 1}, warehouseStocks)
+	require.Nil(t, err)
+	require.Equal(t, "SBY", allocated.WarehouseCode)
+}
+
+func TestFixedPriority(t *testing.T) {
+	allocated, err := FixedPriority{}.Allocate(AllocationRequest{Amount: 1}, warehouseStocks)
+	require.Nil(t, err)
+	require.Equal(t, "JKT", allocated.WarehouseCode)
+}
+
+func TestAllocate_NoWarehouseAvailable(t *testing.T) {
+	// total stok cukup, tetapi order tidak dipecah ke beberapa gudang
+	_, err := FixedPriority{}.Allocate(AllocationRequest{Amount: 21}, warehouseStocks)
+	require.NotNil(t, err)
+	require.Equal(t, response.ErrNoWarehouseAvailable, err)
+}
+
+func TestNewAllocationStrategy(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		strategy, err := NewAllocationStrategy("")
+		require.Nil(t, err)
+		require.Equal(t, ALLOCATION_NEAREST, strategy.Name())
+	})
+	t.Run("most stock", func(t *testing.T) {
+		strategy, err := NewAllocationStrategy(ALLOCATION_MOST_STOCK)
+		require.Nil(t, err)
+		require.Equal(t, ALLOCATION_MOST_STOCK, strategy.Name())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewAllocationStrategy("random")
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrAllocationStrategyInvalid, err)
+	})
+}
+
+func TestSpreadStockDecrease(t *testing.T) {
+	// total stok 25, turun 8 menjadi 17
+	movement := NewStockMovement(1, MovementType_Adjustment, -8, 17, REASON_PRODUCT_UPDATE)
+
+	t.Run("primary warehouse first", func(t *testing.T) {
+		movements, err := SpreadStockDecrease(movement, warehouseStocks)
+		require.Nil(t, err)
+		require.Len(t, movements, 2)
+
+		require.Equal(t, 1, movements[0].WarehouseId)
+		require.Equal(t, -5, movements[0].Quantity)
+		require.Equal(t, 20, movements[0].StockAfter)
+
+		require.Equal(t, 2, movements[1].WarehouseId)
+		require.Equal(t, -3, movements[1].Quantity)
+		require.Equal(t, 17, movements[1].StockAfter)
+	})
+	t.Run("fits in primary warehouse", func(t *testing.T) {
+		small := NewStockMovement(1, MovementType_Adjustment, -2, 23, REASON_PRODUCT_UPDATE)
+
+		movements, err := SpreadStockDecrease(small, warehouseStocks)
+		require.Nil(t, err)
+		require.Len(t, movements, 1)
+		require.Equal(t, 1, movements[0].WarehouseId)
+	})
+	t.Run("not enough stock in all warehouses", func(t *testing.T) {
+		large := NewStockMovement(1, MovementType_Adjustment, -26, -1, REASON_PRODUCT_UPDATE)
+
+		_, err := SpreadStockDecrease(large, warehouseStocks)
+		require.Equal(t, response.ErrStockNegative, err)
+	})
+}
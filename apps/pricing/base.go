@@ -0,0 +1,42 @@
+package pricing
+
+import (
+	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/internal"
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// interval default scheduler jika tidak diatur di config
+const defaultScheduleInterval = time.Minute
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	repo := newCachedRepository(newRepository(db))
+	svc := newService(repo)
+	handler := newHandler(svc)
+
+	priceRoute := router.Group("/products")
+	{
+		priceRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		priceRoute.GET("/:id/price-history", handler.GetPriceHistory)
+		priceRoute.POST("/:id/price-schedules", handler.CreatePriceSchedule)
+		priceRoute.GET("/:id/price-schedules", handler.GetPriceSchedules)
+		priceRoute.DELETE("/:id/price-schedules/:scheduleId", handler.CancelPriceSchedule)
+	}
+}
+
+// StartPriceScheduler menjalankan schedule harga dan sale di background sampai ctx dibatalkan
+func StartPriceScheduler(ctx context.Context, db *sqlx.DB) {
+	interval := config.Cfg.App.Pricing.ScheduleInterval
+	if interval <= 0 {
+		interval = defaultScheduleInterval
+	}
+
+	svc := newService(newCachedRepository(newRepository(db)))
+	go svc.runScheduler(ctx, interval)
+}
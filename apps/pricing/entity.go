@@ -0,0 +1,248 @@
+package pricing
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"time"
+)
+
+// reason perubahan harga di riwayat harga
+const (
+	REASON_INITIAL_PRICE string = "INITIAL_PRICE"
+	REASON_MANUAL        string = "MANUAL"
+	REASON_IMPORT        string = "IMPORT"
+	REASON_SCHEDULED     string = "SCHEDULED"
+	REASON_SALE_START    string = "SALE_START"
+	REASON_SALE_END      string = "SALE_END"
+)
+
+// PriceChange adalah satu baris riwayat harga produk
+type PriceChange struct {
+	Id            int       `db:"id"`
+	ProductId     int       `db:"product_id"`
+	OldPrice      int       `db:"old_price"`
+	NewPrice      int       `db:"new_price"`
+	Reason        string    `db:"reason"`
+	ScheduleId    *int      `db:"schedule_id"`
+	ActorPublicId string    `db:"actor_public_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func NewPriceChange(productId int, oldPrice int, newPrice int, reason string) PriceChange {
+	return PriceChange{
+		ProductId: productId,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (c *PriceChange) WithActor(actorPublicId string) *PriceChange {
+	c.ActorPublicId = actorPublicId
+	return c
+}
+
+func (c *PriceChange) WithSchedule(scheduleId int) *PriceChange {
+	c.ScheduleId = &scheduleId
+	return c
+}
+
+// EndsSale true untuk perubahan harga langsung oleh admin atau import,
+// harga baru menggantikan harga sale yang sedang berjalan
+func (c PriceChange) EndsSale() bool {
+	return c.Reason == REASON_MANUAL || c.Reason == REASON_IMPORT
+}
+
+func (c PriceChange) CursorKey() pagination.Key {
+	return pagination.Key{Id: c.Id}
+}
+
+type ScheduleType uint8
+
+const (
+	ScheduleType_Price ScheduleType = 1
+	ScheduleType_Sale  ScheduleType = 2
+
+	SCHEDULE_PRICE        string = "price"
+	SCHEDULE_SALE         string = "sale"
+	SCHEDULE_TYPE_UNKNOWN string = "unknown"
+)
+
+var (
+	MappingScheduleType = map[ScheduleType]string{
+		ScheduleType_Price: SCHEDULE_PRICE,
+		ScheduleType_Sale:  SCHEDULE_SALE,
+	}
+)
+
+type ScheduleStatus uint8
+
+const (
+	ScheduleStatus_Pending   ScheduleStatus = 1
+	ScheduleStatus_Active    ScheduleStatus = 10
+	ScheduleStatus_Completed ScheduleStatus = 20
+	ScheduleStatus_Cancelled ScheduleStatus = 30
+
+	SCHEDULE_PENDING        string = "PENDING"
+	SCHEDULE_ACTIVE         string = "ACTIVE"
+	SCHEDULE_COMPLETED      string = "COMPLETED"
+	SCHEDULE_CANCELLED      string = "CANCELLED"
+	SCHEDULE_STATUS_UNKNOWN string = "UNKNOWN"
+)
+
+var (
+	MappingScheduleStatus = map[ScheduleStatus]string{
+		ScheduleStatus_Pending:   SCHEDULE_PENDING,
+		ScheduleStatus_Active:    SCHEDULE_ACTIVE,
+		ScheduleStatus_Completed: SCHEDULE_COMPLETED,
+		ScheduleStatus_Cancelled: SCHEDULE_CANCELLED,
+	}
+)
+
+// PriceSchedule adalah perubahan harga terjadwal.
+// Type price mengganti harga permanen di StartsAt, type sale memasang harga sale
+// di StartsAt lalu mengembalikan harga asli di EndsAt.
+type PriceSchedule struct {
+	Id            int            `db:"id"`
+	ProductId     int            `db:"product_id"`
+	Type          ScheduleType   `db:"type"`
+	Price         int            `db:"price"`
+	StartsAt      time.Time      `db:"starts_at"`
+	EndsAt        *time.Time     `db:"ends_at"`
+	OriginalPrice *int           `db:"original_price"`
+	Status        ScheduleStatus `db:"status"`
+	ActorPublicId string         `db:"actor_public_id"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+func NewPriceScheduleFromRequest(productId int, req CreatePriceScheduleRequestPayload) PriceSchedule {
+	now := time.Now()
+
+	scheduleType := ScheduleType(0)
+	for t, name := range MappingScheduleType {
+		if name == req.Type {
+			scheduleType = t
+		}
+	}
+
+	return PriceSchedule{
+		ProductId:     productId,
+		Type:          scheduleType,
+		Price:         req.Price,
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+		Status:        ScheduleStatus_Pending,
+		ActorPublicId: req.UserPublicId,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (s PriceSchedule) Validate(now time.Time) (err error) {
+	if _, ok := MappingScheduleType[s.Type]; !ok {
+		return response.ErrPriceScheduleTypeInvalid
+	}
+	if s.Price <= 0 {
+		return response.ErrPriceInvalid
+	}
+	if !s.StartsAt.After(now) {
+		return response.ErrPriceScheduleTimeInvalid
+	}
+
+	switch s.Type {
+	case ScheduleType_Price:
+		if s.EndsAt != nil {
+			return response.ErrPriceScheduleTimeInvalid
+		}
+	case ScheduleType_Sale:
+		if s.EndsAt == nil || !s.EndsAt.After(s.StartsAt) {
+			return response.ErrPriceScheduleTimeInvalid
+		}
+	}
+	return
+}
+
+// Window adalah rentang waktu schedule, schedule price hanya satu titik waktu
+func (s PriceSchedule) Window() (start time.Time, end time.Time) {
+	if s.EndsAt == nil {
+		return s.StartsAt, s.StartsAt
+	}
+	return s.StartsAt, *s.EndsAt
+}
+
+func (s PriceSchedule) IsSale() bool {
+	return s.Type == ScheduleType_Sale
+}
+
+// IsDue true jika schedule pending sudah waktunya dijalankan atau sale aktif sudah waktunya berakhir
+func (s PriceSchedule) IsDue(now time.Time) bool {
+	switch s.Status {
+	case ScheduleStatus_Pending:
+		return !now.Before(s.StartsAt)
+	case ScheduleStatus_Active:
+		return s.EndsAt != nil && !now.Before(*s.EndsAt)
+	}
+	return false
+}
+
+// Apply menjalankan schedule terhadap harga produk saat ini.
+// changed false jika harga tidak berubah, misalnya sale yang jendelanya sudah lewat sebelum sempat dimulai.
+func (s *PriceSchedule) Apply(currentPrice int, now time.Time) (change PriceChange, changed bool) {
+	s.UpdatedAt = now
+
+	switch {
+	case s.Status == ScheduleStatus_Active:
+		s.Status = ScheduleStatus_Completed
+		change = NewPriceChange(s.ProductId, currentPrice, *s.OriginalPrice, REASON_SALE_END)
+
+	case s.IsSale() && !now.Before(*s.EndsAt):
+		s.Status = ScheduleStatus_Completed
+		return change, false
+
+	case s.IsSale():
+		s.Status = ScheduleStatus_Active
+		s.OriginalPrice = &currentPrice
+		change = NewPriceChange(s.ProductId, currentPrice, s.Price, REASON_SALE_START)
+
+	default:
+		s.Status = ScheduleStatus_Completed
+		change = NewPriceChange(s.ProductId, currentPrice, s.Price, REASON_SCHEDULED)
+	}
+
+	change.WithSchedule(s.Id).WithActor(s.ActorPublicId)
+	return change, change.OldPrice != change.NewPrice
+}
+
+func (s *PriceSchedule) Cancel() (err error) {
+	if s.Status != ScheduleStatus_Pending {
+		return response.ErrPriceScheduleNotCancelable
+	}
+	s.Status = ScheduleStatus_Cancelled
+	s.UpdatedAt = time.Now()
+	return
+}
+
+func (s PriceSchedule) GetType() string {
+	scheduleType, ok := MappingScheduleType[s.Type]
+	if !ok {
+		return SCHEDULE_TYPE_UNKNOWN
+	}
+	return scheduleType
+}
+
+func (s PriceSchedule) GetStatus() string {
+	status, ok := MappingScheduleStatus[s.Status]
+	if !ok {
+		return SCHEDULE_STATUS_UNKNOWN
+	}
+	return status
+}
+
+type Product struct {
+	Id    int    `db:"id"`
+	SKU   string `db:"sku"`
+	Name  string `db:"name"`
+	Price int    `db:"price"`
+}
@@ -0,0 +1,124 @@
+package pricing
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePriceSchedule(t *testing.T) {
+	now := time.Now()
+	startsAt := now.Add(time.Hour)
+	endsAt := now.Add(2 * time.Hour)
+
+	t.Run("price", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: SCHEDULE_PRICE, Price: 10_000, StartsAt: startsAt})
+
+		require.Nil(t, schedule.Validate(now))
+		require.Equal(t, SCHEDULE_PENDING, schedule.GetStatus())
+		require.Equal(t, SCHEDULE_PRICE, schedule.GetType())
+	})
+	t.Run("sale", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: SCHEDULE_SALE, Price: 8_000, StartsAt: startsAt, EndsAt: &endsAt})
+
+		require.Nil(t, schedule.Validate(now))
+	})
+	t.Run("type invalid", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: "flash", Price: 8_000, StartsAt: startsAt})
+
+		require.Equal(t, response.ErrPriceScheduleTypeInvalid, schedule.Validate(now))
+	})
+	t.Run("price invalid", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: SCHEDULE_PRICE, Price: 0, StartsAt: startsAt})
+
+		require.Equal(t, response.ErrPriceInvalid, schedule.Validate(now))
+	})
+	t.Run("starts in the past", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: SCHEDULE_PRICE, Price: 10_000, StartsAt: now.Add(-time.Minute)})
+
+		require.Equal(t, response.ErrPriceScheduleTimeInvalid, schedule.Validate(now))
+	})
+	t.Run("sale without end", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: SCHEDULE_SALE, Price: 8_000, StartsAt: startsAt})
+
+		require.Equal(t, response.ErrPriceScheduleTimeInvalid, schedule.Validate(now))
+	})
+	t.Run("sale ends before start", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: SCHEDULE_SALE, Price: 8_000, StartsAt: endsAt, EndsAt: &startsAt})
+
+		require.Equal(t, response.ErrPriceScheduleTimeInvalid, schedule.Validate(now))
+	})
+	t.Run("price with end", func(t *testing.T) {
+		schedule := NewPriceScheduleFromRequest(1, CreatePriceScheduleRequestPayload{Type: SCHEDULE_PRICE, Price: 10_000, StartsAt: startsAt, EndsAt: &endsAt})
+
+		require.Equal(t, response.ErrPriceScheduleTimeInvalid, schedule.Validate(now))
+	})
+}
+
+func TestApplyPriceSchedule(t *testing.T) {
+	startsAt := time.Now()
+	endsAt := startsAt.Add(time.Hour)
+
+	t.Run("price", func(t *testing.T) {
+		schedule := PriceSchedule{Id: 3, ProductId: 1, Type: ScheduleType_Price, Price: 12_000, StartsAt: startsAt, Status: ScheduleStatus_Pending}
+		require.True(t, schedule.IsDue(startsAt))
+
+		change, changed := schedule.Apply(10_000, startsAt)
+		require.True(t, changed)
+		require.Equal(t, REASON_SCHEDULED, change.Reason)
+		require.Equal(t, 3, *change.ScheduleId)
+		require.Equal(t, SCHEDULE_COMPLETED, schedule.GetStatus())
+		require.False(t, schedule.IsDue(startsAt))
+	})
+	t.Run("sale start and end", func(t *testing.T) {
+		schedule := PriceSchedule{Id: 4, ProductId: 1, Type: ScheduleType_Sale, Price: 8_000, StartsAt: startsAt, EndsAt: &endsAt, Status: ScheduleStatus_Pending}
+
+		change, changed := schedule.Apply(10_000, startsAt)
+		require.True(t, changed)
+		require.Equal(t, REASON_SALE_START, change.Reason)
+		require.Equal(t, 8_000, change.NewPrice)
+		require.Equal(t, 10_000, *schedule.OriginalPrice)
+		require.Equal(t, SCHEDULE_ACTIVE, schedule.GetStatus())
+
+		require.False(t, schedule.IsDue(startsAt))
+		require.True(t, schedule.IsDue(endsAt))
+
+		change, changed = schedule.Apply(8_000, endsAt)
+		require.True(t, changed)
+		require.Equal(t, REASON_SALE_END, change.Reason)
+		require.Equal(t, 10_000, change.NewPrice)
+		require.Equal(t, SCHEDULE_COMPLETED, schedule.GetStatus())
+	})
+	t.Run("sale window already passed", func(t *testing.T) {
+		schedule := PriceSchedule{Id: 5, ProductId: 1, Type: ScheduleType_Sale, Price: 8_000, StartsAt: startsAt, EndsAt: &endsAt, Status: ScheduleStatus_Pending}
+
+		_, changed := schedule.Apply(10_000, endsAt.Add(time.Minute))
+		require.False(t, changed)
+		require.Equal(t, SCHEDULE_COMPLETED, schedule.GetStatus())
+	})
+	t.Run("same price", func(t *testing.T) {
+		schedule := PriceSchedule{Id: 6, ProductId: 1, Type: ScheduleType_Price, Price: 10_000, StartsAt: startsAt, Status: ScheduleStatus_Pending}
+
+		_, changed := schedule.Apply(10_000, startsAt)
+		require.False(t, changed)
+		require.Equal(t, SCHEDULE_COMPLETED, schedule.GetStatus())
+	})
+}
+
+func TestCancelPriceSchedule(t *testing.T) {
+	schedule := PriceSchedule{Status: ScheduleStatus_Pending}
+	require.Nil(t, schedule.Cancel())
+	require.Equal(t, SCHEDULE_CANCELLED, schedule.GetStatus())
+
+	active := PriceSchedule{Status: ScheduleStatus_Active}
+	require.Equal(t, response.ErrPriceScheduleNotCancelable, active.Cancel())
+}
+
+func TestPriceChangeEndsSale(t *testing.T) {
+	require.True(t, NewPriceChange(1, 10_000, 12_000, REASON_MANUAL).EndsSale())
+	require.True(t, NewPriceChange(1, 10_000, 12_000, REASON_IMPORT).EndsSale())
+	require.False(t, NewPriceChange(1, 10_000, 8_000, REASON_SALE_START).EndsSale())
+	require.False(t, NewPriceChange(1, 10_000, 12_000, REASON_SCHEDULED).EndsSale())
+}
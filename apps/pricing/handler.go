@@ -0,0 +1,189 @@
+package pricing
+
+import (
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/infra/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	svc service
+}
+
+func newHandler(svc service) handler {
+	return handler{
+		svc: svc,
+	}
+}
+
+func (h handler) GetPriceHistory(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req ListPriceHistoryRequestPayload
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	changes, meta, err := h.svc.PriceHistory(c.Request.Context(), productId, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get price history success"),
+		infragin.WithPayload(NewPriceChangeListResponse(changes)),
+		infragin.WithMeta(meta),
+	)
+	resp.Send(c)
+}
+
+func (h handler) CreatePriceSchedule(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req CreatePriceScheduleRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+	req.UserPublicId = c.GetString("PUBLIC_ID")
+
+	schedule, err := h.svc.SchedulePrice(c.Request.Context(), productId, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("create price schedule success"),
+		infragin.WithPayload(schedule.ToPriceScheduleResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetPriceSchedules(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	schedules, err := h.svc.PriceSchedules(c.Request.Context(), productId)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get price schedules success"),
+		infragin.WithPayload(NewPriceScheduleListResponse(schedules)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) CancelPriceSchedule(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	scheduleId, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid price schedule ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.CancelPriceSchedule(c.Request.Context(), productId, scheduleId); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("cancel price schedule success"),
+	)
+	resp.Send(c)
+}
@@ -0,0 +1,251 @@
+package pricing
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+func (r repository) Begin(ctx context.Context) (tx *sqlx.Tx, err error) {
+	tx, err = r.db.BeginTxx(ctx, &sql.TxOptions{})
+	return
+}
+
+func (repository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Commit()
+}
+
+func (repository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Rollback()
+}
+
+func (r repository) GetProductById(ctx context.Context, productId int) (product Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, price
+		FROM products
+		WHERE id=$1 AND deleted_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &product, query, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+// GetProductByIdForUpdateWithTx mengunci baris produk sampai transaksi selesai
+func (r repository) GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, price
+		FROM products
+		WHERE id=$1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &product, query, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) UpdateProductPriceWithTx(ctx context.Context, tx *sqlx.Tx, productId int, price int) (err error) {
+	query := `
+		UPDATE products
+		SET price=$2, updated_at=NOW(), version=version + 1
+		WHERE id=$1
+	`
+
+	_, err = tx.ExecContext(ctx, query, productId, price)
+	return
+}
+
+func (r repository) RecordPriceChangeWithTx(ctx context.Context, tx *sqlx.Tx, change PriceChange) (err error) {
+	return RecordPriceChangeWithTx(ctx, tx, change)
+}
+
+func (r repository) GetPriceChangesByProductId(ctx context.Context, productId int, page pagination.Request) (changes []PriceChange, err error) {
+	operator, direction := page.Order(true)
+	query := fmt.Sprintf(`
+		SELECT
+			id, product_id, old_price, new_price, reason
+			, schedule_id, actor_public_id, created_at
+		FROM price_changes
+		WHERE product_id=$1
+			AND ($2 = 0 OR id %s $2)
+		ORDER BY id %s
+		LIMIT $3
+	`, operator, direction)
+
+	cursorId := 0
+	if page.Cursor != nil {
+		cursorId = page.Cursor.Id
+	}
+
+	err = r.db.SelectContext(ctx, &changes, query, productId, cursorId, page.Limit())
+	return
+}
+
+func (r repository) CreatePriceScheduleWithTx(ctx context.Context, tx *sqlx.Tx, schedule PriceSchedule) (id int, err error) {
+	query := `
+		INSERT INTO price_schedules (
+			product_id, type, price, starts_at, ends_at
+			, status, actor_public_id, created_at, updated_at
+		) VALUES (
+			:product_id, :type, :price, :starts_at, :ends_at
+			, :status, :actor_public_id, :created_at, :updated_at
+		)
+		RETURNING id
+	`
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	err = stmt.GetContext(ctx, &id, schedule)
+	return
+}
+
+// HasOverlappingScheduleWithTx memeriksa bentrok dengan sale yang belum selesai.
+// Schedule price tidak boleh jatuh di dalam jendela sale, dan sale baru tidak boleh
+// menimpa sale lain atau schedule price yang masih pending.
+func (r repository) HasOverlappingScheduleWithTx(ctx context.Context, tx *sqlx.Tx, schedule PriceSchedule) (overlap bool, err error) {
+	start, end := schedule.Window()
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM price_schedules
+			WHERE product_id=$1 AND status IN ($2, $3)
+				AND (
+					(type=$4 AND starts_at <= $6 AND ends_at > $5)
+					OR ($7 = $4 AND type=$8 AND starts_at >= $5 AND starts_at < $6)
+				)
+		)
+	`
+
+	err = tx.GetContext(ctx, &overlap, query,
+		schedule.ProductId, ScheduleStatus_Pending, ScheduleStatus_Active,
+		ScheduleType_Sale, start, end, schedule.Type, ScheduleType_Price,
+	)
+	return
+}
+
+func (r repository) GetPriceSchedulesByProductId(ctx context.Context, productId int) (schedules []PriceSchedule, err error) {
+	query := `
+		SELECT
+			id, product_id, type, price, starts_at, ends_at, original_price
+			, status, actor_public_id, created_at, updated_at
+		FROM price_schedules
+		WHERE product_id=$1
+		ORDER BY starts_at DESC, id DESC
+	`
+
+	err = r.db.SelectContext(ctx, &schedules, query, productId)
+	return
+}
+
+func (r repository) GetPriceScheduleByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (schedule PriceSchedule, err error) {
+	query := `
+		SELECT
+			id, product_id, type, price, starts_at, ends_at, original_price
+			, status, actor_public_id, created_at, updated_at
+		FROM price_schedules
+		WHERE id=$1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &schedule, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrPriceScheduleNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) UpdatePriceScheduleWithTx(ctx context.Context, tx *sqlx.Tx, schedule PriceSchedule) (err error) {
+	query := `
+		UPDATE price_schedules
+		SET status=:status, original_price=:original_price, updated_at=:updated_at
+		WHERE id=:id
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, schedule)
+	return
+}
+
+// GetDueSchedules mengambil schedule pending yang sudah mulai dan sale aktif yang sudah berakhir.
+// Sale yang berakhir didahulukan supaya harga asli kembali sebelum schedule berikutnya dijalankan.
+func (r repository) GetDueSchedules(ctx context.Context, now time.Time, limit int) (schedules []PriceSchedule, err error) {
+	query := `
+		SELECT
+			id, product_id, type, price, starts_at, ends_at, original_price
+			, status, actor_public_id, created_at, updated_at
+		FROM price_schedules
+		WHERE (status=$1 AND starts_at <= $3)
+			OR (status=$2 AND ends_at <= $3)
+		ORDER BY status DESC, starts_at, id
+		LIMIT $4
+	`
+
+	err = r.db.SelectContext(ctx, &schedules, query, ScheduleStatus_Pending, ScheduleStatus_Active, now, limit)
+	return
+}
+
+// RecordPriceChangeWithTx dipakai juga oleh modul product dan productimport supaya setiap perubahan
+// harga tercatat di transaksi database yang sama. Perubahan harga langsung oleh admin atau import
+// mengakhiri sale yang sedang aktif, harga baru menjadi harga normal.
+func RecordPriceChangeWithTx(ctx context.Context, tx *sqlx.Tx, change PriceChange) (err error) {
+	query := `
+		INSERT INTO price_changes (
+			product_id, old_price, new_price, reason
+			, schedule_id, actor_public_id, created_at
+		) VALUES (
+			:product_id, :old_price, :new_price, :reason
+			, :schedule_id, :actor_public_id, :created_at
+		)
+	`
+
+	if _, err = tx.NamedExecContext(ctx, query, change); err != nil {
+		return
+	}
+
+	if !change.EndsSale() {
+		return
+	}
+
+	query = `
+		UPDATE price_schedules
+		SET status=$2, updated_at=$4
+		WHERE product_id=$1 AND status=$3
+	`
+
+	_, err = tx.ExecContext(ctx, query, change.ProductId, ScheduleStatus_Completed, ScheduleStatus_Active, change.CreatedAt)
+	return
+}
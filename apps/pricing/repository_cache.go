@@ -0,0 +1,45 @@
+package pricing
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// cachedRepository menghapus cache produk berdasarkan SKU setelah harga berubah.
+// Harga hanya diubah setelah baris produk dikunci, SKU diambil dari produk yang dikunci
+type cachedRepository struct {
+	Repository
+
+	pending *cache.PendingKeys[*sqlx.Tx]
+}
+
+func newCachedRepository(repo Repository) cachedRepository {
+	return cachedRepository{
+		Repository: repo,
+		pending:    cache.NewPendingKeys[*sqlx.Tx](cache.TAG_PRODUCT_SKU),
+	}
+}
+
+func (r cachedRepository) GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error) {
+	if product, err = r.Repository.GetProductByIdForUpdateWithTx(ctx, tx, productId); err != nil {
+		return
+	}
+	r.pending.Add(tx, product.SKU)
+	return
+}
+
+func (r cachedRepository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	if err = r.Repository.Commit(ctx, tx); err != nil {
+		r.pending.Discard(tx)
+		return
+	}
+	r.pending.Flush(ctx, tx)
+	return
+}
+
+func (r cachedRepository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	r.pending.Discard(tx)
+	return r.Repository.Rollback(ctx, tx)
+}
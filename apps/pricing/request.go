@@ -0,0 +1,17 @@
+package pricing
+
+import "time"
+
+type ListPriceHistoryRequestPayload struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size"`
+}
+
+type CreatePriceScheduleRequestPayload struct {
+	// price atau sale
+	Type         string     `json:"type"`
+	Price        int        `json:"price"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	UserPublicId string     `json:"-"`
+}
@@ -0,0 +1,71 @@
+package pricing
+
+import "time"
+
+type PriceChangeResponse struct {
+	Id            int       `json:"id"`
+	ProductId     int       `json:"product_id"`
+	OldPrice      int       `json:"old_price"`
+	NewPrice      int       `json:"new_price"`
+	Reason        string    `json:"reason"`
+	ScheduleId    *int      `json:"schedule_id,omitempty"`
+	ActorPublicId string    `json:"actor_public_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (c PriceChange) ToPriceChangeResponse() PriceChangeResponse {
+	return PriceChangeResponse{
+		Id:            c.Id,
+		ProductId:     c.ProductId,
+		OldPrice:      c.OldPrice,
+		NewPrice:      c.NewPrice,
+		Reason:        c.Reason,
+		ScheduleId:    c.ScheduleId,
+		ActorPublicId: c.ActorPublicId,
+		CreatedAt:     c.CreatedAt,
+	}
+}
+
+func NewPriceChangeListResponse(changes []PriceChange) []PriceChangeResponse {
+	resp := []PriceChangeResponse{}
+	for _, change := range changes {
+		resp = append(resp, change.ToPriceChangeResponse())
+	}
+	return resp
+}
+
+type PriceScheduleResponse struct {
+	Id            int        `json:"id"`
+	ProductId     int        `json:"product_id"`
+	Type          string     `json:"type"`
+	Price         int        `json:"price"`
+	StartsAt      time.Time  `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+	OriginalPrice *int       `json:"original_price,omitempty"`
+	Status        string     `json:"status"`
+	ActorPublicId string     `json:"actor_public_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (s PriceSchedule) ToPriceScheduleResponse() PriceScheduleResponse {
+	return PriceScheduleResponse{
+		Id:            s.Id,
+		ProductId:     s.ProductId,
+		Type:          s.GetType(),
+		Price:         s.Price,
+		StartsAt:      s.StartsAt,
+		EndsAt:        s.EndsAt,
+		OriginalPrice: s.OriginalPrice,
+		Status:        s.GetStatus(),
+		ActorPublicId: s.ActorPublicId,
+		CreatedAt:     s.CreatedAt,
+	}
+}
+
+func NewPriceScheduleListResponse(schedules []PriceSchedule) []PriceScheduleResponse {
+	resp := []PriceScheduleResponse{}
+	for _, schedule := range schedules {
+		resp = append(resp, schedule.ToPriceScheduleResponse())
+	}
+	return resp
+}
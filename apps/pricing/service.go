@@ -0,0 +1,238 @@
+package pricing
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"Ecommerce-basic/internal/log"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	PricingDBRepository
+	PriceChangeRepository
+	PriceScheduleRepository
+	ProductRepository
+}
+
+type PricingDBRepository interface {
+	Begin(ctx context.Context) (tx *sqlx.Tx, err error)
+	Rollback(ctx context.Context, tx *sqlx.Tx) (err error)
+	Commit(ctx context.Context, tx *sqlx.Tx) (err error)
+}
+
+type PriceChangeRepository interface {
+	RecordPriceChangeWithTx(ctx context.Context, tx *sqlx.Tx, change PriceChange) (err error)
+	GetPriceChangesByProductId(ctx context.Context, productId int, page pagination.Request) (changes []PriceChange, err error)
+}
+
+type PriceScheduleRepository interface {
+	CreatePriceScheduleWithTx(ctx context.Context, tx *sqlx.Tx, schedule PriceSchedule) (id int, err error)
+	HasOverlappingScheduleWithTx(ctx context.Context, tx *sqlx.Tx, schedule PriceSchedule) (overlap bool, err error)
+	GetPriceSchedulesByProductId(ctx context.Context, productId int) (schedules []PriceSchedule, err error)
+	GetPriceScheduleByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (schedule PriceSchedule, err error)
+	UpdatePriceScheduleWithTx(ctx context.Context, tx *sqlx.Tx, schedule PriceSchedule) (err error)
+	GetDueSchedules(ctx context.Context, now time.Time, limit int) (schedules []PriceSchedule, err error)
+}
+
+type ProductRepository interface {
+	GetProductById(ctx context.Context, productId int) (product Product, err error)
+	GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error)
+	UpdateProductPriceWithTx(ctx context.Context, tx *sqlx.Tx, productId int, price int) (err error)
+}
+
+// jumlah schedule yang dijalankan dalam satu kali tick scheduler
+const scheduleBatchSize = 100
+
+type service struct {
+	repo Repository
+}
+
+func newService(repo Repository) service {
+	return service{
+		repo: repo,
+	}
+}
+
+func (s service) PriceHistory(ctx context.Context, productId int, req ListPriceHistoryRequestPayload) (changes []PriceChange, meta pagination.Meta, err error) {
+	secret := config.Cfg.App.Encryption.CursorSecret
+
+	page, err := pagination.NewRequest(req.Cursor, req.Size, "", secret)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.repo.GetProductById(ctx, productId); err != nil {
+		return
+	}
+
+	changes, err = s.repo.GetPriceChangesByProductId(ctx, productId, page)
+	if err != nil {
+		return
+	}
+
+	changes, meta = pagination.Paginate(changes, page, PriceChange.CursorKey, secret)
+	if len(changes) == 0 {
+		changes = []PriceChange{}
+	}
+	return
+}
+
+// SchedulePrice menjadwalkan perubahan harga atau sale untuk produk
+func (s service) SchedulePrice(ctx context.Context, productId int, req CreatePriceScheduleRequestPayload) (schedule PriceSchedule, err error) {
+	schedule = NewPriceScheduleFromRequest(productId, req)
+	if err = schedule.Validate(time.Now()); err != nil {
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	// kunci produk supaya dua schedule untuk produk yang sama tidak lolos cek bentrok bersamaan
+	if _, err = s.repo.GetProductByIdForUpdateWithTx(ctx, tx, productId); err != nil {
+		return
+	}
+
+	overlap, err := s.repo.HasOverlappingScheduleWithTx(ctx, tx, schedule)
+	if err != nil {
+		return
+	}
+	if overlap {
+		err = response.ErrPriceScheduleOverlap
+		return
+	}
+
+	if schedule.Id, err = s.repo.CreatePriceScheduleWithTx(ctx, tx, schedule); err != nil {
+		return
+	}
+
+	err = s.repo.Commit(ctx, tx)
+	return
+}
+
+func (s service) PriceSchedules(ctx context.Context, productId int) (schedules []PriceSchedule, err error) {
+	if _, err = s.repo.GetProductById(ctx, productId); err != nil {
+		return
+	}
+
+	schedules, err = s.repo.GetPriceSchedulesByProductId(ctx, productId)
+	if err != nil {
+		return
+	}
+	if len(schedules) == 0 {
+		schedules = []PriceSchedule{}
+	}
+	return
+}
+
+func (s service) CancelPriceSchedule(ctx context.Context, productId int, scheduleId int) (err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	schedule, err := s.repo.GetPriceScheduleByIdForUpdateWithTx(ctx, tx, scheduleId)
+	if err != nil {
+		return
+	}
+	if schedule.ProductId != productId {
+		return response.ErrPriceScheduleNotFound
+	}
+
+	if err = schedule.Cancel(); err != nil {
+		return
+	}
+
+	if err = s.repo.UpdatePriceScheduleWithTx(ctx, tx, schedule); err != nil {
+		return
+	}
+
+	return s.repo.Commit(ctx, tx)
+}
+
+// ApplyDueSchedules menjalankan schedule yang sudah waktunya, masing-masing dalam transaksi sendiri
+// supaya satu schedule yang gagal tidak menahan schedule lain
+func (s service) ApplyDueSchedules(ctx context.Context, now time.Time) (applied int, err error) {
+	schedules, err := s.repo.GetDueSchedules(ctx, now, scheduleBatchSize)
+	if err != nil {
+		log.Log.Errorf(ctx, "[ApplyDueSchedules, GetDueSchedules] with error detail %v", err.Error())
+		return
+	}
+
+	for _, schedule := range schedules {
+		if err := s.applySchedule(ctx, schedule, now); err != nil {
+			log.Log.Errorf(ctx, "[ApplyDueSchedules, applySchedule] schedule %d with error detail %v", schedule.Id, err.Error())
+			continue
+		}
+		applied++
+	}
+	return
+}
+
+func (s service) applySchedule(ctx context.Context, due PriceSchedule, now time.Time) (err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	// produk dikunci lebih dulu, urutan yang sama dengan update produk
+	product, err := s.repo.GetProductByIdForUpdateWithTx(ctx, tx, due.ProductId)
+	if err != nil {
+		if err != response.ErrNotFound {
+			return
+		}
+		// produk sudah dihapus, schedule tidak akan pernah bisa dijalankan
+		product = Product{}
+	}
+
+	schedule, err := s.repo.GetPriceScheduleByIdForUpdateWithTx(ctx, tx, due.Id)
+	if err != nil {
+		return
+	}
+
+	// sudah dijalankan, dibatalkan atau diakhiri oleh update harga sejak dibaca
+	if !schedule.IsDue(now) {
+		return
+	}
+
+	if product.Id == 0 {
+		schedule.Status = ScheduleStatus_Cancelled
+		schedule.UpdatedAt = now
+	} else if change, changed := schedule.Apply(product.Price, now); changed {
+		if err = s.repo.UpdateProductPriceWithTx(ctx, tx, product.Id, change.NewPrice); err != nil {
+			return
+		}
+		if err = s.repo.RecordPriceChangeWithTx(ctx, tx, change); err != nil {
+			return
+		}
+	}
+
+	if err = s.repo.UpdatePriceScheduleWithTx(ctx, tx, schedule); err != nil {
+		return
+	}
+
+	return s.repo.Commit(ctx, tx)
+}
+
+// runScheduler berjalan sampai ctx dibatalkan
+func (s service) runScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ApplyDueSchedules(ctx, now)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package pricing
+
+import (
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo)
+}
+
+func TestSchedulePrice(t *testing.T) {
+	productId := 1
+	startsAt := time.Now().Add(24 * time.Hour)
+	endsAt := startsAt.Add(time.Hour)
+
+	sale, err := svc.SchedulePrice(context.Background(), productId, CreatePriceScheduleRequestPayload{
+		Type: SCHEDULE_SALE, Price: 5_000, StartsAt: startsAt, EndsAt: &endsAt,
+	})
+	require.Nil(t, err)
+	require.NotZero(t, sale.Id)
+
+	t.Run("overlap", func(t *testing.T) {
+		_, err := svc.SchedulePrice(context.Background(), productId, CreatePriceScheduleRequestPayload{
+			Type: SCHEDULE_PRICE, Price: 12_000, StartsAt: startsAt.Add(time.Minute),
+		})
+		require.Equal(t, response.ErrPriceScheduleOverlap, err)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		err := svc.CancelPriceSchedule(context.Background(), productId, sale.Id)
+		require.Nil(t, err)
+
+		err = svc.CancelPriceSchedule(context.Background(), productId, sale.Id)
+		require.Equal(t, response.ErrPriceScheduleNotCancelable, err)
+	})
+}
+
+func TestPriceHistory(t *testing.T) {
+	changes, _, err := svc.PriceHistory(context.Background(), 1, ListPriceHistoryRequestPayload{Size: 5})
+	require.Nil(t, err)
+	require.NotNil(t, changes)
+}
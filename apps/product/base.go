@@ -2,31 +2,78 @@ package product
 
 import (
 	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/infra/blobstore"
 	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/internal"
+	"context"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 )
 
+// interval default scheduler publish / unpublish jika tidak diatur di config
+const defaultLifecycleInterval = time.Minute
+
 func Init(router *gin.Engine, db *sqlx.DB) {
-	repo := newRepository(db)
-	svc := newService(repo)
-	handler := newHandler(svc)
+	repo := newCachedRepository(newRepository(db))
+	svc := newService(repo, blobstore.NewLocal(config.Cfg.App.Digital.BlobRoot))
+
+	// view detail produk dicatat di background supaya tidak menambah latency
+	views := newViewQueue(config.Cfg.App.Product.ViewQueueSize)
+	go views.run(context.Background(), svc.RecordView)
+
+	handler := newHandler(svc, views)
 
 	productRoute := router.Group("/products")
 	{
 		productRoute.GET("", handler.GetListProducts)
-		productRoute.GET("/sku/:sku", handler.GetProductDetail)
+		productRoute.GET("/sku/:sku", infragin.OptionalAuth(), handler.GetProductDetail)
+		productRoute.GET("/slug/:slug", infragin.OptionalAuth(), handler.GetProductDetailBySlug)
 		productRoute.GET("/search", handler.SearchProducts)
 		productRoute.GET("/filter", handler.FilterProducts)
+		productRoute.POST("/view-sessions", handler.CreateViewSession)
 
 		// Authorization middleware
 		authRequired := productRoute.Group("")
 		authRequired.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
 		{
 			authRequired.POST("", handler.CreateProduct)
+			authRequired.POST("/bundles", handler.CreateBundle)
+			authRequired.PUT("/:id/components", handler.UpdateBundleComponents)
+			authRequired.PUT("/:id/attributes", handler.UpdateProductAttributes)
+			authRequired.POST("/:id/license-keys", handler.AddLicenseKeys)
+			authRequired.GET("/:id/license-keys", handler.GetLicenseKeySummary)
+			authRequired.PUT("/:id/file", handler.UploadDigitalFile)
 			authRequired.PUT("/:id", handler.UpdateProduct)
+			authRequired.PATCH("/:id", handler.PatchProduct)
 			authRequired.DELETE("/:id", handler.DeleteProduct)
+			authRequired.PUT("/:id/status", handler.UpdateProductStatus)
+			authRequired.POST("/:id/restore", handler.RestoreProduct)
+			authRequired.DELETE("/:id/purge", handler.PurgeProduct)
 		}
 	}
+
+	adminRoute := router.Group("/admin/products")
+	{
+		adminRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		adminRoute.GET("", handler.GetAdminListProducts)
+		adminRoute.GET("/sku/:sku", handler.GetAdminProductDetail)
+		adminRoute.GET("/slug/:slug", handler.GetAdminProductDetailBySlug)
+		adminRoute.GET("/deleted", handler.GetDeletedProducts)
+	}
+
+	router.GET("/auth/me/recently-viewed", infragin.CheckAuth(), handler.GetRecentlyViewed)
+}
+
+// StartLifecycleScheduler menjalankan jadwal publish / unpublish produk di background sampai ctx dibatalkan
+func StartLifecycleScheduler(ctx context.Context, db *sqlx.DB) {
+	interval := config.Cfg.App.Product.LifecycleInterval
+	if interval <= 0 {
+		interval = defaultLifecycleInterval
+	}
+
+	svc := newService(newCachedRepository(newRepository(db)), blobstore.NewLocal(config.Cfg.App.Digital.BlobRoot))
+	go svc.runLifecycleScheduler(ctx, interval)
 }
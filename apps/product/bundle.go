@@ -0,0 +1,92 @@
+package product
+
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/response"
+	"fmt"
+	"strings"
+)
+
+// stok yang dipakai filter, facet dan list. stok bundle adalah jumlah set yang bisa dirakit dari
+// komponen yang paling sedikit, komponen yang sudah dihapus membuat bundle habis
+var productStockColumn = fmt.Sprintf(`CASE WHEN p.type = %d THEN COALESCE((
+				SELECT MIN(CASE WHEN c.deleted_at IS NULL THEN c.stock / bc.quantity ELSE 0 END)
+				FROM bundle_components bc
+				JOIN products c ON c.id = bc.component_id
+				WHERE bc.bundle_id = p.id
+			), 0) ELSE p.stock END`, ProductType_Bundle)
+
+type BundleComponent struct {
+	BundleId    int    `db:"bundle_id"`
+	ComponentId int    `db:"component_id"`
+	Quantity    int    `db:"quantity"`
+	SKU         string `db:"sku"`
+	Name        string `db:"name"`
+	Stock       int16  `db:"stock"`
+	Deleted     bool   `db:"deleted"`
+
+	// stok komponen yang sedang ditahan reservation aktif, diisi oleh service
+	Reserved int `db:"-"`
+}
+
+// Available jumlah set yang bisa dipenuhi komponen ini
+func (c BundleComponent) Available() int {
+	if c.Deleted || c.Quantity <= 0 {
+		return 0
+	}
+	return inventory.Available(int(c.Stock), c.Reserved) / c.Quantity
+}
+
+// BundleAvailable dibatasi oleh komponen yang paling sedikit, bundle tanpa komponen tidak bisa dibeli
+func (p Product) BundleAvailable() int {
+	if len(p.Components) == 0 {
+		return 0
+	}
+
+	available := p.Components[0].Available()
+	for _, component := range p.Components[1:] {
+		available = min(available, component.Available())
+	}
+	return available
+}
+
+func NewBundleFromCreateBundleRequest(req CreateBundleRequestPayload) Product {
+	bundle := NewProductFromCreateProductRequest(req.CreateProductRequestPayload)
+	bundle.Type = ProductType_Bundle
+	bundle.Stock = 0
+	return bundle
+}
+
+// ValidateBundleComponents memeriksa isi request sebelum SKU komponen dicari di database
+func ValidateBundleComponents(components []BundleComponentRequestPayload) (err error) {
+	if len(components) == 0 {
+		return response.ErrBundleComponentsInvalid
+	}
+
+	seen := map[string]bool{}
+	for _, component := range components {
+		sku := strings.TrimSpace(component.SKU)
+		if sku == "" || component.Quantity <= 0 || seen[sku] {
+			return response.ErrBundleComponentsInvalid
+		}
+		seen[sku] = true
+	}
+	return
+}
+
+// NewBundleComponent komponen harus produk biasa, bundle tidak boleh berisi bundle lain atau dirinya sendiri
+func (p Product) NewBundleComponent(component Product, quantity int) (model BundleComponent, err error) {
+	// produk digital tidak punya stok fisik untuk dirakit menjadi bundle
+	if component.IsBundle() || component.IsDigital() || component.Id == p.Id {
+		return model, response.ErrBundleComponentInvalid
+	}
+
+	return BundleComponent{
+		BundleId:    p.Id,
+		ComponentId: component.Id,
+		Quantity:    quantity,
+		SKU:         component.SKU,
+		Name:        component.Name,
+		Stock:       component.Stock,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleAvailable(t *testing.T) {
+	t.Run("limited by the scarcest component", func(t *testing.T) {
+		bundle := Product{Type: ProductType_Bundle, Components: []BundleComponent{
+			{ComponentId: 1, Quantity: 1, Stock: 10},
+			{ComponentId: 2, Quantity: 2, Stock: 9},
+			{ComponentId: 3, Quantity: 3, Stock: 20, Reserved: 5},
+		}}
+
+		require.Equal(t, 4, bundle.Available())
+	})
+	t.Run("deleted component", func(t *testing.T) {
+		bundle := Product{Type: ProductType_Bundle, Components: []BundleComponent{
+			{ComponentId: 1, Quantity: 1, Stock: 10},
+			{ComponentId: 2, Quantity: 1, Stock: 10, Deleted: true},
+		}}
+
+		require.Equal(t, 0, bundle.Available())
+	})
+	t.Run("without components", func(t *testing.T) {
+		bundle := Product{Type: ProductType_Bundle, Stock: 10}
+
+		require.Equal(t, 0, bundle.Available())
+	})
+}
+
+func TestValidateBundleComponents(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		err := ValidateBundleComponents([]BundleComponentRequestPayload{{SKU: "BJ-01", Quantity: 1}, {SKU: "CL-01", Quantity: 2}})
+		require.Nil(t, err)
+	})
+	t.Run("empty", func(t *testing.T) {
+		require.Equal(t, response.ErrBundleComponentsInvalid, ValidateBundleComponents(nil))
+	})
+	t.Run("quantity must be positive", func(t *testing.T) {
+		err := ValidateBundleComponents([]BundleComponentRequestPayload{{SKU: "BJ-01", Quantity: 0}})
+		require.Equal(t, response.ErrBundleComponentsInvalid, err)
+	})
+	t.Run("duplicate sku", func(t *testing.T) {
+		err := ValidateBundleComponents([]BundleComponentRequestPayload{{SKU: "BJ-01", Quantity: 1}, {SKU: " BJ-01", Quantity: 1}})
+		require.Equal(t, response.ErrBundleComponentsInvalid, err)
+	})
+}
+
+func TestNewBundleComponent(t *testing.T) {
+	bundle := Product{Id: 1, Type: ProductType_Bundle}
+
+	t.Run("success", func(t *testing.T) {
+		component, err := bundle.NewBundleComponent(Product{Id: 2, SKU: "BJ-01", Type: ProductType_Standard, Stock: 5}, 2)
+		require.Nil(t, err)
+		require.Equal(t, BundleComponent{BundleId: 1, ComponentId: 2, Quantity: 2, SKU: "BJ-01", Stock: 5}, component)
+	})
+	t.Run("nested bundle", func(t *testing.T) {
+		_, err := bundle.NewBundleComponent(Product{Id: 2, Type: ProductType_Bundle}, 1)
+		require.Equal(t, response.ErrBundleComponentInvalid, err)
+	})
+	t.Run("itself", func(t *testing.T) {
+		_, err := bundle.NewBundleComponent(Product{Id: 1, Type: ProductType_Standard}, 1)
+		require.Equal(t, response.ErrBundleComponentInvalid, err)
+	})
+}
+
+func TestBundleIgnoresStock(t *testing.T) {
+	bundle := NewBundleFromCreateBundleRequest(CreateBundleRequestPayload{
+		CreateProductRequestPayload: CreateProductRequestPayload{Name: "Paket Hemat", Stock: 10, Price: 50_000},
+	})
+	require.Equal(t, PRODUCT_TYPE_BUNDLE, bundle.GetType())
+	require.Equal(t, int16(0), bundle.Stock)
+	require.Nil(t, bundle.Validate())
+
+	bundle.ApplyUpdate(UpdateProductRequestPayload{Name: "Paket Hemat", Stock: 7, Price: 45_000})
+	require.Equal(t, int16(0), bundle.Stock)
+	require.Equal(t, 45_000, bundle.Price)
+}
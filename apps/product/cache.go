@@ -0,0 +1,164 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/internal"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// nilai default jika app.product.cache_ttl / cache_max_entries / cache_control tidak diatur
+	defaultCacheTTL        = 30 * time.Second
+	defaultCacheMaxEntries = 1000
+	defaultCacheControl    = "public, max-age=30"
+)
+
+// catalogCache dipakai bersama oleh semua service produk supaya perubahan dari scheduler
+// juga menghapus cache endpoint publik. Perubahan stok, harga, reservasi dan rating dari modul lain
+// sampai ke sini lewat tag cache
+var catalogCache = subscribeCatalog(newReadCache())
+
+func subscribeCatalog(c *readCache) *readCache {
+	cache.Subscribe(cache.TAG_PRODUCT_SKU, c)
+	cache.Subscribe(cache.TAG_PRODUCT_CATALOG, c)
+	return c
+}
+
+// cachedResponse adalah response endpoint produk publik yang sudah di-encode,
+// ProductId diisi untuk detail produk supaya view tetap dicatat ketika cache hit
+type cachedResponse struct {
+	infragin.Representation
+	ProductId int
+}
+
+type readCacheEntry struct {
+	value     cachedResponse
+	expiresAt time.Time
+}
+
+// readCache cache read-through sederhana dengan TTL, seluruh isi dihapus setiap kali produk berubah
+// karena satu produk bisa muncul di banyak halaman daftar produk
+type readCache struct {
+	mu      sync.RWMutex
+	entries map[string]readCacheEntry
+
+	// naik setiap Purge, response yang dibaca sebelum Purge tidak disimpan
+	generation uint64
+}
+
+func newReadCache() *readCache {
+	return &readCache{
+		entries: map[string]readCacheEntry{},
+	}
+}
+
+func (c *readCache) Get(key string, now time.Time) (value cachedResponse, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return cachedResponse{}, false
+	}
+	return entry.value, true
+}
+
+func (c *readCache) Generation() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.generation
+}
+
+// Set generation adalah hasil Generation sebelum data dibaca dari database
+func (c *readCache) Set(key string, value cachedResponse, generation uint64, now time.Time, ttl time.Duration, maxEntries int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxEntries {
+		c.evict(now, maxEntries)
+	}
+	c.entries[key] = readCacheEntry{value: value, expiresAt: now.Add(ttl)}
+}
+
+// evict membuang entry yang kedaluwarsa, jika masih penuh entry acak dibuang sampai ada tempat
+func (c *readCache) evict(now time.Time, maxEntries int) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	for key := range c.entries {
+		if len(c.entries) < maxEntries {
+			return
+		}
+		delete(c.entries, key)
+	}
+}
+
+func (c *readCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]readCacheEntry{}
+	c.generation++
+}
+
+// Invalidate seluruh isi dihapus apapun key-nya, key dari tag tidak sama dengan key cache response
+func (c *readCache) Invalidate(ctx context.Context, keys ...string) (err error) {
+	if len(keys) == 0 {
+		return
+	}
+	c.Purge()
+	return
+}
+
+func (c *readCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
+
+// InvalidateCache dipanggil modul lain yang mengubah produk langsung di database, contoh import produk
+func InvalidateCache() {
+	catalogCache.Purge()
+}
+
+func cacheTTL() time.Duration {
+	if ttl := config.Cfg.App.Product.CacheTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultCacheTTL
+}
+
+func cacheMaxEntries() int {
+	if maxEntries := config.Cfg.App.Product.CacheMaxEntries; maxEntries > 0 {
+		return maxEntries
+	}
+	return defaultCacheMaxEntries
+}
+
+func cacheControl() string {
+	if control := config.Cfg.App.Product.CacheControl; control != "" {
+		return control
+	}
+	return defaultCacheControl
+}
+
+// LastModified updated_at terbaru dari daftar produk, nol jika daftar kosong
+func LastModified(products []Product) (lastModified time.Time) {
+	for _, product := range products {
+		if product.UpdatedAt.After(lastModified) {
+			lastModified = product.UpdatedAt
+		}
+	}
+	return
+}
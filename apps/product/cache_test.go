@@ -0,0 +1,67 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"Ecommerce-basic/infra/gin"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCache(t *testing.T) {
+	now := time.Now()
+	value := cachedResponse{Representation: infragin.Representation{ETag: `"a"`}, ProductId: 7}
+
+	t.Run("hit until expired", func(t *testing.T) {
+		cache := newReadCache()
+		cache.Set("sku:A", value, cache.Generation(), now, time.Minute, 10)
+
+		cached, ok := cache.Get("sku:A", now.Add(30*time.Second))
+		require.True(t, ok)
+		require.Equal(t, 7, cached.ProductId)
+
+		_, ok = cache.Get("sku:A", now.Add(time.Minute))
+		require.False(t, ok)
+	})
+	t.Run("purge skips stale reads", func(t *testing.T) {
+		cache := newReadCache()
+		generation := cache.Generation()
+		cache.Purge()
+
+		// response dibaca sebelum produk berubah tidak boleh disimpan
+		cache.Set("sku:A", value, generation, now, time.Minute, 10)
+		_, ok := cache.Get("sku:A", now)
+		require.False(t, ok)
+	})
+	t.Run("bounded entries", func(t *testing.T) {
+		cache := newReadCache()
+		cache.Set("expired", value, cache.Generation(), now.Add(-time.Hour), time.Minute, 2)
+		cache.Set("b", value, cache.Generation(), now, time.Minute, 2)
+		cache.Set("c", value, cache.Generation(), now, time.Minute, 2)
+
+		require.Equal(t, 2, cache.Len())
+		_, ok := cache.Get("expired", now)
+		require.False(t, ok)
+	})
+	t.Run("purged by tag from other modules", func(t *testing.T) {
+		ctx := context.Background()
+
+		// contoh: checkout mengubah stok lewat SKU, reservasi lewat id produk
+		for _, tag := range []string{cache.TAG_PRODUCT_SKU, cache.TAG_PRODUCT_CATALOG} {
+			catalogCache.Set("list", value, catalogCache.Generation(), now, time.Minute, 10)
+			require.Nil(t, cache.Invalidate(ctx, tag))
+			require.Equal(t, 1, catalogCache.Len())
+
+			require.Nil(t, cache.Invalidate(ctx, tag, "SKU-1"))
+			require.Equal(t, 0, catalogCache.Len())
+		}
+	})
+}
+
+func TestLastModified(t *testing.T) {
+	now := time.Now()
+	require.True(t, LastModified(nil).IsZero())
+	require.Equal(t, now, LastModified([]Product{{UpdatedAt: now.Add(-time.Hour)}, {UpdatedAt: now}}))
+}
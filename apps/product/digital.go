@@ -0,0 +1,99 @@
+package product
+
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/response"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// batas jumlah key per request dan panjang satu key
+	maxLicenseKeysPerRequest = 1000
+	maxLicenseKeyLength      = 255
+)
+
+var fileExtensionPattern = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+// LicenseKey adalah satu key dari pool license key produk digital, dipakai sekali oleh satu transaksi
+type LicenseKey struct {
+	Id            int       `db:"id"`
+	ProductId     int       `db:"product_id"`
+	Key           string    `db:"license_key"`
+	TransactionId *int      `db:"transaction_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// LicenseKeySummary jumlah key per produk, isi key tidak pernah ditampilkan ke admin lagi
+type LicenseKeySummary struct {
+	Total     int `db:"total"`
+	Available int `db:"available"`
+	Assigned  int `db:"assigned"`
+}
+
+// NewLicenseKeys memvalidasi key yang ditambahkan admin, key yang sama dalam satu request ditolak
+func (p Product) NewLicenseKeys(keys []string) (licenseKeys []LicenseKey, err error) {
+	if !p.IsDigital() {
+		return nil, response.ErrProductNotDigital
+	}
+	if len(keys) == 0 || len(keys) > maxLicenseKeysPerRequest {
+		return nil, response.ErrLicenseKeysInvalid
+	}
+
+	seen := map[string]bool{}
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || len(key) > maxLicenseKeyLength || seen[key] {
+			return nil, response.ErrLicenseKeysInvalid
+		}
+		seen[key] = true
+
+		licenseKeys = append(licenseKeys, LicenseKey{
+			ProductId: p.Id,
+			Key:       key,
+			CreatedAt: time.Now(),
+		})
+	}
+	return
+}
+
+// AddLicenseKeyStock stok bertambah sebanyak key baru supaya stok selalu sama dengan key yang tersedia
+func (p *Product) AddLicenseKeyStock(added int) (movement inventory.StockMovement, err error) {
+	if int(p.Stock)+added > math.MaxInt16 {
+		return movement, response.ErrLicenseKeysInvalid
+	}
+
+	p.Stock += int16(added)
+	p.Version++
+	p.UpdatedAt = time.Now()
+	return inventory.NewStockMovement(p.Id, inventory.MovementType_Restock, added, int(p.Stock), inventory.REASON_LICENSE_KEYS), nil
+}
+
+// NewDigitalFileKey setiap upload mendapat key baru, file lama tetap ada untuk link download
+// yang sudah terlanjur dibuat
+func (p Product) NewDigitalFileKey(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !fileExtensionPattern.MatchString(ext) {
+		ext = ""
+	}
+	return fmt.Sprintf("products/%d/%s%s", p.Id, uuid.NewString(), ext)
+}
+
+// SetDigitalFile nama file asli dipakai sebagai nama file ketika pembeli mengunduh
+func (p *Product) SetDigitalFile(fileKey string, filename string) (err error) {
+	if !p.IsDigital() {
+		return response.ErrProductNotDigital
+	}
+
+	p.FileKey = fileKey
+	p.FileName = filepath.Base(strings.ReplaceAll(filename, `\`, "/"))
+	p.Version++
+	p.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,75 @@
+package product
+
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/response"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProductType(t *testing.T) {
+	productType, err := ParseProductType("")
+	require.Nil(t, err)
+	require.Equal(t, ProductType_Standard, productType)
+
+	productType, err = ParseProductType("digital")
+	require.Nil(t, err)
+	require.True(t, Product{Type: productType}.IsDigital())
+
+	_, err = ParseProductType("BUNDLE")
+	require.Equal(t, response.ErrProductTypeInvalid, err)
+}
+
+func TestNewLicenseKeys(t *testing.T) {
+	ebook := Product{Id: 5, Type: ProductType_Digital}
+
+	t.Run("success", func(t *testing.T) {
+		keys, err := ebook.NewLicenseKeys([]string{" KEY-1 ", "KEY-2"})
+		require.Nil(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, "KEY-1", keys[0].Key)
+		require.Equal(t, 5, keys[0].ProductId)
+	})
+	t.Run("not digital", func(t *testing.T) {
+		_, err := Product{Type: ProductType_Standard}.NewLicenseKeys([]string{"KEY-1"})
+		require.Equal(t, response.ErrProductNotDigital, err)
+	})
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, keys := range [][]string{nil, {"KEY-1", "KEY-1"}, {" "}, {strings.Repeat("k", 256)}} {
+			_, err := ebook.NewLicenseKeys(keys)
+			require.Equal(t, response.ErrLicenseKeysInvalid, err)
+		}
+	})
+}
+
+func TestAddLicenseKeyStock(t *testing.T) {
+	ebook := Product{Id: 5, Type: ProductType_Digital, Stock: 3, Version: 1}
+
+	movement, err := ebook.AddLicenseKeyStock(2)
+	require.Nil(t, err)
+	require.Equal(t, int16(5), ebook.Stock)
+	require.Equal(t, 2, ebook.Version)
+	require.Equal(t, 2, movement.Quantity)
+	require.Equal(t, 5, movement.StockAfter)
+	require.Equal(t, inventory.REASON_LICENSE_KEYS, movement.ReasonCode)
+
+	_, err = ebook.AddLicenseKeyStock(40_000)
+	require.Equal(t, response.ErrLicenseKeysInvalid, err)
+}
+
+func TestDigitalFile(t *testing.T) {
+	ebook := Product{Id: 5, Type: ProductType_Digital}
+
+	key := ebook.NewDigitalFileKey("Belajar Go.PDF")
+	require.True(t, strings.HasPrefix(key, "products/5/"))
+	require.True(t, strings.HasSuffix(key, ".pdf"))
+	require.NotEqual(t, key, ebook.NewDigitalFileKey("Belajar Go.PDF"))
+
+	require.Nil(t, ebook.SetDigitalFile(key, `C:\docs\Belajar Go.PDF`))
+	require.Equal(t, "Belajar Go.PDF", ebook.FileName)
+
+	shirt := Product{Type: ProductType_Standard}
+	require.Equal(t, response.ErrProductNotDigital, shirt.SetDigitalFile(key, "a.pdf"))
+}
@@ -1,48 +1,101 @@
 package product
 
 import (
+	"Ecommerce-basic/apps/attribute"
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
+	"Ecommerce-basic/infra/pagination"
 	"Ecommerce-basic/infra/response"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"time"
-
-	"github.com/google/uuid"
 )
 
 type Product struct {
 	Id        int        `db:"id"`
 	SKU       string     `db:"sku"`
+	Slug      string     `db:"slug"` // unik dan tidak berubah ketika nama diganti
 	Name      string     `db:"name"`
 	Stock     int16      `db:"stock"`
 	Price     int        `db:"price"`
 	CreatedAt time.Time  `db:"created_at"`
 	UpdatedAt time.Time  `db:"updated_at"`
 	DeletedAt *time.Time `db:"deleted_at"` // Soft delete
-}
 
-type UpdateProductRequestPayload struct {
-	Name  string `json:"name"`
-	Stock int16  `json:"stock"`
-	Price int    `json:"price"`
+	// naik setiap kali name, stock, price atau reorder threshold berubah, dipakai untuk ETag / If-Match
+	Version int `db:"version"`
+
+	// status lifecycle, lihat lifecycle.go
+	Status      ProductStatus `db:"status"`
+	PublishAt   *time.Time    `db:"publish_at"`
+	UnpublishAt *time.Time    `db:"unpublish_at"`
+
+	// STANDARD atau BUNDLE, lihat type.go
+	Type ProductType `db:"type"`
+
+	// isi bundle, diisi oleh service untuk menghitung available
+	Components []BundleComponent `db:"-"`
+
+	// file produk digital di BlobStore, kosong jika produk hanya menjual license key
+	FileKey  string `db:"file_key"`
+	FileName string `db:"file_name"`
+
+	// nilai atribut per code, lihat modul attribute. Specifications diisi service untuk detail produk
+	Attributes     attribute.Values          `db:"attributes"`
+	Specifications []attribute.Specification `db:"-"`
+
+	// jumlah terjual, hanya terisi ketika sort popularity
+	Sold int `db:"sold"`
+
+	// stok per gudang, diisi oleh service setelah produk diambil
+	Locations []inventory.WarehouseStock `db:"-"`
+
+	// stok yang sedang ditahan reservation aktif, diisi oleh service
+	Reserved int `db:"-"`
+
+	// agregat review yang terlihat publik, dijaga oleh modul review
+	RatingCount int `db:"rating_count"`
+	RatingSum   int `db:"rating_sum"`
 }
 
-type ProductPagination struct {
-	Cursor int `json:"cursor"`
-	Size   int `json:"size"`
+type UpdateProductRequestPayload struct {
+	Name         string `json:"name"`
+	Stock        int16  `json:"stock"`
+	Price        int    `json:"price"`
+	IfMatch      string `json:"-"`
+	UserPublicId string `json:"-"`
 }
 
-func NewProductPaginationFromListProductRequest(req ListProductRequestPayload) ProductPagination {
-	req = req.GenerateDefaultValue()
-	return ProductPagination{
-		Cursor: req.Cursor,
-		Size:   req.Size,
+func NewProductQueryFromListProductRequest(req ListProductRequestPayload, page pagination.Request) ProductQuery {
+	return ProductQuery{
+		Keyword:      req.Keyword,
+		NamePrefix:   req.NamePrefix,
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		MinStock:     req.MinStock,
+		MaxStock:     req.MaxStock,
+		InStock:      req.InStock,
+		CreatedAfter: req.CreatedAfter,
+		Sort:         req.Sort,
+		Page:         page,
+		AllStatuses:  req.AllStatuses,
+		Status:       req.Status,
 	}
 }
 
 func NewProductFromCreateProductRequest(req CreateProductRequestPayload) Product {
+	// tipe yang tidak dikenal tetap 0 dan ditolak oleh Validate
+	productType, _ := ParseProductType(req.Type)
+
 	return Product{
-		SKU:       uuid.NewString(),
+		SKU:       strings.TrimSpace(req.SKU), // kosong berarti di-generate saat disimpan
 		Name:      req.Name,
 		Stock:     req.Stock,
 		Price:     req.Price,
+		Status:    ProductStatus_Draft,
+		Type:      productType,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -55,12 +108,35 @@ func (p Product) Validate() (err error) {
 	if err = p.ValidatePrice(); err != nil {
 		return
 	}
+	// stok bundle selalu 0, stok yang tampil dihitung dari komponen
+	if p.IsBundle() {
+		return
+	}
+	// produk digital boleh dibuat tanpa stok, stok bertambah ketika license key ditambahkan
+	if p.IsDigital() && p.Stock == 0 {
+		return
+	}
 	if err = p.ValidateStock(); err != nil {
 		return
 	}
 	return
 }
 
+// ValidateUpdate dipakai PUT dan PATCH, stok 0 boleh supaya produk yang habis tetap bisa diubah
+// nama atau harganya. Stok awal tetap harus lebih dari 0 lewat Validate
+func (p Product) ValidateUpdate() (err error) {
+	if err = p.ValidateName(); err != nil {
+		return
+	}
+	if err = p.ValidatePrice(); err != nil {
+		return
+	}
+	if p.Stock < 0 {
+		return response.ErrStockInvalid
+	}
+	return
+}
+
 func (p Product) ValidateName() (err error) {
 	if p.Name == "" {
 		return response.ErrProductRequired
@@ -88,3 +164,124 @@ func (p Product) ValidatePrice() (err error) {
 func (p Product) IsDeleted() bool {
 	return p.DeletedAt != nil
 }
+
+// ETag berasal dari id dan version, berubah setiap kali produk diubah
+func (p Product) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, p.Id, p.Version)
+}
+
+// ContentETag ETag detail produk publik: id dan version untuk If-Match, ditambah hash isi response
+// supaya berubah juga ketika stok, rating atau harga sale berubah tanpa version naik
+func (p Product) ContentETag(contentHash string) string {
+	return fmt.Sprintf(`"%d-%d-%s"`, p.Id, p.Version, contentHash)
+}
+
+// MatchETag memeriksa header If-Match, kosong berarti tanpa precondition.
+// ETag dari ContentETag cocok selama version-nya sama
+func (p Product) MatchETag(ifMatch string) bool {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return true
+	}
+
+	etag := p.ETag()
+	contentPrefix := strings.TrimSuffix(etag, `"`) + "-"
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == etag || (strings.HasPrefix(candidate, contentPrefix) && strings.HasSuffix(candidate, `"`)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyUpdate mengganti semua field yang bisa diubah dari PUT, stok bundle diabaikan
+func (p *Product) ApplyUpdate(req UpdateProductRequestPayload) {
+	p.Name = req.Name
+	if !p.IsBundle() {
+		p.Stock = req.Stock
+	}
+	p.Price = req.Price
+}
+
+// ApplyMergePatch menerapkan JSON Merge Patch (RFC 7396), field yang tidak dikirim tidak berubah.
+// semua field wajib ada, sehingga null dan field yang tidak dikenal ditolak
+func (p *Product) ApplyMergePatch(patch map[string]json.RawMessage) (err error) {
+	if len(patch) == 0 {
+		return response.ErrProductPatchInvalid
+	}
+
+	for field, value := range patch {
+		if string(value) == "null" {
+			return response.ErrProductPatchInvalid
+		}
+
+		var target interface{}
+		switch field {
+		case "name":
+			target = &p.Name
+		case "stock":
+			target = &p.Stock
+			if p.IsBundle() {
+				target = new(int16)
+			}
+		case "price":
+			target = &p.Price
+		default:
+			return response.ErrProductPatchInvalid
+		}
+
+		if err = json.Unmarshal(value, target); err != nil {
+			return response.ErrProductPatchInvalid
+		}
+	}
+	return
+}
+
+// Restore mengembalikan produk yang sudah di-soft delete
+func (p *Product) Restore() {
+	p.DeletedAt = nil
+	p.UpdatedAt = time.Now()
+	p.Version++
+}
+
+// Available adalah stok yang masih bisa dibeli setelah dikurangi reservation aktif
+func (p Product) Available() int {
+	if p.IsBundle() {
+		return p.BundleAvailable()
+	}
+	return inventory.Available(int(p.Stock), p.Reserved)
+}
+
+// AverageRating dibulatkan satu angka di belakang koma, 0 jika belum ada review
+func (p Product) AverageRating() float64 {
+	if p.RatingCount == 0 {
+		return 0
+	}
+	return math.Round(float64(p.RatingSum)/float64(p.RatingCount)*10) / 10
+}
+
+func (p Product) NewInitialPriceChange() pricing.PriceChange {
+	return pricing.NewPriceChange(p.Id, 0, p.Price, pricing.REASON_INITIAL_PRICE)
+}
+
+// NewPriceChange mencatat perubahan harga dari update produk, changed false jika harga tidak berubah
+func (p Product) NewPriceChange(previousPrice int) (change pricing.PriceChange, changed bool) {
+	if p.Price == previousPrice {
+		return change, false
+	}
+	return pricing.NewPriceChange(p.Id, previousPrice, p.Price, pricing.REASON_MANUAL), true
+}
+
+func (p Product) NewInitialStockMovement() inventory.StockMovement {
+	return inventory.NewStockMovement(p.Id, inventory.MovementType_Restock, int(p.Stock), int(p.Stock), inventory.REASON_INITIAL_STOCK)
+}
+
+// NewStockChangeMovement mencatat selisih stok dari update produk, changed false jika stok tidak berubah
+func (p Product) NewStockChangeMovement(previousStock int16) (movement inventory.StockMovement, changed bool) {
+	delta := int(p.Stock) - int(previousStock)
+	if delta == 0 {
+		return movement, false
+	}
+	return inventory.NewStockMovement(p.Id, inventory.MovementType_Adjustment, delta, int(p.Stock), inventory.REASON_PRODUCT_UPDATE), true
+}
@@ -1,8 +1,12 @@
 package product
 
 import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
 	"Ecommerce-basic/infra/response"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -63,3 +67,125 @@ func TestValidateProduct(t *testing.T) {
 		require.Equal(t, response.ErrPriceInvalid, err)
 	})
 }
+
+func TestValidateUpdate(t *testing.T) {
+	t.Run("sold out product can be renamed", func(t *testing.T) {
+		product := Product{Name: "baju baru", Stock: 0, Price: 10_000}
+		require.Nil(t, product.ValidateUpdate())
+		require.Equal(t, response.ErrStockInvalid, product.Validate())
+	})
+	t.Run("negative stock", func(t *testing.T) {
+		product := Product{Name: "baju", Stock: -1, Price: 10_000}
+		require.Equal(t, response.ErrStockInvalid, product.ValidateUpdate())
+	})
+	t.Run("price still required", func(t *testing.T) {
+		product := Product{Name: "baju", Stock: 0, Price: 0}
+		require.Equal(t, response.ErrPriceInvalid, product.ValidateUpdate())
+	})
+}
+
+func TestNewStockChangeMovement(t *testing.T) {
+	t.Run("stock changed", func(t *testing.T) {
+		product := Product{Id: 1, Stock: 7}
+
+		movement, changed := product.NewStockChangeMovement(10)
+		require.True(t, changed)
+		require.Equal(t, -3, movement.Quantity)
+		require.Equal(t, 7, movement.StockAfter)
+		require.Equal(t, inventory.REASON_PRODUCT_UPDATE, movement.ReasonCode)
+	})
+	t.Run("stock unchanged", func(t *testing.T) {
+		product := Product{Id: 1, Stock: 10}
+
+		_, changed := product.NewStockChangeMovement(10)
+		require.False(t, changed)
+	})
+}
+
+func TestNewPriceChange(t *testing.T) {
+	t.Run("price changed", func(t *testing.T) {
+		product := Product{Id: 1, Price: 12_000}
+
+		change, changed := product.NewPriceChange(10_000)
+		require.True(t, changed)
+		require.Equal(t, 10_000, change.OldPrice)
+		require.Equal(t, 12_000, change.NewPrice)
+		require.Equal(t, pricing.REASON_MANUAL, change.Reason)
+	})
+	t.Run("price unchanged", func(t *testing.T) {
+		product := Product{Id: 1, Price: 10_000}
+
+		_, changed := product.NewPriceChange(10_000)
+		require.False(t, changed)
+	})
+}
+
+func TestAverageRating(t *testing.T) {
+	t.Run("no reviews", func(t *testing.T) {
+		require.Equal(t, float64(0), Product{}.AverageRating())
+	})
+	t.Run("rounded", func(t *testing.T) {
+		product := Product{RatingCount: 3, RatingSum: 13}
+
+		require.Equal(t, 4.3, product.AverageRating())
+	})
+}
+
+func TestRestoreProduct(t *testing.T) {
+	deletedAt := time.Now().Add(-time.Hour)
+	product := Product{Id: 1, DeletedAt: &deletedAt}
+	require.True(t, product.IsDeleted())
+
+	product.Restore()
+	require.False(t, product.IsDeleted())
+	require.True(t, product.UpdatedAt.After(deletedAt))
+}
+
+func TestMatchETag(t *testing.T) {
+	product := Product{Id: 7, Version: 3}
+	require.Equal(t, `"7-3"`, product.ETag())
+
+	t.Run("without precondition", func(t *testing.T) {
+		require.True(t, product.MatchETag(""))
+		require.True(t, product.MatchETag("*"))
+	})
+	t.Run("match", func(t *testing.T) {
+		require.True(t, product.MatchETag(`"7-3"`))
+		require.True(t, product.MatchETag(`"7-2", "7-3"`))
+	})
+	t.Run("stale", func(t *testing.T) {
+		require.False(t, product.MatchETag(`"7-2"`))
+		require.False(t, product.MatchETag(`W/"7-3"`))
+	})
+	t.Run("content etag from detail", func(t *testing.T) {
+		require.Equal(t, `"7-3-abc"`, product.ContentETag("abc"))
+		require.True(t, product.MatchETag(product.ContentETag("abc")))
+		require.False(t, product.MatchETag(`"7-31-abc"`))
+		require.False(t, product.MatchETag(`"7-2-abc"`))
+	})
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Run("only sent fields change", func(t *testing.T) {
+		product := Product{Name: "Baju Baru", Stock: 10, Price: 10_000}
+
+		err := product.ApplyMergePatch(map[string]json.RawMessage{"price": json.RawMessage(`12000`)})
+		require.Nil(t, err)
+		require.Equal(t, "Baju Baru", product.Name)
+		require.Equal(t, int16(10), product.Stock)
+		require.Equal(t, 12_000, product.Price)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		patches := []map[string]json.RawMessage{
+			{},
+			{"name": json.RawMessage(`null`)},
+			{"sku": json.RawMessage(`"new-sku"`)},
+			{"stock": json.RawMessage(`"ten"`)},
+		}
+		for _, patch := range patches {
+			product := Product{Name: "Baju Baru", Stock: 10, Price: 10_000}
+
+			require.Equal(t, response.ErrProductPatchInvalid, product.ApplyMergePatch(patch))
+		}
+	})
+}
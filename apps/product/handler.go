@@ -1,21 +1,30 @@
 package product
 
 import (
+	"Ecommerce-basic/apps/inventory"
 	"Ecommerce-basic/infra/gin"
 	"Ecommerce-basic/infra/response"
+	"context"
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// batas ukuran file produk digital yang boleh diupload, 100 MB
+const maxDigitalFileSize = 100 << 20
+
 type handler struct {
-	svc service
+	svc   service
+	views *viewQueue
 }
 
-func newHandler(svc service) handler {
+func newHandler(svc service, views *viewQueue) handler {
 	return handler{
-		svc: svc,
+		svc:   svc,
+		views: views,
 	}
 }
 
@@ -31,6 +40,7 @@ func (h handler) CreateProduct(ctx *gin.Context) {
 		resp.Send(ctx)
 		return
 	}
+	req.UserPublicId = ctx.GetString("PUBLIC_ID")
 
 	if err := h.svc.CreateProduct(ctx, req); err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
@@ -52,10 +62,10 @@ func (h handler) CreateProduct(ctx *gin.Context) {
 	resp.Send(ctx)
 }
 
-func (h handler) GetListProducts(ctx *gin.Context) {
-	var req ListProductRequestPayload
+func (h handler) CreateBundle(ctx *gin.Context) {
+	var req CreateBundleRequestPayload
 
-	if err := ctx.ShouldBindQuery(&req); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		resp := infragin.NewResponse(
 			infragin.WithHttpCode(http.StatusBadRequest),
 			infragin.WithMessage("invalid payload"),
@@ -64,9 +74,9 @@ func (h handler) GetListProducts(ctx *gin.Context) {
 		resp.Send(ctx)
 		return
 	}
+	req.UserPublicId = ctx.GetString("PUBLIC_ID")
 
-	products, err := h.svc.ListProducts(ctx, req)
-	if err != nil {
+	if err := h.svc.CreateBundle(ctx, req); err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
 		if !ok {
 			myErr = response.ErrorGeneral
@@ -79,30 +89,335 @@ func (h handler) GetListProducts(ctx *gin.Context) {
 		return
 	}
 
-	productListResponse := NewProductListResponseFromEntity(products)
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("create bundle success"),
+	)
+	resp.Send(ctx)
+}
+
+func (h handler) UpdateBundleComponents(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req UpdateBundleComponentsRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	bundle, err := h.svc.UpdateBundleComponents(c.Request.Context(), productID, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
 
+	c.Header("ETag", bundle.ETag())
 	resp := infragin.NewResponse(
 		infragin.WithHttpCode(http.StatusOK),
-		infragin.WithMessage("get list products success"),
-		infragin.WithPayload(productListResponse),
-		infragin.WithQuery(req.GenerateDefaultValue()),
+		infragin.WithMessage("update bundle components success"),
+		infragin.WithPayload(NewBundleComponentListResponse(bundle.Components)),
 	)
-	resp.Send(ctx)
+	resp.Send(c)
+}
+
+func (h handler) UpdateProductAttributes(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req UpdateProductAttributesRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	product, err := h.svc.UpdateProductAttributes(c.Request.Context(), productID, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	c.Header("ETag", product.ETag())
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("update product attributes success"),
+		infragin.WithPayload(NewProductAttributeListResponse(product.Specifications)),
+	)
+	resp.Send(c)
+}
+
+func (h handler) AddLicenseKeys(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req AddLicenseKeysRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+	req.UserPublicId = c.GetString("PUBLIC_ID")
+
+	summary, err := h.svc.AddLicenseKeys(c.Request.Context(), productID, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("add license keys success"),
+		infragin.WithPayload(summary.ToLicenseKeySummaryResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetLicenseKeySummary(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	summary, err := h.svc.LicenseKeySummary(c.Request.Context(), productID)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get license keys success"),
+		infragin.WithPayload(summary.ToLicenseKeySummaryResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) UploadDigitalFile(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(response.ErrDigitalFileRequired.Error()),
+			infragin.WithError(response.ErrorDigitalFileRequired),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if fileHeader.Size > maxDigitalFileSize {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusRequestEntityTooLarge),
+			infragin.WithMessage(response.ErrDigitalFileTooLarge.Error()),
+			infragin.WithError(response.ErrorDigitalFileTooLarge),
+		)
+		resp.Send(c)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorGeneral),
+		)
+		resp.Send(c)
+		return
+	}
+	defer file.Close()
+
+	product, err := h.svc.UploadDigitalFile(c.Request.Context(), productID, fileHeader.Filename, file)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	c.Header("ETag", product.ETag())
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("upload digital file success"),
+		infragin.WithPayload(DigitalFileResponse{FileName: product.FileName}),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetListProducts(ctx *gin.Context) {
+	var req ListProductRequestPayload
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(ctx)
+		return
+	}
+
+	h.sendProductList(ctx, req, "get list products success")
 }
 
 func (h handler) GetProductDetail(ctx *gin.Context) {
 	sku := ctx.Param("sku")
-	if sku == "" {
+	if productId, ok := h.sendProductDetail(ctx, sku, "invalid SKU", h.svc.ProductDetail, "sku:"+sku); ok {
+		h.recordView(ctx, productId)
+	}
+}
+
+// GetAdminProductDetail sama dengan GetProductDetail tetapi juga menampilkan produk DRAFT dan ARCHIVED
+func (h handler) GetAdminProductDetail(ctx *gin.Context) {
+	h.sendProductDetail(ctx, ctx.Param("sku"), "invalid SKU", h.svc.AdminProductDetail, "")
+}
+
+func (h handler) GetProductDetailBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	if productId, ok := h.sendProductDetail(ctx, slug, "invalid slug", h.svc.ProductDetailBySlug, "slug:"+slug); ok {
+		h.recordView(ctx, productId)
+	}
+}
+
+// recordView view tanpa user maupun session id dibuang, signature session id diperiksa saat view dicatat
+func (h handler) recordView(ctx *gin.Context, productId int) {
+	view := NewProductView(ctx.GetString("PUBLIC_ID"), ctx.GetHeader(HEADER_SESSION_ID), productId, time.Now())
+	if view.UserPublicId == "" && view.SessionId == "" {
+		return
+	}
+	h.views.Enqueue(view)
+}
+
+// CreateViewSession membuat session id untuk header X-Session-Id pengunjung yang belum login,
+// dibuat lewat endpoint sendiri karena response detail produk boleh di-cache bersama
+func (h handler) CreateViewSession(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusCreated),
+		infragin.WithMessage("create view session success"),
+		infragin.WithPayload(ViewSessionResponse{SessionId: h.svc.NewViewSession()}),
+	)
+	resp.Send(ctx)
+}
+
+func (h handler) GetAdminProductDetailBySlug(ctx *gin.Context) {
+	h.sendProductDetail(ctx, ctx.Param("slug"), "invalid slug", h.svc.AdminProductDetailBySlug, "")
+}
+
+// sendProductDetail dipakai semua endpoint detail, key adalah SKU atau slug dari path.
+// endpoint publik mengisi cacheKey supaya response diambil dari catalogCache dan mendukung conditional GET
+func (h handler) sendProductDetail(ctx *gin.Context, key string, invalidMessage string, detail func(ctx context.Context, key string) (Product, error), cacheKey string) (productId int, ok bool) {
+	if key == "" {
 		resp := infragin.NewResponse(
 			infragin.WithHttpCode(http.StatusBadRequest),
-			infragin.WithMessage("invalid SKU"),
+			infragin.WithMessage(invalidMessage),
 			infragin.WithError(response.ErrorBadRequest),
 		)
 		resp.Send(ctx)
-		return
+		return 0, false
+	}
+
+	now := time.Now()
+	if cacheKey != "" {
+		if cached, hit := catalogCache.Get(cacheKey, now); hit {
+			cached.Send(ctx, cacheControl())
+			return cached.ProductId, true
+		}
 	}
+	generation := catalogCache.Generation()
 
-	product, err := h.svc.ProductDetail(ctx, sku)
+	product, err := detail(ctx, key)
 	if err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
 		if !ok {
@@ -113,17 +428,33 @@ func (h handler) GetProductDetail(ctx *gin.Context) {
 			infragin.WithError(myErr),
 		)
 		resp.Send(ctx)
-		return
+		return 0, false
 	}
 
 	productDetail := ProductDetailResponse{
 		Id:        product.Id,
 		Name:      product.Name,
 		SKU:       product.SKU,
+		Slug:      product.Slug,
 		Stock:     product.Stock,
 		Price:     product.Price,
 		CreatedAt: product.CreatedAt,
 		UpdatedAt: product.UpdatedAt,
+		Available: product.Available(),
+		Locations: inventory.NewStockLocationListResponse(product.Locations),
+
+		AverageRating: product.AverageRating(),
+		ReviewCount:   product.RatingCount,
+
+		Status:      product.GetStatus(),
+		PublishAt:   product.PublishAt,
+		UnpublishAt: product.UnpublishAt,
+		Type:        product.GetType(),
+		Attributes:  NewProductAttributeListResponse(product.Specifications),
+		FileName:    product.FileName,
+	}
+	if product.IsBundle() {
+		productDetail.Components = NewBundleComponentListResponse(product.Components)
 	}
 
 	resp := infragin.NewResponse(
@@ -131,7 +462,39 @@ func (h handler) GetProductDetail(ctx *gin.Context) {
 		infragin.WithMessage("get product detail success"),
 		infragin.WithPayload(productDetail),
 	)
-	resp.Send(ctx)
+	if cacheKey == "" {
+		ctx.Header("ETag", product.ETag())
+		resp.Send(ctx)
+		return product.Id, true
+	}
+
+	if !h.sendCached(ctx, resp, product.UpdatedAt, cacheKey, generation, now, func(rep *infragin.Representation) {
+		rep.ETag = product.ContentETag(infragin.ContentHash(rep.Body))
+	}, product.Id) {
+		return 0, false
+	}
+	return product.Id, true
+}
+
+// sendCached menyimpan response endpoint publik ke catalogCache lalu mengirimnya dengan ETag,
+// Last-Modified dan Cache-Control
+func (h handler) sendCached(c *gin.Context, resp infragin.Response, lastModified time.Time, cacheKey string, generation uint64, now time.Time, etag func(rep *infragin.Representation), productId int) bool {
+	rep, err := infragin.NewRepresentation(resp, lastModified)
+	if err != nil {
+		errResp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorGeneral),
+		)
+		errResp.Send(c)
+		return false
+	}
+	if etag != nil {
+		etag(&rep)
+	}
+
+	catalogCache.Set(cacheKey, cachedResponse{Representation: rep, ProductId: productId}, generation, now, cacheTTL(), cacheMaxEntries())
+	rep.Send(c, cacheControl())
+	return true
 }
 
 func (h handler) UpdateProduct(c *gin.Context) {
@@ -157,8 +520,60 @@ func (h handler) UpdateProduct(c *gin.Context) {
 		resp.Send(c)
 		return
 	}
+	req.UserPublicId = c.GetString("PUBLIC_ID")
+	req.IfMatch = c.GetHeader("If-Match")
+
+	product, err := h.svc.UpdateProduct(c.Request.Context(), productID, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	c.Header("ETag", product.ETag())
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("update product success"),
+	)
+	resp.Send(c)
+}
+
+// PatchProduct menerima JSON Merge Patch (application/merge-patch+json)
+func (h handler) PatchProduct(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
 
-	if err := h.svc.UpdateProduct(c.Request.Context(), productID, req); err != nil {
+	req := PatchProductRequestPayload{
+		IfMatch:      c.GetHeader("If-Match"),
+		UserPublicId: c.GetString("PUBLIC_ID"),
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req.Patch); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	product, err := h.svc.PatchProduct(c.Request.Context(), productID, req)
+	if err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
 		if !ok {
 			myErr = response.ErrorGeneral
@@ -171,6 +586,7 @@ func (h handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", product.ETag())
 	resp := infragin.NewResponse(
 		infragin.WithHttpCode(http.StatusOK),
 		infragin.WithMessage("update product success"),
@@ -212,8 +628,19 @@ func (h handler) DeleteProduct(c *gin.Context) {
 }
 
 func (h handler) SearchProducts(c *gin.Context) {
-	keyword := c.Query("keyword")
-	if keyword == "" {
+	var req ListProductRequestPayload
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if req.Keyword == "" {
 		resp := infragin.NewResponse(
 			infragin.WithHttpCode(http.StatusBadRequest),
 			infragin.WithMessage("keyword is required"),
@@ -223,12 +650,88 @@ func (h handler) SearchProducts(c *gin.Context) {
 		return
 	}
 
-	pagination := ProductPagination{
-		Cursor: 0,
-		Size:   10,
+	h.sendProductList(c, req, "search products success")
+}
+
+// GetAdminListProducts menampilkan produk semua status, bisa difilter dengan query status
+func (h handler) GetAdminListProducts(c *gin.Context) {
+	var req ListProductRequestPayload
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
 	}
+	req.AllStatuses = true
 
-	products, err := h.svc.SearchProducts(c.Request.Context(), keyword, pagination)
+	h.sendProductList(c, req, "get list products success")
+}
+
+// FilterProducts dipertahankan untuk client lama, filter yang sama tersedia di GET /products
+func (h handler) FilterProducts(c *gin.Context) {
+	var req ListProductRequestPayload
+	var legacy LegacyFilterRequestPayload
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := c.ShouldBindQuery(&legacy); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	h.sendProductList(c, legacy.Apply(req), "filter products success")
+}
+
+// sendProductList daftar produk publik diambil dari catalogCache berdasarkan path dan query,
+// daftar produk admin selalu dibaca dari database
+func (h handler) sendProductList(c *gin.Context, req ListProductRequestPayload, message string) {
+	req.Attributes = c.QueryMap("attr")
+	req.AttributeMin = c.QueryMap("attr_min")
+	req.AttributeMax = c.QueryMap("attr_max")
+
+	var cacheKey string
+	now := time.Now()
+	if !req.AllStatuses {
+		cacheKey = "list:" + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+		if cached, hit := catalogCache.Get(cacheKey, now); hit {
+			cached.Send(c, cacheControl())
+			return
+		}
+	}
+	generation := catalogCache.Generation()
+
+	products, pageMeta, err := h.svc.ListProducts(c.Request.Context(), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	facets, err := h.svc.ProductFacets(c.Request.Context(), req)
 	if err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
 		if !ok {
@@ -243,28 +746,116 @@ func (h handler) SearchProducts(c *gin.Context) {
 	}
 
 	productListResponse := NewProductListResponseFromEntity(products)
+	meta := ProductListMetaResponse{
+		Meta:   pageMeta,
+		Facets: NewProductFacetsResponseFromEntity(facets),
+	}
 
 	resp := infragin.NewResponse(
 		infragin.WithHttpCode(http.StatusOK),
-		infragin.WithMessage("search products success"),
+		infragin.WithMessage(message),
 		infragin.WithPayload(productListResponse),
+		infragin.WithMeta(meta),
+	)
+	if cacheKey == "" {
+		resp.Send(c)
+		return
+	}
+	h.sendCached(c, resp, LastModified(products), cacheKey, generation, now, nil, 0)
+}
+
+func (h handler) GetDeletedProducts(c *gin.Context) {
+	var req ListDeletedProductRequestPayload
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	products, meta, err := h.svc.DeletedProducts(c.Request.Context(), req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get deleted products success"),
+		infragin.WithPayload(NewDeletedProductListResponseFromEntity(products)),
+		infragin.WithMeta(meta),
 	)
 	resp.Send(c)
 }
 
-func (h handler) FilterProducts(c *gin.Context) {
-	minPrice, _ := strconv.Atoi(c.Query("minPrice"))
-	maxPrice, _ := strconv.Atoi(c.Query("maxPrice"))
-	minStock, _ := strconv.Atoi(c.Query("minStock"))
-	maxStock, _ := strconv.Atoi(c.Query("maxStock"))
+func (h handler) RestoreProduct(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
 
-	pagination := ProductPagination{
-		Cursor: 0,
-		Size:   10,
+	product, err := h.svc.RestoreProduct(c.Request.Context(), productID)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
 	}
 
-	products, err := h.svc.FilterProducts(c.Request.Context(), minPrice, maxPrice, int16(minStock), int16(maxStock), pagination)
+	c.Header("ETag", product.ETag())
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("restore product success"),
+		infragin.WithPayload(ProductDetailResponse{
+			Id:        product.Id,
+			SKU:       product.SKU,
+			Name:      product.Name,
+			Stock:     product.Stock,
+			Price:     product.Price,
+			CreatedAt: product.CreatedAt,
+			UpdatedAt: product.UpdatedAt,
+			Available: product.Available(),
+		}),
+	)
+	resp.Send(c)
+}
+
+func (h handler) PurgeProduct(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.PurgeProduct(c.Request.Context(), productID); err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
 		if !ok {
 			myErr = response.ErrorGeneral
@@ -277,12 +868,79 @@ func (h handler) FilterProducts(c *gin.Context) {
 		return
 	}
 
-	productListResponse := NewProductListResponseFromEntity(products)
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("purge product success"),
+	)
+	resp.Send(c)
+}
 
+func (h handler) UpdateProductStatus(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid product ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	var req UpdateProductStatusRequestPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid payload"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	product, err := h.svc.UpdateProductStatus(c.Request.Context(), productID, req)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	c.Header("ETag", product.ETag())
 	resp := infragin.NewResponse(
 		infragin.WithHttpCode(http.StatusOK),
-		infragin.WithMessage("filter products success"),
-		infragin.WithPayload(productListResponse),
+		infragin.WithMessage("update product status success"),
+		infragin.WithPayload(NewProductStatusResponse(product)),
 	)
 	resp.Send(c)
 }
+
+// GetRecentlyViewed riwayat produk yang dilihat user yang login, header X-Session-Id memindahkan view sebelum login
+func (h handler) GetRecentlyViewed(ctx *gin.Context) {
+	products, err := h.svc.RecentlyViewed(ctx.Request.Context(), ctx.GetString("PUBLIC_ID"), ctx.GetHeader(HEADER_SESSION_ID))
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(ctx)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get recently viewed products success"),
+		infragin.WithPayload(NewRecentlyViewedListResponse(products)),
+	)
+	resp.Send(ctx)
+}
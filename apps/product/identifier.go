@@ -0,0 +1,136 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// dipakai jika app.product.sku_pattern tidak diatur atau tidak valid
+	defaultSKUPattern = `^[A-Z0-9][A-Z0-9-]{2,49}$`
+
+	// slug untuk nama yang tidak punya huruf atau angka sama sekali
+	defaultSlug = "product"
+)
+
+var slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ValidateSKU hanya dipanggil untuk SKU yang dikirim admin, SKU hasil generate selalu dianggap valid
+func (p Product) ValidateSKU() (err error) {
+	return validateSKU(p.SKU, config.Cfg.App.Product.SKUPattern)
+}
+
+func validateSKU(sku string, pattern string) (err error) {
+	re, err := regexp.Compile(pattern)
+	if pattern == "" || err != nil {
+		re = regexp.MustCompile(defaultSKUPattern)
+	}
+	if !re.MatchString(sku) {
+		return response.ErrSKUInvalid
+	}
+	return nil
+}
+
+// RenderSKUTemplate mengganti token YYYY, YY dan MM dengan tanggal, dan deretan # dengan nomor urut
+// yang di-pad nol, contoh: CAT-YYYY-##### menjadi CAT-2024-00042.
+// template tanpa # diberi nomor urut di belakang supaya SKU tetap unik
+func RenderSKUTemplate(template string, now time.Time, seq int64) string {
+	var sku strings.Builder
+	hasSequence := false
+
+	for i := 0; i < len(template); {
+		switch {
+		case strings.HasPrefix(template[i:], "YYYY"):
+			sku.WriteString(now.Format("2006"))
+			i += 4
+		case strings.HasPrefix(template[i:], "YY"):
+			sku.WriteString(now.Format("06"))
+			i += 2
+		case strings.HasPrefix(template[i:], "MM"):
+			sku.WriteString(now.Format("01"))
+			i += 2
+		case template[i] == '#':
+			width := 0
+			for i < len(template) && template[i] == '#' {
+				width++
+				i++
+			}
+			sku.WriteString(fmt.Sprintf("%0*d", width, seq))
+			hasSequence = true
+		default:
+			sku.WriteByte(template[i])
+			i++
+		}
+	}
+
+	if !hasSequence {
+		sku.WriteString(strconv.FormatInt(seq, 10))
+	}
+	return sku.String()
+}
+
+// Slugify mengubah nama produk menjadi slug huruf kecil yang dipisah "-"
+func Slugify(name string) string {
+	slug := slugSeparator.ReplaceAllString(strings.ToLower(name), "-")
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return defaultSlug
+	}
+	return slug
+}
+
+// NextSlug memberi akhiran -2, -3, ... jika base sudah dipakai produk lain
+func NextSlug(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+
+	slug := base
+	for n := 2; used[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug
+}
+
+// GenerateSKUWithTx membuat SKU dari app.product.sku_template memakai sequence product_sku_seq,
+// atau UUID jika template tidak diatur
+func GenerateSKUWithTx(ctx context.Context, tx *sqlx.Tx) (sku string, err error) {
+	template := config.Cfg.App.Product.SKUTemplate
+	if template == "" {
+		return uuid.NewString(), nil
+	}
+
+	var seq int64
+	if err = tx.GetContext(ctx, &seq, `SELECT nextval('product_sku_seq')`); err != nil {
+		return
+	}
+	return RenderSKUTemplate(template, time.Now(), seq), nil
+}
+
+// GenerateSlugWithTx membuat slug unik dari nama produk, slug produk yang sudah dihapus
+// ikut dihitung supaya URL lama tidak pernah dipakai produk lain
+func GenerateSlugWithTx(ctx context.Context, tx *sqlx.Tx, name string) (slug string, err error) {
+	base := Slugify(name)
+
+	query := `
+		SELECT slug
+		FROM products
+		WHERE slug = $1 OR slug LIKE $1 || '-%'
+	`
+
+	var taken []string
+	if err = tx.SelectContext(ctx, &taken, query, base); err != nil {
+		return
+	}
+	return NextSlug(base, taken), nil
+}
@@ -0,0 +1,46 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSKU(t *testing.T) {
+	t.Run("default pattern", func(t *testing.T) {
+		require.Nil(t, validateSKU("BJ-2024-001", ""))
+		require.Equal(t, response.ErrSKUInvalid, validateSKU("bj-01", ""))
+		require.Equal(t, response.ErrSKUInvalid, validateSKU("BJ", ""))
+		require.Equal(t, response.ErrSKUInvalid, validateSKU("-BJ-01", ""))
+	})
+	t.Run("configured pattern", func(t *testing.T) {
+		require.Nil(t, validateSKU("CAT-00001", `^CAT-\d{5}$`))
+		require.Equal(t, response.ErrSKUInvalid, validateSKU("BJ-2024-001", `^CAT-\d{5}$`))
+	})
+	t.Run("invalid pattern falls back to default", func(t *testing.T) {
+		require.Nil(t, validateSKU("BJ-01", `[`))
+	})
+}
+
+func TestRenderSKUTemplate(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, "CAT-2024-00042", RenderSKUTemplate("CAT-YYYY-#####", now, 42))
+	require.Equal(t, "PRD2403-7", RenderSKUTemplate("PRDYYMM-#", now, 7))
+	require.Equal(t, "CAT-123456", RenderSKUTemplate("CAT-###", now, 123456))
+	require.Equal(t, "CAT-42", RenderSKUTemplate("CAT-", now, 42))
+}
+
+func TestSlugify(t *testing.T) {
+	require.Equal(t, "baju-baru", Slugify("Baju Baru"))
+	require.Equal(t, "kaos-polos-xl", Slugify("  Kaos -- Polos (XL)!! "))
+	require.Equal(t, "product", Slugify("!!!"))
+}
+
+func TestNextSlug(t *testing.T) {
+	require.Equal(t, "baju-baru", NextSlug("baju-baru", nil))
+	require.Equal(t, "baju-baru-2", NextSlug("baju-baru", []string{"baju-baru"}))
+	require.Equal(t, "baju-baru-4", NextSlug("baju-baru", []string{"baju-baru", "baju-baru-2", "baju-baru-3", "baju-baru-x"}))
+}
@@ -0,0 +1,86 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"strings"
+	"time"
+)
+
+type ProductStatus uint8
+
+const (
+	ProductStatus_Draft     ProductStatus = 1
+	ProductStatus_Published ProductStatus = 10
+	ProductStatus_Archived  ProductStatus = 20
+
+	PRODUCT_DRAFT     string = "DRAFT"
+	PRODUCT_PUBLISHED string = "PUBLISHED"
+	PRODUCT_ARCHIVED  string = "ARCHIVED"
+	PRODUCT_UNKNOWN   string = "UNKNOWN"
+)
+
+var (
+	MappingProductStatus = map[ProductStatus]string{
+		ProductStatus_Draft:     PRODUCT_DRAFT,
+		ProductStatus_Published: PRODUCT_PUBLISHED,
+		ProductStatus_Archived:  PRODUCT_ARCHIVED,
+	}
+)
+
+func ParseProductStatus(name string) (status ProductStatus, err error) {
+	for status, statusName := range MappingProductStatus {
+		if statusName == strings.ToUpper(name) {
+			return status, nil
+		}
+	}
+	return status, response.ErrProductStatusInvalid
+}
+
+// IsPublished hanya produk PUBLISHED yang tampil di endpoint publik dan bisa di-checkout
+func (p Product) IsPublished() bool {
+	return p.Status == ProductStatus_Published
+}
+
+func (p Product) GetStatus() string {
+	status, ok := MappingProductStatus[p.Status]
+	if !ok {
+		return PRODUCT_UNKNOWN
+	}
+	return status
+}
+
+// SetLifecycle mengubah status dan jadwal publish / unpublish, status kosong berarti status tidak berubah.
+// jadwal yang tidak dikirim dihapus
+func (p *Product) SetLifecycle(status string, publishAt *time.Time, unpublishAt *time.Time, now time.Time) (err error) {
+	if status != "" {
+		if p.Status, err = ParseProductStatus(status); err != nil {
+			return
+		}
+	}
+
+	p.PublishAt = publishAt
+	p.UnpublishAt = unpublishAt
+	return p.ValidateSchedule(now)
+}
+
+// ValidateSchedule publish hanya untuk produk yang belum PUBLISHED, unpublish hanya untuk produk yang (akan) PUBLISHED
+func (p Product) ValidateSchedule(now time.Time) (err error) {
+	if p.PublishAt != nil {
+		if !p.PublishAt.After(now) || p.IsPublished() {
+			return response.ErrProductScheduleInvalid
+		}
+	}
+
+	if p.UnpublishAt != nil {
+		if !p.UnpublishAt.After(now) {
+			return response.ErrProductScheduleInvalid
+		}
+		if !p.IsPublished() && p.PublishAt == nil {
+			return response.ErrProductScheduleInvalid
+		}
+		if p.PublishAt != nil && !p.UnpublishAt.After(*p.PublishAt) {
+			return response.ErrProductScheduleInvalid
+		}
+	}
+	return
+}
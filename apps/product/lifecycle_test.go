@@ -0,0 +1,59 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLifecycle(t *testing.T) {
+	now := time.Now()
+	publishAt := now.Add(time.Hour)
+	unpublishAt := now.Add(2 * time.Hour)
+
+	t.Run("new product is draft", func(t *testing.T) {
+		product := NewProductFromCreateProductRequest(CreateProductRequestPayload{Name: "Baju Baru", Stock: 1, Price: 1_000})
+
+		require.Nil(t, product.SetLifecycle("", nil, nil, now))
+		require.Equal(t, PRODUCT_DRAFT, product.GetStatus())
+		require.False(t, product.IsPublished())
+	})
+	t.Run("publish now", func(t *testing.T) {
+		product := Product{Status: ProductStatus_Draft}
+
+		require.Nil(t, product.SetLifecycle("published", nil, &unpublishAt, now))
+		require.True(t, product.IsPublished())
+	})
+	t.Run("scheduled publish and unpublish", func(t *testing.T) {
+		product := Product{Status: ProductStatus_Draft}
+
+		require.Nil(t, product.SetLifecycle("", &publishAt, &unpublishAt, now))
+		require.Equal(t, &publishAt, product.PublishAt)
+	})
+	t.Run("status invalid", func(t *testing.T) {
+		product := Product{Status: ProductStatus_Draft}
+
+		require.Equal(t, response.ErrProductStatusInvalid, product.SetLifecycle("deleted", nil, nil, now))
+	})
+	t.Run("schedule invalid", func(t *testing.T) {
+		past := now.Add(-time.Hour)
+		cases := []struct {
+			status      ProductStatus
+			publishAt   *time.Time
+			unpublishAt *time.Time
+		}{
+			{ProductStatus_Draft, &past, nil},
+			{ProductStatus_Published, &publishAt, nil},
+			{ProductStatus_Draft, nil, &unpublishAt},
+			{ProductStatus_Draft, &unpublishAt, &publishAt},
+			{ProductStatus_Published, nil, &past},
+		}
+		for _, c := range cases {
+			product := Product{Status: c.status}
+
+			require.Equal(t, response.ErrProductScheduleInvalid, product.SetLifecycle("", c.publishAt, c.unpublishAt, now))
+		}
+	})
+}
@@ -0,0 +1,330 @@
+package product
+
+import (
+	"Ecommerce-basic/apps/attribute"
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ProductSort_Default    string = ""
+	ProductSort_Price      string = "price"
+	ProductSort_Newest     string = "newest"
+	ProductSort_Name       string = "name"
+	ProductSort_Popularity string = "popularity"
+
+	// prefix "-" membalik arah urutan, contoh: sort=-price
+	productSortReversePrefix = "-"
+)
+
+type productSort struct {
+	Column string
+	Desc   bool
+	Join   string
+}
+
+// kolom yang dipakai untuk setiap sort key, id selalu dipakai sebagai tie breaker
+var productSorts = map[string]productSort{
+	ProductSort_Default:    {Column: "p.id"},
+	ProductSort_Price:      {Column: "p.price"},
+	ProductSort_Newest:     {Column: "p.created_at", Desc: true},
+	ProductSort_Name:       {Column: "p.name"},
+	ProductSort_Popularity: {Column: "COALESCE(s.sold, 0)", Desc: true, Join: productSoldJoin},
+}
+
+// jumlah produk terjual per product_id, hanya di-join ketika sort popularity
+const productSoldJoin = `
+		LEFT JOIN (
+			SELECT product_id, SUM(amount) AS sold
+			FROM transactions
+			GROUP BY product_id
+		) s ON s.product_id = p.id`
+
+type PriceBand struct {
+	Min int
+	Max int // 0 berarti tanpa batas atas
+}
+
+var DefaultPriceBands = []PriceBand{
+	{Min: 0, Max: 50_000},
+	{Min: 50_000, Max: 100_000},
+	{Min: 100_000, Max: 500_000},
+	{Min: 500_000, Max: 1_000_000},
+	{Min: 1_000_000},
+}
+
+// PriceBandOf band dari DefaultPriceBands yang berisi harga tersebut
+func PriceBandOf(price int) PriceBand {
+	for _, band := range DefaultPriceBands {
+		if price >= band.Min && (band.Max == 0 || price < band.Max) {
+			return band
+		}
+	}
+	return DefaultPriceBands[0]
+}
+
+func (b PriceBand) condition() string {
+	if b.Max == 0 {
+		return fmt.Sprintf("p.price >= %d", b.Min)
+	}
+	return fmt.Sprintf("p.price >= %d AND p.price < %d", b.Min, b.Max)
+}
+
+type ProductQuery struct {
+	Keyword      string
+	NamePrefix   string
+	MinPrice     *int
+	MaxPrice     *int
+	MinStock     *int
+	MaxStock     *int
+	InStock      bool
+	CreatedAfter *time.Time
+	Sort         string
+	Page         pagination.Request
+
+	// filter atribut, sudah divalidasi terhadap definisinya oleh service
+	Attributes []attribute.Filter
+
+	// false hanya menampilkan produk PUBLISHED, true untuk admin dengan filter Status opsional
+	AllStatuses bool
+	Status      string
+}
+
+type ProductFacets struct {
+	Total      int
+	InStock    int
+	OutOfStock int
+	PriceBands []PriceBandCount
+}
+
+type PriceBandCount struct {
+	PriceBand
+	Count int
+}
+
+func (q ProductQuery) Validate() (err error) {
+	if err = q.ValidatePriceRange(); err != nil {
+		return
+	}
+	if err = q.ValidateStockRange(); err != nil {
+		return
+	}
+	if err = q.ValidateSort(); err != nil {
+		return
+	}
+	if err = q.ValidateStatus(); err != nil {
+		return
+	}
+	return
+}
+
+func (q ProductQuery) ValidateStatus() (err error) {
+	if q.AllStatuses && q.Status != "" {
+		_, err = ParseProductStatus(q.Status)
+	}
+	return
+}
+
+func (q ProductQuery) ValidatePriceRange() (err error) {
+	if !isValidRange(q.MinPrice, q.MaxPrice) {
+		return response.ErrPriceRangeInvalid
+	}
+	return
+}
+
+func (q ProductQuery) ValidateStockRange() (err error) {
+	if !isValidRange(q.MinStock, q.MaxStock) {
+		return response.ErrStockRangeInvalid
+	}
+	return
+}
+
+func (q ProductQuery) ValidateSort() (err error) {
+	if _, ok := q.sort(); !ok {
+		return response.ErrSortInvalid
+	}
+	return
+}
+
+func isValidRange(min, max *int) bool {
+	if min != nil && *min < 0 {
+		return false
+	}
+	if max != nil && *max < 0 {
+		return false
+	}
+	if min != nil && max != nil && *min > *max {
+		return false
+	}
+	return true
+}
+
+func (q ProductQuery) sort() (sort productSort, ok bool) {
+	key := strings.TrimPrefix(q.Sort, productSortReversePrefix)
+	sort, ok = productSorts[key]
+	if !ok {
+		return
+	}
+	if key != q.Sort {
+		if key == ProductSort_Default {
+			return productSort{}, false
+		}
+		sort.Desc = !sort.Desc
+	}
+	return
+}
+
+// menyusun kondisi WHERE dengan placeholder "?", di-rebind oleh repository
+func (q ProductQuery) where() (clause string, args []interface{}) {
+	conditions := []string{"p.deleted_at IS NULL"}
+
+	if !q.AllStatuses {
+		conditions = append(conditions, "p.status = ?")
+		args = append(args, ProductStatus_Published)
+	} else if status, err := ParseProductStatus(q.Status); err == nil {
+		conditions = append(conditions, "p.status = ?")
+		args = append(args, status)
+	}
+
+	if q.Keyword != "" {
+		conditions = append(conditions, "(p.name ILIKE ? OR p.sku ILIKE ?)")
+		keyword := "%" + escapeLike(q.Keyword) + "%"
+		args = append(args, keyword, keyword)
+	}
+	if q.NamePrefix != "" {
+		conditions = append(conditions, "p.name ILIKE ?")
+		args = append(args, escapeLike(q.NamePrefix)+"%")
+	}
+	if q.MinPrice != nil {
+		conditions = append(conditions, "p.price >= ?")
+		args = append(args, *q.MinPrice)
+	}
+	if q.MaxPrice != nil {
+		conditions = append(conditions, "p.price <= ?")
+		args = append(args, *q.MaxPrice)
+	}
+	if q.MinStock != nil {
+		conditions = append(conditions, productStockColumn+" >= ?")
+		args = append(args, *q.MinStock)
+	}
+	if q.MaxStock != nil {
+		conditions = append(conditions, productStockColumn+" <= ?")
+		args = append(args, *q.MaxStock)
+	}
+	if q.InStock {
+		conditions = append(conditions, productStockColumn+" > 0")
+	}
+	if q.CreatedAfter != nil {
+		conditions = append(conditions, "p.created_at > ?")
+		args = append(args, *q.CreatedAfter)
+	}
+	for _, filter := range q.Attributes {
+		if len(filter.Values) > 0 {
+			placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.Values)), ", ")
+			conditions = append(conditions, fmt.Sprintf("p.attributes ->> ? IN (%s)", placeholders))
+			args = append(args, filter.Code)
+			for _, value := range filter.Values {
+				args = append(args, value)
+			}
+		}
+		if filter.Min != nil {
+			conditions = append(conditions, "(p.attributes ->> ?)::numeric >= ?")
+			args = append(args, filter.Code, *filter.Min)
+		}
+		if filter.Max != nil {
+			conditions = append(conditions, "(p.attributes ->> ?)::numeric <= ?")
+			args = append(args, filter.Code, *filter.Max)
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// SelectSQL menghasilkan query list produk sesuai filter, sort, dan cursor.
+// Hasil query berisi satu baris lebih banyak dari ukuran halaman, lihat pagination.Paginate.
+func (q ProductQuery) SelectSQL() (query string, args []interface{}) {
+	sort, _ := q.sort()
+	where, args := q.where()
+
+	operator, direction := q.Page.Order(sort.Desc)
+	isDefaultSort := sort.Column == productSorts[ProductSort_Default].Column
+
+	if cursor := q.Page.Cursor; cursor != nil {
+		if isDefaultSort {
+			where += fmt.Sprintf(" AND p.id %s ?", operator)
+			args = append(args, cursor.Id)
+		} else {
+			where += fmt.Sprintf(" AND (%s, p.id) %s (?, ?)", sort.Column, operator)
+			args = append(args, cursor.Value, cursor.Id)
+		}
+	}
+
+	columns := "p.id, p.sku, p.slug, p.name, " + productStockColumn + " AS stock, p.price, p.created_at, p.updated_at, p.deleted_at, p.rating_count, p.rating_sum, p.status, p.publish_at, p.unpublish_at, p.type"
+	if sort.Join != "" {
+		columns += fmt.Sprintf(", %s AS sold", sort.Column)
+	}
+
+	orderBy := fmt.Sprintf("p.id %s", direction)
+	if !isDefaultSort {
+		orderBy = fmt.Sprintf("%s %s, %s", sort.Column, direction, orderBy)
+	}
+
+	query = fmt.Sprintf(`
+		SELECT
+			%s
+		FROM products p%s
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?
+	`, columns, sort.Join, where, orderBy)
+	args = append(args, q.Page.Limit())
+	return
+}
+
+// CursorKey mengambil nilai sort dari produk untuk disimpan di cursor
+func (q ProductQuery) CursorKey(product Product) pagination.Key {
+	key := pagination.Key{Id: product.Id}
+
+	switch strings.TrimPrefix(q.Sort, productSortReversePrefix) {
+	case ProductSort_Price:
+		key.Value = strconv.Itoa(product.Price)
+	case ProductSort_Newest:
+		key.Value = product.CreatedAt.Format(time.RFC3339Nano)
+	case ProductSort_Name:
+		key.Value = product.Name
+	case ProductSort_Popularity:
+		key.Value = strconv.Itoa(product.Sold)
+	}
+	return key
+}
+
+// FacetSQL menghitung facet dari seluruh produk yang lolos filter, tanpa cursor dan limit.
+func (q ProductQuery) FacetSQL(bands []PriceBand) (query string, args []interface{}) {
+	where, args := q.where()
+
+	columns := []string{
+		"COUNT(*)",
+		fmt.Sprintf("COUNT(*) FILTER (WHERE %s > 0)", productStockColumn),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE %s <= 0)", productStockColumn),
+	}
+	for _, band := range bands {
+		columns = append(columns, fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", band.condition()))
+	}
+
+	query = fmt.Sprintf(`
+		SELECT
+			%s
+		FROM products p
+		WHERE %s
+	`, strings.Join(columns, ", "), where)
+	return
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
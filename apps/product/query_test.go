@@ -0,0 +1,197 @@
+package product
+
+import (
+	"Ecommerce-basic/apps/attribute"
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProductQuery(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	t.Run("success", func(t *testing.T) {
+		query := ProductQuery{
+			MinPrice: intPtr(10_000),
+			MaxPrice: intPtr(20_000),
+			Sort:     "-price",
+		}
+
+		err := query.Validate()
+		require.Nil(t, err)
+	})
+	t.Run("only min price", func(t *testing.T) {
+		query := ProductQuery{
+			MinPrice: intPtr(10_000),
+		}
+
+		err := query.Validate()
+		require.Nil(t, err)
+	})
+	t.Run("price range invalid", func(t *testing.T) {
+		query := ProductQuery{
+			MinPrice: intPtr(20_000),
+			MaxPrice: intPtr(10_000),
+		}
+
+		err := query.Validate()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrPriceRangeInvalid, err)
+	})
+	t.Run("stock range invalid", func(t *testing.T) {
+		query := ProductQuery{
+			MinStock: intPtr(-1),
+		}
+
+		err := query.Validate()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrStockRangeInvalid, err)
+	})
+	t.Run("sort invalid", func(t *testing.T) {
+		query := ProductQuery{
+			Sort: "stock",
+		}
+
+		err := query.Validate()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrSortInvalid, err)
+	})
+	t.Run("status invalid", func(t *testing.T) {
+		query := ProductQuery{
+			AllStatuses: true,
+			Status:      "deleted",
+		}
+
+		require.Equal(t, response.ErrProductStatusInvalid, query.Validate())
+	})
+	t.Run("reverse default sort invalid", func(t *testing.T) {
+		query := ProductQuery{
+			Sort: "-",
+		}
+
+		err := query.Validate()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrSortInvalid, err)
+	})
+}
+
+func TestProductQuerySelectSQL(t *testing.T) {
+	t.Run("default only excludes deleted and unpublished", func(t *testing.T) {
+		query, args := ProductQuery{Page: pagination.Request{Size: 10}}.SelectSQL()
+
+		require.Contains(t, query, "WHERE p.deleted_at IS NULL AND p.status = ?")
+		require.Contains(t, query, "ORDER BY p.id ASC")
+		require.Equal(t, []interface{}{ProductStatus_Published, 11}, args)
+	})
+	t.Run("admin sees all statuses", func(t *testing.T) {
+		query, args := ProductQuery{AllStatuses: true, Page: pagination.Request{Size: 10}}.SelectSQL()
+
+		require.NotContains(t, query, "p.status = ?")
+		require.Equal(t, []interface{}{11}, args)
+	})
+	t.Run("admin status filter", func(t *testing.T) {
+		query, args := ProductQuery{AllStatuses: true, Status: "draft", Page: pagination.Request{Size: 10}}.SelectSQL()
+
+		require.Contains(t, query, "p.status = ?")
+		require.Equal(t, []interface{}{ProductStatus_Draft, 11}, args)
+	})
+	t.Run("optional max price is not applied when missing", func(t *testing.T) {
+		minPrice := 10_000
+		query, args := ProductQuery{MinPrice: &minPrice, Page: pagination.Request{Size: 10}}.SelectSQL()
+
+		require.Contains(t, query, "p.price >= ?")
+		require.NotContains(t, query, "p.price <= ?")
+		require.Equal(t, []interface{}{ProductStatus_Published, 10_000, 11}, args)
+	})
+	t.Run("name prefix escapes wildcard", func(t *testing.T) {
+		_, args := ProductQuery{NamePrefix: "50%_off", Page: pagination.Request{Size: 10}}.SelectSQL()
+
+		require.Equal(t, []interface{}{ProductStatus_Published, `50\%\_off%`, 11}, args)
+	})
+	t.Run("sort with next cursor", func(t *testing.T) {
+		page := pagination.Request{
+			Sort:   "-price",
+			Cursor: &pagination.Cursor{Sort: "-price", Value: "10000", Id: 5, Direction: pagination.DirectionNext},
+			Size:   10,
+		}
+		query, args := ProductQuery{Sort: "-price", Page: page}.SelectSQL()
+
+		require.Contains(t, query, "(p.price, p.id) < (?, ?)")
+		require.Contains(t, query, "ORDER BY p.price DESC, p.id DESC")
+		require.Equal(t, []interface{}{ProductStatus_Published, "10000", 5, 11}, args)
+	})
+	t.Run("sort with prev cursor reverses order", func(t *testing.T) {
+		page := pagination.Request{
+			Sort:   "-price",
+			Cursor: &pagination.Cursor{Sort: "-price", Value: "10000", Id: 5, Direction: pagination.DirectionPrev},
+			Size:   10,
+		}
+		query, _ := ProductQuery{Sort: "-price", Page: page}.SelectSQL()
+
+		require.Contains(t, query, "(p.price, p.id) > (?, ?)")
+		require.Contains(t, query, "ORDER BY p.price ASC, p.id ASC")
+	})
+	t.Run("sort by popularity joins sold amount", func(t *testing.T) {
+		query, _ := ProductQuery{Sort: ProductSort_Popularity, Page: pagination.Request{Size: 10}}.SelectSQL()
+
+		require.Contains(t, query, "FROM transactions")
+		require.Contains(t, query, "COALESCE(s.sold, 0) AS sold")
+		require.Contains(t, query, "ORDER BY COALESCE(s.sold, 0) DESC, p.id DESC")
+	})
+	t.Run("attribute filters", func(t *testing.T) {
+		minWeight, maxWeight := 100.0, 500.0
+		filters := []attribute.Filter{
+			{Code: "material", Values: []string{"cotton", "linen"}},
+			{Code: "weight", Min: &minWeight, Max: &maxWeight},
+		}
+		query, args := ProductQuery{Attributes: filters, Page: pagination.Request{Size: 10}}.SelectSQL()
+
+		require.Contains(t, query, "p.attributes ->> ? IN (?, ?)")
+		require.Contains(t, query, "(p.attributes ->> ?)::numeric >= ?")
+		require.Contains(t, query, "(p.attributes ->> ?)::numeric <= ?")
+		require.Equal(t, []interface{}{ProductStatus_Published, "material", "cotton", "linen", "weight", 100.0, "weight", 500.0, 11}, args)
+	})
+}
+
+func TestProductQueryFacetSQL(t *testing.T) {
+	query, args := ProductQuery{InStock: true}.FacetSQL(DefaultPriceBands)
+
+	require.Contains(t, query, "COUNT(*) FILTER (WHERE p.price >= 1000000)")
+	require.Contains(t, query, productStockColumn+" > 0")
+	require.Equal(t, []interface{}{ProductStatus_Published}, args)
+}
+
+func TestPriceBandOf(t *testing.T) {
+	require.Equal(t, PriceBand{Min: 0, Max: 50_000}, PriceBandOf(10_000))
+	require.Equal(t, PriceBand{Min: 50_000, Max: 100_000}, PriceBandOf(50_000))
+	require.Equal(t, PriceBand{Min: 1_000_000}, PriceBandOf(5_000_000))
+}
+
+func TestLegacyFilterRequest(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	t.Run("old names are mapped", func(t *testing.T) {
+		legacy := LegacyFilterRequestPayload{
+			MinPrice: intPtr(10_000),
+			MaxPrice: intPtr(20_000),
+			MinStock: intPtr(1),
+			MaxStock: intPtr(5),
+		}
+
+		req := legacy.Apply(ListProductRequestPayload{Size: 10})
+		require.Equal(t, 10_000, *req.MinPrice)
+		require.Equal(t, 20_000, *req.MaxPrice)
+		require.Equal(t, 1, *req.MinStock)
+		require.Equal(t, 5, *req.MaxStock)
+		require.Equal(t, 10, req.Size)
+	})
+	t.Run("new names win", func(t *testing.T) {
+		legacy := LegacyFilterRequestPayload{MinPrice: intPtr(10_000)}
+
+		req := legacy.Apply(ListProductRequestPayload{MinPrice: intPtr(15_000)})
+		require.Equal(t, 15_000, *req.MinPrice)
+		require.Nil(t, req.MaxPrice)
+	})
+}
@@ -1,12 +1,18 @@
 package product
 
 import (
+	"Ecommerce-basic/apps/attribute"
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
+	"Ecommerce-basic/infra/pagination"
 	"Ecommerce-basic/infra/response"
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type repository struct {
@@ -19,34 +25,44 @@ func newRepository(db *sqlx.DB) repository {
 	}
 }
 
-func (r repository) CreateProduct(ctx context.Context, model Product) (err error) {
+func (r repository) Begin(ctx context.Context) (tx *sqlx.Tx, err error) {
+	tx, err = r.db.BeginTxx(ctx, &sql.TxOptions{})
+	return
+}
+
+func (repository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Commit()
+}
+
+func (repository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Rollback()
+}
+
+func (r repository) CreateProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (id int, err error) {
 	query := `
         INSERT INTO products (
-            sku, name, stock, price, created_at, updated_at
+            sku, slug, name, stock, price, created_at, updated_at
+            , status, publish_at, unpublish_at, type
         ) VALUES (
-            :sku, :name, :stock, :price, :created_at, :updated_at
+            :sku, :slug, :name, :stock, :price, :created_at, :updated_at
+            , :status, :publish_at, :unpublish_at, :type
         )
+        RETURNING id
     `
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	stmt, err := tx.PrepareNamedContext(ctx, query)
 	if err != nil {
 		return
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, model)
+	err = stmt.GetContext(ctx, &id, model)
 	return
 }
 
-func (r repository) GetAllProductsWithPaginationCursor(ctx context.Context, model ProductPagination) (products []Product, err error) {
-	query := `
-        SELECT 
-            id, sku, name, stock, price, created_at, updated_at, deleted_at
-        FROM products
-        WHERE id > $1 AND deleted_at IS NULL
-        ORDER BY id ASC
-        LIMIT $2
-    `
-	err = r.db.SelectContext(ctx, &products, query, model.Cursor, model.Size)
+func (r repository) GetProductsByQuery(ctx context.Context, model ProductQuery) (products []Product, err error) {
+	query, args := model.SelectSQL()
+
+	err = r.db.SelectContext(ctx, &products, r.db.Rebind(query), args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, response.ErrNotFound
@@ -56,13 +72,29 @@ func (r repository) GetAllProductsWithPaginationCursor(ctx context.Context, mode
 	return
 }
 
+func (r repository) GetProductFacetsByQuery(ctx context.Context, model ProductQuery) (facets ProductFacets, err error) {
+	query, args := model.FacetSQL(DefaultPriceBands)
+
+	facets.PriceBands = make([]PriceBandCount, len(DefaultPriceBands))
+	dest := []interface{}{&facets.Total, &facets.InStock, &facets.OutOfStock}
+	for i, band := range DefaultPriceBands {
+		facets.PriceBands[i].PriceBand = band
+		dest = append(dest, &facets.PriceBands[i].Count)
+	}
+
+	err = r.db.QueryRowxContext(ctx, r.db.Rebind(query), args...).Scan(dest...)
+	return
+}
+
 func (r repository) GetProductBySKU(ctx context.Context, sku string) (product Product, err error) {
-	query := `
+	query := fmt.Sprintf(`
         SELECT 
-            id, sku, name, stock, price, created_at, updated_at, deleted_at
-        FROM products
-        WHERE sku = $1 AND deleted_at IS NULL
-    `
+            p.id, p.sku, p.slug, p.name, %s AS stock, p.price, p.created_at, p.updated_at, p.deleted_at, p.version
+            , p.status, p.publish_at, p.unpublish_at, p.type, p.attributes, p.file_key, p.file_name
+            , p.rating_count, p.rating_sum
+        FROM products p
+        WHERE p.sku = $1 AND p.deleted_at IS NULL
+    `, productStockColumn)
 	err = r.db.GetContext(ctx, &product, query, sku)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -73,10 +105,31 @@ func (r repository) GetProductBySKU(ctx context.Context, sku string) (product Pr
 	return
 }
 
+func (r repository) GetProductBySlug(ctx context.Context, slug string) (product Product, err error) {
+	query := fmt.Sprintf(`
+        SELECT 
+            p.id, p.sku, p.slug, p.name, %s AS stock, p.price, p.created_at, p.updated_at, p.deleted_at, p.version
+            , p.status, p.publish_at, p.unpublish_at, p.type, p.attributes, p.file_key, p.file_name
+            , p.rating_count, p.rating_sum
+        FROM products p
+        WHERE p.slug = $1 AND p.deleted_at IS NULL
+    `, productStockColumn)
+	err = r.db.GetContext(ctx, &product, query, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
 func (r repository) GetProductByID(ctx context.Context, id int) (product Product, err error) {
 	query := `
 		SELECT 
-			id, sku, name, stock, price, created_at, updated_at, deleted_at
+			id, sku, slug, name, stock, price, created_at, updated_at, deleted_at, version
+			, status, publish_at, unpublish_at, type, attributes, file_key, file_name
+			, rating_count, rating_sum
 		FROM products
 		WHERE id=$1 AND deleted_at IS NULL
 	`
@@ -91,24 +144,224 @@ func (r repository) GetProductByID(ctx context.Context, id int) (product Product
 	return
 }
 
-func (r repository) UpdateProduct(ctx context.Context, model Product) (err error) {
+// GetProductByIDForUpdateWithTx mengunci baris produk agar selisih stok untuk ledger akurat
+func (r repository) GetProductByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (product Product, err error) {
+	query := `
+		SELECT 
+			id, sku, slug, name, stock, price, created_at, updated_at, deleted_at, version
+			, status, publish_at, unpublish_at, type, attributes, file_key, file_name
+		FROM products
+		WHERE id=$1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &product, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) UpdateProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
 	query := `
 		UPDATE products
-		SET name=:name, stock=:stock, price=:price, updated_at=:updated_at
+		SET name=:name, stock=:stock, price=:price, updated_at=:updated_at, version=:version
 		WHERE id=:id AND deleted_at IS NULL
 	`
 
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, model)
 	if err != nil {
 		return
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+	return
+}
+
+// GetWarehouseStocksByProductIds mengambil stok per gudang aktif untuk beberapa produk sekaligus
+func (r repository) GetWarehouseStocksByProductIds(ctx context.Context, productIds []int) (stocks []inventory.WarehouseStock, err error) {
+	query := `
+		SELECT
+			w.id AS warehouse_id, p.id AS product_id, COALESCE(ws.stock, 0) AS stock
+			, w.code AS warehouse_code, w.name AS warehouse_name, w.city
+			, w.latitude, w.longitude, w.priority
+		FROM products p
+		CROSS JOIN warehouses w
+		LEFT JOIN warehouse_stocks ws ON ws.warehouse_id = w.id AND ws.product_id = p.id
+		WHERE p.id = ANY($1) AND w.is_active
+		ORDER BY p.id, w.priority, w.id
+	`
+
+	err = r.db.SelectContext(ctx, &stocks, query, pq.Array(productIds))
+	return
+}
+
+// GetReservedStocksByProductIds menjumlahkan reservation aktif yang belum kedaluwarsa
+func (r repository) GetReservedStocksByProductIds(ctx context.Context, productIds []int) (reserved []inventory.ReservedStock, err error) {
+	query := `
+		SELECT product_id, COALESCE(SUM(quantity), 0) AS reserved
+		FROM stock_reservations
+		WHERE product_id = ANY($1) AND status = $2 AND expires_at > NOW()
+		GROUP BY product_id
+	`
+
+	err = r.db.SelectContext(ctx, &reserved, query, pq.Array(productIds), inventory.ReservationStatus_Active)
+	return
+}
+
+// GetBundleComponentsByBundleIds mengambil isi beberapa bundle sekaligus beserta stok komponennya
+func (r repository) GetBundleComponentsByBundleIds(ctx context.Context, bundleIds []int) (components []BundleComponent, err error) {
+	query := `
+		SELECT
+			bc.bundle_id, bc.component_id, bc.quantity
+			, c.sku, c.name, c.stock, c.deleted_at IS NOT NULL AS deleted
+		FROM bundle_components bc
+		JOIN products c ON c.id = bc.component_id
+		WHERE bc.bundle_id = ANY($1)
+		ORDER BY bc.bundle_id, bc.component_id
+	`
+
+	err = r.db.SelectContext(ctx, &components, query, pq.Array(bundleIds))
+	return
+}
+
+func (r repository) CreateBundleComponentsWithTx(ctx context.Context, tx *sqlx.Tx, components []BundleComponent) (err error) {
+	query := `
+		INSERT INTO bundle_components (
+			bundle_id, component_id, quantity
+		) VALUES (
+			:bundle_id, :component_id, :quantity
+		)
+	`
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return
+	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, model)
+	for _, component := range components {
+		if _, err = stmt.ExecContext(ctx, component); err != nil {
+			return
+		}
+	}
 	return
 }
 
+func (r repository) DeleteBundleComponentsWithTx(ctx context.Context, tx *sqlx.Tx, bundleId int) (err error) {
+	query := `
+		DELETE FROM bundle_components
+		WHERE bundle_id = $1
+	`
+
+	_, err = tx.ExecContext(ctx, query, bundleId)
+	return
+}
+
+// TouchProductWithTx menaikkan version ketika isi bundle berubah supaya ETag lama tidak berlaku
+func (r repository) TouchProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	query := `
+		UPDATE products
+		SET version=:version, updated_at=:updated_at
+		WHERE id=:id
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, model)
+	return
+}
+
+func (r repository) UpdateProductAttributesWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	query := `
+		UPDATE products
+		SET attributes=:attributes, version=:version, updated_at=:updated_at
+		WHERE id=:id
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, model)
+	return
+}
+
+func (r repository) UpdateProductFileWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	query := `
+		UPDATE products
+		SET file_key=:file_key, file_name=:file_name, version=:version, updated_at=:updated_at
+		WHERE id=:id
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, model)
+	return
+}
+
+// CreateLicenseKeysWithTx key yang sudah ada di pool produk yang sama dilewati, added hanya menghitung key baru
+func (r repository) CreateLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, keys []LicenseKey) (added int, err error) {
+	query := `
+		INSERT INTO license_keys (
+			product_id, license_key, created_at
+		) VALUES (
+			:product_id, :license_key, :created_at
+		)
+		ON CONFLICT (product_id, license_key) DO NOTHING
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, keys)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+func (r repository) GetLicenseKeySummary(ctx context.Context, productId int) (summary LicenseKeySummary, err error) {
+	query := `
+		SELECT
+			COUNT(*) AS total
+			, COUNT(*) FILTER (WHERE transaction_id IS NULL AND revoked_at IS NULL) AS available
+			, COUNT(*) FILTER (WHERE transaction_id IS NOT NULL) AS assigned
+		FROM license_keys
+		WHERE product_id=$1
+	`
+
+	err = r.db.GetContext(ctx, &summary, query, productId)
+	return
+}
+
+func (r repository) GetAttributes(ctx context.Context) (attributes []attribute.Attribute, err error) {
+	return attribute.GetAttributes(ctx, r.db)
+}
+
+func (r repository) GenerateSKUWithTx(ctx context.Context, tx *sqlx.Tx) (sku string, err error) {
+	return GenerateSKUWithTx(ctx, tx)
+}
+
+func (r repository) GenerateSlugWithTx(ctx context.Context, tx *sqlx.Tx, name string) (slug string, err error) {
+	return GenerateSlugWithTx(ctx, tx, name)
+}
+
+func (r repository) RecordPriceChangeWithTx(ctx context.Context, tx *sqlx.Tx, change pricing.PriceChange) (err error) {
+	return pricing.RecordPriceChangeWithTx(ctx, tx, change)
+}
+
+func (r repository) RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement inventory.StockMovement) (err error) {
+	return inventory.RecordStockMovementWithTx(ctx, tx, movement)
+}
+
 func (r repository) SoftDeleteProduct(ctx context.Context, id int) error {
 	query := `
 		UPDATE products
@@ -118,41 +371,161 @@ func (r repository) SoftDeleteProduct(ctx context.Context, id int) error {
 
 	deletedAt := time.Now()
 
-	_, err := r.db.NamedExecContext(ctx, query, map[string]interface{}{
+	result, err := r.db.NamedExecContext(ctx, query, map[string]interface{}{
 		"id":         id,
 		"deleted_at": deletedAt,
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+	return nil
 }
 
-func (r repository) SearchProducts(ctx context.Context, keyword string, pagination ProductPagination) (products []Product, err error) {
+// GetDeletedProducts menampilkan produk yang sudah di-soft delete, terbaru dihapus lebih dulu berdasarkan id
+func (r repository) GetDeletedProducts(ctx context.Context, page pagination.Request) (products []Product, err error) {
+	operator, direction := page.Order(true)
+	query := fmt.Sprintf(`
+		SELECT
+			id, sku, slug, name, stock, price, created_at, updated_at, deleted_at
+		FROM products
+		WHERE deleted_at IS NOT NULL
+			AND ($1 = 0 OR id %s $1)
+		ORDER BY id %s
+		LIMIT $2
+	`, operator, direction)
+
+	cursorId := 0
+	if page.Cursor != nil {
+		cursorId = page.Cursor.Id
+	}
+
+	err = r.db.SelectContext(ctx, &products, query, cursorId, page.Limit())
+	return
+}
+
+func (r repository) GetDeletedProductByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (product Product, err error) {
 	query := `
-		SELECT 
-			id, sku, name, stock, price, created_at, updated_at, deleted_at
+		SELECT
+			id, sku, slug, name, stock, price, created_at, updated_at, deleted_at, version, type
 		FROM products
-		WHERE (name ILIKE $1 OR sku ILIKE $1) AND deleted_at IS NULL
-		ORDER BY id ASC
-		LIMIT $2 OFFSET $3
+		WHERE id=$1 AND deleted_at IS NOT NULL
+		FOR UPDATE
 	`
 
-	keyword = "%" + keyword + "%"
-	err = r.db.SelectContext(ctx, &products, query, keyword, pagination.Size, pagination.Cursor)
+	err = tx.GetContext(ctx, &product, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
 	return
 }
 
-func (r repository) FilterProducts(ctx context.Context, minPrice, maxPrice int, minStock, maxStock int16, pagination ProductPagination) (products []Product, err error) {
+func (r repository) RestoreProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
 	query := `
-        SELECT 
-            id, sku, name, stock, price, created_at, updated_at, deleted_at
-        FROM products
-        WHERE (price BETWEEN $1 AND $2) 
-          AND (stock BETWEEN $3 AND $4) 
-          AND deleted_at IS NULL
-        ORDER BY id ASC
-        LIMIT $5 OFFSET $6
-    `
-	err = r.db.SelectContext(ctx, &products, query, minPrice, maxPrice, minStock, maxStock, pagination.Size, pagination.Cursor)
+		UPDATE products
+		SET deleted_at=:deleted_at, updated_at=:updated_at, version=:version
+		WHERE id=:id AND deleted_at IS NOT NULL
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, model)
+	return
+}
+
+func (r repository) UpdateProductStatusWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	query := `
+		UPDATE products
+		SET status=:status, publish_at=:publish_at, unpublish_at=:unpublish_at
+			, updated_at=:updated_at, version=:version
+		WHERE id=:id AND deleted_at IS NULL
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, model)
+	return
+}
+
+// PublishDueProducts mem-publish produk yang jadwal publish-nya sudah lewat dan mengembalikan SKU-nya
+func (r repository) PublishDueProducts(ctx context.Context, now time.Time) (skus []string, err error) {
+	query := `
+		UPDATE products
+		SET status=$2, publish_at=NULL, updated_at=NOW(), version=version + 1
+		WHERE deleted_at IS NULL AND publish_at <= $1
+		RETURNING sku
+	`
+
+	err = r.db.SelectContext(ctx, &skus, query, now, ProductStatus_Published)
+	return
+}
+
+// UnpublishDueProducts mengarsipkan produk PUBLISHED yang jadwal unpublish-nya sudah lewat dan mengembalikan SKU-nya
+func (r repository) UnpublishDueProducts(ctx context.Context, now time.Time) (skus []string, err error) {
+	query := `
+		UPDATE products
+		SET status=$2, unpublish_at=NULL, updated_at=NOW(), version=version + 1
+		WHERE deleted_at IS NULL AND unpublish_at <= $1 AND status=$3
+		RETURNING sku
+	`
+
+	err = r.db.SelectContext(ctx, &skus, query, now, ProductStatus_Archived, ProductStatus_Published)
+	return
+}
+
+func (r repository) HasTransactionsWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (exists bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM transactions WHERE product_id=$1
+		)
+	`
+
+	err = tx.GetContext(ctx, &exists, query, productId)
+	return
+}
+
+// IsBundleComponentWithTx produk yang masih menjadi isi bundle tidak boleh dihapus permanen
+func (r repository) IsBundleComponentWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (exists bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM bundle_components WHERE component_id=$1
+		)
+	`
+
+	err = tx.GetContext(ctx, &exists, query, productId)
+	return
+}
+
+// PurgeProductWithTx menghapus produk beserta data turunannya, hanya untuk produk yang sudah di-soft delete.
+// ledger stok dan riwayat harga produk yang sudah di-soft delete boleh dihapus (lihat rule di schema)
+func (r repository) PurgeProductWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (err error) {
+	queries := []string{
+		"DELETE FROM stock_alerts WHERE product_id=$1",
+		"DELETE FROM stock_subscriptions WHERE product_id=$1",
+		"DELETE FROM stock_reservations WHERE product_id=$1",
+		"DELETE FROM warehouse_stocks WHERE product_id=$1",
+		"DELETE FROM stock_movements WHERE product_id=$1",
+		"DELETE FROM price_changes WHERE product_id=$1",
+		"DELETE FROM price_schedules WHERE product_id=$1",
+		"DELETE FROM reviews WHERE product_id=$1",
+		"DELETE FROM bundle_components WHERE bundle_id=$1",
+		"DELETE FROM license_keys WHERE product_id=$1",
+		"DELETE FROM product_relations WHERE product_id=$1 OR related_product_id=$1",
+		"DELETE FROM product_views WHERE product_id=$1",
+		"DELETE FROM products WHERE id=$1 AND deleted_at IS NOT NULL",
+	}
+
+	for _, query := range queries {
+		if _, err = tx.ExecContext(ctx, query, productId); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -160,7 +533,7 @@ func (r repository) FilterProducts(ctx context.Context, minPrice, maxPrice int,
 func (r repository) GetProductByName(ctx context.Context, name string) (product Product, err error) {
 	query := `
        SELECT 
-          id, sku, name, stock, price, created_at, updated_at, deleted_at
+          id, sku, slug, name, stock, price, created_at, updated_at, deleted_at
        FROM products
        WHERE name=$1 AND deleted_at IS NULL
     `
@@ -174,3 +547,72 @@ func (r repository) GetProductByName(ctx context.Context, name string) (product
 	}
 	return
 }
+
+// UpsertProductViewWithTx view ulang produk yang sama hanya memperbarui viewed_at
+func (r repository) UpsertProductViewWithTx(ctx context.Context, tx *sqlx.Tx, view ProductView) (err error) {
+	query := `
+		INSERT INTO product_views (
+			user_public_id, session_id, product_id, viewed_at
+		) VALUES (
+			:user_public_id, :session_id, :product_id, :viewed_at
+		)
+		ON CONFLICT (user_public_id, session_id, product_id)
+		DO UPDATE SET viewed_at = GREATEST(product_views.viewed_at, EXCLUDED.viewed_at)
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, view)
+	return
+}
+
+// ClaimProductViewsWithTx memindahkan view session anonim ke user, produk yang sudah ada memakai view terakhir
+func (r repository) ClaimProductViewsWithTx(ctx context.Context, tx *sqlx.Tx, sessionId string, userPublicId string) (err error) {
+	query := `
+		INSERT INTO product_views (
+			user_public_id, session_id, product_id, viewed_at
+		)
+		SELECT $2, '', product_id, viewed_at
+		FROM product_views
+		WHERE user_public_id='' AND session_id=$1
+		ON CONFLICT (user_public_id, session_id, product_id)
+		DO UPDATE SET viewed_at = GREATEST(product_views.viewed_at, EXCLUDED.viewed_at)
+	`
+
+	if _, err = tx.ExecContext(ctx, query, sessionId, userPublicId); err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM product_views WHERE user_public_id='' AND session_id=$1", sessionId)
+	return
+}
+
+// TrimProductViewsWithTx hanya menyimpan view terbaru sebanyak limit per user / session
+func (r repository) TrimProductViewsWithTx(ctx context.Context, tx *sqlx.Tx, userPublicId string, sessionId string, limit int) (err error) {
+	query := `
+		DELETE FROM product_views
+		WHERE user_public_id=$1 AND session_id=$2 AND id NOT IN (
+			SELECT id FROM product_views
+			WHERE user_public_id=$1 AND session_id=$2
+			ORDER BY viewed_at DESC, id DESC
+			LIMIT $3
+		)
+	`
+
+	_, err = tx.ExecContext(ctx, query, userPublicId, sessionId, limit)
+	return
+}
+
+// GetRecentlyViewed produk yang sudah tidak dijual tidak ditampilkan tetapi tetap dihitung dalam batas riwayat
+func (r repository) GetRecentlyViewed(ctx context.Context, userPublicId string, limit int) (products []RecentlyViewedProduct, err error) {
+	query := `
+		SELECT
+			p.sku, p.slug, p.name, p.price, v.viewed_at
+		FROM product_views v
+		JOIN products p ON p.id = v.product_id
+		WHERE v.user_public_id=$1 AND v.session_id='' AND p.deleted_at IS NULL AND p.status=$2
+		ORDER BY v.viewed_at DESC, v.id DESC
+		LIMIT $3
+	`
+
+	err = r.db.SelectContext(ctx, &products, query, userPublicId, ProductStatus_Published, limit)
+	return
+}
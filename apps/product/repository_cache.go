@@ -0,0 +1,125 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"Ecommerce-basic/internal/log"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// cachedRepository menambahkan cache GetProductBySKU tanpa mengubah service.
+// SKU produk yang diubah di dalam transaksi database baru dihapus dari cache setelah commit,
+// perubahan massal dari scheduler publish / unpublish dihapus berdasarkan SKU yang dikembalikan query
+type cachedRepository struct {
+	Repository
+
+	products *cache.Loader[Product]
+	pending  *cache.PendingKeys[*sqlx.Tx]
+}
+
+// newCachedRepository mengembalikan repo apa adanya jika app.cache.driver none
+func newCachedRepository(repo Repository) Repository {
+	products := cache.FromConfig[Product]("product:sku")
+	if products == nil {
+		return repo
+	}
+	return newCachedRepositoryWithLoader(repo, products)
+}
+
+func newCachedRepositoryWithLoader(repo Repository, products *cache.Loader[Product]) cachedRepository {
+	cache.Subscribe(cache.TAG_PRODUCT_SKU, products)
+
+	return cachedRepository{
+		Repository: repo,
+		products:   products,
+		pending:    cache.NewPendingKeys[*sqlx.Tx](cache.TAG_PRODUCT_SKU),
+	}
+}
+
+func (r cachedRepository) GetProductBySKU(ctx context.Context, sku string) (product Product, err error) {
+	return r.products.Load(ctx, sku, func(ctx context.Context) (Product, error) {
+		return r.Repository.GetProductBySKU(ctx, sku)
+	})
+}
+
+func (r cachedRepository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	if err = r.Repository.Commit(ctx, tx); err != nil {
+		r.pending.Discard(tx)
+		return
+	}
+	r.pending.Flush(ctx, tx)
+	return
+}
+
+func (r cachedRepository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	r.pending.Discard(tx)
+	return r.Repository.Rollback(ctx, tx)
+}
+
+func (r cachedRepository) UpdateProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	r.pending.Add(tx, model.SKU)
+	return r.Repository.UpdateProductWithTx(ctx, tx, model)
+}
+
+func (r cachedRepository) UpdateProductStatusWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	r.pending.Add(tx, model.SKU)
+	return r.Repository.UpdateProductStatusWithTx(ctx, tx, model)
+}
+
+func (r cachedRepository) UpdateProductAttributesWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	r.pending.Add(tx, model.SKU)
+	return r.Repository.UpdateProductAttributesWithTx(ctx, tx, model)
+}
+
+func (r cachedRepository) UpdateProductFileWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	r.pending.Add(tx, model.SKU)
+	return r.Repository.UpdateProductFileWithTx(ctx, tx, model)
+}
+
+func (r cachedRepository) TouchProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	r.pending.Add(tx, model.SKU)
+	return r.Repository.TouchProductWithTx(ctx, tx, model)
+}
+
+func (r cachedRepository) RestoreProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error) {
+	r.pending.Add(tx, model.SKU)
+	return r.Repository.RestoreProductWithTx(ctx, tx, model)
+}
+
+// SoftDeleteProduct tidak memakai transaksi dan hanya menerima id, SKU dicari sebelum produk dihapus
+func (r cachedRepository) SoftDeleteProduct(ctx context.Context, id int) (err error) {
+	product, getErr := r.Repository.GetProductByID(ctx, id)
+
+	if err = r.Repository.SoftDeleteProduct(ctx, id); err != nil {
+		return
+	}
+	if getErr == nil {
+		r.invalidate(ctx, product.SKU)
+	}
+	return
+}
+
+func (r cachedRepository) PublishDueProducts(ctx context.Context, now time.Time) (skus []string, err error) {
+	if skus, err = r.Repository.PublishDueProducts(ctx, now); err != nil {
+		return
+	}
+	r.invalidate(ctx, skus...)
+	return
+}
+
+func (r cachedRepository) UnpublishDueProducts(ctx context.Context, now time.Time) (skus []string, err error) {
+	if skus, err = r.Repository.UnpublishDueProducts(ctx, now); err != nil {
+		return
+	}
+	r.invalidate(ctx, skus...)
+	return
+}
+
+// invalidate perubahan sudah tersimpan, cache yang gagal dihapus hanya dicatat dan hilang setelah TTL
+func (r cachedRepository) invalidate(ctx context.Context, skus ...string) {
+	if err := cache.Invalidate(ctx, cache.TAG_PRODUCT_SKU, skus...); err != nil {
+		log.Log.Errorf(ctx, "[cachedRepository, Invalidate] with error detail %v", err.Error())
+	}
+}
@@ -0,0 +1,155 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRepository hanya mengisi method yang dipakai cachedRepository
+type stubRepository struct {
+	Repository
+
+	products map[string]Product
+	reads    int
+}
+
+func (r *stubRepository) GetProductBySKU(ctx context.Context, sku string) (Product, error) {
+	r.reads++
+	product, ok := r.products[sku]
+	if !ok {
+		return Product{}, response.ErrNotFound
+	}
+	return product, nil
+}
+
+func (r *stubRepository) GetProductByID(ctx context.Context, id int) (Product, error) {
+	for _, product := range r.products {
+		if product.Id == id {
+			return product, nil
+		}
+	}
+	return Product{}, response.ErrNotFound
+}
+
+func (r *stubRepository) UpdateProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) error {
+	r.products[model.SKU] = model
+	return nil
+}
+
+func (r *stubRepository) SoftDeleteProduct(ctx context.Context, id int) error {
+	for sku, product := range r.products {
+		if product.Id == id {
+			delete(r.products, sku)
+		}
+	}
+	return nil
+}
+
+func (r *stubRepository) PublishDueProducts(ctx context.Context, now time.Time) (skus []string, err error) {
+	for sku, product := range r.products {
+		if product.PublishAt != nil && !product.PublishAt.After(now) {
+			product.Status, product.PublishAt = ProductStatus_Published, nil
+			r.products[sku] = product
+			skus = append(skus, sku)
+		}
+	}
+	return
+}
+
+func (r *stubRepository) Commit(ctx context.Context, tx *sqlx.Tx) error   { return nil }
+func (r *stubRepository) Rollback(ctx context.Context, tx *sqlx.Tx) error { return nil }
+
+func TestCachedRepository(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func() (*stubRepository, cachedRepository) {
+		stub := &stubRepository{products: map[string]Product{
+			"SKU-1": {Id: 1, SKU: "SKU-1", Name: "Kaos", Price: 10_000},
+		}}
+		return stub, newCachedRepositoryWithLoader(stub, cache.NewLoader[Product](cache.NewLRU[Product](10), time.Minute))
+	}
+
+	t.Run("second read is served from cache", func(t *testing.T) {
+		stub, repo := newRepo()
+
+		for range 2 {
+			product, err := repo.GetProductBySKU(ctx, "SKU-1")
+			require.Nil(t, err)
+			require.Equal(t, "Kaos", product.Name)
+		}
+		require.Equal(t, 1, stub.reads)
+	})
+	t.Run("not found is not cached", func(t *testing.T) {
+		stub, repo := newRepo()
+
+		for range 2 {
+			_, err := repo.GetProductBySKU(ctx, "SKU-2")
+			require.Equal(t, response.ErrNotFound, err)
+		}
+		require.Equal(t, 2, stub.reads)
+	})
+	t.Run("update is visible after commit", func(t *testing.T) {
+		_, repo := newRepo()
+		tx := &sqlx.Tx{}
+		repo.GetProductBySKU(ctx, "SKU-1")
+
+		require.Nil(t, repo.UpdateProductWithTx(ctx, tx, Product{Id: 1, SKU: "SKU-1", Name: "Kaos", Price: 12_000}))
+
+		// sebelum commit cache masih berisi data lama
+		product, _ := repo.GetProductBySKU(ctx, "SKU-1")
+		require.Equal(t, 10_000, product.Price)
+
+		require.Nil(t, repo.Commit(ctx, tx))
+		product, _ = repo.GetProductBySKU(ctx, "SKU-1")
+		require.Equal(t, 12_000, product.Price)
+	})
+	t.Run("rollback keeps cache", func(t *testing.T) {
+		stub, repo := newRepo()
+		tx := &sqlx.Tx{}
+		repo.GetProductBySKU(ctx, "SKU-1")
+
+		repo.UpdateProductWithTx(ctx, tx, Product{Id: 1, SKU: "SKU-1", Name: "Kaos", Price: 12_000})
+		require.Nil(t, repo.Rollback(ctx, tx))
+
+		repo.GetProductBySKU(ctx, "SKU-1")
+		require.Equal(t, 1, stub.reads)
+	})
+	t.Run("soft delete", func(t *testing.T) {
+		_, repo := newRepo()
+		repo.GetProductBySKU(ctx, "SKU-1")
+
+		require.Nil(t, repo.SoftDeleteProduct(ctx, 1))
+		_, err := repo.GetProductBySKU(ctx, "SKU-1")
+		require.Equal(t, response.ErrNotFound, err)
+	})
+	t.Run("change from another module", func(t *testing.T) {
+		stub, repo := newRepo()
+		repo.GetProductBySKU(ctx, "SKU-1")
+
+		// contoh: pricing mengubah harga lalu menghapus cache lewat tag
+		stub.products["SKU-1"] = Product{Id: 1, SKU: "SKU-1", Name: "Kaos", Price: 8_000}
+		require.Nil(t, cache.Invalidate(ctx, cache.TAG_PRODUCT_SKU, "SKU-1"))
+
+		product, _ := repo.GetProductBySKU(ctx, "SKU-1")
+		require.Equal(t, 8_000, product.Price)
+	})
+	t.Run("scheduled publish", func(t *testing.T) {
+		stub, repo := newRepo()
+		now := time.Now()
+		product, _ := repo.GetProductBySKU(ctx, "SKU-1")
+		product.Status, product.PublishAt = ProductStatus_Draft, &now
+		stub.products["SKU-1"] = product
+
+		skus, err := repo.PublishDueProducts(ctx, now)
+		require.Nil(t, err)
+		require.Equal(t, []string{"SKU-1"}, skus)
+
+		product, _ = repo.GetProductBySKU(ctx, "SKU-1")
+		require.Equal(t, ProductStatus_Published, product.Status)
+	})
+}
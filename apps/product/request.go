@@ -1,22 +1,120 @@
 package product
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type CreateProductRequestPayload struct {
+	// opsional, harus cocok dengan app.product.sku_pattern. kosong berarti dibuat dari app.product.sku_template
+	SKU   string `json:"sku"`
 	Name  string `json:"name"`
 	Stock int16  `json:"stock"`
 	Price int    `json:"price"`
+
+	// STANDARD (default) atau DIGITAL
+	Type string `json:"type"`
+
+	// DRAFT (default), PUBLISHED atau ARCHIVED
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+
+	UserPublicId string `json:"-"`
+}
+
+// CreateBundleRequestPayload sama dengan produk biasa ditambah komponen, stock diabaikan
+type CreateBundleRequestPayload struct {
+	CreateProductRequestPayload
+	Components []BundleComponentRequestPayload `json:"components"`
+}
+
+type BundleComponentRequestPayload struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type UpdateBundleComponentsRequestPayload struct {
+	Components []BundleComponentRequestPayload `json:"components"`
 }
 
 type ListProductRequestPayload struct {
-	Cursor int `query:"cursor" json:"cursor"`
-	Size   int `query:"size" json:"size"`
+	Cursor       string     `form:"cursor"`
+	Size         int        `form:"size"`
+	Keyword      string     `form:"keyword"`
+	NamePrefix   string     `form:"name_prefix"`
+	MinPrice     *int       `form:"min_price"`
+	MaxPrice     *int       `form:"max_price"`
+	MinStock     *int       `form:"min_stock"`
+	MaxStock     *int       `form:"max_stock"`
+	InStock      bool       `form:"in_stock"`
+	CreatedAfter *time.Time `form:"created_after"`
+	Sort         string     `form:"sort"`
+
+	// filter status hanya untuk admin, endpoint publik selalu PUBLISHED
+	Status      string `form:"status"`
+	AllStatuses bool   `form:"-"`
+
+	// attr[code]=a,b, attr_min[code] dan attr_max[code], diisi handler dari query map
+	Attributes   map[string]string `form:"-"`
+	AttributeMin map[string]string `form:"-"`
+	AttributeMax map[string]string `form:"-"`
+}
+
+// LegacyFilterRequestPayload nama query lama /products/filter (minPrice, maxPrice, minStock, maxStock),
+// masih diterima supaya client lama tidak kehilangan filternya
+type LegacyFilterRequestPayload struct {
+	MinPrice *int `form:"minPrice"`
+	MaxPrice *int `form:"maxPrice"`
+	MinStock *int `form:"minStock"`
+	MaxStock *int `form:"maxStock"`
 }
 
-func (l ListProductRequestPayload) GenerateDefaultValue() ListProductRequestPayload {
-	if l.Cursor < 0 {
-		l.Cursor = 0
+// Apply nama query baru didahulukan jika keduanya dikirim
+func (legacy LegacyFilterRequestPayload) Apply(req ListProductRequestPayload) ListProductRequestPayload {
+	if req.MinPrice == nil {
+		req.MinPrice = legacy.MinPrice
 	}
-	if l.Size <= 0 {
-		l.Size = 10
+	if req.MaxPrice == nil {
+		req.MaxPrice = legacy.MaxPrice
 	}
-	return l
+	if req.MinStock == nil {
+		req.MinStock = legacy.MinStock
+	}
+	if req.MaxStock == nil {
+		req.MaxStock = legacy.MaxStock
+	}
+	return req
+}
+
+// HasAttributeFilters definisi atribut hanya perlu diambil jika ada filter atribut
+func (req ListProductRequestPayload) HasAttributeFilters() bool {
+	return len(req.Attributes) > 0 || len(req.AttributeMin) > 0 || len(req.AttributeMax) > 0
+}
+
+// UpdateProductAttributesRequestPayload mengganti seluruh nilai atribut produk
+type UpdateProductAttributesRequestPayload struct {
+	Attributes map[string]json.RawMessage `json:"attributes"`
+}
+
+type ListDeletedProductRequestPayload struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size"`
+}
+
+type PatchProductRequestPayload struct {
+	Patch        map[string]json.RawMessage
+	IfMatch      string
+	UserPublicId string
+}
+
+type UpdateProductStatusRequestPayload struct {
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+type AddLicenseKeysRequestPayload struct {
+	Keys         []string `json:"keys"`
+	UserPublicId string   `json:"-"`
 }
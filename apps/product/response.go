@@ -1,15 +1,32 @@
 package product
 
 import (
+	"Ecommerce-basic/apps/attribute"
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/pagination"
 	"time"
 )
 
 type ProductListResponse struct {
 	Id    int    `json:"id"`
 	SKU   string `json:"sku"`
+	Slug  string `json:"slug"`
 	Name  string `json:"name"`
 	Stock int16  `json:"stock"`
 	Price int    `json:"price"`
+
+	// stok dikurangi reservation aktif
+	Available int `json:"available"`
+
+	AverageRating float64 `json:"average_rating"`
+	ReviewCount   int     `json:"review_count"`
+
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+	Type        string     `json:"type"`
+
+	Locations []inventory.StockLocationResponse `json:"locations"`
 }
 
 func NewProductListResponseFromEntity(products []Product) []ProductListResponse {
@@ -20,9 +37,19 @@ func NewProductListResponseFromEntity(products []Product) []ProductListResponse
 		productList = append(productList, ProductListResponse{
 			Id:    product.Id,
 			SKU:   product.SKU,
+			Slug:  product.Slug,
 			Name:  product.Name,
 			Stock: product.Stock,
 			Price: product.Price,
+
+			Available:     product.Available(),
+			AverageRating: product.AverageRating(),
+			ReviewCount:   product.RatingCount,
+			Status:        product.GetStatus(),
+			PublishAt:     product.PublishAt,
+			UnpublishAt:   product.UnpublishAt,
+			Type:          product.GetType(),
+			Locations:     inventory.NewStockLocationListResponse(product.Locations),
 		})
 	}
 
@@ -32,9 +59,216 @@ func NewProductListResponseFromEntity(products []Product) []ProductListResponse
 type ProductDetailResponse struct {
 	Id        int       `json:"id"`
 	SKU       string    `json:"sku"`
+	Slug      string    `json:"slug"`
 	Name      string    `json:"name"`
 	Stock     int16     `json:"stock"`
 	Price     int       `json:"price"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	Available     int                               `json:"available"`
+	AverageRating float64                           `json:"average_rating"`
+	ReviewCount   int                               `json:"review_count"`
+	Status        string                            `json:"status"`
+	PublishAt     *time.Time                        `json:"publish_at,omitempty"`
+	UnpublishAt   *time.Time                        `json:"unpublish_at,omitempty"`
+	Type          string                            `json:"type"`
+	Locations     []inventory.StockLocationResponse `json:"locations"`
+
+	// hanya untuk bundle
+	Components []BundleComponentResponse `json:"components,omitempty"`
+
+	Attributes []ProductAttributeResponse `json:"attributes"`
+
+	// hanya untuk produk digital yang punya file
+	FileName string `json:"file_name,omitempty"`
+}
+
+type DigitalFileResponse struct {
+	FileName string `json:"file_name"`
+}
+
+type LicenseKeySummaryResponse struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	Assigned  int `json:"assigned"`
+}
+
+func (s LicenseKeySummary) ToLicenseKeySummaryResponse() LicenseKeySummaryResponse {
+	return LicenseKeySummaryResponse{
+		Total:     s.Total,
+		Available: s.Available,
+		Assigned:  s.Assigned,
+	}
+}
+
+type ProductAttributeResponse struct {
+	Code  string      `json:"code"`
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+	Unit  string      `json:"unit,omitempty"`
+}
+
+func NewProductAttributeListResponse(specifications []attribute.Specification) []ProductAttributeResponse {
+	var attributeList = []ProductAttributeResponse{}
+
+	for _, specification := range specifications {
+		attributeList = append(attributeList, ProductAttributeResponse{
+			Code:  specification.Code,
+			Name:  specification.Name,
+			Type:  specification.GetType(),
+			Value: specification.Value,
+			Unit:  specification.Unit,
+		})
+	}
+
+	return attributeList
+}
+
+type BundleComponentResponse struct {
+	ProductId int    `json:"product_id"`
+	SKU       string `json:"sku"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+
+	// jumlah set yang bisa dipenuhi komponen ini
+	Available int `json:"available"`
+}
+
+func NewBundleComponentListResponse(components []BundleComponent) []BundleComponentResponse {
+	var componentList = []BundleComponentResponse{}
+
+	for _, component := range components {
+		componentList = append(componentList, BundleComponentResponse{
+			ProductId: component.ComponentId,
+			SKU:       component.SKU,
+			Name:      component.Name,
+			Quantity:  component.Quantity,
+			Available: component.Available(),
+		})
+	}
+
+	return componentList
+}
+
+type ProductListMetaResponse struct {
+	pagination.Meta
+	Facets ProductFacetsResponse `json:"facets"`
+}
+
+type ProductFacetsResponse struct {
+	Total        int               `json:"total"`
+	Availability AvailabilityFacet `json:"availability"`
+	PriceRanges  []PriceRangeFacet `json:"price_ranges"`
+}
+
+type AvailabilityFacet struct {
+	InStock    int `json:"in_stock"`
+	OutOfStock int `json:"out_of_stock"`
+}
+
+type PriceRangeFacet struct {
+	Min   int  `json:"min"`
+	Max   *int `json:"max"`
+	Count int  `json:"count"`
+}
+
+func NewProductFacetsResponseFromEntity(facets ProductFacets) ProductFacetsResponse {
+	var priceRanges = []PriceRangeFacet{}
+
+	for _, band := range facets.PriceBands {
+		priceRange := PriceRangeFacet{
+			Min:   band.Min,
+			Count: band.Count,
+		}
+		if band.Max != 0 {
+			max := band.Max
+			priceRange.Max = &max
+		}
+		priceRanges = append(priceRanges, priceRange)
+	}
+
+	return ProductFacetsResponse{
+		Total: facets.Total,
+		Availability: AvailabilityFacet{
+			InStock:    facets.InStock,
+			OutOfStock: facets.OutOfStock,
+		},
+		PriceRanges: priceRanges,
+	}
+}
+
+// DeletedProductResponse dipakai di daftar produk terhapus untuk admin
+type DeletedProductResponse struct {
+	Id        int       `json:"id"`
+	SKU       string    `json:"sku"`
+	Name      string    `json:"name"`
+	Stock     int16     `json:"stock"`
+	Price     int       `json:"price"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func NewDeletedProductListResponseFromEntity(products []Product) []DeletedProductResponse {
+	var productList = []DeletedProductResponse{}
+
+	for _, product := range products {
+		resp := DeletedProductResponse{
+			Id:    product.Id,
+			SKU:   product.SKU,
+			Name:  product.Name,
+			Stock: product.Stock,
+			Price: product.Price,
+		}
+		if product.DeletedAt != nil {
+			resp.DeletedAt = *product.DeletedAt
+		}
+		productList = append(productList, resp)
+	}
+
+	return productList
+}
+
+type ProductStatusResponse struct {
+	Id          int        `json:"id"`
+	SKU         string     `json:"sku"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+func NewProductStatusResponse(product Product) ProductStatusResponse {
+	return ProductStatusResponse{
+		Id:          product.Id,
+		SKU:         product.SKU,
+		Status:      product.GetStatus(),
+		PublishAt:   product.PublishAt,
+		UnpublishAt: product.UnpublishAt,
+	}
+}
+
+type RecentlyViewedResponse struct {
+	SKU      string    `json:"sku"`
+	Slug     string    `json:"slug"`
+	Name     string    `json:"name"`
+	Price    int       `json:"price"`
+	ViewedAt time.Time `json:"viewed_at"`
+}
+
+func NewRecentlyViewedListResponse(products []RecentlyViewedProduct) []RecentlyViewedResponse {
+	resp := []RecentlyViewedResponse{}
+	for _, p := range products {
+		resp = append(resp, RecentlyViewedResponse{
+			SKU:      p.SKU,
+			Slug:     p.Slug,
+			Name:     p.Name,
+			Price:    p.Price,
+			ViewedAt: p.ViewedAt,
+		})
+	}
+	return resp
+}
+
+type ViewSessionResponse struct {
+	SessionId string `json:"session_id"`
 }
@@ -1,42 +1,104 @@
 package product
 
 import (
+	"Ecommerce-basic/apps/attribute"
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
+	"Ecommerce-basic/infra/blobstore"
+	"Ecommerce-basic/infra/pagination"
 	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
 	"Ecommerce-basic/internal/log"
 	"context"
+	"io"
+	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type Repository interface {
-	CreateProduct(ctx context.Context, model Product) (err error)
-	GetAllProductsWithPaginationCursor(ctx context.Context, model ProductPagination) (products []Product, err error)
+	Begin(ctx context.Context) (tx *sqlx.Tx, err error)
+	Rollback(ctx context.Context, tx *sqlx.Tx) (err error)
+	Commit(ctx context.Context, tx *sqlx.Tx) (err error)
+	CreateProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (id int, err error)
+	GetProductsByQuery(ctx context.Context, model ProductQuery) (products []Product, err error)
+	GetProductFacetsByQuery(ctx context.Context, model ProductQuery) (facets ProductFacets, err error)
 	GetProductBySKU(ctx context.Context, sku string) (product Product, err error)
-	GetProductByID(ctx context.Context, id int) (product Product, err error)                                                                            // Method baru
-	UpdateProduct(ctx context.Context, model Product) (err error)                                                                                       // Method baru
-	SoftDeleteProduct(ctx context.Context, id int) (err error)                                                                                          // Method baru
-	SearchProducts(ctx context.Context, keyword string, pagination ProductPagination) (products []Product, err error)                                   // Method baru
-	FilterProducts(ctx context.Context, minPrice, maxPrice int, minStock, maxStock int16, pagination ProductPagination) (products []Product, err error) // Method baru
+	GetProductBySlug(ctx context.Context, slug string) (product Product, err error)
+	GenerateSKUWithTx(ctx context.Context, tx *sqlx.Tx) (sku string, err error)
+	GetBundleComponentsByBundleIds(ctx context.Context, bundleIds []int) (components []BundleComponent, err error)
+	CreateBundleComponentsWithTx(ctx context.Context, tx *sqlx.Tx, components []BundleComponent) (err error)
+	DeleteBundleComponentsWithTx(ctx context.Context, tx *sqlx.Tx, bundleId int) (err error)
+	TouchProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error)
+	GenerateSlugWithTx(ctx context.Context, tx *sqlx.Tx, name string) (slug string, err error)
+	GetProductByID(ctx context.Context, id int) (product Product, err error) // Method baru
+	GetProductByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (product Product, err error)
+	UpdateProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error)
+	SoftDeleteProduct(ctx context.Context, id int) (err error) // Method baru
 	GetProductByName(ctx context.Context, name string) (product Product, err error)
+	RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement inventory.StockMovement) (err error)
+	RecordPriceChangeWithTx(ctx context.Context, tx *sqlx.Tx, change pricing.PriceChange) (err error)
+	GetWarehouseStocksByProductIds(ctx context.Context, productIds []int) (stocks []inventory.WarehouseStock, err error)
+	GetReservedStocksByProductIds(ctx context.Context, productIds []int) (reserved []inventory.ReservedStock, err error)
+	GetDeletedProducts(ctx context.Context, page pagination.Request) (products []Product, err error)
+	GetDeletedProductByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (product Product, err error)
+	RestoreProductWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error)
+	HasTransactionsWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (exists bool, err error)
+	IsBundleComponentWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (exists bool, err error)
+	PurgeProductWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (err error)
+	UpdateProductStatusWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error)
+	PublishDueProducts(ctx context.Context, now time.Time) (skus []string, err error)
+	UnpublishDueProducts(ctx context.Context, now time.Time) (skus []string, err error)
+	GetAttributes(ctx context.Context) (attributes []attribute.Attribute, err error)
+	UpdateProductAttributesWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error)
+	UpdateProductFileWithTx(ctx context.Context, tx *sqlx.Tx, model Product) (err error)
+	CreateLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, keys []LicenseKey) (added int, err error)
+	GetLicenseKeySummary(ctx context.Context, productId int) (summary LicenseKeySummary, err error)
+	UpsertProductViewWithTx(ctx context.Context, tx *sqlx.Tx, view ProductView) (err error)
+	ClaimProductViewsWithTx(ctx context.Context, tx *sqlx.Tx, sessionId string, userPublicId string) (err error)
+	TrimProductViewsWithTx(ctx context.Context, tx *sqlx.Tx, userPublicId string, sessionId string, limit int) (err error)
+	GetRecentlyViewed(ctx context.Context, userPublicId string, limit int) (products []RecentlyViewedProduct, err error)
 }
 
 type service struct {
-	repo Repository
+	repo  Repository
+	blobs blobstore.BlobStore
 }
 
-func newService(repo Repository) service {
+func newService(repo Repository, blobs blobstore.BlobStore) service {
 	return service{
-		repo: repo,
+		repo:  repo,
+		blobs: blobs,
 	}
 }
 
 func (s service) CreateProduct(ctx context.Context, req CreateProductRequestPayload) (err error) {
-	productEntity := NewProductFromCreateProductRequest(req)
+	return s.createProduct(ctx, NewProductFromCreateProductRequest(req), req, nil)
+}
 
+// CreateBundle membuat produk BUNDLE dari SKU komponen, stok bundle dihitung dari komponennya
+func (s service) CreateBundle(ctx context.Context, req CreateBundleRequestPayload) (err error) {
+	bundle := NewBundleFromCreateBundleRequest(req)
+
+	components, err := s.resolveBundleComponents(ctx, bundle, req.Components)
+	if err != nil {
+		return
+	}
+	return s.createProduct(ctx, bundle, req.CreateProductRequestPayload, components)
+}
+
+func (s service) createProduct(ctx context.Context, productEntity Product, req CreateProductRequestPayload, components []BundleComponent) (err error) {
 	if err = productEntity.Validate(); err != nil {
 		log.Log.Errorf(ctx, "[CreateProduct, Validate] with error detail %v", err.Error())
 		return
 	}
 
+	// produk baru berstatus DRAFT kecuali status lain dikirim
+	if err = productEntity.SetLifecycle(req.Status, req.PublishAt, req.UnpublishAt, time.Now()); err != nil {
+		return
+	}
+
 	// Validasi keunikan nama produk
 	existingProduct, err := s.repo.GetProductByName(ctx, productEntity.Name)
 	if err != nil && err != response.ErrNotFound {
@@ -46,11 +108,65 @@ func (s service) CreateProduct(ctx context.Context, req CreateProductRequestPayl
 		return response.ErrProductAlreadyExists
 	}
 
-	if err = s.repo.CreateProduct(ctx, productEntity); err != nil {
+	// SKU dari admin harus sesuai format dan belum dipakai
+	if productEntity.SKU != "" {
+		if err = productEntity.ValidateSKU(); err != nil {
+			return
+		}
+
+		existingProduct, err = s.repo.GetProductBySKU(ctx, productEntity.SKU)
+		if err != nil && err != response.ErrNotFound {
+			return
+		}
+		if existingProduct.Id != 0 {
+			return response.ErrSKUAlreadyExists
+		}
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
 		return
 	}
+	defer s.repo.Rollback(ctx, tx)
 
-	return
+	if productEntity.SKU == "" {
+		if productEntity.SKU, err = s.repo.GenerateSKUWithTx(ctx, tx); err != nil {
+			return
+		}
+	}
+	if productEntity.Slug, err = s.repo.GenerateSlugWithTx(ctx, tx, productEntity.Name); err != nil {
+		return
+	}
+
+	if productEntity.Id, err = s.repo.CreateProductWithTx(ctx, tx, productEntity); err != nil {
+		return
+	}
+
+	if productEntity.IsBundle() {
+		for i := range components {
+			components[i].BundleId = productEntity.Id
+		}
+		if err = s.repo.CreateBundleComponentsWithTx(ctx, tx, components); err != nil {
+			return
+		}
+	} else if productEntity.Stock != 0 {
+		// stok awal dicatat di ledger supaya jumlah ledger selalu sama dengan kolom stock,
+		// produk digital yang dibuat tanpa stok tidak perlu dicatat
+		movement := productEntity.NewInitialStockMovement()
+		movement.WithActor(req.UserPublicId)
+		if err = s.repo.RecordStockMovementWithTx(ctx, tx, movement); err != nil {
+			return
+		}
+	}
+
+	// harga awal menjadi baris pertama riwayat harga
+	change := productEntity.NewInitialPriceChange()
+	change.WithActor(req.UserPublicId)
+	if err = s.repo.RecordPriceChangeWithTx(ctx, tx, change); err != nil {
+		return
+	}
+
+	return s.commit(ctx, tx)
 }
 
 //func (s service) CreateProduct(ctx context.Context, req CreateProductRequestPayload) (err error) {
@@ -68,46 +184,320 @@ func (s service) CreateProduct(ctx context.Context, req CreateProductRequestPayl
 //	return
 //}
 
-func (s service) ListProducts(ctx context.Context, req ListProductRequestPayload) (products []Product, err error) {
-	pagination := NewProductPaginationFromListProductRequest(req)
-	log.Log.Infof(ctx, "Fetching products with pagination: %+v", pagination)
+func (s service) ListProducts(ctx context.Context, req ListProductRequestPayload) (products []Product, meta pagination.Meta, err error) {
+	query, err := s.productQuery(ctx, req)
+	if err != nil {
+		return
+	}
 
-	products, err = s.repo.GetAllProductsWithPaginationCursor(ctx, pagination)
+	secret := config.Cfg.App.Encryption.CursorSecret
+	query.Page, err = pagination.NewRequest(req.Cursor, req.Size, req.Sort, secret)
+	if err != nil {
+		return
+	}
+	log.Log.Infof(ctx, "Fetching products with query: %+v", query)
+
+	products, err = s.repo.GetProductsByQuery(ctx, query)
 	if err != nil {
 		log.Log.Errorf(ctx, "Failed to fetch products: %v", err)
 		if err == response.ErrNotFound {
-			return []Product{}, nil
+			return []Product{}, meta, nil
 		}
 		return
 	}
 
+	products, meta = pagination.Paginate(products, query.Page, query.CursorKey, secret)
+
+	if err = s.attachLocations(ctx, products); err != nil {
+		return
+	}
+
 	log.Log.Infof(ctx, "Fetched %d products", len(products))
 	return
 }
 
+// ProductDetail untuk endpoint publik, produk yang belum PUBLISHED dianggap tidak ada
 func (s service) ProductDetail(ctx context.Context, sku string) (model Product, err error) {
-	model, err = s.repo.GetProductBySKU(ctx, sku)
+	model, err = s.AdminProductDetail(ctx, sku)
+	if err != nil {
+		return
+	}
+	if !model.IsPublished() {
+		return Product{}, response.ErrNotFound
+	}
+	return
+}
+
+func (s service) AdminProductDetail(ctx context.Context, sku string) (model Product, err error) {
+	return s.productDetail(ctx, s.repo.GetProductBySKU, sku)
+}
+
+// ProductDetailBySlug sama dengan ProductDetail tetapi dicari dari slug
+func (s service) ProductDetailBySlug(ctx context.Context, slug string) (model Product, err error) {
+	model, err = s.AdminProductDetailBySlug(ctx, slug)
+	if err != nil {
+		return
+	}
+	if !model.IsPublished() {
+		return Product{}, response.ErrNotFound
+	}
+	return
+}
+
+func (s service) AdminProductDetailBySlug(ctx context.Context, slug string) (model Product, err error) {
+	return s.productDetail(ctx, s.repo.GetProductBySlug, slug)
+}
+
+func (s service) productDetail(ctx context.Context, get func(ctx context.Context, key string) (Product, error), key string) (model Product, err error) {
+	model, err = get(ctx, key)
 	if err != nil {
 		if err == response.ErrNotFound {
 			return Product{}, response.ErrNotFound
 		}
 		return
 	}
+
+	products := []Product{model}
+	if err = s.attachLocations(ctx, products); err != nil {
+		return
+	}
+	model = products[0]
+
+	if len(model.Attributes) > 0 {
+		definitions, err := s.repo.GetAttributes(ctx)
+		if err != nil {
+			return Product{}, err
+		}
+		model.Specifications = model.Attributes.Specifications(definitions)
+	}
+	return model, nil
+}
+
+// UpdateProductAttributes mengganti seluruh nilai atribut produk, atribut yang tidak dikirim dihapus
+func (s service) UpdateProductAttributes(ctx context.Context, id int, req UpdateProductAttributesRequestPayload) (product Product, err error) {
+	definitions, err := s.repo.GetAttributes(ctx)
+	if err != nil {
+		return
+	}
+
+	values, err := attribute.ParseValues(definitions, req.Attributes)
+	if err != nil {
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	product, err = s.repo.GetProductByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	product.Attributes = values
+	product.Version++
+	product.UpdatedAt = time.Now()
+	if err = s.repo.UpdateProductAttributesWithTx(ctx, tx, product); err != nil {
+		return
+	}
+
+	if err = s.commit(ctx, tx); err != nil {
+		return
+	}
+	return s.AdminProductDetail(ctx, product.SKU)
+}
+
+// UpdateBundleComponents mengganti seluruh isi bundle, transaksi lama tetap memakai snapshot isinya
+func (s service) UpdateBundleComponents(ctx context.Context, id int, req UpdateBundleComponentsRequestPayload) (bundle Product, err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	bundle, err = s.repo.GetProductByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+	if !bundle.IsBundle() {
+		return Product{}, response.ErrProductNotBundle
+	}
+
+	components, err := s.resolveBundleComponents(ctx, bundle, req.Components)
+	if err != nil {
+		return
+	}
+
+	if err = s.repo.DeleteBundleComponentsWithTx(ctx, tx, bundle.Id); err != nil {
+		return
+	}
+	if err = s.repo.CreateBundleComponentsWithTx(ctx, tx, components); err != nil {
+		return
+	}
+
+	bundle.Version++
+	bundle.UpdatedAt = time.Now()
+	if err = s.repo.TouchProductWithTx(ctx, tx, bundle); err != nil {
+		return
+	}
+
+	if err = s.commit(ctx, tx); err != nil {
+		return
+	}
+	return s.AdminProductDetail(ctx, bundle.SKU)
+}
+
+// resolveBundleComponents mencari produk untuk setiap SKU komponen
+func (s service) resolveBundleComponents(ctx context.Context, bundle Product, reqs []BundleComponentRequestPayload) (components []BundleComponent, err error) {
+	if err = ValidateBundleComponents(reqs); err != nil {
+		return
+	}
+
+	for _, req := range reqs {
+		component, err := s.repo.GetProductBySKU(ctx, strings.TrimSpace(req.SKU))
+		if err != nil {
+			if err == response.ErrNotFound {
+				return nil, response.ErrBundleComponentInvalid
+			}
+			return nil, err
+		}
+
+		model, err := bundle.NewBundleComponent(component, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, model)
+	}
+	return
+}
+
+// attachLocations mengisi stok per gudang dan stok yang di-reserve untuk semua produk sekaligus
+func (s service) attachLocations(ctx context.Context, products []Product) (err error) {
+	if len(products) == 0 {
+		return
+	}
+
+	productIds := make([]int, 0, len(products))
+	for _, product := range products {
+		productIds = append(productIds, product.Id)
+	}
+
+	stocks, err := s.repo.GetWarehouseStocksByProductIds(ctx, productIds)
+	if err != nil {
+		return
+	}
+
+	locations := map[int][]inventory.WarehouseStock{}
+	for _, stock := range stocks {
+		locations[stock.ProductId] = append(locations[stock.ProductId], stock)
+	}
+
+	reservedStocks, err := s.repo.GetReservedStocksByProductIds(ctx, productIds)
+	if err != nil {
+		return
+	}
+
+	reserved := map[int]int{}
+	for _, stock := range reservedStocks {
+		reserved[stock.ProductId] = stock.Reserved
+	}
+
+	for i := range products {
+		products[i].Locations = locations[products[i].Id]
+		products[i].Reserved = reserved[products[i].Id]
+	}
+
+	return s.attachComponents(ctx, products)
+}
+
+// attachComponents mengisi komponen bundle beserta stok yang di-reserve, dipakai untuk menghitung available bundle
+func (s service) attachComponents(ctx context.Context, products []Product) (err error) {
+	bundleIds := []int{}
+	for _, product := range products {
+		if product.IsBundle() {
+			bundleIds = append(bundleIds, product.Id)
+		}
+	}
+	if len(bundleIds) == 0 {
+		return
+	}
+
+	components, err := s.repo.GetBundleComponentsByBundleIds(ctx, bundleIds)
+	if err != nil {
+		return
+	}
+
+	componentIds := make([]int, 0, len(components))
+	for _, component := range components {
+		componentIds = append(componentIds, component.ComponentId)
+	}
+
+	reservedStocks, err := s.repo.GetReservedStocksByProductIds(ctx, componentIds)
+	if err != nil {
+		return
+	}
+
+	reserved := map[int]int{}
+	for _, stock := range reservedStocks {
+		reserved[stock.ProductId] = stock.Reserved
+	}
+
+	bundles := map[int][]BundleComponent{}
+	for _, component := range components {
+		component.Reserved = reserved[component.ComponentId]
+		bundles[component.BundleId] = append(bundles[component.BundleId], component)
+	}
+
+	for i := range products {
+		if products[i].IsBundle() {
+			products[i].Components = bundles[products[i].Id]
+		}
+	}
 	return
 }
 
-func (s service) UpdateProduct(ctx context.Context, id int, req UpdateProductRequestPayload) (err error) {
-	product, err := s.repo.GetProductByID(ctx, id)
+func (s service) UpdateProduct(ctx context.Context, id int, req UpdateProductRequestPayload) (product Product, err error) {
+	return s.saveProduct(ctx, id, req.IfMatch, req.UserPublicId, func(product *Product) error {
+		product.ApplyUpdate(req)
+		return nil
+	})
+}
+
+// PatchProduct hanya mengubah field yang dikirim, sehingga dua admin yang mengubah field berbeda tidak saling menimpa
+func (s service) PatchProduct(ctx context.Context, id int, req PatchProductRequestPayload) (product Product, err error) {
+	return s.saveProduct(ctx, id, req.IfMatch, req.UserPublicId, func(product *Product) error {
+		return product.ApplyMergePatch(req.Patch)
+	})
+}
+
+// saveProduct dipakai PUT dan PATCH, If-Match diperiksa setelah baris produk dikunci
+func (s service) saveProduct(ctx context.Context, id int, ifMatch string, userPublicId string, apply func(product *Product) error) (product Product, err error) {
+	tx, err := s.repo.Begin(ctx)
 	if err != nil {
 		return
 	}
+	defer s.repo.Rollback(ctx, tx)
 
-	product.Name = req.Name
-	product.Stock = req.Stock
-	product.Price = req.Price
+	current, err := s.repo.GetProductByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	if !current.MatchETag(ifMatch) {
+		err = response.ErrProductVersionConflict
+		return
+	}
+
+	product = current
+	if err = apply(&product); err != nil {
+		return
+	}
 	product.UpdatedAt = time.Now()
+	product.Version = current.Version + 1
 
-	if err = product.Validate(); err != nil {
+	if err = product.ValidateUpdate(); err != nil {
 		return
 	}
 
@@ -117,15 +507,34 @@ func (s service) UpdateProduct(ctx context.Context, id int, req UpdateProductReq
 		if err == response.ErrNotFound {
 			//product not found, so it is valid to update the current product.
 		} else {
-			return err //return other errors
+			return product, err //return other errors
 		}
 	} else {
 		if existingProduct.Id != 0 && existingProduct.Id != product.Id {
-			return response.ErrProductAlreadyExists
+			return product, response.ErrProductAlreadyExists
 		}
 	}
 
-	return s.repo.UpdateProduct(ctx, product)
+	if err = s.repo.UpdateProductWithTx(ctx, tx, product); err != nil {
+		return
+	}
+
+	if movement, changed := product.NewStockChangeMovement(current.Stock); changed {
+		movement.WithActor(userPublicId)
+		if err = s.repo.RecordStockMovementWithTx(ctx, tx, movement); err != nil {
+			return
+		}
+	}
+
+	if change, changed := product.NewPriceChange(current.Price); changed {
+		change.WithActor(userPublicId)
+		if err = s.repo.RecordPriceChangeWithTx(ctx, tx, change); err != nil {
+			return
+		}
+	}
+
+	err = s.commit(ctx, tx)
+	return
 }
 
 //func (s service) UpdateProduct(ctx context.Context, id int, req UpdateProductRequestPayload) (err error) {
@@ -151,13 +560,371 @@ func (s service) UpdateProduct(ctx context.Context, id int, req UpdateProductReq
 //}
 
 func (s service) DeleteProduct(ctx context.Context, id int) (err error) {
-	return s.repo.SoftDeleteProduct(ctx, id)
+	if err = s.repo.SoftDeleteProduct(ctx, id); err != nil {
+		return
+	}
+	catalogCache.Purge()
+	return
 }
 
-func (s service) SearchProducts(ctx context.Context, keyword string, pagination ProductPagination) (products []Product, err error) {
-	return s.repo.SearchProducts(ctx, keyword, pagination)
+func (s service) DeletedProducts(ctx context.Context, req ListDeletedProductRequestPayload) (products []Product, meta pagination.Meta, err error) {
+	secret := config.Cfg.App.Encryption.CursorSecret
+
+	page, err := pagination.NewRequest(req.Cursor, req.Size, "", secret)
+	if err != nil {
+		return
+	}
+
+	products, err = s.repo.GetDeletedProducts(ctx, page)
+	if err != nil {
+		return
+	}
+
+	// daftar produk terhapus selalu urut berdasarkan id
+	products, meta = pagination.Paginate(products, page, ProductQuery{}.CursorKey, secret)
+	if len(products) == 0 {
+		products = []Product{}
+	}
+	return
+}
+
+// RestoreProduct gagal jika nama produk sudah dipakai produk lain selama produk ini terhapus
+func (s service) RestoreProduct(ctx context.Context, id int) (product Product, err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	product, err = s.repo.GetDeletedProductByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	existingProduct, err := s.repo.GetProductByName(ctx, product.Name)
+	if err != nil && err != response.ErrNotFound {
+		return
+	}
+	if err == nil && existingProduct.Id != product.Id {
+		err = response.ErrProductAlreadyExists
+		return
+	}
+
+	// SKU produk yang terhapus bisa sudah dipakai produk baru
+	existingProduct, err = s.repo.GetProductBySKU(ctx, product.SKU)
+	if err != nil && err != response.ErrNotFound {
+		return
+	}
+	if err == nil && existingProduct.Id != product.Id {
+		err = response.ErrSKUAlreadyExists
+		return
+	}
+
+	product.Restore()
+	if err = s.repo.RestoreProductWithTx(ctx, tx, product); err != nil {
+		return
+	}
+
+	err = s.commit(ctx, tx)
+	return
+}
+
+// PurgeProduct menghapus permanen produk yang sudah di-soft delete dan belum pernah ada di transaksi
+func (s service) PurgeProduct(ctx context.Context, id int) (err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	product, err := s.repo.GetDeletedProductByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	exists, err := s.repo.HasTransactionsWithTx(ctx, tx, product.Id)
+	if err != nil {
+		return
+	}
+	if exists {
+		return response.ErrProductHasTransactions
+	}
+
+	if exists, err = s.repo.IsBundleComponentWithTx(ctx, tx, product.Id); err != nil {
+		return
+	}
+	if exists {
+		return response.ErrProductInBundle
+	}
+
+	if err = s.repo.PurgeProductWithTx(ctx, tx, product.Id); err != nil {
+		return
+	}
+
+	return s.commit(ctx, tx)
+}
+
+func (s service) ProductFacets(ctx context.Context, req ListProductRequestPayload) (facets ProductFacets, err error) {
+	query, err := s.productQuery(ctx, req)
+	if err != nil {
+		return
+	}
+
+	return s.repo.GetProductFacetsByQuery(ctx, query)
+}
+
+// productQuery menyusun query list produk, filter atribut divalidasi terhadap definisi atribut yang ada
+func (s service) productQuery(ctx context.Context, req ListProductRequestPayload) (query ProductQuery, err error) {
+	query = NewProductQueryFromListProductRequest(req, pagination.Request{})
+	if err = query.Validate(); err != nil {
+		return
+	}
+
+	if !req.HasAttributeFilters() {
+		return
+	}
+
+	definitions, err := s.repo.GetAttributes(ctx)
+	if err != nil {
+		return
+	}
+	query.Attributes, err = attribute.NewFilters(definitions, req.Attributes, req.AttributeMin, req.AttributeMax)
+	return
 }
 
-func (s service) FilterProducts(ctx context.Context, minPrice, maxPrice int, minStock, maxStock int16, pagination ProductPagination) (products []Product, err error) {
-	return s.repo.FilterProducts(ctx, minPrice, maxPrice, minStock, maxStock, pagination)
+// UpdateProductStatus mengubah status dan jadwal publish / unpublish produk
+func (s service) UpdateProductStatus(ctx context.Context, id int, req UpdateProductStatusRequestPayload) (product Product, err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	product, err = s.repo.GetProductByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	if err = product.SetLifecycle(req.Status, req.PublishAt, req.UnpublishAt, now); err != nil {
+		return
+	}
+	product.UpdatedAt = now
+	product.Version++
+
+	if err = s.repo.UpdateProductStatusWithTx(ctx, tx, product); err != nil {
+		return
+	}
+
+	err = s.commit(ctx, tx)
+	return
+}
+
+// ApplyDueLifecycleSchedules menjalankan jadwal publish lebih dulu, sehingga publish dan unpublish
+// yang jatuh tempo di tick yang sama berakhir ARCHIVED
+func (s service) ApplyDueLifecycleSchedules(ctx context.Context, now time.Time) (published int64, unpublished int64, err error) {
+	publishedSKUs, err := s.repo.PublishDueProducts(ctx, now)
+	if err != nil {
+		log.Log.Errorf(ctx, "[ApplyDueLifecycleSchedules, PublishDueProducts] with error detail %v", err.Error())
+		return
+	}
+	published = int64(len(publishedSKUs))
+
+	unpublishedSKUs, err := s.repo.UnpublishDueProducts(ctx, now)
+	if err != nil {
+		log.Log.Errorf(ctx, "[ApplyDueLifecycleSchedules, UnpublishDueProducts] with error detail %v", err.Error())
+		return
+	}
+	unpublished = int64(len(unpublishedSKUs))
+
+	if published > 0 || unpublished > 0 {
+		catalogCache.Purge()
+		log.Log.Infof(ctx, "[ApplyDueLifecycleSchedules] published %d, unpublished %d products", published, unpublished)
+	}
+	return
+}
+
+// runLifecycleScheduler berjalan sampai ctx dibatalkan
+func (s service) runLifecycleScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ApplyDueLifecycleSchedules(ctx, now)
+		}
+	}
+}
+
+// AddLicenseKeys menambah key ke pool produk digital, stok bertambah sebanyak key yang benar-benar baru
+func (s service) AddLicenseKeys(ctx context.Context, id int, req AddLicenseKeysRequestPayload) (summary LicenseKeySummary, err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	product, err := s.repo.GetProductByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	keys, err := product.NewLicenseKeys(req.Keys)
+	if err != nil {
+		return
+	}
+
+	added, err := s.repo.CreateLicenseKeysWithTx(ctx, tx, keys)
+	if err != nil {
+		return
+	}
+
+	if added > 0 {
+		movement, err := product.AddLicenseKeyStock(added)
+		if err != nil {
+			return summary, err
+		}
+		if err = s.repo.UpdateProductWithTx(ctx, tx, product); err != nil {
+			return summary, err
+		}
+
+		movement.WithActor(req.UserPublicId)
+		if err = s.repo.RecordStockMovementWithTx(ctx, tx, movement); err != nil {
+			return summary, err
+		}
+	}
+
+	if err = s.commit(ctx, tx); err != nil {
+		return
+	}
+	return s.repo.GetLicenseKeySummary(ctx, id)
+}
+
+func (s service) LicenseKeySummary(ctx context.Context, id int) (summary LicenseKeySummary, err error) {
+	product, err := s.repo.GetProductByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if !product.IsDigital() {
+		return summary, response.ErrProductNotDigital
+	}
+	return s.repo.GetLicenseKeySummary(ctx, id)
+}
+
+// UploadDigitalFile file disimpan ke BlobStore lebih dulu, jika update produk gagal file hanya menjadi
+// blob yang tidak dipakai
+func (s service) UploadDigitalFile(ctx context.Context, id int, filename string, file io.Reader) (product Product, err error) {
+	product, err = s.repo.GetProductByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if !product.IsDigital() {
+		return Product{}, response.ErrProductNotDigital
+	}
+
+	fileKey := product.NewDigitalFileKey(filename)
+	if err = s.blobs.Put(ctx, fileKey, file); err != nil {
+		log.Log.Errorf(ctx, "[UploadDigitalFile, Put] with error detail %v", err.Error())
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	if product, err = s.repo.GetProductByIDForUpdateWithTx(ctx, tx, id); err != nil {
+		return
+	}
+	if err = product.SetDigitalFile(fileKey, filename); err != nil {
+		return
+	}
+	if err = s.repo.UpdateProductFileWithTx(ctx, tx, product); err != nil {
+		return
+	}
+
+	if err = s.commit(ctx, tx); err != nil {
+		return
+	}
+	return s.AdminProductDetail(ctx, product.SKU)
+}
+
+// RecordView dipanggil dari antrean view, produk yang sama hanya disimpan sekali dengan waktu view terakhir
+// dan riwayat dipotong sampai app.product.recently_viewed_limit
+func (s service) RecordView(ctx context.Context, view ProductView) (err error) {
+	if err = view.Validate(config.Cfg.App.Encryption.CursorSecret); err != nil {
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	if err = s.repo.UpsertProductViewWithTx(ctx, tx, view); err != nil {
+		return
+	}
+	if err = s.repo.TrimProductViewsWithTx(ctx, tx, view.UserPublicId, view.SessionId, recentlyViewedLimit()); err != nil {
+		return
+	}
+
+	return s.repo.Commit(ctx, tx)
+}
+
+// NewViewSession session id baru untuk mencatat view pengunjung yang belum login
+func (s service) NewViewSession() string {
+	return NewSessionId(config.Cfg.App.Encryption.CursorSecret)
+}
+
+// RecentlyViewed riwayat view user, view dari session anonim sebelum login ikut dipindahkan ke user
+func (s service) RecentlyViewed(ctx context.Context, userPublicId string, sessionId string) (products []RecentlyViewedProduct, err error) {
+	if sessionId != "" {
+		if err = ValidateSessionId(sessionId, config.Cfg.App.Encryption.CursorSecret); err != nil {
+			return
+		}
+		if err = s.claimViews(ctx, sessionId, userPublicId); err != nil {
+			log.Log.Errorf(ctx, "[RecentlyViewed, claimViews] with error detail %v", err.Error())
+			return
+		}
+	}
+
+	return s.repo.GetRecentlyViewed(ctx, userPublicId, recentlyViewedLimit())
+}
+
+func (s service) claimViews(ctx context.Context, sessionId string, userPublicId string) (err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	if err = s.repo.ClaimProductViewsWithTx(ctx, tx, sessionId, userPublicId); err != nil {
+		return
+	}
+	if err = s.repo.TrimProductViewsWithTx(ctx, tx, userPublicId, "", recentlyViewedLimit()); err != nil {
+		return
+	}
+
+	return s.repo.Commit(ctx, tx)
+}
+
+func recentlyViewedLimit() int {
+	if limit := config.Cfg.App.Product.RecentlyViewedLimit; limit > 0 {
+		return limit
+	}
+	return defaultRecentlyViewedLimit
+}
+
+// commit menghapus catalogCache setelah perubahan produk tersimpan
+func (s service) commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	if err = s.repo.Commit(ctx, tx); err != nil {
+		return
+	}
+	catalogCache.Purge()
+	return
 }
@@ -2,12 +2,19 @@ package product
 
 import (
 	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/blobstore"
 	"Ecommerce-basic/infra/response"
 	"Ecommerce-basic/internal"
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,7 +33,7 @@ func init() {
 	}
 
 	repo := newRepository(db)
-	svc = newService(repo)
+	svc = newService(repo, blobstore.NewLocal(filepath.Join(os.TempDir(), "ecommerce-blobs")))
 }
 
 func TestCreateProduct_Success(t *testing.T) {
@@ -55,15 +62,14 @@ func TestCreateProduct_Fail(t *testing.T) {
 }
 
 func TestListProduct_Success(t *testing.T) {
-	pagination := ListProductRequestPayload{
-		Cursor: 0,
-		Size:   10,
+	req := ListProductRequestPayload{
+		Size: 10,
 	}
 
-	products, err := svc.ListProducts(context.Background(), pagination)
+	products, meta, err := svc.ListProducts(context.Background(), req)
 	require.Nil(t, err)
 	require.NotNil(t, products)
-	log.Printf("%+v", products)
+	log.Printf("%+v %+v", products, meta)
 }
 
 func TestProductDetail_Success(t *testing.T) {
@@ -78,9 +84,8 @@ func TestProductDetail_Success(t *testing.T) {
 	err := svc.CreateProduct(ctx, req)
 	require.Nil(t, err)
 
-	products, err := svc.ListProducts(ctx, ListProductRequestPayload{
-		Cursor: 0,
-		Size:   10,
+	products, _, err := svc.ListProducts(ctx, ListProductRequestPayload{
+		Size: 10,
 	})
 
 	require.Nil(t, err)
@@ -93,3 +98,277 @@ func TestProductDetail_Success(t *testing.T) {
 
 	log.Printf("%+v", product)
 }
+
+func TestListProductWithFilter_Success(t *testing.T) {
+	minPrice := 1_000
+	req := ListProductRequestPayload{
+		MinPrice: &minPrice,
+		InStock:  true,
+		Sort:     ProductSort_Newest,
+		Size:     10,
+	}
+
+	ctx := context.Background()
+
+	products, _, err := svc.ListProducts(ctx, req)
+	require.Nil(t, err)
+	require.NotNil(t, products)
+
+	facets, err := svc.ProductFacets(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, 0, facets.OutOfStock)
+	require.Len(t, facets.PriceBands, len(DefaultPriceBands))
+}
+
+func TestListProduct_NextPage(t *testing.T) {
+	ctx := context.Background()
+
+	first, meta, err := svc.ListProducts(ctx, ListProductRequestPayload{Size: 1, Sort: ProductSort_Price})
+	require.Nil(t, err)
+	if !meta.HasMore {
+		t.Skip("need at least two products")
+	}
+
+	second, meta, err := svc.ListProducts(ctx, ListProductRequestPayload{Size: 1, Sort: ProductSort_Price, Cursor: *meta.NextCursor})
+	require.Nil(t, err)
+	require.Len(t, second, 1)
+	require.NotEqual(t, first[0].Id, second[0].Id)
+	require.NotNil(t, meta.PrevCursor)
+}
+
+func TestListProductWithFilter_Fail(t *testing.T) {
+	t.Run("sort is invalid", func(t *testing.T) {
+		req := ListProductRequestPayload{
+			Sort: "cheapest",
+		}
+
+		_, _, err := svc.ListProducts(context.Background(), req)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrSortInvalid, err)
+	})
+	t.Run("cursor is invalid", func(t *testing.T) {
+		req := ListProductRequestPayload{
+			Cursor: "not-a-cursor",
+		}
+
+		_, _, err := svc.ListProducts(context.Background(), req)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrCursorInvalid, err)
+	})
+}
+
+func TestDeleteAndRestoreProduct(t *testing.T) {
+	ctx := context.Background()
+	name := fmt.Sprintf("Restore %s", uuid.NewString()[:8])
+
+	require.Nil(t, svc.CreateProduct(ctx, CreateProductRequestPayload{Name: name, Stock: 5, Price: 10_000}))
+
+	product, err := svc.repo.GetProductByName(ctx, name)
+	require.Nil(t, err)
+
+	t.Run("delete", func(t *testing.T) {
+		require.Nil(t, svc.DeleteProduct(ctx, product.Id))
+		require.Equal(t, response.ErrNotFound, svc.DeleteProduct(ctx, product.Id))
+	})
+	t.Run("restore", func(t *testing.T) {
+		restored, err := svc.RestoreProduct(ctx, product.Id)
+		require.Nil(t, err)
+		require.False(t, restored.IsDeleted())
+
+		_, err = svc.RestoreProduct(ctx, product.Id)
+		require.Equal(t, response.ErrNotFound, err)
+	})
+	t.Run("purge requires soft delete", func(t *testing.T) {
+		require.Equal(t, response.ErrNotFound, svc.PurgeProduct(ctx, product.Id))
+
+		require.Nil(t, svc.DeleteProduct(ctx, product.Id))
+		require.Nil(t, svc.PurgeProduct(ctx, product.Id))
+	})
+	t.Run("update not found", func(t *testing.T) {
+		_, err := svc.UpdateProduct(ctx, product.Id, UpdateProductRequestPayload{Name: name, Stock: 1, Price: 1_000})
+		require.Equal(t, response.ErrNotFound, err)
+	})
+}
+
+func TestPatchProduct(t *testing.T) {
+	ctx := context.Background()
+	name := fmt.Sprintf("Patch %s", uuid.NewString()[:8])
+
+	require.Nil(t, svc.CreateProduct(ctx, CreateProductRequestPayload{Name: name, Stock: 5, Price: 10_000}))
+
+	product, err := svc.repo.GetProductByName(ctx, name)
+	require.Nil(t, err)
+	product, err = svc.repo.GetProductByID(ctx, product.Id)
+	require.Nil(t, err)
+
+	patched, err := svc.PatchProduct(ctx, product.Id, PatchProductRequestPayload{
+		Patch:   map[string]json.RawMessage{"price": json.RawMessage(`12000`)},
+		IfMatch: product.ETag(),
+	})
+	require.Nil(t, err)
+	require.Equal(t, product.Version+1, patched.Version)
+	require.Equal(t, product.Stock, patched.Stock)
+
+	t.Run("stale if-match", func(t *testing.T) {
+		_, err := svc.UpdateProduct(ctx, product.Id, UpdateProductRequestPayload{Name: name, Stock: 1, Price: 1_000, IfMatch: product.ETag()})
+		require.Equal(t, response.ErrProductVersionConflict, err)
+	})
+}
+
+func TestProductLifecycle(t *testing.T) {
+	ctx := context.Background()
+	name := fmt.Sprintf("Draft %s", uuid.NewString()[:8])
+
+	require.Nil(t, svc.CreateProduct(ctx, CreateProductRequestPayload{Name: name, Stock: 5, Price: 10_000}))
+
+	product, err := svc.repo.GetProductByName(ctx, name)
+	require.Nil(t, err)
+
+	t.Run("draft is hidden from public", func(t *testing.T) {
+		_, err := svc.ProductDetail(ctx, product.SKU)
+		require.Equal(t, response.ErrNotFound, err)
+
+		draft, err := svc.AdminProductDetail(ctx, product.SKU)
+		require.Nil(t, err)
+		require.Equal(t, PRODUCT_DRAFT, draft.GetStatus())
+	})
+	t.Run("publish", func(t *testing.T) {
+		_, err := svc.UpdateProductStatus(ctx, product.Id, UpdateProductStatusRequestPayload{Status: PRODUCT_PUBLISHED})
+		require.Nil(t, err)
+
+		published, err := svc.ProductDetail(ctx, product.SKU)
+		require.Nil(t, err)
+		require.True(t, published.IsPublished())
+	})
+}
+
+func TestCreateProduct_SKUAndSlug(t *testing.T) {
+	ctx := context.Background()
+	name := fmt.Sprintf("Produk Slug %v", uuid.NewString())
+
+	t.Run("generated sku and slug", func(t *testing.T) {
+		err := svc.CreateProduct(ctx, CreateProductRequestPayload{Name: name, Stock: 1, Price: 1_000, Status: PRODUCT_PUBLISHED})
+		require.Nil(t, err)
+
+		product, err := svc.ProductDetailBySlug(ctx, Slugify(name))
+		require.Nil(t, err)
+		require.Equal(t, name, product.Name)
+		require.NotEmpty(t, product.SKU)
+	})
+	t.Run("admin sku", func(t *testing.T) {
+		sku := fmt.Sprintf("BJ-%d", time.Now().UnixNano())
+		err := svc.CreateProduct(ctx, CreateProductRequestPayload{SKU: sku, Name: name + " 2", Stock: 1, Price: 1_000})
+		require.Nil(t, err)
+
+		product, err := svc.AdminProductDetail(ctx, sku)
+		require.Nil(t, err)
+		require.Equal(t, Slugify(name+" 2"), product.Slug)
+
+		err = svc.CreateProduct(ctx, CreateProductRequestPayload{SKU: sku, Name: name + " 3", Stock: 1, Price: 1_000})
+		require.Equal(t, response.ErrSKUAlreadyExists, err)
+	})
+	t.Run("invalid sku", func(t *testing.T) {
+		err := svc.CreateProduct(ctx, CreateProductRequestPayload{SKU: "bj 01", Name: name + " 4", Stock: 1, Price: 1_000})
+		require.Equal(t, response.ErrSKUInvalid, err)
+	})
+}
+
+func TestCreateBundle(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.NewString()
+
+	components := []BundleComponentRequestPayload{}
+	for i, stock := range []int16{10, 5} {
+		name := fmt.Sprintf("Komponen %d %v", i, suffix)
+		err := svc.CreateProduct(ctx, CreateProductRequestPayload{Name: name, Stock: stock, Price: 10_000})
+		require.Nil(t, err)
+
+		products, _, err := svc.ListProducts(ctx, ListProductRequestPayload{Keyword: name, AllStatuses: true, Size: 1})
+		require.Nil(t, err)
+		components = append(components, BundleComponentRequestPayload{SKU: products[0].SKU, Quantity: i + 1})
+	}
+
+	name := fmt.Sprintf("Paket %v", suffix)
+	err := svc.CreateBundle(ctx, CreateBundleRequestPayload{
+		CreateProductRequestPayload: CreateProductRequestPayload{Name: name, Price: 15_000, Status: PRODUCT_PUBLISHED},
+		Components:                  components,
+	})
+	require.Nil(t, err)
+
+	bundle, err := svc.ProductDetailBySlug(ctx, Slugify(name))
+	require.Nil(t, err)
+	require.True(t, bundle.IsBundle())
+	require.Len(t, bundle.Components, 2)
+	require.Equal(t, int16(2), bundle.Stock)
+	require.Equal(t, 2, bundle.Available())
+
+	t.Run("nested bundle", func(t *testing.T) {
+		err := svc.CreateBundle(ctx, CreateBundleRequestPayload{
+			CreateProductRequestPayload: CreateProductRequestPayload{Name: name + " 2", Price: 15_000},
+			Components:                  []BundleComponentRequestPayload{{SKU: bundle.SKU, Quantity: 1}},
+		})
+		require.Equal(t, response.ErrBundleComponentInvalid, err)
+	})
+}
+
+func TestUpdateProductAttributes(t *testing.T) {
+	ctx := context.Background()
+	name := fmt.Sprintf("Produk Atribut %v", uuid.NewString())
+
+	err := svc.CreateProduct(ctx, CreateProductRequestPayload{Name: name, Stock: 5, Price: 10_000})
+	require.Nil(t, err)
+
+	product, err := svc.AdminProductDetailBySlug(ctx, Slugify(name))
+	require.Nil(t, err)
+	require.Empty(t, product.Specifications)
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		_, err := svc.UpdateProductAttributes(ctx, product.Id, UpdateProductAttributesRequestPayload{
+			Attributes: map[string]json.RawMessage{uuid.NewString(): json.RawMessage(`"x"`)},
+		})
+		require.Equal(t, response.ErrAttributeValueInvalid, err)
+	})
+	t.Run("clear attributes bumps version", func(t *testing.T) {
+		updated, err := svc.UpdateProductAttributes(ctx, product.Id, UpdateProductAttributesRequestPayload{})
+		require.Nil(t, err)
+		require.Equal(t, product.Version+1, updated.Version)
+	})
+	t.Run("unknown attribute filter", func(t *testing.T) {
+		_, _, err := svc.ListProducts(ctx, ListProductRequestPayload{Attributes: map[string]string{uuid.NewString(): "x"}})
+		require.Equal(t, response.ErrAttributeFilterInvalid, err)
+	})
+}
+
+func TestRecentlyViewed(t *testing.T) {
+	ctx := context.Background()
+	userPublicId := uuid.NewString()
+	sessionId := uuid.NewString()
+
+	var products []Product
+	for i := 0; i < 2; i++ {
+		name := fmt.Sprintf("Produk View %v", uuid.NewString())
+		err := svc.CreateProduct(ctx, CreateProductRequestPayload{Name: name, Stock: 5, Price: 10_000, Status: PRODUCT_PUBLISHED})
+		require.Nil(t, err)
+
+		product, err := svc.ProductDetailBySlug(ctx, Slugify(name))
+		require.Nil(t, err)
+		products = append(products, product)
+	}
+
+	now := time.Now()
+	require.Nil(t, svc.RecordView(ctx, NewProductView("", sessionId, products[0].Id, now.Add(-2*time.Minute))))
+	require.Nil(t, svc.RecordView(ctx, NewProductView(userPublicId, "", products[1].Id, now.Add(-time.Minute))))
+	require.Nil(t, svc.RecordView(ctx, NewProductView(userPublicId, "", products[0].Id, now)))
+
+	t.Run("deduplicated and newest first", func(t *testing.T) {
+		viewed, err := svc.RecentlyViewed(ctx, userPublicId, sessionId)
+		require.Nil(t, err)
+		require.Len(t, viewed, 2)
+		require.Equal(t, products[0].SKU, viewed[0].SKU)
+		require.Equal(t, products[1].SKU, viewed[1].SKU)
+	})
+	t.Run("invalid session id", func(t *testing.T) {
+		_, err := svc.RecentlyViewed(ctx, userPublicId, "x")
+		require.Equal(t, response.ErrSessionIdInvalid, err)
+	})
+}
@@ -0,0 +1,57 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"strings"
+)
+
+type ProductType uint8
+
+const (
+	ProductType_Standard ProductType = 1
+	ProductType_Bundle   ProductType = 2
+	ProductType_Digital  ProductType = 3
+
+	PRODUCT_TYPE_STANDARD string = "STANDARD"
+	PRODUCT_TYPE_BUNDLE   string = "BUNDLE"
+	PRODUCT_TYPE_DIGITAL  string = "DIGITAL"
+	PRODUCT_TYPE_UNKNOWN  string = "UNKNOWN"
+)
+
+var (
+	MappingProductType = map[ProductType]string{
+		ProductType_Standard: PRODUCT_TYPE_STANDARD,
+		ProductType_Bundle:   PRODUCT_TYPE_BUNDLE,
+		ProductType_Digital:  PRODUCT_TYPE_DIGITAL,
+	}
+)
+
+// ParseProductType untuk POST /products, kosong berarti STANDARD. BUNDLE dibuat lewat POST /products/bundles
+func ParseProductType(name string) (productType ProductType, err error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "", PRODUCT_TYPE_STANDARD:
+		return ProductType_Standard, nil
+	case PRODUCT_TYPE_DIGITAL:
+		return ProductType_Digital, nil
+	}
+	return productType, response.ErrProductTypeInvalid
+}
+
+// IsBundle stok bundle tidak disimpan sendiri, melainkan dihitung dari komponennya
+func (p Product) IsBundle() bool {
+	return p.Type == ProductType_Bundle
+}
+
+// IsDigital stok produk digital adalah jumlah salinan yang bisa dijual, untuk produk dengan
+// license key selalu sama dengan jumlah key yang belum terpakai
+func (p Product) IsDigital() bool {
+	return p.Type == ProductType_Digital
+}
+
+func (p Product) GetType() string {
+	productType, ok := MappingProductType[p.Type]
+	if !ok {
+		return PRODUCT_TYPE_UNKNOWN
+	}
+	return productType
+}
@@ -0,0 +1,130 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal/log"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// header session id pengunjung yang belum login, dibuat server lewat POST /products/view-sessions
+	HEADER_SESSION_ID string = "X-Session-Id"
+
+	// nilai default jika app.product.view_queue_size / recently_viewed_limit tidak diatur
+	defaultViewQueueSize       = 1000
+	defaultRecentlyViewedLimit = 20
+)
+
+// ProductView adalah view terakhir satu produk oleh user atau session anonim,
+// user_public_id dan session_id yang tidak dipakai diisi string kosong
+type ProductView struct {
+	Id           int       `db:"id"`
+	UserPublicId string    `db:"user_public_id"`
+	SessionId    string    `db:"session_id"`
+	ProductId    int       `db:"product_id"`
+	ViewedAt     time.Time `db:"viewed_at"`
+}
+
+// NewProductView view user yang login disimpan ke user, session id hanya dipakai untuk pengunjung anonim
+func NewProductView(userPublicId string, sessionId string, productId int, now time.Time) ProductView {
+	if userPublicId != "" {
+		sessionId = ""
+	}
+	return ProductView{
+		UserPublicId: userPublicId,
+		SessionId:    sessionId,
+		ProductId:    productId,
+		ViewedAt:     now,
+	}
+}
+
+func (v ProductView) Validate(secret string) (err error) {
+	if v.UserPublicId != "" {
+		return
+	}
+	return ValidateSessionId(v.SessionId, secret)
+}
+
+// NewSessionId session id acak yang ditandatangani server dengan format <uuid>_<signature>
+func NewSessionId(secret string) string {
+	id := uuid.NewString()
+	return id + "_" + signSessionId(id, secret)
+}
+
+// ValidateSessionId hanya menerima session id dari NewSessionId,
+// supaya client tidak bisa mengarang session id lalu mengambil riwayat view session lain
+func ValidateSessionId(sessionId string, secret string) (err error) {
+	id, signature, found := strings.Cut(sessionId, "_")
+	if !found || id == "" || !hmac.Equal([]byte(signature), []byte(signSessionId(id, secret))) {
+		return response.ErrSessionIdInvalid
+	}
+	return
+}
+
+func signSessionId(id string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("session:" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// RecentlyViewedProduct produk dari riwayat view user beserta waktu view terakhir
+type RecentlyViewedProduct struct {
+	SKU      string    `db:"sku"`
+	Slug     string    `db:"slug"`
+	Name     string    `db:"name"`
+	Price    int       `db:"price"`
+	ViewedAt time.Time `db:"viewed_at"`
+}
+
+// viewQueue antrean in-process supaya pencatatan view tidak menambah latency detail produk,
+// view dibuang ketika antrean penuh karena riwayat view tidak wajib lengkap
+type viewQueue struct {
+	views   chan ProductView
+	dropped atomic.Int64
+}
+
+func newViewQueue(size int) *viewQueue {
+	if size <= 0 {
+		size = defaultViewQueueSize
+	}
+	return &viewQueue{
+		views: make(chan ProductView, size),
+	}
+}
+
+// Enqueue tidak pernah menunggu, false berarti view dibuang
+func (q *viewQueue) Enqueue(view ProductView) bool {
+	select {
+	case q.views <- view:
+		return true
+	default:
+		q.dropped.Add(1)
+		return false
+	}
+}
+
+func (q *viewQueue) Dropped() int64 {
+	return q.dropped.Load()
+}
+
+// run mencatat view satu per satu sampai ctx dibatalkan
+func (q *viewQueue) run(ctx context.Context, record func(ctx context.Context, view ProductView) error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case view := <-q.views:
+			if err := record(ctx, view); err != nil {
+				log.Log.Errorf(ctx, "[viewQueue, record] with error detail %v", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package product
+
+import (
+	"Ecommerce-basic/infra/response"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProductView(t *testing.T) {
+	now := time.Now()
+	secret := "secret"
+	sessionId := NewSessionId(secret)
+
+	t.Run("authenticated user", func(t *testing.T) {
+		view := NewProductView("user-1", sessionId, 5, now)
+		require.Nil(t, view.Validate(secret))
+		require.Equal(t, "user-1", view.UserPublicId)
+		require.Empty(t, view.SessionId)
+	})
+	t.Run("anonymous session", func(t *testing.T) {
+		view := NewProductView("", sessionId, 5, now)
+		require.Nil(t, view.Validate(secret))
+		require.Equal(t, sessionId, view.SessionId)
+	})
+	t.Run("anonymous without valid session", func(t *testing.T) {
+		for _, sessionId := range []string{"", "session-123", "session_id", NewSessionId("other")} {
+			require.Equal(t, response.ErrSessionIdInvalid, NewProductView("", sessionId, 5, now).Validate(secret))
+		}
+	})
+}
+
+func TestValidateSessionId(t *testing.T) {
+	secret := "secret"
+	sessionId := NewSessionId(secret)
+	require.Nil(t, ValidateSessionId(sessionId, secret))
+	require.NotEqual(t, sessionId, NewSessionId(secret))
+
+	// id yang diganti tidak cocok lagi dengan signature
+	_, signature, _ := strings.Cut(sessionId, "_")
+	require.Equal(t, response.ErrSessionIdInvalid, ValidateSessionId(uuid.NewString()+"_"+signature, secret))
+}
+
+func TestViewQueue(t *testing.T) {
+	queue := newViewQueue(2)
+	require.True(t, queue.Enqueue(ProductView{ProductId: 1}))
+	require.True(t, queue.Enqueue(ProductView{ProductId: 2}))
+
+	// antrean penuh, view dibuang tanpa menunggu
+	require.False(t, queue.Enqueue(ProductView{ProductId: 3}))
+	require.Equal(t, int64(1), queue.Dropped())
+
+	recorded := make(chan int, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.run(ctx, func(ctx context.Context, view ProductView) error {
+		recorded <- view.ProductId
+		return nil
+	})
+
+	require.Equal(t, 1, <-recorded)
+	require.Equal(t, 2, <-recorded)
+}
@@ -0,0 +1,24 @@
+package productimport
+
+import (
+	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/infra/gin"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	repo := newCachedRepository(newRepository(db))
+	svc := newService(repo)
+	handler := newHandler(svc)
+
+	importRoute := router.Group("/products/imports")
+	{
+		importRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		importRoute.POST("", handler.CreateImport)
+		importRoute.GET("/:id", handler.GetImport)
+		importRoute.GET("/:id/errors", handler.GetImportErrors)
+	}
+}
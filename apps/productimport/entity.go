@@ -0,0 +1,331 @@
+package productimport
+
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/response"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ImportJobStatus uint8
+
+const (
+	ImportJobStatus_Pending   ImportJobStatus = 1
+	ImportJobStatus_Running   ImportJobStatus = 10
+	ImportJobStatus_Completed ImportJobStatus = 20
+	ImportJobStatus_Failed    ImportJobStatus = 30
+
+	IMPORT_PENDING   string = "PENDING"
+	IMPORT_RUNNING   string = "RUNNING"
+	IMPORT_COMPLETED string = "COMPLETED"
+	IMPORT_FAILED    string = "FAILED"
+	IMPORT_UNKNOWN   string = "UNKNOWN"
+
+	FORMAT_CSV   string = "csv"
+	FORMAT_JSONL string = "jsonl"
+
+	MODE_CREATE string = "create"
+	MODE_UPSERT string = "upsert"
+
+	// progress disimpan ke database setiap sekian baris
+	progressInterval = 50
+)
+
+var (
+	MappingImportJobStatus = map[ImportJobStatus]string{
+		ImportJobStatus_Pending:   IMPORT_PENDING,
+		ImportJobStatus_Running:   IMPORT_RUNNING,
+		ImportJobStatus_Completed: IMPORT_COMPLETED,
+		ImportJobStatus_Failed:    IMPORT_FAILED,
+	}
+
+	// ekstensi file yang dikenali ketika format tidak dikirim
+	MappingFileExtension = map[string]string{
+		".csv":    FORMAT_CSV,
+		".jsonl":  FORMAT_JSONL,
+		".ndjson": FORMAT_JSONL,
+	}
+)
+
+type ImportJob struct {
+	Id            int             `db:"id"`
+	Format        string          `db:"format"`
+	Mode          string          `db:"mode"`
+	DryRun        bool            `db:"dry_run"`
+	Status        ImportJobStatus `db:"status"`
+	TotalRows     int             `db:"total_rows"`
+	ProcessedRows int             `db:"processed_rows"`
+	CreatedRows   int             `db:"created_rows"`
+	UpdatedRows   int             `db:"updated_rows"`
+	FailedRows    int             `db:"failed_rows"`
+	Message       string          `db:"message"`
+	CreatedBy     string          `db:"created_by"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+	FinishedAt    *time.Time      `db:"finished_at"`
+}
+
+type ImportRow struct {
+	Line  int    `json:"-"`
+	SKU   string `json:"sku"`
+	Name  string `json:"name"`
+	Stock int16  `json:"stock"`
+	Price int    `json:"price"`
+
+	// status produk baru, kosong berarti DRAFT seperti produk dari API
+	Status string `json:"status"`
+
+	// error ketika baris tidak bisa dibaca, contoh: stock bukan angka
+	ParseErr error `json:"-"`
+}
+
+type ImportRowError struct {
+	JobId   int    `db:"job_id"`
+	Line    int    `db:"line"`
+	SKU     string `db:"sku"`
+	Name    string `db:"name"`
+	Message string `db:"message"`
+}
+
+func NewImportJobFromCreateRequest(req CreateImportRequestPayload) ImportJob {
+	return ImportJob{
+		Format:    req.Format,
+		Mode:      req.Mode,
+		DryRun:    req.DryRun,
+		Status:    ImportJobStatus_Pending,
+		CreatedBy: req.UserPublicId,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (j ImportJob) Validate() (err error) {
+	if j.Format != FORMAT_CSV && j.Format != FORMAT_JSONL {
+		return response.ErrImportFormatInvalid
+	}
+	if j.Mode != MODE_CREATE && j.Mode != MODE_UPSERT {
+		return response.ErrImportModeInvalid
+	}
+	return
+}
+
+func (j ImportJob) GetStatus() string {
+	status, ok := MappingImportJobStatus[j.Status]
+	if !ok {
+		return IMPORT_UNKNOWN
+	}
+	return status
+}
+
+func (j ImportJob) IsFinished() bool {
+	return j.Status == ImportJobStatus_Completed || j.Status == ImportJobStatus_Failed
+}
+
+func (j *ImportJob) Start(totalRows int) {
+	j.Status = ImportJobStatus_Running
+	j.TotalRows = totalRows
+	j.UpdatedAt = time.Now()
+}
+
+func (j *ImportJob) Finish() {
+	now := time.Now()
+	j.Status = ImportJobStatus_Completed
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+func (j *ImportJob) Fail(err error) {
+	now := time.Now()
+	j.Status = ImportJobStatus_Failed
+	j.Message = err.Error()
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+// ShouldSaveProgress mengurangi jumlah update ke database untuk file yang besar
+func (j ImportJob) ShouldSaveProgress() bool {
+	return j.ProcessedRows%progressInterval == 0
+}
+
+func FormatFromFilename(filename string) string {
+	return MappingFileExtension[strings.ToLower(filepath.Ext(filename))]
+}
+
+// ToProduct membiarkan SKU kosong jika baris tidak punya sku, SKU dibuat saat produk disimpan.
+// Status hanya dipakai untuk produk baru, import tidak mengubah status produk yang sudah ada
+func (r ImportRow) ToProduct() (model product.Product, err error) {
+	model = product.Product{
+		SKU:       r.SKU,
+		Name:      r.Name,
+		Stock:     r.Stock,
+		Price:     r.Price,
+		Status:    product.ProductStatus_Draft,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if r.Status != "" {
+		if model.Status, err = product.ParseProductStatus(strings.TrimSpace(r.Status)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// NewImportStockMovement mencatat selisih stok dari baris import, changed false jika stok tidak berubah
+func NewImportStockMovement(job ImportJob, model product.Product, previousStock int16) (movement inventory.StockMovement, changed bool) {
+	delta := int(model.Stock) - int(previousStock)
+	if delta == 0 {
+		return movement, false
+	}
+
+	movement = inventory.NewStockMovement(model.Id, inventory.MovementType_Import, delta, int(model.Stock), inventory.REASON_IMPORT)
+	movement.WithReference(inventory.REFERENCE_IMPORT_JOB, job.Id).
+		WithActor(job.CreatedBy)
+	return movement, true
+}
+
+// NewImportPriceChange mencatat perubahan harga dari baris import, changed false jika harga tidak berubah.
+// Produk baru dicatat sebagai harga awal.
+func NewImportPriceChange(job ImportJob, model product.Product, previousPrice int, created bool) (change pricing.PriceChange, changed bool) {
+	if created {
+		change = pricing.NewPriceChange(model.Id, 0, model.Price, pricing.REASON_INITIAL_PRICE)
+	} else if model.Price != previousPrice {
+		change = pricing.NewPriceChange(model.Id, previousPrice, model.Price, pricing.REASON_IMPORT)
+	} else {
+		return change, false
+	}
+
+	change.WithActor(job.CreatedBy)
+	return change, true
+}
+
+func (r ImportRow) ToRowError(jobId int, err error) ImportRowError {
+	return ImportRowError{
+		JobId:   jobId,
+		Line:    r.Line,
+		SKU:     r.SKU,
+		Name:    r.Name,
+		Message: err.Error(),
+	}
+}
+
+// ParseRows membaca isi file sesuai format. Baris yang rusak tetap dikembalikan
+// dengan ParseErr supaya muncul di laporan error, hanya file yang tidak bisa dibaca
+// sama sekali yang mengembalikan error.
+func ParseRows(format string, data []byte) (rows []ImportRow, err error) {
+	switch format {
+	case FORMAT_CSV:
+		return parseCSV(bytes.NewReader(data))
+	case FORMAT_JSONL:
+		return parseJSONL(bytes.NewReader(data))
+	}
+	return nil, response.ErrImportFormatInvalid
+}
+
+func parseCSV(reader io.Reader) (rows []ImportRow, err error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, response.ErrImportHeaderInvalid
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{"name", "stock", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, response.ErrImportHeaderInvalid
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	for {
+		record, readErr := csvReader.Read()
+		if readErr == io.EOF {
+			break
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		row := ImportRow{Line: line}
+
+		if readErr != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(readErr, &parseErr) {
+				return nil, readErr
+			}
+			row.Line = parseErr.StartLine
+			row.ParseErr = fmt.Errorf("invalid csv row: %w", parseErr.Err)
+			rows = append(rows, row)
+			continue
+		}
+
+		row.SKU = field(record, "sku")
+		row.Name = field(record, "name")
+		row.Status = field(record, "status")
+
+		stock, stockErr := strconv.ParseInt(field(record, "stock"), 10, 16)
+		if stockErr != nil {
+			row.ParseErr = fmt.Errorf("stock must be a valid number")
+		}
+		row.Stock = int16(stock)
+
+		price, priceErr := strconv.Atoi(field(record, "price"))
+		if priceErr != nil && row.ParseErr == nil {
+			row.ParseErr = fmt.Errorf("price must be a valid number")
+		}
+		row.Price = price
+
+		rows = append(rows, row)
+	}
+	return
+}
+
+func parseJSONL(reader io.Reader) (rows []ImportRow, err error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := ImportRow{}
+		if jsonErr := json.Unmarshal([]byte(text), &row); jsonErr != nil {
+			row = ImportRow{ParseErr: fmt.Errorf("invalid json line: %v", jsonErr)}
+		}
+		row.Line = line
+
+		rows = append(rows, row)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
@@ -0,0 +1,167 @@
+package productimport
+
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRows(t *testing.T) {
+	t.Run("csv success", func(t *testing.T) {
+		data := []byte("sku,name,stock,price\nBJ-01,Baju Baru,10,100000\n,Celana Baru,5,150000\n")
+
+		rows, err := ParseRows(FORMAT_CSV, data)
+		require.Nil(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, ImportRow{Line: 2, SKU: "BJ-01", Name: "Baju Baru", Stock: 10, Price: 100_000}, rows[0])
+		require.Equal(t, 3, rows[1].Line)
+		require.Empty(t, rows[1].SKU)
+	})
+	t.Run("csv header in any order", func(t *testing.T) {
+		data := []byte("Price,Name,Stock\n100000,Baju Baru,10\n")
+
+		rows, err := ParseRows(FORMAT_CSV, data)
+		require.Nil(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "Baju Baru", rows[0].Name)
+		require.Equal(t, 100_000, rows[0].Price)
+	})
+	t.Run("csv header invalid", func(t *testing.T) {
+		data := []byte("title,qty\nBaju Baru,10\n")
+
+		_, err := ParseRows(FORMAT_CSV, data)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrImportHeaderInvalid, err)
+	})
+	t.Run("csv row with invalid number", func(t *testing.T) {
+		data := []byte("name,stock,price\nBaju Baru,sepuluh,100000\n")
+
+		rows, err := ParseRows(FORMAT_CSV, data)
+		require.Nil(t, err)
+		require.Len(t, rows, 1)
+		require.NotNil(t, rows[0].ParseErr)
+	})
+	t.Run("jsonl success", func(t *testing.T) {
+		data := []byte("{\"sku\":\"BJ-01\",\"name\":\"Baju Baru\",\"stock\":10,\"price\":100000}\n\n{\"name\":\"Celana Baru\",\"stock\":5,\"price\":150000}\n")
+
+		rows, err := ParseRows(FORMAT_JSONL, data)
+		require.Nil(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, ImportRow{Line: 1, SKU: "BJ-01", Name: "Baju Baru", Stock: 10, Price: 100_000}, rows[0])
+		require.Equal(t, 3, rows[1].Line)
+	})
+	t.Run("jsonl invalid line", func(t *testing.T) {
+		data := []byte("{\"name\":\"Baju Baru\",\"stock\":10,\"price\":100000}\nnot json\n")
+
+		rows, err := ParseRows(FORMAT_JSONL, data)
+		require.Nil(t, err)
+		require.Len(t, rows, 2)
+		require.Nil(t, rows[0].ParseErr)
+		require.NotNil(t, rows[1].ParseErr)
+		require.Equal(t, 2, rows[1].Line)
+	})
+}
+
+func TestImportRowToProduct(t *testing.T) {
+	t.Run("draft by default", func(t *testing.T) {
+		model, err := ImportRow{Name: "Baju Baru", Stock: 10, Price: 100_000}.ToProduct()
+		require.Nil(t, err)
+		require.Equal(t, product.ProductStatus_Draft, model.Status)
+	})
+	t.Run("status from row", func(t *testing.T) {
+		rows, err := ParseRows(FORMAT_CSV, []byte("name,stock,price,status\nBaju Baru,10,100000,published\n"))
+		require.Nil(t, err)
+
+		model, err := rows[0].ToProduct()
+		require.Nil(t, err)
+		require.Equal(t, product.ProductStatus_Published, model.Status)
+	})
+	t.Run("status invalid", func(t *testing.T) {
+		_, err := ImportRow{Name: "Baju Baru", Status: "live"}.ToProduct()
+		require.Equal(t, response.ErrProductStatusInvalid, err)
+	})
+}
+
+func TestValidateImportJob(t *testing.T) {
+	t.Run("format from filename", func(t *testing.T) {
+		req := CreateImportRequestPayload{}.GenerateDefaultValue("products.JSONL")
+
+		job := NewImportJobFromCreateRequest(req)
+		require.Nil(t, job.Validate())
+		require.Equal(t, FORMAT_JSONL, job.Format)
+		require.Equal(t, MODE_CREATE, job.Mode)
+	})
+	t.Run("format invalid", func(t *testing.T) {
+		req := CreateImportRequestPayload{}.GenerateDefaultValue("products.xlsx")
+
+		err := NewImportJobFromCreateRequest(req).Validate()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrImportFormatInvalid, err)
+	})
+	t.Run("mode invalid", func(t *testing.T) {
+		req := CreateImportRequestPayload{Mode: "replace"}.GenerateDefaultValue("products.csv")
+
+		err := NewImportJobFromCreateRequest(req).Validate()
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrImportModeInvalid, err)
+	})
+}
+
+func TestImportJobResponse(t *testing.T) {
+	job := ImportJob{Id: 1, Status: ImportJobStatus_Running, TotalRows: 200, ProcessedRows: 50, FailedRows: 2}
+
+	resp := job.ToImportJobResponse()
+	require.Equal(t, IMPORT_RUNNING, resp.Status)
+	require.Equal(t, 25, resp.Progress)
+	require.Equal(t, "/products/imports/1/errors", resp.ErrorReportURL)
+}
+
+func TestNewImportStockMovement(t *testing.T) {
+	job := ImportJob{Id: 7, CreatedBy: "admin-1"}
+
+	t.Run("new product", func(t *testing.T) {
+		movement, changed := NewImportStockMovement(job, product.Product{Id: 1, Stock: 10}, 0)
+		require.True(t, changed)
+		require.Equal(t, inventory.MovementType_Import, movement.Type)
+		require.Equal(t, 10, movement.Quantity)
+		require.Equal(t, 7, *movement.ReferenceId)
+		require.Equal(t, "admin-1", movement.ActorPublicId)
+	})
+	t.Run("upsert lowers stock", func(t *testing.T) {
+		movement, changed := NewImportStockMovement(job, product.Product{Id: 1, Stock: 4}, 10)
+		require.True(t, changed)
+		require.Equal(t, -6, movement.Quantity)
+		require.Equal(t, 4, movement.StockAfter)
+	})
+	t.Run("stock unchanged", func(t *testing.T) {
+		_, changed := NewImportStockMovement(job, product.Product{Id: 1, Stock: 4}, 4)
+		require.False(t, changed)
+	})
+}
+
+func TestNewImportPriceChange(t *testing.T) {
+	job := ImportJob{Id: 7, CreatedBy: "admin-1"}
+
+	t.Run("new product", func(t *testing.T) {
+		change, changed := NewImportPriceChange(job, product.Product{Id: 1, Price: 10_000}, 0, true)
+		require.True(t, changed)
+		require.Equal(t, pricing.REASON_INITIAL_PRICE, change.Reason)
+		require.Equal(t, 10_000, change.NewPrice)
+		require.Equal(t, "admin-1", change.ActorPublicId)
+	})
+	t.Run("upsert changes price", func(t *testing.T) {
+		change, changed := NewImportPriceChange(job, product.Product{Id: 1, Price: 12_000}, 10_000, false)
+		require.True(t, changed)
+		require.Equal(t, pricing.REASON_IMPORT, change.Reason)
+		require.Equal(t, 10_000, change.OldPrice)
+		require.True(t, change.EndsSale())
+	})
+	t.Run("price unchanged", func(t *testing.T) {
+		_, changed := NewImportPriceChange(job, product.Product{Id: 1, Price: 10_000}, 10_000, false)
+		require.False(t, changed)
+	})
+}
@@ -0,0 +1,178 @@
+package productimport
+
+import (
+	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/infra/response"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// batas ukuran file yang boleh diupload, 10 MB
+const maxImportFileSize = 10 << 20
+
+type handler struct {
+	svc service
+}
+
+func newHandler(svc service) handler {
+	return handler{
+		svc: svc,
+	}
+}
+
+func (h handler) CreateImport(c *gin.Context) {
+	var req CreateImportRequestPayload
+
+	if err := c.ShouldBind(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(response.ErrImportFileRequired.Error()),
+			infragin.WithError(response.ErrorImportFileRequired),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusRequestEntityTooLarge),
+			infragin.WithMessage(response.ErrImportFileTooLarge.Error()),
+			infragin.WithError(response.ErrorImportFileTooLarge),
+		)
+		resp.Send(c)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorGeneral),
+		)
+		resp.Send(c)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorGeneral),
+		)
+		resp.Send(c)
+		return
+	}
+
+	req.UserPublicId = c.GetString("PUBLIC_ID")
+
+	job, err := h.svc.CreateImport(c.Request.Context(), req, fileHeader.Filename, data)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusAccepted),
+		infragin.WithMessage("create product import success"),
+		infragin.WithPayload(job.ToImportJobResponse()),
+	)
+	resp.Send(c)
+}
+
+func (h handler) GetImport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid import ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	job, err := h.svc.ImportJob(c.Request.Context(), id)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("get product import success"),
+		infragin.WithPayload(job.ToImportJobResponse()),
+	)
+	resp.Send(c)
+}
+
+// GetImportErrors mengirim laporan error per baris sebagai file CSV
+func (h handler) GetImportErrors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid import ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	rowErrors, err := h.svc.ImportErrorReport(c.Request.Context(), id)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="product-import-%d-errors.csv"`, id))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"line", "sku", "name", "error"})
+	for _, rowError := range rowErrors {
+		writer.Write([]string{strconv.Itoa(rowError.Line), rowError.SKU, rowError.Name, rowError.Message})
+	}
+	writer.Flush()
+}
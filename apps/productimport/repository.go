@@ -0,0 +1,224 @@
+package productimport
+
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+func (r repository) CreateImportJob(ctx context.Context, model ImportJob) (id int, err error) {
+	query := `
+		INSERT INTO product_import_jobs (
+			format, mode, dry_run, status, total_rows
+			, processed_rows, created_rows, updated_rows, failed_rows
+			, message, created_by, created_at, updated_at
+		) VALUES (
+			:format, :mode, :dry_run, :status, :total_rows
+			, :processed_rows, :created_rows, :updated_rows, :failed_rows
+			, :message, :created_by, :created_at, :updated_at
+		)
+		RETURNING id
+	`
+
+	stmt, err := r.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	err = stmt.GetContext(ctx, &id, model)
+	return
+}
+
+func (r repository) UpdateImportJob(ctx context.Context, model ImportJob) (err error) {
+	query := `
+		UPDATE product_import_jobs
+		SET status=:status, total_rows=:total_rows, processed_rows=:processed_rows
+			, created_rows=:created_rows, updated_rows=:updated_rows, failed_rows=:failed_rows
+			, message=:message, updated_at=:updated_at, finished_at=:finished_at
+		WHERE id=:id
+	`
+
+	_, err = r.db.NamedExecContext(ctx, query, model)
+	return
+}
+
+func (r repository) GetImportJobById(ctx context.Context, id int) (model ImportJob, err error) {
+	query := `
+		SELECT
+			id, format, mode, dry_run, status, total_rows
+			, processed_rows, created_rows, updated_rows, failed_rows
+			, message, created_by, created_at, updated_at, finished_at
+		FROM product_import_jobs
+		WHERE id=$1
+	`
+
+	err = r.db.GetContext(ctx, &model, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) CreateImportRowErrors(ctx context.Context, rowErrors []ImportRowError) (err error) {
+	if len(rowErrors) == 0 {
+		return
+	}
+
+	query := `
+		INSERT INTO product_import_errors (
+			job_id, line, sku, name, message
+		) VALUES (
+			:job_id, :line, :sku, :name, :message
+		)
+	`
+
+	_, err = r.db.NamedExecContext(ctx, query, rowErrors)
+	return
+}
+
+func (r repository) GetImportRowErrorsByJobId(ctx context.Context, jobId int) (rowErrors []ImportRowError, err error) {
+	query := `
+		SELECT
+			job_id, line, sku, name, message
+		FROM product_import_errors
+		WHERE job_id=$1
+		ORDER BY line ASC
+	`
+
+	err = r.db.SelectContext(ctx, &rowErrors, query, jobId)
+	return
+}
+
+func (r repository) GetProductBySKU(ctx context.Context, sku string) (model product.Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, stock, price, created_at, updated_at, deleted_at
+		FROM products
+		WHERE sku=$1 AND deleted_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &model, query, sku)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetProductByName(ctx context.Context, name string) (model product.Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, stock, price, created_at, updated_at, deleted_at
+		FROM products
+		WHERE name=$1 AND deleted_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &model, query, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) Begin(ctx context.Context) (tx *sqlx.Tx, err error) {
+	tx, err = r.db.BeginTxx(ctx, &sql.TxOptions{})
+	return
+}
+
+func (repository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Commit()
+}
+
+func (repository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Rollback()
+}
+
+func (r repository) GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (model product.Product, err error) {
+	query := `
+		SELECT
+			id, sku, name, stock, price, created_at, updated_at, deleted_at
+		FROM products
+		WHERE id=$1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &model, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) CreateProductWithTx(ctx context.Context, tx *sqlx.Tx, model product.Product) (id int, err error) {
+	query := `
+		INSERT INTO products (
+			sku, slug, name, stock, price, status, created_at, updated_at
+		) VALUES (
+			:sku, :slug, :name, :stock, :price, :status, :created_at, :updated_at
+		)
+		RETURNING id
+	`
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	err = stmt.GetContext(ctx, &id, model)
+	return
+}
+
+func (r repository) UpdateProductWithTx(ctx context.Context, tx *sqlx.Tx, model product.Product) (err error) {
+	query := `
+		UPDATE products
+		SET name=:name, stock=:stock, price=:price, updated_at=:updated_at, version=version + 1
+		WHERE id=:id AND deleted_at IS NULL
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, model)
+	return
+}
+
+func (r repository) GenerateSKUWithTx(ctx context.Context, tx *sqlx.Tx) (sku string, err error) {
+	return product.GenerateSKUWithTx(ctx, tx)
+}
+
+func (r repository) GenerateSlugWithTx(ctx context.Context, tx *sqlx.Tx, name string) (slug string, err error) {
+	return product.GenerateSlugWithTx(ctx, tx, name)
+}
+
+func (r repository) RecordPriceChangeWithTx(ctx context.Context, tx *sqlx.Tx, change pricing.PriceChange) (err error) {
+	return pricing.RecordPriceChangeWithTx(ctx, tx, change)
+}
+
+func (r repository) RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement inventory.StockMovement) (err error) {
+	return inventory.RecordStockMovementWithTx(ctx, tx, movement)
+}
@@ -0,0 +1,42 @@
+package productimport
+
+import (
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/cache"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// cachedRepository menghapus cache produk berdasarkan SKU setelah baris import mengubah produk yang sudah ada
+type cachedRepository struct {
+	Repository
+
+	pending *cache.PendingKeys[*sqlx.Tx]
+}
+
+func newCachedRepository(repo Repository) cachedRepository {
+	return cachedRepository{
+		Repository: repo,
+		pending:    cache.NewPendingKeys[*sqlx.Tx](cache.TAG_PRODUCT_SKU),
+	}
+}
+
+func (r cachedRepository) UpdateProductWithTx(ctx context.Context, tx *sqlx.Tx, model product.Product) (err error) {
+	r.pending.Add(tx, model.SKU)
+	return r.Repository.UpdateProductWithTx(ctx, tx, model)
+}
+
+func (r cachedRepository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	if err = r.Repository.Commit(ctx, tx); err != nil {
+		r.pending.Discard(tx)
+		return
+	}
+	r.pending.Flush(ctx, tx)
+	return
+}
+
+func (r cachedRepository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	r.pending.Discard(tx)
+	return r.Repository.Rollback(ctx, tx)
+}
@@ -0,0 +1,18 @@
+package productimport
+
+type CreateImportRequestPayload struct {
+	Format       string `form:"format"`
+	Mode         string `form:"mode"`
+	DryRun       bool   `form:"dry_run"`
+	UserPublicId string `form:"-"`
+}
+
+func (c CreateImportRequestPayload) GenerateDefaultValue(filename string) CreateImportRequestPayload {
+	if c.Format == "" {
+		c.Format = FormatFromFilename(filename)
+	}
+	if c.Mode == "" {
+		c.Mode = MODE_CREATE
+	}
+	return c
+}
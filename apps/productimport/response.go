@@ -0,0 +1,55 @@
+package productimport
+
+import (
+	"fmt"
+	"time"
+)
+
+type ImportJobResponse struct {
+	Id             int        `json:"id"`
+	Status         string     `json:"status"`
+	Format         string     `json:"format"`
+	Mode           string     `json:"mode"`
+	DryRun         bool       `json:"dry_run"`
+	TotalRows      int        `json:"total_rows"`
+	ProcessedRows  int        `json:"processed_rows"`
+	CreatedRows    int        `json:"created_rows"`
+	UpdatedRows    int        `json:"updated_rows"`
+	FailedRows     int        `json:"failed_rows"`
+	Progress       int        `json:"progress"`
+	Message        string     `json:"message,omitempty"`
+	ErrorReportURL string     `json:"error_report_url,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+}
+
+func (j ImportJob) ToImportJobResponse() ImportJobResponse {
+	progress := 0
+	if j.TotalRows > 0 {
+		progress = j.ProcessedRows * 100 / j.TotalRows
+	} else if j.IsFinished() {
+		progress = 100
+	}
+
+	resp := ImportJobResponse{
+		Id:            j.Id,
+		Status:        j.GetStatus(),
+		Format:        j.Format,
+		Mode:          j.Mode,
+		DryRun:        j.DryRun,
+		TotalRows:     j.TotalRows,
+		ProcessedRows: j.ProcessedRows,
+		CreatedRows:   j.CreatedRows,
+		UpdatedRows:   j.UpdatedRows,
+		FailedRows:    j.FailedRows,
+		Progress:      progress,
+		Message:       j.Message,
+		CreatedAt:     j.CreatedAt,
+		FinishedAt:    j.FinishedAt,
+	}
+
+	if j.FailedRows > 0 {
+		resp.ErrorReportURL = fmt.Sprintf("/products/imports/%d/errors", j.Id)
+	}
+	return resp
+}
@@ -0,0 +1,259 @@
+package productimport
+
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal/log"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	ImportDBRepository
+	ImportJobRepository
+	ProductRepository
+}
+
+type ImportDBRepository interface {
+	Begin(ctx context.Context) (tx *sqlx.Tx, err error)
+	Rollback(ctx context.Context, tx *sqlx.Tx) (err error)
+	Commit(ctx context.Context, tx *sqlx.Tx) (err error)
+}
+
+type ImportJobRepository interface {
+	CreateImportJob(ctx context.Context, model ImportJob) (id int, err error)
+	UpdateImportJob(ctx context.Context, model ImportJob) (err error)
+	GetImportJobById(ctx context.Context, id int) (model ImportJob, err error)
+	CreateImportRowErrors(ctx context.Context, rowErrors []ImportRowError) (err error)
+	GetImportRowErrorsByJobId(ctx context.Context, jobId int) (rowErrors []ImportRowError, err error)
+}
+
+type ProductRepository interface {
+	GetProductBySKU(ctx context.Context, sku string) (model product.Product, err error)
+	GetProductByName(ctx context.Context, name string) (model product.Product, err error)
+	GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (model product.Product, err error)
+	CreateProductWithTx(ctx context.Context, tx *sqlx.Tx, model product.Product) (id int, err error)
+	GenerateSKUWithTx(ctx context.Context, tx *sqlx.Tx) (sku string, err error)
+	GenerateSlugWithTx(ctx context.Context, tx *sqlx.Tx, name string) (slug string, err error)
+	UpdateProductWithTx(ctx context.Context, tx *sqlx.Tx, model product.Product) (err error)
+	RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement inventory.StockMovement) (err error)
+	RecordPriceChangeWithTx(ctx context.Context, tx *sqlx.Tx, change pricing.PriceChange) (err error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func newService(repo Repository) service {
+	return service{
+		repo: repo,
+	}
+}
+
+// CreateImport menyimpan job lalu memproses file di background,
+// progress bisa dipantau lewat ImportJob.
+func (s service) CreateImport(ctx context.Context, req CreateImportRequestPayload, filename string, data []byte) (job ImportJob, err error) {
+	req = req.GenerateDefaultValue(filename)
+	job = NewImportJobFromCreateRequest(req)
+
+	if err = job.Validate(); err != nil {
+		return
+	}
+
+	job.Id, err = s.repo.CreateImportJob(ctx, job)
+	if err != nil {
+		return
+	}
+
+	// context request sudah selesai ketika job masih berjalan
+	go s.runImport(context.Background(), job, data)
+	return
+}
+
+func (s service) ImportJob(ctx context.Context, id int) (job ImportJob, err error) {
+	return s.repo.GetImportJobById(ctx, id)
+}
+
+func (s service) ImportErrorReport(ctx context.Context, id int) (rowErrors []ImportRowError, err error) {
+	if _, err = s.repo.GetImportJobById(ctx, id); err != nil {
+		return
+	}
+
+	rowErrors, err = s.repo.GetImportRowErrorsByJobId(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if len(rowErrors) == 0 {
+		rowErrors = []ImportRowError{}
+	}
+	return
+}
+
+func (s service) runImport(ctx context.Context, job ImportJob, data []byte) ImportJob {
+	rows, err := ParseRows(job.Format, data)
+	if err != nil {
+		log.Log.Errorf(ctx, "[runImport, ParseRows] job %d with error detail %v", job.Id, err.Error())
+		job.Fail(err)
+		s.saveJob(ctx, job, nil)
+		return job
+	}
+
+	job.Start(len(rows))
+	s.saveJob(ctx, job, nil)
+
+	seenNames := map[string]bool{}
+	rowErrors := []ImportRowError{}
+
+	for _, row := range rows {
+		created, err := s.importRow(ctx, job, row, seenNames)
+		job.ProcessedRows++
+
+		switch {
+		case err != nil:
+			job.FailedRows++
+			rowErrors = append(rowErrors, row.ToRowError(job.Id, err))
+		case created:
+			job.CreatedRows++
+		default:
+			job.UpdatedRows++
+		}
+
+		if job.ShouldSaveProgress() {
+			s.saveJob(ctx, job, rowErrors)
+			rowErrors = []ImportRowError{}
+		}
+	}
+
+	job.Finish()
+	s.saveJob(ctx, job, rowErrors)
+
+	log.Log.Infof(ctx, "import job %d finished: %d created, %d updated, %d failed",
+		job.Id, job.CreatedRows, job.UpdatedRows, job.FailedRows)
+	return job
+}
+
+// importRow memakai aturan yang sama dengan CreateProduct dan UpdateProduct
+func (s service) importRow(ctx context.Context, job ImportJob, row ImportRow, seenNames map[string]bool) (created bool, err error) {
+	if row.ParseErr != nil {
+		return false, row.ParseErr
+	}
+
+	model, err := row.ToProduct()
+	if err != nil {
+		return
+	}
+	if err = model.Validate(); err != nil {
+		return
+	}
+
+	// nama yang sama di dalam satu file juga harus ditolak, terutama saat dry run
+	if seenNames[model.Name] {
+		return false, response.ErrProductAlreadyExists
+	}
+	seenNames[model.Name] = true
+
+	if row.SKU != "" {
+		existing, err := s.repo.GetProductBySKU(ctx, row.SKU)
+		if err != nil && err != response.ErrNotFound {
+			return false, err
+		}
+
+		if existing.Id != 0 {
+			if job.Mode != MODE_UPSERT {
+				return false, response.ErrSKUAlreadyExists
+			}
+			model.Id = existing.Id
+			model.CreatedAt = existing.CreatedAt
+		}
+	}
+
+	existingProduct, err := s.repo.GetProductByName(ctx, model.Name)
+	if err != nil && err != response.ErrNotFound {
+		return
+	}
+	err = nil
+	if existingProduct.Id != 0 && existingProduct.Id != model.Id {
+		return false, response.ErrProductAlreadyExists
+	}
+
+	created = model.Id == 0
+	if job.DryRun {
+		return
+	}
+
+	err = s.saveProduct(ctx, job, model)
+	return
+}
+
+// saveProduct menyimpan produk dan mencatat perubahan stoknya di ledger dalam satu transaksi database
+func (s service) saveProduct(ctx context.Context, job ImportJob, model product.Product) (err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	var previousStock int16
+	var previousPrice int
+	created := model.Id == 0
+	if created {
+		// baris tanpa sku memakai format SKU yang sama dengan produk dari API
+		if model.SKU == "" {
+			if model.SKU, err = s.repo.GenerateSKUWithTx(ctx, tx); err != nil {
+				return
+			}
+		}
+		if model.Slug, err = s.repo.GenerateSlugWithTx(ctx, tx, model.Name); err != nil {
+			return
+		}
+
+		if model.Id, err = s.repo.CreateProductWithTx(ctx, tx, model); err != nil {
+			return
+		}
+	} else {
+		current, err := s.repo.GetProductByIdForUpdateWithTx(ctx, tx, model.Id)
+		if err != nil {
+			return err
+		}
+		previousStock = current.Stock
+		previousPrice = current.Price
+
+		if err = s.repo.UpdateProductWithTx(ctx, tx, model); err != nil {
+			return err
+		}
+	}
+
+	if movement, changed := NewImportStockMovement(job, model, previousStock); changed {
+		if err = s.repo.RecordStockMovementWithTx(ctx, tx, movement); err != nil {
+			return
+		}
+	}
+
+	if change, changed := NewImportPriceChange(job, model, previousPrice, created); changed {
+		if err = s.repo.RecordPriceChangeWithTx(ctx, tx, change); err != nil {
+			return
+		}
+	}
+
+	if err = s.repo.Commit(ctx, tx); err != nil {
+		return
+	}
+
+	// response GET /products yang di-cache tidak berlaku lagi
+	product.InvalidateCache()
+	return
+}
+
+func (s service) saveJob(ctx context.Context, job ImportJob, rowErrors []ImportRowError) {
+	if err := s.repo.CreateImportRowErrors(ctx, rowErrors); err != nil {
+		log.Log.Errorf(ctx, "[saveJob, CreateImportRowErrors] job %d with error detail %v", job.Id, err.Error())
+	}
+
+	if err := s.repo.UpdateImportJob(ctx, job); err != nil {
+		log.Log.Errorf(ctx, "[saveJob, UpdateImportJob] job %d with error detail %v", job.Id, err.Error())
+	}
+}
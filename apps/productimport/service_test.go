@@ -0,0 +1,78 @@
+package productimport
+
+import (
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo)
+}
+
+func TestRunImport_DryRun(t *testing.T) {
+	ctx := context.Background()
+	name := fmt.Sprintf("Produk %v", uuid.NewString())
+	data := []byte(fmt.Sprintf("name,stock,price\n%[1]s,10,100000\n%[1]s,5,150000\nab,1,1000\n", name))
+
+	job := NewImportJobFromCreateRequest(CreateImportRequestPayload{Format: FORMAT_CSV, Mode: MODE_CREATE, DryRun: true})
+	id, err := svc.repo.CreateImportJob(ctx, job)
+	require.Nil(t, err)
+	job.Id = id
+
+	job = svc.runImport(ctx, job, data)
+	require.Equal(t, ImportJobStatus_Completed, job.Status)
+	require.Equal(t, 3, job.ProcessedRows)
+	require.Equal(t, 1, job.CreatedRows)
+	require.Equal(t, 2, job.FailedRows)
+
+	rowErrors, err := svc.ImportErrorReport(ctx, id)
+	require.Nil(t, err)
+	require.Len(t, rowErrors, 2)
+	require.Equal(t, response.ErrProductAlreadyExists.Error(), rowErrors[0].Message)
+
+	// dry run tidak boleh membuat produk
+	_, err = svc.repo.GetProductByName(ctx, name)
+	require.Equal(t, response.ErrNotFound, err)
+}
+
+func TestRunImport_Upsert(t *testing.T) {
+	ctx := context.Background()
+	sku := uuid.NewString()
+	name := fmt.Sprintf("Produk %v", uuid.NewString())
+
+	create := NewImportJobFromCreateRequest(CreateImportRequestPayload{Format: FORMAT_JSONL, Mode: MODE_CREATE})
+	create.Id, _ = svc.repo.CreateImportJob(ctx, create)
+	create = svc.runImport(ctx, create, []byte(fmt.Sprintf(`{"sku":%q,"name":%q,"stock":10,"price":100000}`, sku, name)))
+	require.Equal(t, 1, create.CreatedRows)
+
+	upsert := NewImportJobFromCreateRequest(CreateImportRequestPayload{Format: FORMAT_JSONL, Mode: MODE_UPSERT})
+	upsert.Id, _ = svc.repo.CreateImportJob(ctx, upsert)
+	upsert = svc.runImport(ctx, upsert, []byte(fmt.Sprintf(`{"sku":%q,"name":%q,"stock":20,"price":120000}`, sku, name)))
+	require.Equal(t, 1, upsert.UpdatedRows)
+
+	product, err := svc.repo.GetProductBySKU(ctx, sku)
+	require.Nil(t, err)
+	require.Equal(t, int16(20), product.Stock)
+	require.Equal(t, 120_000, product.Price)
+}
@@ -0,0 +1,32 @@
+package recommendation
+
+import (
+	"Ecommerce-basic/internal"
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// interval default batch produk terkait jika tidak diatur di config
+const defaultRefreshInterval = time.Hour
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	repo := newRepository(db)
+	svc := newService(repo, config.Cfg.App.Recommendation.TopN)
+	handler := newHandler(svc)
+
+	router.GET("/products/sku/:sku/related", handler.GetRelatedProducts)
+}
+
+// StartRelationRefresher menjalankan batch produk yang sering dibeli bersama di background sampai ctx dibatalkan
+func StartRelationRefresher(ctx context.Context, db *sqlx.DB) {
+	interval := config.Cfg.App.Recommendation.RefreshInterval
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+
+	svc := newService(newRepository(db), config.Cfg.App.Recommendation.TopN)
+	go svc.runRefresher(ctx, interval)
+}
@@ -0,0 +1,95 @@
+package recommendation
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// sumber produk terkait di response GET /products/sku/:sku/related
+	SOURCE_CO_PURCHASE string = "CO_PURCHASE"
+	SOURCE_PRICE_BAND  string = "PRICE_BAND"
+
+	// jumlah produk terkait default jika app.recommendation.top_n tidak diatur
+	defaultTopN = 10
+)
+
+// CoPurchase jumlah pembeli yang membeli kedua produk, transaksi yang dibatalkan tidak dihitung
+type CoPurchase struct {
+	ProductId        int `db:"product_id"`
+	RelatedProductId int `db:"related_product_id"`
+	Score            int `db:"score"`
+}
+
+// Relation adalah satu baris top-N produk terkait yang disimpan batch di tabel product_relations
+type Relation struct {
+	ProductId        int       `db:"product_id"`
+	RelatedProductId int       `db:"related_product_id"`
+	Score            int       `db:"score"`
+	Rank             int       `db:"rank"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// RankRelations mengambil topN pasangan dengan skor tertinggi per produk,
+// skor yang sama diurutkan berdasarkan id supaya hasil batch selalu sama
+func RankRelations(counts []CoPurchase, topN int, now time.Time) (relations []Relation) {
+	byProduct := map[int][]CoPurchase{}
+	productIds := []int{}
+	for _, count := range counts {
+		if count.ProductId == count.RelatedProductId || count.Score <= 0 {
+			continue
+		}
+		if _, ok := byProduct[count.ProductId]; !ok {
+			productIds = append(productIds, count.ProductId)
+		}
+		byProduct[count.ProductId] = append(byProduct[count.ProductId], count)
+	}
+	sort.Ints(productIds)
+
+	for _, productId := range productIds {
+		related := byProduct[productId]
+		sort.Slice(related, func(i, j int) bool {
+			if related[i].Score != related[j].Score {
+				return related[i].Score > related[j].Score
+			}
+			return related[i].RelatedProductId < related[j].RelatedProductId
+		})
+
+		for i, count := range related[:min(len(related), topN)] {
+			relations = append(relations, Relation{
+				ProductId:        productId,
+				RelatedProductId: count.RelatedProductId,
+				Score:            count.Score,
+				Rank:             i + 1,
+				UpdatedAt:        now,
+			})
+		}
+	}
+	return
+}
+
+// Product adalah data produk yang ditampilkan sebagai rekomendasi
+type Product struct {
+	Id    int    `db:"id"`
+	SKU   string `db:"sku"`
+	Slug  string `db:"slug"`
+	Name  string `db:"name"`
+	Price int    `db:"price"`
+
+	// 0 untuk produk dari fallback price band
+	Score int `db:"score"`
+}
+
+// Related adalah hasil rekomendasi beserta sumbernya
+type Related struct {
+	Source   string
+	Products []Product
+}
+
+// NormalizeLimit limit dari query dibatasi top-N yang disimpan batch
+func NormalizeLimit(limit int, topN int) int {
+	if limit <= 0 || limit > topN {
+		return topN
+	}
+	return limit
+}
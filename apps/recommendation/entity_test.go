@@ -0,0 +1,35 @@
+package recommendation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankRelations(t *testing.T) {
+	now := time.Now()
+	counts := []CoPurchase{
+		{ProductId: 2, RelatedProductId: 1, Score: 1},
+		{ProductId: 1, RelatedProductId: 4, Score: 2},
+		{ProductId: 1, RelatedProductId: 2, Score: 5},
+		{ProductId: 1, RelatedProductId: 3, Score: 2},
+		{ProductId: 1, RelatedProductId: 1, Score: 9},
+		{ProductId: 3, RelatedProductId: 1, Score: 0},
+	}
+
+	relations := RankRelations(counts, 2, now)
+	require.Equal(t, []Relation{
+		{ProductId: 1, RelatedProductId: 2, Score: 5, Rank: 1, UpdatedAt: now},
+		{ProductId: 1, RelatedProductId: 3, Score: 2, Rank: 2, UpdatedAt: now},
+		{ProductId: 2, RelatedProductId: 1, Score: 1, Rank: 1, UpdatedAt: now},
+	}, relations)
+
+	require.Empty(t, RankRelations(nil, 10, now))
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	require.Equal(t, 10, NormalizeLimit(0, 10))
+	require.Equal(t, 3, NormalizeLimit(3, 10))
+	require.Equal(t, 10, NormalizeLimit(50, 10))
+}
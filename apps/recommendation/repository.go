@@ -0,0 +1,128 @@
+package recommendation
+
+import (
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+func (r repository) Begin(ctx context.Context) (tx *sqlx.Tx, err error) {
+	tx, err = r.db.BeginTxx(ctx, &sql.TxOptions{})
+	return
+}
+
+func (r repository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Commit()
+}
+
+func (r repository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Rollback()
+}
+
+// GetCoPurchaseCounts menghitung pasangan produk yang dibeli oleh pembeli yang sama,
+// setiap pembeli dihitung sekali per pasangan dan produk yang sudah dihapus dilewati
+func (r repository) GetCoPurchaseCounts(ctx context.Context) (counts []CoPurchase, err error) {
+	query := `
+		WITH buyers AS (
+			SELECT DISTINCT t.user_public_id, t.product_id
+			FROM transactions t
+			JOIN products p ON p.id = t.product_id
+			WHERE t.status <> $1 AND p.deleted_at IS NULL
+		)
+		SELECT
+			a.product_id, b.product_id AS related_product_id, COUNT(*) AS score
+		FROM buyers a
+		JOIN buyers b ON b.user_public_id = a.user_public_id AND b.product_id <> a.product_id
+		GROUP BY a.product_id, b.product_id
+	`
+
+	err = r.db.SelectContext(ctx, &counts, query, transaction.TransactionStatus_Cancelled)
+	return
+}
+
+// ReplaceRelationsWithTx hasil batch selalu menggantikan seluruh isi tabel
+func (r repository) ReplaceRelationsWithTx(ctx context.Context, tx *sqlx.Tx, relations []Relation) (err error) {
+	if _, err = tx.ExecContext(ctx, "DELETE FROM product_relations"); err != nil {
+		return
+	}
+
+	query := `
+		INSERT INTO product_relations (
+			product_id, related_product_id, score, rank, updated_at
+		) VALUES (
+			:product_id, :related_product_id, :score, :rank, :updated_at
+		)
+	`
+
+	for _, relation := range relations {
+		if _, err = tx.NamedExecContext(ctx, query, relation); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// GetProductBySku hanya produk PUBLISHED yang punya halaman rekomendasi
+func (r repository) GetProductBySku(ctx context.Context, sku string) (model Product, err error) {
+	query := `
+		SELECT
+			id, sku, slug, name, price
+		FROM products
+		WHERE sku=$1 AND deleted_at IS NULL AND status=$2
+	`
+
+	err = r.db.GetContext(ctx, &model, query, sku, product.ProductStatus_Published)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+// GetRelatedProducts produk terkait yang sudah tidak dijual dilewati tanpa menunggu batch berikutnya
+func (r repository) GetRelatedProducts(ctx context.Context, productId int, limit int) (products []Product, err error) {
+	query := `
+		SELECT
+			p.id, p.sku, p.slug, p.name, p.price, r.score
+		FROM product_relations r
+		JOIN products p ON p.id = r.related_product_id
+		WHERE r.product_id=$1 AND p.deleted_at IS NULL AND p.status=$2
+		ORDER BY r.rank
+		LIMIT $3
+	`
+
+	err = r.db.SelectContext(ctx, &products, query, productId, product.ProductStatus_Published, limit)
+	return
+}
+
+// GetProductsInPriceBand produk lain di price band yang sama, yang harganya paling dekat lebih dulu
+func (r repository) GetProductsInPriceBand(ctx context.Context, model Product, band product.PriceBand, limit int) (products []Product, err error) {
+	query := `
+		SELECT
+			id, sku, slug, name, price
+		FROM products
+		WHERE id<>$1 AND deleted_at IS NULL AND status=$2
+			AND price >= $3 AND ($4 = 0 OR price < $4)
+		ORDER BY ABS(price - $5), id
+		LIMIT $6
+	`
+
+	err = r.db.SelectContext(ctx, &products, query, model.Id, product.ProductStatus_Published, band.Min, band.Max, model.Price, limit)
+	return
+}
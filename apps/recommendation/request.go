@@ -0,0 +1,5 @@
+package recommendation
+
+type ListRelatedRequestPayload struct {
+	Limit int `form:"limit"`
+}
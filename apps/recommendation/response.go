@@ -0,0 +1,31 @@
+package recommendation
+
+type RelatedProductResponse struct {
+	SKU   string `json:"sku"`
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+	Score int    `json:"score,omitempty"`
+}
+
+type RelatedResponse struct {
+	Source   string                   `json:"source"`
+	Products []RelatedProductResponse `json:"products"`
+}
+
+func (r Related) ToRelatedResponse() RelatedResponse {
+	resp := RelatedResponse{
+		Source:   r.Source,
+		Products: []RelatedProductResponse{},
+	}
+	for _, p := range r.Products {
+		resp.Products = append(resp.Products, RelatedProductResponse{
+			SKU:   p.SKU,
+			Slug:  p.Slug,
+			Name:  p.Name,
+			Price: p.Price,
+			Score: p.Score,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,112 @@
+package recommendation
+
+import (
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/internal/log"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	RecommendationDBRepository
+	RelationRepository
+	ProductRepository
+}
+
+type RecommendationDBRepository interface {
+	Begin(ctx context.Context) (tx *sqlx.Tx, err error)
+	Rollback(ctx context.Context, tx *sqlx.Tx) (err error)
+	Commit(ctx context.Context, tx *sqlx.Tx) (err error)
+}
+
+type RelationRepository interface {
+	GetCoPurchaseCounts(ctx context.Context) (counts []CoPurchase, err error)
+	ReplaceRelationsWithTx(ctx context.Context, tx *sqlx.Tx, relations []Relation) (err error)
+}
+
+type ProductRepository interface {
+	GetProductBySku(ctx context.Context, sku string) (product Product, err error)
+	GetRelatedProducts(ctx context.Context, productId int, limit int) (products []Product, err error)
+	GetProductsInPriceBand(ctx context.Context, model Product, band product.PriceBand, limit int) (products []Product, err error)
+}
+
+type service struct {
+	repo Repository
+	topN int
+}
+
+func newService(repo Repository, topN int) service {
+	if topN <= 0 {
+		topN = defaultTopN
+	}
+	return service{
+		repo: repo,
+		topN: topN,
+	}
+}
+
+// RefreshRelations menghitung ulang top-N produk terkait dari seluruh transaksi
+func (s service) RefreshRelations(ctx context.Context, now time.Time) (relations []Relation, err error) {
+	counts, err := s.repo.GetCoPurchaseCounts(ctx)
+	if err != nil {
+		log.Log.Errorf(ctx, "[RefreshRelations, GetCoPurchaseCounts] with error detail %v", err.Error())
+		return
+	}
+	relations = RankRelations(counts, s.topN, now)
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	if err = s.repo.ReplaceRelationsWithTx(ctx, tx, relations); err != nil {
+		log.Log.Errorf(ctx, "[RefreshRelations, ReplaceRelationsWithTx] with error detail %v", err.Error())
+		return
+	}
+
+	if err = s.repo.Commit(ctx, tx); err != nil {
+		return
+	}
+	return
+}
+
+// runRefresher menghitung sekali saat start supaya rekomendasi langsung tersedia, lalu setiap interval
+func (s service) runRefresher(ctx context.Context, interval time.Duration) {
+	s.RefreshRelations(ctx, time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.RefreshRelations(ctx, now)
+		}
+	}
+}
+
+// Related produk yang sering dibeli bersama, atau produk di price band yang sama jika belum ada data pembelian
+func (s service) Related(ctx context.Context, sku string, req ListRelatedRequestPayload) (related Related, err error) {
+	myProduct, err := s.repo.GetProductBySku(ctx, sku)
+	if err != nil {
+		return
+	}
+	limit := NormalizeLimit(req.Limit, s.topN)
+
+	related.Source = SOURCE_CO_PURCHASE
+	if related.Products, err = s.repo.GetRelatedProducts(ctx, myProduct.Id, limit); err != nil {
+		return
+	}
+	if len(related.Products) > 0 {
+		return
+	}
+
+	related.Source = SOURCE_PRICE_BAND
+	related.Products, err = s.repo.GetProductsInPriceBand(ctx, myProduct, product.PriceBandOf(myProduct.Price), limit)
+	return
+}
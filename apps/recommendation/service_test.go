@@ -0,0 +1,47 @@
+package recommendation
+
+import (
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo, config.Cfg.App.Recommendation.TopN)
+}
+
+func TestRelated(t *testing.T) {
+	_, err := svc.RefreshRelations(context.Background(), time.Now())
+	require.Nil(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		related, err := svc.Related(context.Background(), "a98dcf06-7b4b-4f33-a6d2-20738bb8081b", ListRelatedRequestPayload{Limit: 3})
+		require.Nil(t, err)
+		require.Contains(t, []string{SOURCE_CO_PURCHASE, SOURCE_PRICE_BAND}, related.Source)
+		require.LessOrEqual(t, len(related.Products), 3)
+	})
+	t.Run("product not found", func(t *testing.T) {
+		_, err := svc.Related(context.Background(), uuid.NewString(), ListRelatedRequestPayload{})
+		require.Equal(t, response.ErrNotFound, err)
+	})
+}
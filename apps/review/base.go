@@ -0,0 +1,29 @@
+package review
+
+import (
+	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/infra/gin"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	repo := newCachedRepository(newRepository(db))
+	svc := newService(repo)
+	handler := newHandler(svc)
+
+	reviewRoute := router.Group("/products/sku/:sku/reviews")
+	{
+		reviewRoute.GET("", handler.GetProductReviews)
+		reviewRoute.POST("", infragin.CheckAuth(), handler.CreateReview)
+	}
+
+	adminRoute := router.Group("/admin/reviews")
+	{
+		adminRoute.Use(infragin.CheckAuth(), infragin.CheckRoles([]string{string(auth.ROLE_Admin)}))
+
+		adminRoute.GET("", handler.GetAdminReviews)
+		adminRoute.PUT("/:id/status", handler.ModerateReview)
+	}
+}
@@ -0,0 +1,167 @@
+package review
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type ReviewStatus uint8
+
+const (
+	ReviewStatus_Published ReviewStatus = 1
+	ReviewStatus_Approved  ReviewStatus = 10
+	ReviewStatus_Hidden    ReviewStatus = 20
+
+	REVIEW_PUBLISHED string = "PUBLISHED"
+	REVIEW_APPROVED  string = "APPROVED"
+	REVIEW_HIDDEN    string = "HIDDEN"
+	REVIEW_UNKNOWN   string = "UNKNOWN"
+)
+
+var (
+	MappingReviewStatus = map[ReviewStatus]string{
+		ReviewStatus_Published: REVIEW_PUBLISHED,
+		ReviewStatus_Approved:  REVIEW_APPROVED,
+		ReviewStatus_Hidden:    REVIEW_HIDDEN,
+	}
+
+	// status yang bisa dipilih admin saat moderasi
+	moderationStatuses = map[string]ReviewStatus{
+		REVIEW_APPROVED: ReviewStatus_Approved,
+		REVIEW_HIDDEN:   ReviewStatus_Hidden,
+	}
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+
+	// panjang maksimal teks review dalam karakter
+	MaxReviewTextLength = 2000
+)
+
+// Review langsung tampil setelah dibuat, admin bisa menyembunyikan atau menandainya sudah dicek (approved)
+type Review struct {
+	Id           int          `db:"id"`
+	ProductId    int          `db:"product_id"`
+	UserPublicId string       `db:"user_public_id"`
+	Rating       int          `db:"rating"`
+	Text         string       `db:"text"`
+	Status       ReviewStatus `db:"status"`
+	ModeratedBy  string       `db:"moderated_by"`
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    time.Time    `db:"updated_at"`
+
+	// diisi dari tabel products
+	ProductSKU string `db:"sku"`
+}
+
+func NewReviewFromRequest(product Product, req CreateReviewRequestPayload) Review {
+	now := time.Now()
+	return Review{
+		ProductId:    product.Id,
+		UserPublicId: req.UserPublicId,
+		Rating:       req.Rating,
+		Text:         strings.TrimSpace(req.Text),
+		Status:       ReviewStatus_Published,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		ProductSKU:   product.SKU,
+	}
+}
+
+func (r Review) Validate() (err error) {
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return response.ErrRatingInvalid
+	}
+	if utf8.RuneCountInString(r.Text) > MaxReviewTextLength {
+		return response.ErrReviewTextTooLong
+	}
+	return
+}
+
+func (r Review) IsVisible() bool {
+	return r.Status == ReviewStatus_Published || r.Status == ReviewStatus_Approved
+}
+
+// Moderate mengubah status review dan mengembalikan perubahan agregat rating produk
+func (r *Review) Moderate(status string, moderatorPublicId string) (delta RatingDelta, err error) {
+	next, ok := moderationStatuses[strings.ToUpper(status)]
+	if !ok {
+		return delta, response.ErrReviewStatusInvalid
+	}
+
+	before := r.Contribution()
+	r.Status = next
+	r.ModeratedBy = moderatorPublicId
+	r.UpdatedAt = time.Now()
+
+	return r.Contribution().Sub(before), nil
+}
+
+// Contribution adalah sumbangan review ke agregat rating produk, review tersembunyi tidak dihitung
+func (r Review) Contribution() RatingDelta {
+	if !r.IsVisible() {
+		return RatingDelta{}
+	}
+	return RatingDelta{Count: 1, Sum: r.Rating}
+}
+
+func (r Review) GetStatus() string {
+	status, ok := MappingReviewStatus[r.Status]
+	if !ok {
+		return REVIEW_UNKNOWN
+	}
+	return status
+}
+
+func (r Review) CursorKey() pagination.Key {
+	return pagination.Key{Id: r.Id}
+}
+
+// RatingDelta adalah perubahan rating_count dan rating_sum di tabel products
+type RatingDelta struct {
+	Count int
+	Sum   int
+}
+
+func (d RatingDelta) Sub(other RatingDelta) RatingDelta {
+	return RatingDelta{Count: d.Count - other.Count, Sum: d.Sum - other.Sum}
+}
+
+func (d RatingDelta) IsZero() bool {
+	return d.Count == 0 && d.Sum == 0
+}
+
+// ReviewQuery dipakai untuk daftar review publik per produk dan daftar moderasi admin
+type ReviewQuery struct {
+	ProductId int
+	Status    *ReviewStatus
+	// true hanya menampilkan review yang terlihat publik
+	VisibleOnly bool
+	Page        pagination.Request
+}
+
+func NewReviewQueryFromAdminRequest(req ListAdminReviewRequestPayload, page pagination.Request) (query ReviewQuery, err error) {
+	query.Page = page
+	if req.Status == "" {
+		return
+	}
+
+	for status, name := range MappingReviewStatus {
+		if name == strings.ToUpper(req.Status) {
+			query.Status = &status
+			return
+		}
+	}
+	return query, response.ErrReviewStatusInvalid
+}
+
+type Product struct {
+	Id   int    `db:"id"`
+	SKU  string `db:"sku"`
+	Name string `db:"name"`
+}
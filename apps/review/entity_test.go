@@ -0,0 +1,86 @@
+package review
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReview(t *testing.T) {
+	product := Product{Id: 1, SKU: "sku-1"}
+
+	t.Run("success", func(t *testing.T) {
+		review := NewReviewFromRequest(product, CreateReviewRequestPayload{Rating: 5, Text: "  bagus  ", UserPublicId: "user-1"})
+
+		require.Nil(t, review.Validate())
+		require.Equal(t, "bagus", review.Text)
+		require.Equal(t, REVIEW_PUBLISHED, review.GetStatus())
+	})
+	t.Run("rating invalid", func(t *testing.T) {
+		for _, rating := range []int{0, 6} {
+			review := NewReviewFromRequest(product, CreateReviewRequestPayload{Rating: rating})
+
+			require.Equal(t, response.ErrRatingInvalid, review.Validate())
+		}
+	})
+	t.Run("text too long", func(t *testing.T) {
+		review := NewReviewFromRequest(product, CreateReviewRequestPayload{Rating: 4, Text: strings.Repeat("a", MaxReviewTextLength+1)})
+
+		require.Equal(t, response.ErrReviewTextTooLong, review.Validate())
+	})
+}
+
+func TestModerateReview(t *testing.T) {
+	t.Run("hide published", func(t *testing.T) {
+		review := Review{Rating: 4, Status: ReviewStatus_Published}
+
+		delta, err := review.Moderate("hidden", "admin-1")
+		require.Nil(t, err)
+		require.Equal(t, RatingDelta{Count: -1, Sum: -4}, delta)
+		require.Equal(t, REVIEW_HIDDEN, review.GetStatus())
+		require.Equal(t, "admin-1", review.ModeratedBy)
+	})
+	t.Run("approve hidden", func(t *testing.T) {
+		review := Review{Rating: 2, Status: ReviewStatus_Hidden}
+
+		delta, err := review.Moderate(REVIEW_APPROVED, "admin-1")
+		require.Nil(t, err)
+		require.Equal(t, RatingDelta{Count: 1, Sum: 2}, delta)
+	})
+	t.Run("approve published", func(t *testing.T) {
+		review := Review{Rating: 5, Status: ReviewStatus_Published}
+
+		delta, err := review.Moderate(REVIEW_APPROVED, "admin-1")
+		require.Nil(t, err)
+		require.True(t, delta.IsZero())
+	})
+	t.Run("status invalid", func(t *testing.T) {
+		review := Review{Rating: 5, Status: ReviewStatus_Published}
+
+		_, err := review.Moderate(REVIEW_PUBLISHED, "admin-1")
+		require.Equal(t, response.ErrReviewStatusInvalid, err)
+		require.Equal(t, REVIEW_PUBLISHED, review.GetStatus())
+	})
+}
+
+func TestNewReviewQueryFromAdminRequest(t *testing.T) {
+	page := pagination.Request{Size: 10}
+
+	t.Run("without status", func(t *testing.T) {
+		query, err := NewReviewQueryFromAdminRequest(ListAdminReviewRequestPayload{}, page)
+		require.Nil(t, err)
+		require.Nil(t, query.Status)
+	})
+	t.Run("with status", func(t *testing.T) {
+		query, err := NewReviewQueryFromAdminRequest(ListAdminReviewRequestPayload{Status: "hidden"}, page)
+		require.Nil(t, err)
+		require.Equal(t, ReviewStatus_Hidden, *query.Status)
+	})
+	t.Run("status invalid", func(t *testing.T) {
+		_, err := NewReviewQueryFromAdminRequest(ListAdminReviewRequestPayload{Status: "deleted"}, page)
+		require.Equal(t, response.ErrReviewStatusInvalid, err)
+	})
+}
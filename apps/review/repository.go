@@ -0,0 +1,184 @@
+package review
+
+import (
+	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+func (r repository) Begin(ctx context.Context) (tx *sqlx.Tx, err error) {
+	tx, err = r.db.BeginTxx(ctx, &sql.TxOptions{})
+	return
+}
+
+func (repository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Commit()
+}
+
+func (repository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	return tx.Rollback()
+}
+
+func (r repository) GetProductBySku(ctx context.Context, sku string) (product Product, err error) {
+	query := `
+		SELECT
+			id, sku, name
+		FROM products
+		WHERE sku=$1 AND deleted_at IS NULL
+	`
+
+	err = r.db.GetContext(ctx, &product, query, sku)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+// HasCompletedTransaction memastikan user pernah membeli produk sampai transaksinya selesai
+func (r repository) HasCompletedTransaction(ctx context.Context, userPublicId string, productId int) (completed bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM transactions
+			WHERE user_public_id=$1 AND product_id=$2 AND status=$3
+		)
+	`
+
+	err = r.db.GetContext(ctx, &completed, query, userPublicId, productId, transaction.TransactionStatus_Completed)
+	return
+}
+
+func (r repository) HasReviewed(ctx context.Context, userPublicId string, productId int) (reviewed bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM reviews
+			WHERE user_public_id=$1 AND product_id=$2
+		)
+	`
+
+	err = r.db.GetContext(ctx, &reviewed, query, userPublicId, productId)
+	return
+}
+
+func (r repository) CreateReviewWithTx(ctx context.Context, tx *sqlx.Tx, review Review) (id int, err error) {
+	query := `
+		INSERT INTO reviews (
+			product_id, user_public_id, rating, text
+			, status, moderated_by, created_at, updated_at
+		) VALUES (
+			:product_id, :user_public_id, :rating, :text
+			, :status, :moderated_by, :created_at, :updated_at
+		)
+		ON CONFLICT (product_id, user_public_id) DO NOTHING
+		RETURNING id
+	`
+
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	err = stmt.GetContext(ctx, &id, review)
+	if err != nil {
+		// review lain dari user yang sama masuk lebih dulu
+		if err == sql.ErrNoRows {
+			err = response.ErrReviewAlreadyExists
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetReviewByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (review Review, err error) {
+	query := `
+		SELECT
+			rv.id, rv.product_id, rv.user_public_id, rv.rating, rv.text
+			, rv.status, rv.moderated_by, rv.created_at, rv.updated_at
+			, p.sku
+		FROM reviews rv
+		JOIN products p ON p.id = rv.product_id
+		WHERE rv.id=$1
+		FOR UPDATE OF rv
+	`
+
+	err = tx.GetContext(ctx, &review, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrReviewNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) UpdateReviewStatusWithTx(ctx context.Context, tx *sqlx.Tx, review Review) (err error) {
+	query := `
+		UPDATE reviews
+		SET status=:status, moderated_by=:moderated_by, updated_at=:updated_at
+		WHERE id=:id
+	`
+
+	_, err = tx.NamedExecContext(ctx, query, review)
+	return
+}
+
+// UpdateProductRatingWithTx mengubah agregat rating secara incremental, tanpa menghitung ulang semua review
+func (r repository) UpdateProductRatingWithTx(ctx context.Context, tx *sqlx.Tx, productId int, delta RatingDelta) (err error) {
+	query := `
+		UPDATE products
+		SET rating_count = rating_count + $2, rating_sum = rating_sum + $3, updated_at=NOW()
+		WHERE id=$1
+	`
+
+	_, err = tx.ExecContext(ctx, query, productId, delta.Count, delta.Sum)
+	return
+}
+
+func (r repository) GetReviewsByQuery(ctx context.Context, model ReviewQuery) (reviews []Review, err error) {
+	operator, direction := model.Page.Order(true)
+	query := fmt.Sprintf(`
+		SELECT
+			rv.id, rv.product_id, rv.user_public_id, rv.rating, rv.text
+			, rv.status, rv.moderated_by, rv.created_at, rv.updated_at
+			, p.sku
+		FROM reviews rv
+		JOIN products p ON p.id = rv.product_id
+		WHERE ($1 = 0 OR rv.product_id = $1)
+			AND ($2::int IS NULL OR rv.status = $2)
+			AND (NOT $3 OR rv.status IN ($4, $5))
+			AND ($6 = 0 OR rv.id %s $6)
+		ORDER BY rv.id %s
+		LIMIT $7
+	`, operator, direction)
+
+	cursorId := 0
+	if model.Page.Cursor != nil {
+		cursorId = model.Page.Cursor.Id
+	}
+
+	err = r.db.SelectContext(ctx, &reviews, query,
+		model.ProductId, model.Status, model.VisibleOnly,
+		ReviewStatus_Published, ReviewStatus_Approved,
+		cursorId, model.Page.Limit(),
+	)
+	return
+}
@@ -0,0 +1,53 @@
+package review
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// cachedRepository menghapus cache produk berdasarkan SKU setelah agregat rating berubah.
+// SKU diambil dari review yang dibuat atau dikunci di dalam transaksi yang sama
+type cachedRepository struct {
+	Repository
+
+	pending *cache.PendingKeys[*sqlx.Tx]
+}
+
+func newCachedRepository(repo Repository) cachedRepository {
+	return cachedRepository{
+		Repository: repo,
+		pending:    cache.NewPendingKeys[*sqlx.Tx](cache.TAG_PRODUCT_SKU),
+	}
+}
+
+func (r cachedRepository) CreateReviewWithTx(ctx context.Context, tx *sqlx.Tx, review Review) (id int, err error) {
+	if id, err = r.Repository.CreateReviewWithTx(ctx, tx, review); err != nil {
+		return
+	}
+	r.pending.Add(tx, review.ProductSKU)
+	return
+}
+
+func (r cachedRepository) GetReviewByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (review Review, err error) {
+	if review, err = r.Repository.GetReviewByIdForUpdateWithTx(ctx, tx, id); err != nil {
+		return
+	}
+	r.pending.Add(tx, review.ProductSKU)
+	return
+}
+
+func (r cachedRepository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	if err = r.Repository.Commit(ctx, tx); err != nil {
+		r.pending.Discard(tx)
+		return
+	}
+	r.pending.Flush(ctx, tx)
+	return
+}
+
+func (r cachedRepository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	r.pending.Discard(tx)
+	return r.Repository.Rollback(ctx, tx)
+}
@@ -0,0 +1,24 @@
+package review
+
+type CreateReviewRequestPayload struct {
+	Rating       int    `json:"rating"`
+	Text         string `json:"text"`
+	UserPublicId string `json:"-"`
+}
+
+type ListReviewRequestPayload struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size"`
+}
+
+type ListAdminReviewRequestPayload struct {
+	Status string `form:"status"`
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size"`
+}
+
+type ModerateReviewRequestPayload struct {
+	// APPROVED atau HIDDEN
+	Status       string `json:"status"`
+	UserPublicId string `json:"-"`
+}
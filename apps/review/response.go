@@ -0,0 +1,58 @@
+package review
+
+import "time"
+
+type ReviewResponse struct {
+	Id         int       `json:"id"`
+	ProductSKU string    `json:"product_sku"`
+	Rating     int       `json:"rating"`
+	Text       string    `json:"text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r Review) ToReviewResponse() ReviewResponse {
+	return ReviewResponse{
+		Id:         r.Id,
+		ProductSKU: r.ProductSKU,
+		Rating:     r.Rating,
+		Text:       r.Text,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func NewReviewListResponse(reviews []Review) []ReviewResponse {
+	resp := []ReviewResponse{}
+	for _, review := range reviews {
+		resp = append(resp, review.ToReviewResponse())
+	}
+	return resp
+}
+
+// AdminReviewResponse menampilkan data moderasi yang tidak ditampilkan ke publik
+type AdminReviewResponse struct {
+	ReviewResponse
+	ProductId    int       `json:"product_id"`
+	UserPublicId string    `json:"user_public_id"`
+	Status       string    `json:"status"`
+	ModeratedBy  string    `json:"moderated_by,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (r Review) ToAdminReviewResponse() AdminReviewResponse {
+	return AdminReviewResponse{
+		ReviewResponse: r.ToReviewResponse(),
+		ProductId:      r.ProductId,
+		UserPublicId:   r.UserPublicId,
+		Status:         r.GetStatus(),
+		ModeratedBy:    r.ModeratedBy,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+func NewAdminReviewListResponse(reviews []Review) []AdminReviewResponse {
+	resp := []AdminReviewResponse{}
+	for _, review := range reviews {
+		resp = append(resp, review.ToAdminReviewResponse())
+	}
+	return resp
+}
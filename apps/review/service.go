@@ -0,0 +1,173 @@
+package review
+
+import (
+	"Ecommerce-basic/infra/pagination"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	ReviewDBRepository
+	ReviewRepository
+	ProductRepository
+}
+
+type ReviewDBRepository interface {
+	Begin(ctx context.Context) (tx *sqlx.Tx, err error)
+	Rollback(ctx context.Context, tx *sqlx.Tx) (err error)
+	Commit(ctx context.Context, tx *sqlx.Tx) (err error)
+}
+
+type ReviewRepository interface {
+	HasReviewed(ctx context.Context, userPublicId string, productId int) (reviewed bool, err error)
+	CreateReviewWithTx(ctx context.Context, tx *sqlx.Tx, review Review) (id int, err error)
+	GetReviewByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id int) (review Review, err error)
+	UpdateReviewStatusWithTx(ctx context.Context, tx *sqlx.Tx, review Review) (err error)
+	GetReviewsByQuery(ctx context.Context, model ReviewQuery) (reviews []Review, err error)
+}
+
+type ProductRepository interface {
+	GetProductBySku(ctx context.Context, sku string) (product Product, err error)
+	HasCompletedTransaction(ctx context.Context, userPublicId string, productId int) (completed bool, err error)
+	UpdateProductRatingWithTx(ctx context.Context, tx *sqlx.Tx, productId int, delta RatingDelta) (err error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func newService(repo Repository) service {
+	return service{
+		repo: repo,
+	}
+}
+
+// CreateReview hanya untuk pembeli yang transaksinya untuk produk ini sudah COMPLETED, satu review per produk
+func (s service) CreateReview(ctx context.Context, sku string, req CreateReviewRequestPayload) (review Review, err error) {
+	product, err := s.repo.GetProductBySku(ctx, sku)
+	if err != nil {
+		return
+	}
+
+	review = NewReviewFromRequest(product, req)
+	if err = review.Validate(); err != nil {
+		return
+	}
+
+	completed, err := s.repo.HasCompletedTransaction(ctx, req.UserPublicId, product.Id)
+	if err != nil {
+		return
+	}
+	if !completed {
+		err = response.ErrReviewNotVerifiedUser
+		return
+	}
+
+	reviewed, err := s.repo.HasReviewed(ctx, req.UserPublicId, product.Id)
+	if err != nil {
+		return
+	}
+	if reviewed {
+		err = response.ErrReviewAlreadyExists
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	if review.Id, err = s.repo.CreateReviewWithTx(ctx, tx, review); err != nil {
+		return
+	}
+
+	if err = s.repo.UpdateProductRatingWithTx(ctx, tx, product.Id, review.Contribution()); err != nil {
+		return
+	}
+
+	err = s.repo.Commit(ctx, tx)
+	return
+}
+
+// ProductReviews menampilkan review yang terlihat publik untuk satu produk
+func (s service) ProductReviews(ctx context.Context, sku string, req ListReviewRequestPayload) (reviews []Review, meta pagination.Meta, err error) {
+	secret := config.Cfg.App.Encryption.CursorSecret
+
+	page, err := pagination.NewRequest(req.Cursor, req.Size, "", secret)
+	if err != nil {
+		return
+	}
+
+	product, err := s.repo.GetProductBySku(ctx, sku)
+	if err != nil {
+		return
+	}
+
+	query := ReviewQuery{ProductId: product.Id, VisibleOnly: true, Page: page}
+	return s.listReviews(ctx, query, secret)
+}
+
+// AdminReviews menampilkan semua review untuk moderasi, bisa difilter per status
+func (s service) AdminReviews(ctx context.Context, req ListAdminReviewRequestPayload) (reviews []Review, meta pagination.Meta, err error) {
+	secret := config.Cfg.App.Encryption.CursorSecret
+
+	page, err := pagination.NewRequest(req.Cursor, req.Size, "", secret)
+	if err != nil {
+		return
+	}
+
+	query, err := NewReviewQueryFromAdminRequest(req, page)
+	if err != nil {
+		return
+	}
+	return s.listReviews(ctx, query, secret)
+}
+
+func (s service) listReviews(ctx context.Context, query ReviewQuery, secret string) (reviews []Review, meta pagination.Meta, err error) {
+	reviews, err = s.repo.GetReviewsByQuery(ctx, query)
+	if err != nil {
+		return
+	}
+
+	reviews, meta = pagination.Paginate(reviews, query.Page, Review.CursorKey, secret)
+	if len(reviews) == 0 {
+		reviews = []Review{}
+	}
+	return
+}
+
+// ModerateReview mengubah status review dan menyesuaikan agregat rating produk dalam transaksi yang sama
+func (s service) ModerateReview(ctx context.Context, id int, req ModerateReviewRequestPayload) (review Review, err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer s.repo.Rollback(ctx, tx)
+
+	review, err = s.repo.GetReviewByIdForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return
+	}
+
+	delta, err := review.Moderate(req.Status, req.UserPublicId)
+	if err != nil {
+		return
+	}
+
+	if err = s.repo.UpdateReviewStatusWithTx(ctx, tx, review); err != nil {
+		return
+	}
+
+	if !delta.IsZero() {
+		if err = s.repo.UpdateProductRatingWithTx(ctx, tx, review.ProductId, delta); err != nil {
+			return
+		}
+	}
+
+	err = s.repo.Commit(ctx, tx)
+	return
+}
@@ -0,0 +1,52 @@
+package review
+
+import (
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo)
+}
+
+func TestCreateReview(t *testing.T) {
+	productSKU := "a98dcf06-7b4b-4f33-a6d2-20738bb8081b"
+
+	t.Run("not verified user", func(t *testing.T) {
+		_, err := svc.CreateReview(context.Background(), productSKU, CreateReviewRequestPayload{
+			Rating: 5, Text: "mantap", UserPublicId: uuid.NewString(),
+		})
+		require.Equal(t, response.ErrReviewNotVerifiedUser, err)
+	})
+	t.Run("product not found", func(t *testing.T) {
+		_, err := svc.CreateReview(context.Background(), uuid.NewString(), CreateReviewRequestPayload{
+			Rating: 5, UserPublicId: uuid.NewString(),
+		})
+		require.Equal(t, response.ErrNotFound, err)
+	})
+}
+
+func TestAdminReviews(t *testing.T) {
+	_, _, err := svc.AdminReviews(context.Background(), ListAdminReviewRequestPayload{Status: "deleted"})
+	require.Equal(t, response.ErrReviewStatusInvalid, err)
+}
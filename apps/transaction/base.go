@@ -1,16 +1,30 @@
 package transaction
 
 import (
+	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/blobstore"
 	"Ecommerce-basic/infra/gin"
+	"Ecommerce-basic/internal"
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 )
 
 func Init(router *gin.Engine, db *sqlx.DB) {
-	repo := newRepository(db)
-	svc := newService(repo)
+	allocator, err := inventory.NewAllocationStrategy(config.Cfg.App.Inventory.AllocationStrategy)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newCachedRepository(newRepository(db))
+	svc := newService(repo, allocator, blobstore.NewLocal(config.Cfg.App.Digital.BlobRoot))
 	handler := newHandler(svc)
 
+	// link download sudah ditandatangani, tidak butuh login
+	router.GET("/downloads/:id", handler.DownloadDigitalFile)
+
 	trxRoute := router.Group("transactions")
 	{
 		// menggunakan middleware
@@ -19,7 +33,22 @@ func Init(router *gin.Engine, db *sqlx.DB) {
 		// route dibawahnya akan menggunakan middleware tersebut
 		trxRoute.POST("/checkout", handler.CreateTransaction)
 		trxRoute.GET("/user/histories", handler.GetTransactionByUser)
-		trxRoute.PUT("/status", handler.UpdateTransactionStatus)
+		trxRoute.PUT("/status", infragin.CheckRoles([]string{string(auth.ROLE_Admin)}), handler.UpdateTransactionStatus)
+		trxRoute.POST("/:id/cancel", handler.CancelTransaction)
 		trxRoute.GET("/product/:sku/histories", handler.GetTransactionHistoriesByProduct)
+		trxRoute.GET("/:id/delivery", handler.GetDelivery)
+	}
+}
+
+// Checkout adalah logic checkout yang sama dengan POST /transactions/checkout, dipakai modul lain seperti wishlist
+type Checkout interface {
+	CreateTransaction(ctx context.Context, req CreateTransactionRequestPayload) (err error)
+}
+
+func NewCheckout(db *sqlx.DB) (Checkout, error) {
+	allocator, err := inventory.NewAllocationStrategy(config.Cfg.App.Inventory.AllocationStrategy)
+	if err != nil {
+		return nil, err
 	}
+	return newService(newCachedRepository(newRepository(db)), allocator, blobstore.NewLocal(config.Cfg.App.Digital.BlobRoot)), nil
 }
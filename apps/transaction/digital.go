@@ -0,0 +1,81 @@
+package transaction
+
+import (
+	"Ecommerce-basic/infra/response"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// masa berlaku link download jika app.digital.download_ttl tidak diatur
+const defaultDownloadTTL = 15 * time.Minute
+
+// Delivery adalah isi pesanan digital untuk pembeli: license key dan link download file
+type Delivery struct {
+	TransactionId int
+	LicenseKeys   []string
+	FileName      string
+	DownloadURL   string
+	ExpiresAt     *time.Time
+}
+
+// NewDownloadURL membuat link /downloads/:id yang ditandatangani dan hanya berlaku sampai expiresAt,
+// link tidak butuh login sehingga bisa dibuka dari browser atau download manager
+func NewDownloadURL(trx Transaction, fileKey string, expiresAt time.Time, secret string) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", signDownload(trx.Id, fileKey, expires, secret))
+	return fmt.Sprintf("/downloads/%d?%s", trx.Id, query.Encode())
+}
+
+// VerifyDownload signature diperiksa lebih dulu supaya link yang diubah tidak pernah dianggap kedaluwarsa
+func VerifyDownload(trxId int, fileKey string, expires string, signature string, now time.Time, secret string) (err error) {
+	expected := signDownload(trxId, fileKey, expires, secret)
+	if fileKey == "" || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return response.ErrDownloadLinkInvalid
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return response.ErrDownloadLinkInvalid
+	}
+	if now.Unix() > expiresAt {
+		return response.ErrDownloadLinkExpired
+	}
+	return nil
+}
+
+func signDownload(trxId int, fileKey string, expires string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d:%s:%s", trxId, fileKey, expires)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateDelivery hanya pemilik transaksi digital yang sudah dibayar dan belum dibatalkan yang bisa melihat isi pesanan
+func (t Transaction) ValidateDelivery(userPublicId string) (err error) {
+	if t.UserPublicId != userPublicId {
+		return response.ErrNotFound
+	}
+	if !t.IsDigital() {
+		return response.ErrTransactionNotDigital
+	}
+	return t.ValidateDownloadable()
+}
+
+// ValidateDownloadable license key dan file baru dikirim setelah pembayaran (ON_PROGRESS atau sesudahnya),
+// sehingga pembeli tidak bisa mengambil isi pesanan lalu membatalkan transaksi yang belum dibayar
+func (t Transaction) ValidateDownloadable() (err error) {
+	if t.IsCancelled() {
+		return response.ErrTransactionCancelled
+	}
+	if !t.IsPaid() {
+		return response.ErrTransactionNotPaid
+	}
+	return
+}
@@ -1,6 +1,8 @@
 package transaction
 
 import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/pagination"
 	"Ecommerce-basic/infra/response"
 	"encoding/json"
 	"time"
@@ -13,11 +15,13 @@ const (
 	TransactionStatus_Progress   TransactionStatus = 10
 	TransactionStatus_InDelivery TransactionStatus = 15
 	TransactionStatus_Completed  TransactionStatus = 20
+	TransactionStatus_Cancelled  TransactionStatus = 30
 
 	TRX_CREATED     string = "CREATED"
 	TRX_ON_PROGRESS string = "ON_PROGRESS"
 	TRX_IN_DELIVERY string = "IN_DELIVERY"
 	TRX_COMPLETED   string = "COMPLETED"
+	TRX_CANCELLED   string = "CANCELLED"
 	TRX_UNKNOWN     string = "UNKNOWN"
 )
 
@@ -27,6 +31,7 @@ var (
 		TransactionStatus_Progress:   TRX_ON_PROGRESS,
 		TransactionStatus_InDelivery: TRX_IN_DELIVERY,
 		TransactionStatus_Completed:  TRX_COMPLETED,
+		TransactionStatus_Cancelled:  TRX_CANCELLED,
 	}
 )
 
@@ -43,6 +48,28 @@ type Transaction struct {
 	ProductJSON  json.RawMessage   `db:"product_snapshot"`
 	CreatedAt    time.Time         `db:"created_at"`
 	UpdatedAt    time.Time         `db:"updated_at"`
+
+	// gudang yang memenuhi order, nil untuk transaksi sebelum multi gudang
+	WarehouseId        *int            `db:"warehouse_id"`
+	AllocationStrategy string          `db:"allocation_strategy"`
+	ShippingJSON       json.RawMessage `db:"shipping_address"`
+}
+
+type ShippingAddress struct {
+	Address    string   `json:"address"`
+	City       string   `json:"city"`
+	Province   string   `json:"province"`
+	PostalCode string   `json:"postal_code"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+}
+
+func (a ShippingAddress) Location() inventory.Location {
+	return inventory.Location{
+		City:      a.City,
+		Latitude:  a.Latitude,
+		Longitude: a.Longitude,
+	}
 }
 
 func NewTransaction(userPublicId string) Transaction {
@@ -54,13 +81,15 @@ func NewTransaction(userPublicId string) Transaction {
 	}
 }
 func NewTransactionFromCreateRequest(req CreateTransactionRequestPayload) Transaction {
-	return Transaction{
+	trx := Transaction{
 		UserPublicId: req.UserPublicId,
 		Amount:       req.Amount,
 		Status:       TransactionStatus_Created,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
+	trx.SetShippingAddress(req.ShippingAddress)
+	return trx
 }
 
 func (t Transaction) Validate() (err error) {
@@ -77,6 +106,14 @@ func (t Transaction) ValidateStock(productStock uint8) (err error) {
 	return
 }
 
+// ValidateAvailable memastikan stok yang tidak ditahan reservation lain cukup
+func (t Transaction) ValidateAvailable(productStock int, reserved int) (err error) {
+	if int(t.Amount) > inventory.Available(productStock, reserved) {
+		return response.ErrAmountGreaterThanAvailable
+	}
+	return
+}
+
 func (t *Transaction) SetSubTotal() {
 	if t.SubTotal == 0 {
 		t.SubTotal = t.ProductPrice * uint(t.Amount)
@@ -109,6 +146,8 @@ func (t *Transaction) FromProduct(product Product) *Transaction {
 }
 
 func (t *Transaction) SetProductJSON(product Product) (err error) {
+	product.Digital = product.IsDigital()
+
 	productJSON, err := json.Marshal(product)
 	if err != nil {
 		return
@@ -127,6 +166,46 @@ func (t Transaction) GetProduct() (product Product, err error) {
 	return
 }
 
+func (t *Transaction) SetShippingAddress(address ShippingAddress) (err error) {
+	addressJSON, err := json.Marshal(address)
+	if err != nil {
+		return
+	}
+
+	t.ShippingJSON = addressJSON
+	return
+}
+
+func (t Transaction) GetShippingAddress() (address *ShippingAddress) {
+	if len(t.ShippingJSON) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(t.ShippingJSON, &address); err != nil {
+		return nil
+	}
+	return
+}
+
+// SetAllocation mencatat gudang yang dipilih oleh allocation strategy
+func (t *Transaction) SetAllocation(stock inventory.WarehouseStock, strategy string) *Transaction {
+	warehouseId := stock.WarehouseId
+	t.WarehouseId = &warehouseId
+	t.AllocationStrategy = strategy
+	return t
+}
+
+func (t Transaction) NewAllocationRequest(address ShippingAddress) inventory.AllocationRequest {
+	return inventory.AllocationRequest{
+		ProductId:   int(t.ProductId),
+		Amount:      int(t.Amount),
+		Destination: address.Location(),
+	}
+}
+
+func (t Transaction) CursorKey() pagination.Key {
+	return pagination.Key{Id: t.Id}
+}
+
 func (t Transaction) GetStatus() string {
 	status, ok := MappingTransactionStatus[t.Status]
 	if !ok {
@@ -141,6 +220,109 @@ func (t *Transaction) UpdateStatus(newStatus TransactionStatus) {
 	t.UpdatedAt = time.Now()
 }
 
+// transaksi yang sudah dibatalkan tidak bisa diubah lagi karena stoknya sudah dikembalikan
+func (t Transaction) ValidateStatusChange(newStatus TransactionStatus) (err error) {
+	if _, ok := MappingTransactionStatus[newStatus]; !ok {
+		return response.ErrTransactionStatusInvalid
+	}
+	if t.IsCancelled() {
+		return response.ErrTransactionCancelled
+	}
+	if newStatus == TransactionStatus_Cancelled && t.Status == TransactionStatus_Completed {
+		return response.ErrTransactionNotCancelable
+	}
+	// produk digital dikirim lewat license key dan link download, tidak ada pengiriman fisik
+	if newStatus == TransactionStatus_InDelivery && t.IsDigital() {
+		return response.ErrDigitalNotShipped
+	}
+	return
+}
+
+// ValidateCancelBy pembeli hanya bisa membatalkan transaksi miliknya sendiri sebelum dikirim,
+// status lain hanya diubah oleh admin
+func (t Transaction) ValidateCancelBy(userPublicId string) (err error) {
+	if t.UserPublicId != userPublicId {
+		return response.ErrNotFound
+	}
+	if err = t.ValidateStatusChange(TransactionStatus_Cancelled); err != nil {
+		return
+	}
+	if t.Status != TransactionStatus_Created && t.Status != TransactionStatus_Progress {
+		return response.ErrTransactionShipped
+	}
+	// isi pesanan digital sudah bisa diambil sejak dibayar
+	if t.IsDigital() && t.IsPaid() {
+		return response.ErrTransactionDelivered
+	}
+	return
+}
+
+func (t Transaction) IsDigital() bool {
+	product, err := t.GetProduct()
+	if err != nil {
+		return false
+	}
+	return product.IsDigital()
+}
+
+func (t Transaction) IsCancelled() bool {
+	return t.Status == TransactionStatus_Cancelled
+}
+
+// IsPaid status ON_PROGRESS dan sesudahnya, transaksi yang dibatalkan tidak dianggap dibayar
+func (t Transaction) IsPaid() bool {
+	return t.Status >= TransactionStatus_Progress && !t.IsCancelled()
+}
+
+func (t Transaction) NewSaleMovement(stockAfter int) inventory.StockMovement {
+	return t.newSaleMovement(int(t.ProductId), int(t.Amount), stockAfter)
+}
+
+func (t Transaction) NewCancellationMovement(stockAfter int) inventory.StockMovement {
+	return t.newCancellationMovement(int(t.ProductId), int(t.Amount), stockAfter)
+}
+
+// ComponentAmount jumlah unit komponen yang dipakai untuk seluruh bundle di transaksi ini
+func (t Transaction) ComponentAmount(component BundleComponent) int {
+	return component.Quantity * int(t.Amount)
+}
+
+// ValidateComponentAvailable memastikan stok komponen yang tidak ditahan reservation cukup untuk semua bundle,
+// komponen yang sudah dihapus membuat bundle tidak bisa dibeli
+func (t Transaction) ValidateComponentAvailable(component BundleComponent, reserved int) (err error) {
+	if component.Deleted || t.ComponentAmount(component) > inventory.Available(component.Stock, reserved) {
+		return response.ErrAmountGreaterThanAvailable
+	}
+	return
+}
+
+func (t Transaction) NewComponentSaleMovement(component BundleComponent, stockAfter int) inventory.StockMovement {
+	return t.newSaleMovement(component.ProductId, t.ComponentAmount(component), stockAfter)
+}
+
+func (t Transaction) NewComponentCancellationMovement(component BundleComponent, stockAfter int) inventory.StockMovement {
+	return t.newCancellationMovement(component.ProductId, t.ComponentAmount(component), stockAfter)
+}
+
+func (t Transaction) newSaleMovement(productId int, amount int, stockAfter int) inventory.StockMovement {
+	movement := inventory.NewStockMovement(productId, inventory.MovementType_Sale, -amount, stockAfter, inventory.REASON_ORDER)
+	movement.WithReference(inventory.REFERENCE_TRANSACTION, t.Id).
+		WithActor(t.UserPublicId)
+	if t.WarehouseId != nil {
+		movement.WithWarehouse(*t.WarehouseId)
+	}
+	return movement
+}
+
+func (t Transaction) newCancellationMovement(productId int, amount int, stockAfter int) inventory.StockMovement {
+	movement := inventory.NewStockMovement(productId, inventory.MovementType_Cancellation, amount, stockAfter, inventory.REASON_ORDER_CANCELLED)
+	movement.WithReference(inventory.REFERENCE_TRANSACTION, t.Id)
+	if t.WarehouseId != nil {
+		movement.WithWarehouse(*t.WarehouseId)
+	}
+	return movement
+}
+
 func (t Transaction) ToTransactionHistoryResponse() TransactionHisotryResponse {
 	product, err := t.GetProduct()
 	if err != nil {
@@ -159,5 +341,41 @@ func (t Transaction) ToTransactionHistoryResponse() TransactionHisotryResponse {
 		CreatedAt:    t.CreatedAt,
 		UpdatedAt:    t.UpdatedAt,
 		Product:      product,
+
+		ShippingAddress: t.GetShippingAddress(),
+		Allocation:      t.ToAllocationResponse(),
+	}
+}
+
+func (t Transaction) ToProductTransactionHistoryResponse() ProductTransactionHistoryResponse {
+	product, err := t.GetProduct()
+	if err != nil {
+		product = Product{}
+	}
+	product.FileKey, product.FileName = "", ""
+
+	return ProductTransactionHistoryResponse{
+		Id:           t.Id,
+		UserPublicId: t.UserPublicId,
+		ProductId:    t.ProductId,
+		ProductPrice: t.ProductPrice,
+		Amount:       t.Amount,
+		SubTotal:     t.SubTotal,
+		PlatformFee:  t.PlatformFee,
+		GrandTotal:   t.GrandTotal,
+		Status:       t.GetStatus(),
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+		Product:      product,
+	}
+}
+
+func (t Transaction) ToAllocationResponse() *AllocationResponse {
+	if t.WarehouseId == nil {
+		return nil
+	}
+	return &AllocationResponse{
+		WarehouseId: *t.WarehouseId,
+		Strategy:    t.AllocationStrategy,
 	}
 }
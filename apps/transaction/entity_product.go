@@ -1,19 +1,68 @@
 package transaction
 
-import "Ecommerce-basic/infra/response"
+import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/response"
+)
 
 type Product struct {
-	Id    int    `db:"id" json:"id"`
-	SKU   string `db:"sku" json:"sku"`
-	Name  string `db:"name" json:"name"`
-	Stock int    `db:"stock" json:"-"`
-	Price int    `db:"price" json:"price"`
+	Id     int                   `db:"id" json:"id"`
+	SKU    string                `db:"sku" json:"sku"`
+	Name   string                `db:"name" json:"name"`
+	Stock  int                   `db:"stock" json:"-"`
+	Price  int                   `db:"price" json:"price"`
+	Status product.ProductStatus `db:"status" json:"-"`
+	Type   product.ProductType   `db:"type" json:"-"`
+
+	// hanya diisi GetProductByIdForUpdateWithTx, tidak ikut disimpan di snapshot
+	Deleted bool `db:"deleted" json:"-"`
+
+	// file produk digital saat checkout, link download selalu mengarah ke file yang dibeli
+	FileKey  string `db:"file_key" json:"file_key,omitempty"`
+	FileName string `db:"file_name" json:"file_name,omitempty"`
+
+	// type tidak ikut disimpan di snapshot, penanda ini dipakai untuk transaksi produk digital
+	Digital bool `db:"-" json:"digital,omitempty"`
+
+	// isi bundle saat checkout, disimpan di product_snapshot supaya pembatalan
+	// tetap mengembalikan stok komponen yang benar walaupun isi bundle diubah
+	Components []BundleComponent `db:"-" json:"components,omitempty"`
+}
+
+type BundleComponent struct {
+	ProductId int    `db:"component_id" json:"product_id"`
+	SKU       string `db:"sku" json:"sku"`
+	Name      string `db:"name" json:"name"`
+	Quantity  int    `db:"quantity" json:"quantity"`
+	Stock     int    `db:"stock" json:"-"`
+	Deleted   bool   `db:"deleted" json:"-"`
 }
 
 func (p Product) IsExists() bool {
 	return p.Id != 0
 }
 
+func (p Product) IsBundle() bool {
+	return p.Type == product.ProductType_Bundle
+}
+
+func (p Product) IsDigital() bool {
+	return p.Type == product.ProductType_Digital || p.Digital
+}
+
+// ValidateSellable hanya produk PUBLISHED yang belum dihapus yang bisa di-checkout,
+// soft delete tidak mengubah status produk
+func (p Product) ValidateSellable() (err error) {
+	if p.Deleted {
+		return response.ErrNotFound
+	}
+	if p.Status != product.ProductStatus_Published {
+		return response.ErrProductNotPublished
+	}
+	return
+}
+
 func (p *Product) UpdateStockProduct(amount uint8) (err error) {
 	if p.Stock < int(amount) {
 		return response.ErrAmountGreaterThanStock
@@ -22,3 +71,37 @@ func (p *Product) UpdateStockProduct(amount uint8) (err error) {
 	p.Stock = p.Stock - int(amount)
 	return
 }
+
+func (p *Product) RestockProduct(amount uint8) {
+	p.Stock = p.Stock + int(amount)
+}
+
+// NewBundleWarehouseStocks menghitung berapa set bundle yang bisa dipenuhi setiap gudang,
+// hanya gudang yang menyimpan semua komponen yang bisa dipilih allocation strategy
+func NewBundleWarehouseStocks(components []BundleComponent, stocks map[int][]inventory.WarehouseStock) (bundleStocks []inventory.WarehouseStock) {
+	if len(components) == 0 {
+		return
+	}
+
+	sets := map[int]int{}
+	counted := map[int]int{}
+	for _, component := range components {
+		for _, stock := range stocks[component.ProductId] {
+			available := stock.Stock / component.Quantity
+			if counted[stock.WarehouseId] == 0 || available < sets[stock.WarehouseId] {
+				sets[stock.WarehouseId] = available
+			}
+			counted[stock.WarehouseId]++
+		}
+	}
+
+	for _, stock := range stocks[components[0].ProductId] {
+		if counted[stock.WarehouseId] != len(components) {
+			continue
+		}
+		stock.ProductId = 0
+		stock.Stock = sets[stock.WarehouseId]
+		bundleStocks = append(bundleStocks, stock)
+	}
+	return
+}
@@ -1,7 +1,14 @@
 package transaction
 
 import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/response"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -120,3 +127,238 @@ func TestTransactionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateStatusChange(t *testing.T) {
+	t.Run("cancel created transaction", func(t *testing.T) {
+		trx := Transaction{Status: TransactionStatus_Created}
+		require.Nil(t, trx.ValidateStatusChange(TransactionStatus_Cancelled))
+	})
+	t.Run("status invalid", func(t *testing.T) {
+		trx := Transaction{Status: TransactionStatus_Created}
+
+		err := trx.ValidateStatusChange(99)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrTransactionStatusInvalid, err)
+	})
+	t.Run("already cancelled", func(t *testing.T) {
+		trx := Transaction{Status: TransactionStatus_Cancelled}
+
+		err := trx.ValidateStatusChange(TransactionStatus_Progress)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrTransactionCancelled, err)
+	})
+	t.Run("completed cannot be cancelled", func(t *testing.T) {
+		trx := Transaction{Status: TransactionStatus_Completed}
+
+		err := trx.ValidateStatusChange(TransactionStatus_Cancelled)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrTransactionNotCancelable, err)
+	})
+}
+
+func TestValidateCancelBy(t *testing.T) {
+	owner := "5c534133-f81f-4df4-977e-38669242eb48"
+
+	t.Run("owner cancels before delivery", func(t *testing.T) {
+		require.Nil(t, Transaction{UserPublicId: owner, Status: TransactionStatus_Created}.ValidateCancelBy(owner))
+		require.Nil(t, Transaction{UserPublicId: owner, Status: TransactionStatus_Progress}.ValidateCancelBy(owner))
+	})
+	t.Run("other user", func(t *testing.T) {
+		trx := Transaction{UserPublicId: owner, Status: TransactionStatus_Created}
+		require.Equal(t, response.ErrNotFound, trx.ValidateCancelBy("other"))
+	})
+	t.Run("in delivery", func(t *testing.T) {
+		trx := Transaction{UserPublicId: owner, Status: TransactionStatus_InDelivery}
+		require.Equal(t, response.ErrTransactionShipped, trx.ValidateCancelBy(owner))
+	})
+	t.Run("completed", func(t *testing.T) {
+		trx := Transaction{UserPublicId: owner, Status: TransactionStatus_Completed}
+		require.Equal(t, response.ErrTransactionNotCancelable, trx.ValidateCancelBy(owner))
+	})
+}
+
+func TestStockMovement(t *testing.T) {
+	trx := Transaction{Id: 12, ProductId: 3, Amount: 2, UserPublicId: "user-1"}
+
+	sale := trx.NewSaleMovement(8)
+	require.Equal(t, -2, sale.Quantity)
+	require.Equal(t, 8, sale.StockAfter)
+	require.Equal(t, inventory.MovementType_Sale, sale.Type)
+	require.Equal(t, 12, *sale.ReferenceId)
+
+	cancellation := trx.NewCancellationMovement(10)
+	require.Equal(t, 2, cancellation.Quantity)
+	require.Equal(t, inventory.MovementType_Cancellation, cancellation.Type)
+}
+
+func TestSetAllocation(t *testing.T) {
+	req := CreateTransactionRequestPayload{
+		Amount:          2,
+		ShippingAddress: ShippingAddress{Address: "Jl. Tunjungan 1", City: "Surabaya"},
+	}
+	trx := NewTransactionFromCreateRequest(req)
+	trx.SetAllocation(inventory.WarehouseStock{WarehouseId: 2}, inventory.ALLOCATION_NEAREST)
+
+	require.Equal(t, "Surabaya", trx.GetShippingAddress().City)
+	require.Equal(t, 2, *trx.WarehouseId)
+
+	sale := trx.NewSaleMovement(8)
+	require.Equal(t, 2, sale.WarehouseId)
+
+	resp := trx.ToTransactionHistoryResponse()
+	require.Equal(t, 2, resp.Allocation.WarehouseId)
+	require.Equal(t, inventory.ALLOCATION_NEAREST, resp.Allocation.Strategy)
+}
+
+func TestToProductTransactionHistoryResponse(t *testing.T) {
+	req := CreateTransactionRequestPayload{
+		Amount:          1,
+		ShippingAddress: ShippingAddress{Address: "Jl. Tunjungan 1", City: "Surabaya"},
+	}
+	trx := NewTransactionFromCreateRequest(req)
+	trx.SetAllocation(inventory.WarehouseStock{WarehouseId: 2}, inventory.ALLOCATION_NEAREST)
+	require.Nil(t, trx.SetProductJSON(Product{Id: 1, SKU: "EBOOK-1", FileKey: "products/1/ebook.pdf", FileName: "ebook.pdf"}))
+
+	data, err := json.Marshal(trx.ToProductTransactionHistoryResponse())
+	require.Nil(t, err)
+	require.NotContains(t, string(data), "Tunjungan")
+	require.NotContains(t, string(data), "allocation")
+	require.NotContains(t, string(data), "ebook.pdf")
+	require.Contains(t, string(data), "EBOOK-1")
+}
+
+func TestValidateAvailable(t *testing.T) {
+	trx := NewTransactionFromCreateRequest(CreateTransactionRequestPayload{Amount: 3})
+
+	require.Nil(t, trx.ValidateAvailable(10, 7))
+	require.Equal(t, response.ErrAmountGreaterThanAvailable, trx.ValidateAvailable(10, 8))
+}
+
+func TestValidateSellable(t *testing.T) {
+	t.Run("published", func(t *testing.T) {
+		myProduct := Product{Id: 1, Status: product.ProductStatus_Published}
+
+		require.Nil(t, myProduct.ValidateSellable())
+	})
+	t.Run("not published", func(t *testing.T) {
+		for _, status := range []product.ProductStatus{product.ProductStatus_Draft, product.ProductStatus_Archived} {
+			myProduct := Product{Id: 1, Status: status}
+
+			require.Equal(t, response.ErrProductNotPublished, myProduct.ValidateSellable())
+		}
+	})
+	t.Run("soft deleted", func(t *testing.T) {
+		myProduct := Product{Id: 1, Status: product.ProductStatus_Published, Deleted: true}
+
+		require.Equal(t, response.ErrNotFound, myProduct.ValidateSellable())
+	})
+}
+
+func TestBundleCheckout(t *testing.T) {
+	trx := Transaction{Id: 7, ProductId: 1, Amount: 3}
+	shirt := BundleComponent{ProductId: 2, Quantity: 1, Stock: 10}
+	socks := BundleComponent{ProductId: 3, Quantity: 2, Stock: 10}
+
+	t.Run("component amount", func(t *testing.T) {
+		require.Equal(t, 6, trx.ComponentAmount(socks))
+	})
+	t.Run("component available", func(t *testing.T) {
+		require.Nil(t, trx.ValidateComponentAvailable(socks, 4))
+		require.Equal(t, response.ErrAmountGreaterThanAvailable, trx.ValidateComponentAvailable(socks, 5))
+
+		deleted := shirt
+		deleted.Deleted = true
+		require.Equal(t, response.ErrAmountGreaterThanAvailable, trx.ValidateComponentAvailable(deleted, 0))
+	})
+	t.Run("component movements", func(t *testing.T) {
+		sale := trx.NewComponentSaleMovement(socks, 4)
+		require.Equal(t, 3, sale.ProductId)
+		require.Equal(t, -6, sale.Quantity)
+		require.Equal(t, 4, sale.StockAfter)
+
+		cancel := trx.NewComponentCancellationMovement(socks, 10)
+		require.Equal(t, 6, cancel.Quantity)
+	})
+	t.Run("snapshot keeps composition", func(t *testing.T) {
+		bundle := Product{Id: 1, SKU: "SET-01", Name: "Paket", Price: 50_000, Type: product.ProductType_Bundle, Components: []BundleComponent{shirt, socks}}
+		require.Nil(t, trx.SetProductJSON(bundle))
+
+		snapshot, err := trx.GetProduct()
+		require.Nil(t, err)
+		require.Len(t, snapshot.Components, 2)
+		require.Equal(t, 2, snapshot.Components[1].Quantity)
+		require.Zero(t, snapshot.Components[1].Stock)
+	})
+}
+
+func TestNewBundleWarehouseStocks(t *testing.T) {
+	components := []BundleComponent{{ProductId: 2, Quantity: 1}, {ProductId: 3, Quantity: 2}}
+	stocks := map[int][]inventory.WarehouseStock{
+		2: {{WarehouseId: 1, ProductId: 2, Stock: 5, City: "Jakarta"}, {WarehouseId: 2, ProductId: 2, Stock: 1}, {WarehouseId: 3, ProductId: 2, Stock: 4}},
+		3: {{WarehouseId: 1, ProductId: 3, Stock: 4, City: "Jakarta"}, {WarehouseId: 2, ProductId: 3, Stock: 10}},
+	}
+
+	bundleStocks := NewBundleWarehouseStocks(components, stocks)
+	require.Equal(t, []inventory.WarehouseStock{
+		{WarehouseId: 1, Stock: 2, City: "Jakarta"},
+		{WarehouseId: 2, Stock: 1},
+	}, bundleStocks)
+}
+
+func TestDigitalDelivery(t *testing.T) {
+	ebook := Product{Id: 5, SKU: "EBOOK-01", Name: "E-Book", Price: 30_000, Type: product.ProductType_Digital, FileKey: "products/5/abc.pdf", FileName: "go.pdf"}
+	trx := Transaction{Id: 9, UserPublicId: "buyer", Status: TransactionStatus_Created}
+	require.Nil(t, trx.SetProductJSON(ebook))
+
+	now := time.Now()
+	url := NewDownloadURL(trx, ebook.FileKey, now.Add(time.Minute), "secret")
+	require.True(t, strings.HasPrefix(url, "/downloads/9?"))
+
+	expires := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+	signature := signDownload(trx.Id, ebook.FileKey, expires, "secret")
+
+	t.Run("valid link", func(t *testing.T) {
+		require.Nil(t, VerifyDownload(trx.Id, ebook.FileKey, expires, signature, now, "secret"))
+	})
+	t.Run("tampered link", func(t *testing.T) {
+		require.Equal(t, response.ErrDownloadLinkInvalid, VerifyDownload(10, ebook.FileKey, expires, signature, now, "secret"))
+		require.Equal(t, response.ErrDownloadLinkInvalid, VerifyDownload(trx.Id, ebook.FileKey, expires, signature, now, "other"))
+	})
+	t.Run("expired link", func(t *testing.T) {
+		require.Equal(t, response.ErrDownloadLinkExpired, VerifyDownload(trx.Id, ebook.FileKey, expires, signature, now.Add(2*time.Minute), "secret"))
+	})
+	t.Run("digital order is not shipped", func(t *testing.T) {
+		require.Equal(t, response.ErrDigitalNotShipped, trx.ValidateStatusChange(TransactionStatus_InDelivery))
+		require.Nil(t, trx.ValidateStatusChange(TransactionStatus_Completed))
+	})
+	t.Run("delivery only for buyer", func(t *testing.T) {
+		paid := trx
+		paid.Status = TransactionStatus_Progress
+
+		require.Nil(t, paid.ValidateDelivery("buyer"))
+		require.Equal(t, response.ErrNotFound, paid.ValidateDelivery("someone"))
+
+		physical := Transaction{Id: 10, UserPublicId: "buyer", Status: TransactionStatus_Progress}
+		require.Nil(t, physical.SetProductJSON(Product{Id: 1, SKU: "SHIRT", Name: "Kaos", Price: 10_000, Type: product.ProductType_Standard}))
+		require.Equal(t, response.ErrTransactionNotDigital, physical.ValidateDelivery("buyer"))
+	})
+	t.Run("delivery only after payment", func(t *testing.T) {
+		require.Equal(t, response.ErrTransactionNotPaid, trx.ValidateDelivery("buyer"))
+
+		cancelled := trx
+		cancelled.Status = TransactionStatus_Cancelled
+		require.Equal(t, response.ErrTransactionCancelled, cancelled.ValidateDownloadable())
+
+		completed := trx
+		completed.Status = TransactionStatus_Completed
+		require.Nil(t, completed.ValidateDownloadable())
+	})
+	t.Run("buyer cannot cancel after delivery", func(t *testing.T) {
+		require.Nil(t, trx.ValidateCancelBy("buyer"))
+
+		paid := trx
+		paid.Status = TransactionStatus_Progress
+		require.Equal(t, response.ErrTransactionDelivered, paid.ValidateCancelBy("buyer"))
+		require.Nil(t, paid.ValidateStatusChange(TransactionStatus_Cancelled))
+	})
+}
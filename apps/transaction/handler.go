@@ -2,7 +2,9 @@ package transaction
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
 
 	"Ecommerce-basic/infra/gin"
 	"Ecommerce-basic/infra/response"
@@ -84,8 +86,19 @@ func (h handler) GetTransactionByUser(c *gin.Context) {
 		return
 	}
 
+	var req ListTransactionRequestPayload
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
 	// Panggil service untuk mendapatkan riwayat transaksi
-	trxs, err := h.svc.TransactionHistories(c.Request.Context(), fmt.Sprintf("%v", userPublicId))
+	trxs, meta, err := h.svc.TransactionHistories(c.Request.Context(), fmt.Sprintf("%v", userPublicId), req)
 	if err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
 		if !ok {
@@ -101,15 +114,16 @@ func (h handler) GetTransactionByUser(c *gin.Context) {
 	}
 
 	// Map data transaksi ke response
-	var response = []TransactionHisotryResponse{}
+	var response = []ProductTransactionHistoryResponse{}
 	for _, trx := range trxs {
-		response = append(response, trx.ToTransactionHistoryResponse())
+		response = append(response, trx.ToProductTransactionHistoryResponse())
 	}
 
 	// Kirim response sukses
 	resp := infragin.NewResponse(
 		infragin.WithHttpCode(http.StatusOK), // Ubah dari Created ke OK karena ini GET request
 		infragin.WithPayload(response),
+		infragin.WithMeta(meta),
 		infragin.WithMessage("get transaction histories success"),
 	)
 	resp.Send(c)
@@ -156,12 +170,57 @@ func (h handler) UpdateTransactionStatus(c *gin.Context) {
 	resp.Send(c)
 }
 
+// CancelTransaction pembatalan oleh pemilik transaksi
+func (h handler) CancelTransaction(c *gin.Context) {
+	trxId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid transaction ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	if err := h.svc.CancelTransaction(c.Request.Context(), c.GetString("PUBLIC_ID"), trxId); err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithMessage("cancel transaction success"),
+	)
+	resp.Send(c)
+}
+
 // mendapatkan riwayat transaksi berdasarkan product_sku
 func (h handler) GetTransactionHistoriesByProduct(c *gin.Context) {
 	productSKU := c.Param("sku")
 
+	var req ListTransactionRequestPayload
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
 	// Panggil service untuk mendapatkan riwayat transaksi
-	trxs, err := h.svc.GetTransactionHistoriesByProduct(c.Request.Context(), productSKU)
+	trxs, meta, err := h.svc.GetTransactionHistoriesByProduct(c.Request.Context(), productSKU, req)
 	if err != nil {
 		myErr, ok := response.ErrorMapping[err.Error()]
 		if !ok {
@@ -177,16 +236,87 @@ func (h handler) GetTransactionHistoriesByProduct(c *gin.Context) {
 	}
 
 	// Map data transaksi ke response
-	var response = []TransactionHisotryResponse{}
+	var response = []ProductTransactionHistoryResponse{}
 	for _, trx := range trxs {
-		response = append(response, trx.ToTransactionHistoryResponse())
+		response = append(response, trx.ToProductTransactionHistoryResponse())
 	}
 
 	// Kirim response sukses
 	resp := infragin.NewResponse(
 		infragin.WithHttpCode(http.StatusOK),
 		infragin.WithPayload(response),
+		infragin.WithMeta(meta),
 		infragin.WithMessage("get transaction histories by product success"),
 	)
 	resp.Send(c)
 }
+
+// GetDelivery license key dan link download pesanan digital milik user yang login
+func (h handler) GetDelivery(c *gin.Context) {
+	trxId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithHttpCode(http.StatusBadRequest),
+			infragin.WithMessage("invalid transaction ID"),
+			infragin.WithError(response.ErrorBadRequest),
+		)
+		resp.Send(c)
+		return
+	}
+
+	delivery, err := h.svc.Delivery(c.Request.Context(), c.GetString("PUBLIC_ID"), trxId)
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+
+	resp := infragin.NewResponse(
+		infragin.WithHttpCode(http.StatusOK),
+		infragin.WithPayload(delivery.ToDeliveryResponse()),
+		infragin.WithMessage("get transaction delivery success"),
+	)
+	resp.Send(c)
+}
+
+// DownloadDigitalFile mengirim file produk digital dari link yang ditandatangani
+func (h handler) DownloadDigitalFile(c *gin.Context) {
+	trxId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		resp := infragin.NewResponse(
+			infragin.WithMessage(response.ErrDownloadLinkInvalid.Error()),
+			infragin.WithError(response.ErrorDownloadLinkInvalid),
+		)
+		resp.Send(c)
+		return
+	}
+
+	file, fileName, err := h.svc.Download(c.Request.Context(), trxId, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		myErr, ok := response.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = response.ErrorGeneral
+		}
+
+		resp := infragin.NewResponse(
+			infragin.WithMessage(err.Error()),
+			infragin.WithError(myErr),
+		)
+		resp.Send(c)
+		return
+	}
+	defer file.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", file, map[string]string{
+		"Content-Disposition": disposition,
+	})
+}
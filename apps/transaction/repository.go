@@ -1,9 +1,12 @@
 package transaction
 
 import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/pagination"
 	"Ecommerce-basic/infra/response"
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -19,18 +22,23 @@ type repository struct {
 }
 
 // GetTransactionsByUserPublicId implements Repository.
-func (r repository) GetTransactionsByUserPublicId(ctx context.Context, userPublicId string) (trxs []Transaction, err error) {
-	query := `
+func (r repository) GetTransactionsByUserPublicId(ctx context.Context, userPublicId string, page pagination.Request) (trxs []Transaction, err error) {
+	operator, direction := page.Order(true)
+	query := fmt.Sprintf(`
 		SELECT 
 			id, user_public_id, product_id, product_price
 			, amount, sub_total, platform_fee
 			, grand_total, status, product_snapshot
+			, warehouse_id, allocation_strategy, shipping_address
 			, created_at, updated_at
 		FROM transactions
 		WHERE user_public_id=$1
-	`
+			AND ($2 = 0 OR id %s $2)
+		ORDER BY id %s
+		LIMIT $3
+	`, operator, direction)
 
-	err = r.db.SelectContext(ctx, &trxs, query, userPublicId)
+	err = r.db.SelectContext(ctx, &trxs, query, userPublicId, cursorId(page), page.Limit())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = response.ErrNotFound
@@ -58,20 +66,23 @@ func (repository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
 }
 
 // CreateTransactionWithTx implements Repository.
-func (r repository) CreateTransactionWithTx(ctx context.Context, tx *sqlx.Tx, trx Transaction) (err error) {
+func (r repository) CreateTransactionWithTx(ctx context.Context, tx *sqlx.Tx, trx Transaction) (id int, err error) {
 	query := `
 		INSERT INTO transactions (
 			user_public_id, product_id, product_price
 			, amount, sub_total, platform_fee
 			, grand_total, status, product_snapshot
+			, warehouse_id, allocation_strategy, shipping_address
 			, created_at, updated_at
 		) VALUES (
 			:user_public_id, :product_id, :product_price
 			, :amount, :sub_total, :platform_fee
 			, :grand_total, :status, :product_snapshot
+			, :warehouse_id, :allocation_strategy, :shipping_address
 			, :created_at, :updated_at
 				
 		)
+		RETURNING id
 	`
 
 	stmt, err := tx.PrepareNamedContext(ctx, query)
@@ -81,7 +92,7 @@ func (r repository) CreateTransactionWithTx(ctx context.Context, tx *sqlx.Tx, tr
 
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, trx)
+	err = stmt.GetContext(ctx, &id, trx)
 
 	return
 }
@@ -93,6 +104,7 @@ func (r repository) GetTransactionById(ctx context.Context, trxId int) (trx Tran
             id, user_public_id, product_id, product_price
             , amount, sub_total, platform_fee
             , grand_total, status, product_snapshot
+            , warehouse_id, allocation_strategy, shipping_address
             , created_at, updated_at
         FROM transactions
         WHERE id=$1
@@ -108,13 +120,37 @@ func (r repository) GetTransactionById(ctx context.Context, trxId int) (trx Tran
 	return
 }
 
+// mengunci transaksi agar perubahan status tidak diproses dua kali
+func (r repository) GetTransactionByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, trxId int) (trx Transaction, err error) {
+	query := `
+        SELECT 
+            id, user_public_id, product_id, product_price
+            , amount, sub_total, platform_fee
+            , grand_total, status, product_snapshot
+            , warehouse_id, allocation_strategy, shipping_address
+            , created_at, updated_at
+        FROM transactions
+        WHERE id=$1
+        FOR UPDATE
+    `
+
+	err = tx.GetContext(ctx, &trx, query, trxId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
 // GetProductBySku implements Repository.
 func (r repository) GetProductBySku(ctx context.Context, productSKU string) (product Product, err error) {
 	query := `
 		SELECT 
-			id, sku, name, stock, price
+			id, sku, name, stock, price, status, type, file_key, file_name
 		FROM products
-		WHERE sku=$1
+		WHERE sku=$1 AND deleted_at IS NULL
 	`
 
 	err = r.db.GetContext(ctx, &product, query, productSKU)
@@ -128,11 +164,135 @@ func (r repository) GetProductBySku(ctx context.Context, productSKU string) (pro
 	return
 }
 
+// mengunci baris produk sehingga stok yang dihitung untuk ledger tidak basi. Produk yang sudah dihapus
+// tetap dikembalikan supaya pembatalan bisa mengembalikan stoknya, checkout menolaknya lewat ValidateSellable
+func (r repository) GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error) {
+	query := `
+		SELECT 
+			id, sku, name, stock, price, status, type, file_key, file_name
+			, deleted_at IS NOT NULL AS deleted
+		FROM products
+		WHERE id=$1
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &product, query, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Product{}, response.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+// mengunci semua komponen bundle berurutan berdasarkan id supaya checkout bundle yang berbarengan tidak deadlock
+func (r repository) GetBundleComponentsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, bundleId int) (components []BundleComponent, err error) {
+	query := `
+		SELECT
+			bc.component_id, bc.quantity
+			, c.sku, c.name, c.stock, c.deleted_at IS NOT NULL AS deleted
+		FROM bundle_components bc
+		JOIN products c ON c.id = bc.component_id
+		WHERE bc.bundle_id = $1
+		ORDER BY c.id
+		FOR UPDATE OF c
+	`
+
+	err = tx.SelectContext(ctx, &components, query, bundleId)
+	return
+}
+
+func (r repository) GetWarehouseStocksWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (stocks []inventory.WarehouseStock, err error) {
+	return inventory.GetWarehouseStocksWithTx(ctx, tx, productId)
+}
+
+func (r repository) GetReservationByPublicIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, publicId string) (reservation inventory.Reservation, err error) {
+	return inventory.GetReservationByPublicIdForUpdateWithTx(ctx, tx, publicId)
+}
+
+func (r repository) UpdateReservationStatusWithTx(ctx context.Context, tx *sqlx.Tx, reservation inventory.Reservation) (err error) {
+	return inventory.UpdateReservationStatusWithTx(ctx, tx, reservation)
+}
+
+func (r repository) GetReservedStockWithTx(ctx context.Context, tx *sqlx.Tx, productId int, excludeReservationId int) (reserved int, err error) {
+	return inventory.GetReservedStockWithTx(ctx, tx, productId, excludeReservationId)
+}
+
+func (r repository) RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement inventory.StockMovement) (err error) {
+	return inventory.RecordStockMovementWithTx(ctx, tx, movement)
+}
+
+func (r repository) HasLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (exists bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM license_keys WHERE product_id=$1
+		)
+	`
+
+	err = tx.GetContext(ctx, &exists, query, productId)
+	return
+}
+
+// AssignLicenseKeysWithTx memberikan key tertua yang belum dipakai, baris produk sudah dikunci
+// oleh checkout sehingga dua checkout tidak bisa mengambil key yang sama
+func (r repository) AssignLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, productId int, trxId int, amount int) (assigned int, err error) {
+	query := `
+		UPDATE license_keys
+		SET transaction_id=$2, assigned_at=NOW()
+		WHERE id IN (
+			SELECT id FROM license_keys
+			WHERE product_id=$1 AND transaction_id IS NULL AND revoked_at IS NULL
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE
+		)
+	`
+
+	result, err := tx.ExecContext(ctx, query, productId, trxId, amount)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// RevokeLicenseKeysWithTx key yang sudah diberikan ke pembeli tidak kembali ke pool ketika transaksi dibatalkan
+func (r repository) RevokeLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, trxId int) (revoked int, err error) {
+	query := `
+		UPDATE license_keys
+		SET revoked_at=NOW()
+		WHERE transaction_id=$1 AND revoked_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, trxId)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+func (r repository) GetLicenseKeysByTransactionId(ctx context.Context, trxId int) (keys []string, err error) {
+	query := `
+		SELECT license_key
+		FROM license_keys
+		WHERE transaction_id=$1 AND revoked_at IS NULL
+		ORDER BY id
+	`
+
+	err = r.db.SelectContext(ctx, &keys, query, trxId)
+	return
+}
+
 // UpdateProductStockWithTx implements Repository.
 func (r repository) UpdateProductStockWithTx(ctx context.Context, tx *sqlx.Tx, product Product) (err error) {
 	query := `
 		UPDATE products
-		SET stock=:stock
+		SET stock=:stock, updated_at=NOW(), version=version + 1
 		WHERE id=:id
 	`
 
@@ -167,21 +327,26 @@ func (r repository) UpdateTransactionStatusWithTx(ctx context.Context, tx *sqlx.
 	return
 }
 
-// Mendapatkan Riwayat Transaksi Berdasarkan Produk
-func (r repository) GetTransactionsByProductSku(ctx context.Context, productSKU string) (trxs []Transaction, err error) {
-	query := `
+// Mendapatkan Riwayat Transaksi Berdasarkan Produk, termasuk produk yang sudah dihapus dengan SKU yang sama
+func (r repository) GetTransactionsByProductSku(ctx context.Context, productSKU string, page pagination.Request) (trxs []Transaction, err error) {
+	operator, direction := page.Order(true)
+	query := fmt.Sprintf(`
         SELECT 
             id, user_public_id, product_id, product_price
             , amount, sub_total, platform_fee
             , grand_total, status, product_snapshot
+            , warehouse_id, allocation_strategy, shipping_address
             , created_at, updated_at
         FROM transactions
-        WHERE product_id = (
+        WHERE product_id IN (
             SELECT id FROM products WHERE sku = $1
         )
-    `
+            AND ($2 = 0 OR id %s $2)
+        ORDER BY id %s
+        LIMIT $3
+    `, operator, direction)
 
-	err = r.db.SelectContext(ctx, &trxs, query, productSKU)
+	err = r.db.SelectContext(ctx, &trxs, query, productSKU, cursorId(page), page.Limit())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = response.ErrNotFound
@@ -191,3 +356,11 @@ func (r repository) GetTransactionsByProductSku(ctx context.Context, productSKU
 	}
 	return
 }
+
+// riwayat transaksi diurutkan dari yang terbaru, cursor hanya membutuhkan id
+func cursorId(page pagination.Request) int {
+	if page.Cursor == nil {
+		return 0
+	}
+	return page.Cursor.Id
+}
@@ -0,0 +1,78 @@
+package transaction
+
+import (
+	"Ecommerce-basic/infra/cache"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// cachedRepository menambahkan cache GetProductBySku tanpa mengubah service.
+// Stok dan status tetap diperiksa ulang setelah baris produk dikunci, produk yang dikunci
+// di dalam transaksi database dihapus dari cache setelah commit karena stoknya berubah
+type cachedRepository struct {
+	Repository
+
+	products *cache.Loader[Product]
+	pending  *cache.PendingKeys[*sqlx.Tx]
+}
+
+// newCachedRepository tanpa cache GetProductBySku jika app.cache.driver none, tetapi SKU yang stoknya
+// berubah tetap di-Invalidate supaya cache daftar produk di modul product ikut dihapus
+func newCachedRepository(repo Repository) Repository {
+	return newCachedRepositoryWithLoader(repo, cache.FromConfig[Product]("transaction:product:sku"))
+}
+
+func newCachedRepositoryWithLoader(repo Repository, products *cache.Loader[Product]) cachedRepository {
+	if products != nil {
+		cache.Subscribe(cache.TAG_PRODUCT_SKU, products)
+	}
+
+	return cachedRepository{
+		Repository: repo,
+		products:   products,
+		pending:    cache.NewPendingKeys[*sqlx.Tx](cache.TAG_PRODUCT_SKU),
+	}
+}
+
+func (r cachedRepository) GetProductBySku(ctx context.Context, productSKU string) (product Product, err error) {
+	if r.products == nil {
+		return r.Repository.GetProductBySku(ctx, productSKU)
+	}
+	return r.products.Load(ctx, productSKU, func(ctx context.Context) (Product, error) {
+		return r.Repository.GetProductBySku(ctx, productSKU)
+	})
+}
+
+func (r cachedRepository) GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error) {
+	if product, err = r.Repository.GetProductByIdForUpdateWithTx(ctx, tx, productId); err != nil {
+		return
+	}
+	r.pending.Add(tx, product.SKU)
+	return
+}
+
+// GetBundleComponentsForUpdateWithTx stok komponen bundle diubah hanya dengan id, SKU diambil dari sini
+func (r cachedRepository) GetBundleComponentsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, bundleId int) (components []BundleComponent, err error) {
+	if components, err = r.Repository.GetBundleComponentsForUpdateWithTx(ctx, tx, bundleId); err != nil {
+		return
+	}
+	for _, component := range components {
+		r.pending.Add(tx, component.SKU)
+	}
+	return
+}
+
+func (r cachedRepository) Commit(ctx context.Context, tx *sqlx.Tx) (err error) {
+	if err = r.Repository.Commit(ctx, tx); err != nil {
+		r.pending.Discard(tx)
+		return
+	}
+	r.pending.Flush(ctx, tx)
+	return
+}
+
+func (r cachedRepository) Rollback(ctx context.Context, tx *sqlx.Tx) (err error) {
+	r.pending.Discard(tx)
+	return r.Repository.Rollback(ctx, tx)
+}
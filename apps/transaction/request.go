@@ -1,7 +1,14 @@
 package transaction
 
 type CreateTransactionRequestPayload struct {
-	ProductSKU   string `json:"product_sku"`
-	Amount       uint8  `json:"amount"`
-	UserPublicId string `json:"-"`
+	ProductSKU      string          `json:"product_sku"`
+	Amount          uint8           `json:"amount"`
+	ShippingAddress ShippingAddress `json:"shipping_address"`
+	ReservationId   string          `json:"reservation_id"`
+	UserPublicId    string          `json:"-"`
+}
+
+type ListTransactionRequestPayload struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size"`
 }
@@ -18,4 +18,52 @@ type TransactionHisotryResponse struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 
 	Product Product `json:"product"`
+
+	ShippingAddress *ShippingAddress    `json:"shipping_address,omitempty"`
+	Allocation      *AllocationResponse `json:"allocation,omitempty"`
+}
+
+// ProductTransactionHistoryResponse riwayat transaksi per produk bisa dibaca semua user yang login,
+// alamat pengiriman, gudang dan file produk digital milik pembeli tidak ikut dikirim
+type ProductTransactionHistoryResponse struct {
+	Id           int       `json:"id"`
+	UserPublicId string    `json:"user_public_id"`
+	ProductId    uint      `json:"product_id"`
+	ProductPrice uint      `json:"product_price"`
+	Amount       uint8     `json:"amount"`
+	SubTotal     uint      `json:"sub_total"`
+	PlatformFee  uint      `json:"platform_fee"`
+	GrandTotal   uint      `json:"grand_total"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Product Product `json:"product"`
+}
+
+type AllocationResponse struct {
+	WarehouseId int    `json:"warehouse_id"`
+	Strategy    string `json:"strategy"`
+}
+
+type DeliveryResponse struct {
+	TransactionId int        `json:"transaction_id"`
+	LicenseKeys   []string   `json:"license_keys"`
+	FileName      string     `json:"file_name,omitempty"`
+	DownloadURL   string     `json:"download_url,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+func (d Delivery) ToDeliveryResponse() DeliveryResponse {
+	keys := d.LicenseKeys
+	if keys == nil {
+		keys = []string{}
+	}
+	return DeliveryResponse{
+		TransactionId: d.TransactionId,
+		LicenseKeys:   keys,
+		FileName:      d.FileName,
+		DownloadURL:   d.DownloadURL,
+		ExpiresAt:     d.ExpiresAt,
+	}
 }
@@ -1,8 +1,15 @@
 package transaction
 
 import (
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/infra/blobstore"
+	"Ecommerce-basic/infra/pagination"
 	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"Ecommerce-basic/internal/log"
 	"context"
+	"io"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -11,6 +18,8 @@ type Repository interface {
 	TransactionDBRepository
 	TransactionRepository
 	ProductRepository
+	ReservationRepository
+	LicenseKeyRepository
 }
 
 type TransactionDBRepository interface {
@@ -20,24 +29,47 @@ type TransactionDBRepository interface {
 }
 
 type TransactionRepository interface {
-	CreateTransactionWithTx(ctx context.Context, tx *sqlx.Tx, trx Transaction) (err error)
-	GetTransactionsByUserPublicId(ctx context.Context, userPublicId string) (trxs []Transaction, err error)
-	GetTransactionById(ctx context.Context, trxId int) (trx Transaction, err error)                     // Method baru
-	UpdateTransactionStatusWithTx(ctx context.Context, tx *sqlx.Tx, trx Transaction) (err error)        // Method baru
-	GetTransactionsByProductSku(ctx context.Context, productSKU string) (trxs []Transaction, err error) // Method baru
+	CreateTransactionWithTx(ctx context.Context, tx *sqlx.Tx, trx Transaction) (id int, err error)
+	GetTransactionsByUserPublicId(ctx context.Context, userPublicId string, page pagination.Request) (trxs []Transaction, err error)
+	GetTransactionById(ctx context.Context, trxId int) (trx Transaction, err error)                                              // Method baru
+	UpdateTransactionStatusWithTx(ctx context.Context, tx *sqlx.Tx, trx Transaction) (err error)                                 // Method baru
+	GetTransactionsByProductSku(ctx context.Context, productSKU string, page pagination.Request) (trxs []Transaction, err error) // Method baru
+
+	GetTransactionByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, trxId int) (trx Transaction, err error)
 }
 type ProductRepository interface {
 	GetProductBySku(ctx context.Context, productSKU string) (product Product, err error)
+	GetProductByIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (product Product, err error)
+	GetWarehouseStocksWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (stocks []inventory.WarehouseStock, err error)
+	GetBundleComponentsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, bundleId int) (components []BundleComponent, err error)
 	UpdateProductStockWithTx(ctx context.Context, tx *sqlx.Tx, product Product) (err error)
+	RecordStockMovementWithTx(ctx context.Context, tx *sqlx.Tx, movement inventory.StockMovement) (err error)
+}
+
+type LicenseKeyRepository interface {
+	HasLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, productId int) (exists bool, err error)
+	AssignLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, productId int, trxId int, amount int) (assigned int, err error)
+	RevokeLicenseKeysWithTx(ctx context.Context, tx *sqlx.Tx, trxId int) (revoked int, err error)
+	GetLicenseKeysByTransactionId(ctx context.Context, trxId int) (keys []string, err error)
+}
+
+type ReservationRepository interface {
+	GetReservationByPublicIdForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, publicId string) (reservation inventory.Reservation, err error)
+	UpdateReservationStatusWithTx(ctx context.Context, tx *sqlx.Tx, reservation inventory.Reservation) (err error)
+	GetReservedStockWithTx(ctx context.Context, tx *sqlx.Tx, productId int, excludeReservationId int) (reserved int, err error)
 }
 
 type service struct {
-	repo Repository
+	repo      Repository
+	allocator inventory.AllocationStrategy
+	blobs     blobstore.BlobStore
 }
 
-func newService(repo Repository) service {
+func newService(repo Repository, allocator inventory.AllocationStrategy, blobs blobstore.BlobStore) service {
 	return service{
-		repo: repo,
+		repo:      repo,
+		allocator: allocator,
+		blobs:     blobs,
 	}
 }
 
@@ -52,6 +84,10 @@ func (s service) CreateTransaction(ctx context.Context, req CreateTransactionReq
 		return
 	}
 
+	if err = myProduct.ValidateSellable(); err != nil {
+		return
+	}
+
 	trx := NewTransactionFromCreateRequest(req)
 	trx.FromProduct(myProduct).
 		SetPlatformFee(1_000).
@@ -61,8 +97,11 @@ func (s service) CreateTransaction(ctx context.Context, req CreateTransactionReq
 		return
 	}
 
-	if err = trx.ValidateStock(uint8(myProduct.Stock)); err != nil {
-		return
+	// stok bundle diperiksa per komponen setelah baris komponen dikunci
+	if !myProduct.IsBundle() {
+		if err = trx.ValidateStock(uint8(myProduct.Stock)); err != nil {
+			return
+		}
 	}
 
 	// start transaction database
@@ -74,10 +113,85 @@ func (s service) CreateTransaction(ctx context.Context, req CreateTransactionReq
 	// defer rollback if any error or after commit
 	defer s.repo.Rollback(ctx, tx)
 
-	if err = s.repo.CreateTransactionWithTx(ctx, tx, trx); err != nil {
+	// lock product row, stock may have changed since it was read
+	if myProduct, err = s.repo.GetProductByIdForUpdateWithTx(ctx, tx, myProduct.Id); err != nil {
+		return
+	}
+
+	// status may have changed since it was read
+	if err = myProduct.ValidateSellable(); err != nil {
+		return
+	}
+
+	// a reservation holds stock for this buyer, other active holds are not for sale
+	var reservation inventory.Reservation
+	if req.ReservationId != "" {
+		if reservation, err = s.repo.GetReservationByPublicIdForUpdateWithTx(ctx, tx, req.ReservationId); err != nil {
+			return
+		}
+		if err = reservation.ValidateConvert(req.UserPublicId, myProduct.Id, int(trx.Amount), time.Now()); err != nil {
+			return
+		}
+	}
+
+	// a bundle takes its stock from every component instead of its own row
+	if myProduct.IsBundle() {
+		err = s.checkoutBundle(ctx, tx, &trx, myProduct, req.ShippingAddress)
+	} else {
+		err = s.checkoutProduct(ctx, tx, &trx, myProduct, reservation, req.ShippingAddress)
+	}
+	if err != nil {
+		return
+	}
+
+	// the hold is now a sale
+	if reservation.Id != 0 {
+		reservation.Convert(trx.Id)
+		if err = s.repo.UpdateReservationStatusWithTx(ctx, tx, reservation); err != nil {
+			return
+		}
+	}
+
+	// commit to end the transactions
+	if err = s.repo.Commit(ctx, tx); err != nil {
+		return
+	}
+	return
+
+}
+
+func (s service) checkoutProduct(ctx context.Context, tx *sqlx.Tx, trx *Transaction, myProduct Product, reservation inventory.Reservation, address ShippingAddress) (err error) {
+	reserved, err := s.repo.GetReservedStockWithTx(ctx, tx, myProduct.Id, reservation.Id)
+	if err != nil {
+		return
+	}
+	if err = trx.ValidateAvailable(myProduct.Stock, reserved); err != nil {
+		return
+	}
+
+	// choose the warehouse that fulfils the whole order
+	stocks, err := s.repo.GetWarehouseStocksWithTx(ctx, tx, myProduct.Id)
+	if err != nil {
 		return
 	}
 
+	allocated, err := s.allocator.Allocate(trx.NewAllocationRequest(address), stocks)
+	if err != nil {
+		return
+	}
+	trx.SetAllocation(allocated, s.allocator.Name())
+
+	if trx.Id, err = s.repo.CreateTransactionWithTx(ctx, tx, *trx); err != nil {
+		return
+	}
+
+	// produk digital dengan pool license key memberikan satu key per unit
+	if myProduct.IsDigital() {
+		if err = s.assignLicenseKeys(ctx, tx, *trx, myProduct); err != nil {
+			return
+		}
+	}
+
 	// update current stock
 	if err = myProduct.UpdateStockProduct(trx.Amount); err != nil {
 		return
@@ -88,34 +202,125 @@ func (s service) CreateTransaction(ctx context.Context, req CreateTransactionReq
 		return
 	}
 
-	// commit to end the transactions
-	if err = s.repo.Commit(ctx, tx); err != nil {
+	// record the sale in the stock ledger
+	return s.repo.RecordStockMovementWithTx(ctx, tx, trx.NewSaleMovement(myProduct.Stock))
+}
+
+// assignLicenseKeys produk digital tanpa pool (contoh e-book) hanya mengandalkan file download
+func (s service) assignLicenseKeys(ctx context.Context, tx *sqlx.Tx, trx Transaction, myProduct Product) (err error) {
+	hasPool, err := s.repo.HasLicenseKeysWithTx(ctx, tx, myProduct.Id)
+	if err != nil || !hasPool {
+		return
+	}
+
+	assigned, err := s.repo.AssignLicenseKeysWithTx(ctx, tx, myProduct.Id, trx.Id, int(trx.Amount))
+	if err != nil {
 		return
 	}
+	if assigned < int(trx.Amount) {
+		return response.ErrLicenseKeysExhausted
+	}
 	return
+}
+
+// checkoutBundle mengurangi stok setiap komponen dalam transaksi database yang sama,
+// isi bundle disimpan di product_snapshot
+func (s service) checkoutBundle(ctx context.Context, tx *sqlx.Tx, trx *Transaction, bundle Product, address ShippingAddress) (err error) {
+	components, err := s.repo.GetBundleComponentsForUpdateWithTx(ctx, tx, bundle.Id)
+	if err != nil {
+		return
+	}
+	if len(components) == 0 {
+		return response.ErrAmountGreaterThanAvailable
+	}
+
+	stocks := map[int][]inventory.WarehouseStock{}
+	for _, component := range components {
+		reserved, err := s.repo.GetReservedStockWithTx(ctx, tx, component.ProductId, 0)
+		if err != nil {
+			return err
+		}
+		if err = trx.ValidateComponentAvailable(component, reserved); err != nil {
+			return err
+		}
+
+		if stocks[component.ProductId], err = s.repo.GetWarehouseStocksWithTx(ctx, tx, component.ProductId); err != nil {
+			return err
+		}
+	}
+
+	// choose one warehouse that holds every component
+	allocated, err := s.allocator.Allocate(trx.NewAllocationRequest(address), NewBundleWarehouseStocks(components, stocks))
+	if err != nil {
+		return
+	}
+	trx.SetAllocation(allocated, s.allocator.Name())
+
+	bundle.Components = components
+	if err = trx.SetProductJSON(bundle); err != nil {
+		return
+	}
+
+	if trx.Id, err = s.repo.CreateTransactionWithTx(ctx, tx, *trx); err != nil {
+		return
+	}
+
+	for _, component := range components {
+		component.Stock -= trx.ComponentAmount(component)
+		if err = s.repo.UpdateProductStockWithTx(ctx, tx, Product{Id: component.ProductId, Stock: component.Stock}); err != nil {
+			return
+		}
 
+		if err = s.repo.RecordStockMovementWithTx(ctx, tx, trx.NewComponentSaleMovement(component, component.Stock)); err != nil {
+			return
+		}
+	}
+	return
 }
 
-func (s service) TransactionHistories(ctx context.Context, userPublicId string) (trxs []Transaction, err error) {
-	trxs, err = s.repo.GetTransactionsByUserPublicId(ctx, userPublicId)
+func (s service) TransactionHistories(ctx context.Context, userPublicId string, req ListTransactionRequestPayload) (trxs []Transaction, meta pagination.Meta, err error) {
+	secret := config.Cfg.App.Encryption.CursorSecret
+
+	page, err := pagination.NewRequest(req.Cursor, req.Size, "", secret)
+	if err != nil {
+		return
+	}
+
+	trxs, err = s.repo.GetTransactionsByUserPublicId(ctx, userPublicId, page)
 	if err != nil {
 		if err == response.ErrNotFound {
 			trxs = []Transaction{}
-			return trxs, nil
+			return trxs, meta, nil
 		}
 
 		return
 	}
 
+	trxs, meta = pagination.Paginate(trxs, page, Transaction.CursorKey, secret)
 	if len(trxs) == 0 {
 		trxs = []Transaction{}
-		return trxs, nil
+		return trxs, meta, nil
 	}
 	return
 }
 
 // method untuk mengupdate status transaksi:
+// UpdateTransactionStatus dipakai admin, semua perubahan status yang valid diizinkan
 func (s service) UpdateTransactionStatus(ctx context.Context, trxId int, newStatus TransactionStatus) (err error) {
+	return s.updateTransactionStatus(ctx, trxId, newStatus, func(trx Transaction) error {
+		return trx.ValidateStatusChange(newStatus)
+	})
+}
+
+// CancelTransaction pembatalan oleh pembeli, hanya untuk transaksi miliknya yang belum dikirim
+func (s service) CancelTransaction(ctx context.Context, userPublicId string, trxId int) (err error) {
+	return s.updateTransactionStatus(ctx, trxId, TransactionStatus_Cancelled, func(trx Transaction) error {
+		return trx.ValidateCancelBy(userPublicId)
+	})
+}
+
+// updateTransactionStatus validate dijalankan setelah baris transaksi dikunci
+func (s service) updateTransactionStatus(ctx context.Context, trxId int, newStatus TransactionStatus, validate func(trx Transaction) error) (err error) {
 	// Mulai transaksi database
 	tx, err := s.repo.Begin(ctx)
 	if err != nil {
@@ -125,11 +330,15 @@ func (s service) UpdateTransactionStatus(ctx context.Context, trxId int, newStat
 	defer s.repo.Rollback(ctx, tx)
 
 	// Dapatkan transaksi berdasarkan ID
-	trx, err := s.repo.GetTransactionById(ctx, trxId)
+	trx, err := s.repo.GetTransactionByIdForUpdateWithTx(ctx, tx, trxId)
 	if err != nil {
 		return
 	}
 
+	if err = validate(trx); err != nil {
+		return
+	}
+
 	// Update status transaksi
 	trx.UpdateStatus(newStatus)
 
@@ -138,6 +347,13 @@ func (s service) UpdateTransactionStatus(ctx context.Context, trxId int, newStat
 		return
 	}
 
+	// Pembatalan mengembalikan stok dan dicatat di ledger
+	if trx.IsCancelled() {
+		if err = s.restockCancelledTransaction(ctx, tx, trx); err != nil {
+			return
+		}
+	}
+
 	// Commit transaksi
 	if err = s.repo.Commit(ctx, tx); err != nil {
 		return
@@ -146,20 +362,135 @@ func (s service) UpdateTransactionStatus(ctx context.Context, trxId int, newStat
 	return
 }
 
+func (s service) restockCancelledTransaction(ctx context.Context, tx *sqlx.Tx, trx Transaction) (err error) {
+	// bundle mengembalikan stok komponen sesuai snapshot saat checkout
+	if snapshot, err := trx.GetProduct(); err == nil && len(snapshot.Components) > 0 {
+		return s.restockCancelledBundle(ctx, tx, trx, snapshot.Components)
+	}
+
+	// license key yang sudah diberikan dicabut dan tidak dijual lagi, sehingga stoknya tidak dikembalikan
+	if trx.IsDigital() {
+		revoked, err := s.repo.RevokeLicenseKeysWithTx(ctx, tx, trx.Id)
+		if err != nil || revoked > 0 {
+			return err
+		}
+	}
+
+	product, err := s.repo.GetProductByIdForUpdateWithTx(ctx, tx, int(trx.ProductId))
+	if err != nil {
+		return
+	}
+
+	product.RestockProduct(trx.Amount)
+	if err = s.repo.UpdateProductStockWithTx(ctx, tx, product); err != nil {
+		return
+	}
+
+	return s.repo.RecordStockMovementWithTx(ctx, tx, trx.NewCancellationMovement(product.Stock))
+}
+
+func (s service) restockCancelledBundle(ctx context.Context, tx *sqlx.Tx, trx Transaction, components []BundleComponent) (err error) {
+	for _, component := range components {
+		product, err := s.repo.GetProductByIdForUpdateWithTx(ctx, tx, component.ProductId)
+		if err != nil {
+			return err
+		}
+
+		product.Stock += trx.ComponentAmount(component)
+		if err = s.repo.UpdateProductStockWithTx(ctx, tx, product); err != nil {
+			return err
+		}
+
+		if err = s.repo.RecordStockMovementWithTx(ctx, tx, trx.NewComponentCancellationMovement(component, product.Stock)); err != nil {
+			return err
+		}
+	}
+	return
+}
+
 // method untuk mendapatkan riwayat transaksi
-func (s service) GetTransactionHistoriesByProduct(ctx context.Context, productSKU string) (trxs []Transaction, err error) {
-	trxs, err = s.repo.GetTransactionsByProductSku(ctx, productSKU)
+func (s service) GetTransactionHistoriesByProduct(ctx context.Context, productSKU string, req ListTransactionRequestPayload) (trxs []Transaction, meta pagination.Meta, err error) {
+	secret := config.Cfg.App.Encryption.CursorSecret
+
+	page, err := pagination.NewRequest(req.Cursor, req.Size, "", secret)
+	if err != nil {
+		return
+	}
+
+	trxs, err = s.repo.GetTransactionsByProductSku(ctx, productSKU, page)
 	if err != nil {
 		if err == response.ErrNotFound {
 			trxs = []Transaction{}
-			return trxs, nil
+			return trxs, meta, nil
 		}
 		return
 	}
 
+	trxs, meta = pagination.Paginate(trxs, page, Transaction.CursorKey, secret)
 	if len(trxs) == 0 {
 		trxs = []Transaction{}
-		return trxs, nil
+		return trxs, meta, nil
+	}
+	return
+}
+
+// Delivery license key dan link download untuk pesanan digital milik pembeli
+func (s service) Delivery(ctx context.Context, userPublicId string, trxId int) (delivery Delivery, err error) {
+	trx, err := s.repo.GetTransactionById(ctx, trxId)
+	if err != nil {
+		return
+	}
+	if err = trx.ValidateDelivery(userPublicId); err != nil {
+		return
+	}
+
+	delivery.TransactionId = trx.Id
+	if delivery.LicenseKeys, err = s.repo.GetLicenseKeysByTransactionId(ctx, trx.Id); err != nil {
+		return
+	}
+
+	product, err := trx.GetProduct()
+	if err != nil {
+		return
+	}
+	if product.FileKey != "" {
+		ttl := config.Cfg.App.Digital.DownloadTTL
+		if ttl <= 0 {
+			ttl = defaultDownloadTTL
+		}
+
+		expiresAt := time.Now().Add(ttl)
+		delivery.FileName = product.FileName
+		delivery.DownloadURL = NewDownloadURL(trx, product.FileKey, expiresAt, config.Cfg.App.Digital.DownloadSecret)
+		delivery.ExpiresAt = &expiresAt
 	}
 	return
 }
+
+// Download membuka file dari link yang ditandatangani, transaksi yang sudah dibatalkan tidak bisa diunduh lagi
+func (s service) Download(ctx context.Context, trxId int, expires string, signature string) (file io.ReadCloser, fileName string, err error) {
+	trx, err := s.repo.GetTransactionById(ctx, trxId)
+	if err != nil {
+		if err == response.ErrNotFound {
+			err = response.ErrDownloadLinkInvalid
+		}
+		return
+	}
+
+	product, err := trx.GetProduct()
+	if err != nil {
+		return
+	}
+	if err = VerifyDownload(trx.Id, product.FileKey, expires, signature, time.Now(), config.Cfg.App.Digital.DownloadSecret); err != nil {
+		return
+	}
+	if err = trx.ValidateDownloadable(); err != nil {
+		return nil, "", err
+	}
+
+	if file, err = s.blobs.Open(ctx, product.FileKey); err != nil {
+		log.Log.Errorf(ctx, "[Download, Open] with error detail %v", err.Error())
+		return
+	}
+	return file, product.FileName, nil
+}
@@ -1,9 +1,14 @@
 package transaction
 
 import (
+	"Ecommerce-basic/apps/inventory"
 	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/blobstore"
+	"Ecommerce-basic/infra/response"
 	"Ecommerce-basic/internal"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -23,7 +28,7 @@ func init() {
 		panic(err)
 	}
 	repo := newRepository(db)
-	svc = newService(repo)
+	svc = newService(repo, inventory.NearestWarehouse{}, blobstore.NewLocal(filepath.Join(os.TempDir(), "ecommerce-blobs")))
 }
 
 func TestCreateTransaction(t *testing.T) {
@@ -37,6 +42,17 @@ func TestCreateTransaction(t *testing.T) {
 		err := svc.CreateTransaction(context.Background(), req)
 		require.Nil(t, err)
 	})
+	t.Run("reservation not found", func(t *testing.T) {
+		req := CreateTransactionRequestPayload{
+			ProductSKU:    "a98dcf06-7b4b-4f33-a6d2-20738bb8081b",
+			Amount:        2,
+			ReservationId: "00000000-0000-0000-0000-000000000000",
+			UserPublicId:  "5c534133-f81f-4df4-977e-38669242eb48",
+		}
+
+		err := svc.CreateTransaction(context.Background(), req)
+		require.Equal(t, response.ErrReservationNotFound, err)
+	})
 }
 
 func TestUpdateTransactionStatus(t *testing.T) {
@@ -53,8 +69,15 @@ func TestGetTransactionHistoriesByProduct(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		productSKU := "a98dcf06-7b4b-4f33-a6d2-20738bb8081b"
 
-		trxs, err := svc.GetTransactionHistoriesByProduct(context.Background(), productSKU)
+		trxs, meta, err := svc.GetTransactionHistoriesByProduct(context.Background(), productSKU, ListTransactionRequestPayload{Size: 1})
 		require.Nil(t, err)
 		require.NotEmpty(t, trxs)
+		require.Len(t, trxs, 1)
+
+		if meta.HasMore {
+			next, _, err := svc.GetTransactionHistoriesByProduct(context.Background(), productSKU, ListTransactionRequestPayload{Size: 1, Cursor: *meta.NextCursor})
+			require.Nil(t, err)
+			require.Less(t, next[0].Id, trxs[0].Id)
+		}
 	})
 }
@@ -0,0 +1,30 @@
+package wishlist
+
+import (
+	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/infra/gin"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+func Init(router *gin.Engine, db *sqlx.DB) {
+	checkout, err := transaction.NewCheckout(db)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc := newService(repo, checkout)
+	handler := newHandler(svc)
+
+	wishlistRoute := router.Group("/wishlist")
+	{
+		wishlistRoute.Use(infragin.CheckAuth())
+
+		wishlistRoute.GET("", handler.GetWishlist)
+		wishlistRoute.POST("", handler.AddWishlistItem)
+		wishlistRoute.DELETE("/:sku", handler.RemoveWishlistItem)
+		wishlistRoute.POST("/:sku/checkout", handler.CheckoutWishlistItem)
+	}
+}
@@ -0,0 +1,72 @@
+package wishlist
+
+import (
+	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/infra/response"
+	"strings"
+	"time"
+)
+
+// WishlistItem menyimpan SKU produk, data produk diambil ulang setiap kali wishlist dibaca
+type WishlistItem struct {
+	Id           int       `db:"id"`
+	UserPublicId string    `db:"user_public_id"`
+	ProductSKU   string    `db:"product_sku"`
+	CreatedAt    time.Time `db:"created_at"`
+
+	// diisi dari tabel products saat dibaca
+	Name  string `db:"name"`
+	Price int    `db:"price"`
+	Stock int16  `db:"stock"`
+
+	// true jika produk sudah dihapus, item tetap ditampilkan supaya user tahu
+	ProductDeleted bool `db:"product_deleted"`
+}
+
+func NewWishlistItemFromRequest(req AddWishlistItemRequestPayload) WishlistItem {
+	return WishlistItem{
+		UserPublicId: req.UserPublicId,
+		ProductSKU:   strings.TrimSpace(req.ProductSKU),
+		CreatedAt:    time.Now(),
+	}
+}
+
+func (w WishlistItem) Validate() (err error) {
+	if w.ProductSKU == "" {
+		return response.ErrProductRequired
+	}
+	return
+}
+
+func (w WishlistItem) InStock() bool {
+	return !w.ProductDeleted && w.Stock > 0
+}
+
+// ValidateCheckout produk yang sudah dihapus tidak bisa di-checkout
+func (w WishlistItem) ValidateCheckout() (err error) {
+	if w.ProductDeleted {
+		return response.ErrNotFound
+	}
+	return
+}
+
+// NewTransactionRequest menyusun request checkout dari item wishlist, jumlah default 1
+func (w WishlistItem) NewTransactionRequest(req CheckoutWishlistItemRequestPayload) transaction.CreateTransactionRequestPayload {
+	amount := req.Amount
+	if amount == 0 {
+		amount = 1
+	}
+
+	return transaction.CreateTransactionRequestPayload{
+		ProductSKU:      w.ProductSKU,
+		Amount:          amount,
+		ShippingAddress: req.ShippingAddress,
+		ReservationId:   req.ReservationId,
+		UserPublicId:    w.UserPublicId,
+	}
+}
+
+type Product struct {
+	Id  int    `db:"id"`
+	SKU string `db:"sku"`
+}
@@ -0,0 +1,42 @@
+package wishlist
+
+import (
+	"Ecommerce-basic/infra/response"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWishlistItem(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		item := NewWishlistItemFromRequest(AddWishlistItemRequestPayload{ProductSKU: " sku-1 ", UserPublicId: "user-1"})
+
+		require.Nil(t, item.Validate())
+		require.Equal(t, "sku-1", item.ProductSKU)
+	})
+	t.Run("sku required", func(t *testing.T) {
+		item := NewWishlistItemFromRequest(AddWishlistItemRequestPayload{UserPublicId: "user-1"})
+
+		require.Equal(t, response.ErrProductRequired, item.Validate())
+	})
+}
+
+func TestWishlistItemCheckout(t *testing.T) {
+	t.Run("default amount", func(t *testing.T) {
+		item := WishlistItem{UserPublicId: "user-1", ProductSKU: "sku-1", Stock: 5}
+
+		require.Nil(t, item.ValidateCheckout())
+		require.True(t, item.InStock())
+
+		req := item.NewTransactionRequest(CheckoutWishlistItemRequestPayload{})
+		require.Equal(t, uint8(1), req.Amount)
+		require.Equal(t, "sku-1", req.ProductSKU)
+		require.Equal(t, "user-1", req.UserPublicId)
+	})
+	t.Run("product deleted", func(t *testing.T) {
+		item := WishlistItem{ProductSKU: "sku-1", Stock: 5, ProductDeleted: true}
+
+		require.Equal(t, response.ErrNotFound, item.ValidateCheckout())
+		require.False(t, item.InStock())
+	})
+}
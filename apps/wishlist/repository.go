@@ -0,0 +1,119 @@
+package wishlist
+
+import (
+	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/infra/response"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func newRepository(db *sqlx.DB) repository {
+	return repository{
+		db: db,
+	}
+}
+
+func (r repository) GetProductBySku(ctx context.Context, sku string) (model Product, err error) {
+	query := `
+		SELECT
+			id, sku
+		FROM products
+		WHERE sku=$1 AND deleted_at IS NULL AND status=$2
+	`
+
+	// hanya produk PUBLISHED yang bisa ditambahkan ke wishlist
+	err = r.db.GetContext(ctx, &model, query, sku, product.ProductStatus_Published)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrNotFound
+		}
+		return
+	}
+	return
+}
+
+// CreateWishlistItem bersifat idempotent, produk yang sudah ada di wishlist tidak ditambahkan dua kali
+func (r repository) CreateWishlistItem(ctx context.Context, item WishlistItem) (err error) {
+	query := `
+		INSERT INTO wishlists (
+			user_public_id, product_sku, created_at
+		) VALUES (
+			:user_public_id, :product_sku, :created_at
+		)
+		ON CONFLICT (user_public_id, product_sku) DO NOTHING
+	`
+
+	_, err = r.db.NamedExecContext(ctx, query, item)
+	return
+}
+
+// produk yang sudah dihapus tetap ikut, ditandai dengan product_deleted. SKU produk yang dihapus
+// bisa dipakai lagi oleh produk baru, produk yang belum dihapus didahulukan
+const selectWishlistItemSQL = `
+	SELECT
+		w.id, w.user_public_id, w.product_sku, w.created_at
+		, COALESCE(p.name, '') AS name
+		, COALESCE(p.price, 0) AS price
+		, COALESCE(p.stock, 0) AS stock
+		, (p.id IS NULL OR p.deleted_at IS NOT NULL) AS product_deleted
+	FROM wishlists w
+	LEFT JOIN LATERAL (
+		SELECT id, name, price, stock, deleted_at
+		FROM products
+		WHERE sku = w.product_sku
+		ORDER BY deleted_at DESC NULLS FIRST
+		LIMIT 1
+	) p ON true
+`
+
+func (r repository) GetWishlistItem(ctx context.Context, userPublicId string, sku string) (item WishlistItem, err error) {
+	query := selectWishlistItemSQL + `
+		WHERE w.user_public_id=$1 AND w.product_sku=$2
+	`
+
+	err = r.db.GetContext(ctx, &item, query, userPublicId, sku)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = response.ErrWishlistItemNotFound
+		}
+		return
+	}
+	return
+}
+
+func (r repository) GetWishlistItemsByUserPublicId(ctx context.Context, userPublicId string) (items []WishlistItem, err error) {
+	query := selectWishlistItemSQL + `
+		WHERE w.user_public_id=$1
+		ORDER BY w.id DESC
+	`
+
+	err = r.db.SelectContext(ctx, &items, query, userPublicId)
+	return
+}
+
+func (r repository) DeleteWishlistItem(ctx context.Context, userPublicId string, sku string) (err error) {
+	query := `
+		DELETE FROM wishlists
+		WHERE user_public_id=$1 AND product_sku=$2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userPublicId, sku)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		return response.ErrWishlistItemNotFound
+	}
+	return
+}
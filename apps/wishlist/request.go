@@ -0,0 +1,14 @@
+package wishlist
+
+import "Ecommerce-basic/apps/transaction"
+
+type AddWishlistItemRequestPayload struct {
+	ProductSKU   string `json:"product_sku"`
+	UserPublicId string `json:"-"`
+}
+
+type CheckoutWishlistItemRequestPayload struct {
+	Amount          uint8                       `json:"amount"`
+	ShippingAddress transaction.ShippingAddress `json:"shipping_address"`
+	ReservationId   string                      `json:"reservation_id"`
+}
@@ -0,0 +1,33 @@
+package wishlist
+
+import "time"
+
+type WishlistItemResponse struct {
+	ProductSKU     string    `json:"product_sku"`
+	Name           string    `json:"name"`
+	Price          int       `json:"price"`
+	Stock          int16     `json:"stock"`
+	InStock        bool      `json:"in_stock"`
+	ProductDeleted bool      `json:"product_deleted"`
+	AddedAt        time.Time `json:"added_at"`
+}
+
+func (w WishlistItem) ToWishlistItemResponse() WishlistItemResponse {
+	return WishlistItemResponse{
+		ProductSKU:     w.ProductSKU,
+		Name:           w.Name,
+		Price:          w.Price,
+		Stock:          w.Stock,
+		InStock:        w.InStock(),
+		ProductDeleted: w.ProductDeleted,
+		AddedAt:        w.CreatedAt,
+	}
+}
+
+func NewWishlistListResponse(items []WishlistItem) []WishlistItemResponse {
+	resp := []WishlistItemResponse{}
+	for _, item := range items {
+		resp = append(resp, item.ToWishlistItemResponse())
+	}
+	return resp
+}
@@ -0,0 +1,90 @@
+package wishlist
+
+import (
+	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/internal/log"
+	"context"
+)
+
+type Repository interface {
+	WishlistRepository
+	ProductRepository
+}
+
+type WishlistRepository interface {
+	CreateWishlistItem(ctx context.Context, item WishlistItem) (err error)
+	GetWishlistItem(ctx context.Context, userPublicId string, sku string) (item WishlistItem, err error)
+	GetWishlistItemsByUserPublicId(ctx context.Context, userPublicId string) (items []WishlistItem, err error)
+	DeleteWishlistItem(ctx context.Context, userPublicId string, sku string) (err error)
+}
+
+type ProductRepository interface {
+	GetProductBySku(ctx context.Context, sku string) (product Product, err error)
+}
+
+type service struct {
+	repo     Repository
+	checkout transaction.Checkout
+}
+
+func newService(repo Repository, checkout transaction.Checkout) service {
+	return service{
+		repo:     repo,
+		checkout: checkout,
+	}
+}
+
+func (s service) AddItem(ctx context.Context, req AddWishlistItemRequestPayload) (item WishlistItem, err error) {
+	item = NewWishlistItemFromRequest(req)
+	if err = item.Validate(); err != nil {
+		return
+	}
+
+	// hanya produk yang masih dijual yang bisa ditambahkan
+	if _, err = s.repo.GetProductBySku(ctx, item.ProductSKU); err != nil {
+		return
+	}
+
+	if err = s.repo.CreateWishlistItem(ctx, item); err != nil {
+		return
+	}
+
+	return s.repo.GetWishlistItem(ctx, item.UserPublicId, item.ProductSKU)
+}
+
+func (s service) Items(ctx context.Context, userPublicId string) (items []WishlistItem, err error) {
+	items, err = s.repo.GetWishlistItemsByUserPublicId(ctx, userPublicId)
+	if err != nil {
+		return
+	}
+	if len(items) == 0 {
+		items = []WishlistItem{}
+	}
+	return
+}
+
+func (s service) RemoveItem(ctx context.Context, userPublicId string, sku string) (err error) {
+	return s.repo.DeleteWishlistItem(ctx, userPublicId, sku)
+}
+
+// MoveToCheckout membuat transaksi dari item wishlist lalu menghapus item tersebut dari wishlist
+func (s service) MoveToCheckout(ctx context.Context, userPublicId string, sku string, req CheckoutWishlistItemRequestPayload) (err error) {
+	item, err := s.repo.GetWishlistItem(ctx, userPublicId, sku)
+	if err != nil {
+		return
+	}
+
+	if err = item.ValidateCheckout(); err != nil {
+		return
+	}
+
+	if err = s.checkout.CreateTransaction(ctx, item.NewTransactionRequest(req)); err != nil {
+		return
+	}
+
+	// transaksi sudah tercatat, gagal menghapus item tidak membatalkan checkout
+	if err := s.repo.DeleteWishlistItem(ctx, userPublicId, sku); err != nil {
+		log.Log.Errorf(ctx, "[MoveToCheckout, DeleteWishlistItem] with error detail %v", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package wishlist
+
+import (
+	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/infra/response"
+	"Ecommerce-basic/internal"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var svc service
+
+func init() {
+	filename := "../../cmd/api/config.yaml"
+	err := config.LoadConfig(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		panic(err)
+	}
+
+	checkout, err := transaction.NewCheckout(db)
+	if err != nil {
+		panic(err)
+	}
+
+	repo := newRepository(db)
+	svc = newService(repo, checkout)
+}
+
+func TestWishlist(t *testing.T) {
+	userPublicId := uuid.NewString()
+	productSKU := "a98dcf06-7b4b-4f33-a6d2-20738bb8081b"
+
+	t.Run("add item twice", func(t *testing.T) {
+		req := AddWishlistItemRequestPayload{ProductSKU: productSKU, UserPublicId: userPublicId}
+
+		_, err := svc.AddItem(context.Background(), req)
+		require.Nil(t, err)
+
+		item, err := svc.AddItem(context.Background(), req)
+		require.Nil(t, err)
+		require.False(t, item.ProductDeleted)
+
+		items, err := svc.Items(context.Background(), userPublicId)
+		require.Nil(t, err)
+		require.Len(t, items, 1)
+	})
+	t.Run("product not found", func(t *testing.T) {
+		_, err := svc.AddItem(context.Background(), AddWishlistItemRequestPayload{ProductSKU: uuid.NewString(), UserPublicId: userPublicId})
+		require.Equal(t, response.ErrNotFound, err)
+	})
+	t.Run("remove item", func(t *testing.T) {
+		require.Nil(t, svc.RemoveItem(context.Background(), userPublicId, productSKU))
+		require.Equal(t, response.ErrWishlistItemNotFound, svc.RemoveItem(context.Background(), userPublicId, productSKU))
+	})
+	t.Run("checkout item not in wishlist", func(t *testing.T) {
+		err := svc.MoveToCheckout(context.Background(), userPublicId, productSKU, CheckoutWishlistItemRequestPayload{})
+		require.Equal(t, response.ErrWishlistItemNotFound, err)
+	})
+}
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"Ecommerce-basic/apps/attribute"
 	"Ecommerce-basic/apps/auth"
+	"Ecommerce-basic/apps/export"
+	"Ecommerce-basic/apps/inventory"
+	"Ecommerce-basic/apps/pricing"
 	"Ecommerce-basic/apps/product"
+	"Ecommerce-basic/apps/productimport"
+	"Ecommerce-basic/apps/recommendation"
+	"Ecommerce-basic/apps/review"
 	"Ecommerce-basic/apps/transaction"
+	"Ecommerce-basic/apps/wishlist"
 	"Ecommerce-basic/external/database"
 	"Ecommerce-basic/infra/gin"
 	"Ecommerce-basic/internal"
+	"context"
 	"log"
 	"runtime"
 
@@ -32,13 +41,33 @@ func main() {
 	// Buat instance Gin
 	router := gin.Default()
 
+	// IP klien dipakai untuk membatasi login gagal, X-Forwarded-For hanya dipercaya dari proxy yang terdaftar
+	if err := router.SetTrustedProxies(config.Cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
+
 	// Middleware tracing (menggantikan infrafiber.Trace())
 	router.Use(infragin.Trace())
 
 	// Inisialisasi modul aplikasi
 	auth.Init(router, db)
 	product.Init(router, db)
+	attribute.Init(router, db)
+	productimport.Init(router, db)
 	transaction.Init(router, db)
+	export.Init(router, db)
+	inventory.Init(router, db)
+	pricing.Init(router, db)
+	review.Init(router, db)
+	wishlist.Init(router, db)
+	recommendation.Init(router, db)
+
+	// Background job
+	inventory.StartReservationSweeper(context.Background(), db)
+	inventory.StartStockAlertDispatcher(context.Background(), db, inventory.LogAlertEmitter{})
+	pricing.StartPriceScheduler(context.Background(), db)
+	product.StartLifecycleScheduler(context.Background(), db)
+	recommendation.StartRelationRefresher(context.Background(), db)
 
 	// Jalankan server
 	port := config.Cfg.App.Port
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"Ecommerce-basic/apps/export"
+	"Ecommerce-basic/external/database"
+	"Ecommerce-basic/internal"
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// Contoh penggunaan untuk export terjadwal (cron):
+//
+//	go run cmd/export/main.go -resource transactions -format xlsx -from 2024-01-01 -to 2024-01-31 -status COMPLETED -out trx-januari.xlsx
+func main() {
+	configFile := flag.String("config", "cmd/api/config.yaml", "lokasi file konfigurasi")
+	resource := flag.String("resource", export.RESOURCE_PRODUCTS, "data yang diexport: products atau transactions")
+	format := flag.String("format", export.FORMAT_CSV, "format file: csv, jsonl atau xlsx")
+	from := flag.String("from", "", "tanggal awal (YYYY-MM-DD atau RFC3339)")
+	to := flag.String("to", "", "tanggal akhir (YYYY-MM-DD atau RFC3339)")
+	status := flag.String("status", "", "status transaksi, contoh: COMPLETED")
+	out := flag.String("out", "", "file tujuan, default <resource>-<waktu>.<format>")
+	flag.Parse()
+
+	if *resource != export.RESOURCE_PRODUCTS && *resource != export.RESOURCE_TRANSACTIONS {
+		log.Fatalf("Unknown resource %q", *resource)
+	}
+
+	// Load konfigurasi aplikasi
+	if err := config.LoadConfig(*configFile); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Koneksi ke database
+	db, err := database.ConnectPostgres(config.Cfg.DB)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// stdout tidak dipakai karena LoadConfig juga mencetak ke stdout
+	if *out == "" {
+		*out = fmt.Sprintf("%s-%s.%s", *resource, time.Now().Format("20060102-150405"), *format)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	req := export.ExportRequestPayload{
+		Format: *format,
+		From:   *from,
+		To:     *to,
+		Status: *status,
+	}
+
+	if err := export.Export(context.Background(), db, *resource, req, writer); err != nil {
+		log.Fatalf("Failed to export %s: %v", *resource, err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		log.Fatalf("Failed to write output: %v", err)
+	}
+	log.Printf("Export %s finished: %s", *resource, *out)
+}
@@ -41,6 +41,390 @@ CREATE TABLE transactions
     updated_at       TIMESTAMP   DEFAULT NOW()
 );
 
+-- PRODUCT IMPORT JOBS
+CREATE TABLE product_import_jobs
+(
+    id             SERIAL PRIMARY KEY,
+    format         VARCHAR(10)  NOT NULL,
+    mode           VARCHAR(10)  NOT NULL,
+    dry_run        BOOLEAN      NOT NULL DEFAULT FALSE,
+    status         INT          NOT NULL,
+    total_rows     INT          NOT NULL DEFAULT 0,
+    processed_rows INT          NOT NULL DEFAULT 0,
+    created_rows   INT          NOT NULL DEFAULT 0,
+    updated_rows   INT          NOT NULL DEFAULT 0,
+    failed_rows    INT          NOT NULL DEFAULT 0,
+    message        TEXT         NOT NULL DEFAULT '',
+    created_by     VARCHAR(100) NOT NULL,
+    created_at     TIMESTAMP    DEFAULT NOW(),
+    updated_at     TIMESTAMP    DEFAULT NOW(),
+    finished_at    TIMESTAMP
+);
+
+-- PRODUCT IMPORT ERRORS (laporan error per baris)
+CREATE TABLE product_import_errors
+(
+    id      SERIAL PRIMARY KEY,
+    job_id  INT  NOT NULL REFERENCES product_import_jobs (id),
+    line    INT  NOT NULL,
+    sku     TEXT NOT NULL DEFAULT '',
+    name    TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL
+);
+CREATE INDEX idx_product_import_errors_job_id ON product_import_errors (job_id);
+
+-- WAREHOUSES (priority terkecil = gudang utama)
+CREATE TABLE warehouses
+(
+    id         SERIAL PRIMARY KEY,
+    code       VARCHAR(10)      NOT NULL UNIQUE,
+    name       VARCHAR(100)     NOT NULL,
+    city       VARCHAR(100)     NOT NULL,
+    latitude   DOUBLE PRECISION NOT NULL,
+    longitude  DOUBLE PRECISION NOT NULL,
+    priority   INT              NOT NULL DEFAULT 0,
+    is_active  BOOLEAN          NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP        DEFAULT NOW()
+);
+
+INSERT INTO warehouses (code, name, city, latitude, longitude, priority)
+VALUES ('JKT', 'Gudang Jakarta', 'Jakarta', -6.2088, 106.8456, 1),
+       ('SBY', 'Gudang Surabaya', 'Surabaya', -7.2575, 112.7521, 2);
+
+-- WAREHOUSE STOCKS (total per produk selalu sama dengan products.stock)
+CREATE TABLE warehouse_stocks
+(
+    warehouse_id INT       NOT NULL REFERENCES warehouses (id),
+    product_id   INT       NOT NULL REFERENCES products (id),
+    stock        INT       NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (warehouse_id, product_id)
+);
+
+-- stok yang sudah ada dipindahkan ke gudang utama
+INSERT INTO warehouse_stocks (warehouse_id, product_id, stock)
+SELECT (SELECT id FROM warehouses WHERE code = 'JKT'), id, stock
+FROM products
+WHERE stock <> 0;
+
+-- alokasi gudang dan alamat pengiriman pada transaksi
+ALTER TABLE transactions
+    ADD COLUMN warehouse_id        INT REFERENCES warehouses (id),
+    ADD COLUMN allocation_strategy VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN shipping_address    JSONB;
+
+-- STOCK MOVEMENTS (ledger stok append-only, quantity bertanda)
+CREATE TABLE stock_movements
+(
+    id              SERIAL PRIMARY KEY,
+    product_id      INT          NOT NULL REFERENCES products (id),
+    warehouse_id    INT          NOT NULL REFERENCES warehouses (id),
+    type            INT          NOT NULL,
+    quantity        INT          NOT NULL,
+    stock_after     INT          NOT NULL,
+    reason_code     VARCHAR(30)  NOT NULL,
+    note            TEXT         NOT NULL DEFAULT '',
+    reference_type  VARCHAR(30),
+    reference_id    INT,
+    actor_public_id VARCHAR(100) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP    DEFAULT NOW()
+);
+CREATE INDEX idx_stock_movements_product_id ON stock_movements (product_id, id);
+
+-- ledger hanya boleh ditambah, tidak boleh diubah atau dihapus
+-- kecuali dihapus bersama produk yang sudah di-soft delete (purge)
+CREATE RULE stock_movements_no_update AS ON UPDATE TO stock_movements DO INSTEAD NOTHING;
+CREATE RULE stock_movements_no_delete AS ON DELETE TO stock_movements
+    WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = OLD.product_id AND p.deleted_at IS NOT NULL)
+    DO INSTEAD NOTHING;
+
+-- saldo awal untuk produk yang sudah ada sebelum ledger dibuat (type 4 = ADJUSTMENT)
+INSERT INTO stock_movements (product_id, warehouse_id, type, quantity, stock_after, reason_code)
+SELECT id, (SELECT id FROM warehouses WHERE code = 'JKT'), 4, stock, stock, 'OPENING_BALANCE'
+FROM products
+WHERE stock <> 0;
+
+-- STOCK RESERVATIONS (hold stok sementara selama checkout)
+CREATE TABLE stock_reservations
+(
+    id             SERIAL PRIMARY KEY,
+    public_id      UUID         NOT NULL UNIQUE,
+    product_id     INT          NOT NULL REFERENCES products (id),
+    user_public_id VARCHAR(100) NOT NULL,
+    quantity       INT          NOT NULL CHECK (quantity > 0),
+    status         INT          NOT NULL,
+    expires_at     TIMESTAMP    NOT NULL,
+    transaction_id INT REFERENCES transactions (id),
+    created_at     TIMESTAMP    DEFAULT NOW(),
+    updated_at     TIMESTAMP    DEFAULT NOW()
+);
+CREATE INDEX idx_stock_reservations_active ON stock_reservations (product_id, status, expires_at);
+
+-- LOW STOCK ALERTS
+-- alert dikirim ketika stok turun sampai reorder_threshold (default 0 = saat stok habis)
+ALTER TABLE products
+    ADD COLUMN reorder_threshold INT NOT NULL DEFAULT 0 CHECK (reorder_threshold >= 0);
+
+-- outbox alert, ditulis di transaksi yang sama dengan perubahan stok lalu dikirim oleh dispatcher
+CREATE TABLE stock_alerts
+(
+    id            SERIAL PRIMARY KEY,
+    product_id    INT       NOT NULL REFERENCES products (id),
+    type          INT       NOT NULL,
+    stock_before  INT       NOT NULL,
+    stock_after   INT       NOT NULL,
+    threshold     INT       NOT NULL,
+    created_at    TIMESTAMP DEFAULT NOW(),
+    dispatched_at TIMESTAMP
+);
+CREATE INDEX idx_stock_alerts_pending ON stock_alerts (id) WHERE dispatched_at IS NULL;
+
+-- pembeli yang minta diberi tahu ketika produk tersedia lagi
+CREATE TABLE stock_subscriptions
+(
+    id             SERIAL PRIMARY KEY,
+    public_id      UUID         NOT NULL UNIQUE,
+    product_id     INT          NOT NULL REFERENCES products (id),
+    user_public_id VARCHAR(100) NOT NULL,
+    created_at     TIMESTAMP    DEFAULT NOW(),
+    notified_at    TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_stock_subscriptions_pending ON stock_subscriptions (product_id, user_public_id) WHERE notified_at IS NULL;
+
+-- PRICE SCHEDULES (perubahan harga terjadwal dan sale)
+CREATE TABLE price_schedules
+(
+    id              SERIAL PRIMARY KEY,
+    product_id      INT          NOT NULL REFERENCES products (id),
+    type            INT          NOT NULL,
+    price           INT          NOT NULL CHECK (price > 0),
+    starts_at       TIMESTAMP    NOT NULL,
+    ends_at         TIMESTAMP,
+    original_price  INT,
+    status          INT          NOT NULL,
+    actor_public_id VARCHAR(100) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP    DEFAULT NOW(),
+    updated_at      TIMESTAMP    DEFAULT NOW()
+);
+CREATE INDEX idx_price_schedules_product_id ON price_schedules (product_id, status);
+CREATE INDEX idx_price_schedules_due ON price_schedules (status, starts_at);
+
+-- PRICE CHANGES (riwayat harga append-only)
+CREATE TABLE price_changes
+(
+    id              SERIAL PRIMARY KEY,
+    product_id      INT          NOT NULL REFERENCES products (id),
+    old_price       INT          NOT NULL,
+    new_price       INT          NOT NULL,
+    reason          VARCHAR(30)  NOT NULL,
+    schedule_id     INT REFERENCES price_schedules (id),
+    actor_public_id VARCHAR(100) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP    DEFAULT NOW()
+);
+CREATE INDEX idx_price_changes_product_id ON price_changes (product_id, id);
+
+CREATE RULE price_changes_no_update AS ON UPDATE TO price_changes DO INSTEAD NOTHING;
+CREATE RULE price_changes_no_delete AS ON DELETE TO price_changes
+    WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = OLD.product_id AND p.deleted_at IS NOT NULL)
+    DO INSTEAD NOTHING;
+
+-- harga produk yang sudah ada menjadi baris pertama riwayat harga
+INSERT INTO price_changes (product_id, old_price, new_price, reason)
+SELECT id, 0, price, 'INITIAL_PRICE'
+FROM products;
+
+-- REVIEWS (satu review per pembeli per produk)
+ALTER TABLE products
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_sum   INT NOT NULL DEFAULT 0;
+
+CREATE TABLE reviews
+(
+    id             SERIAL PRIMARY KEY,
+    product_id     INT          NOT NULL REFERENCES products (id),
+    user_public_id VARCHAR(100) NOT NULL,
+    rating         INT          NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text           TEXT         NOT NULL DEFAULT '',
+    status         INT          NOT NULL,
+    moderated_by   VARCHAR(100) NOT NULL DEFAULT '',
+    created_at     TIMESTAMP    DEFAULT NOW(),
+    updated_at     TIMESTAMP    DEFAULT NOW(),
+    UNIQUE (product_id, user_public_id)
+);
+CREATE INDEX idx_reviews_product_id ON reviews (product_id, status, id);
+
+-- WISHLISTS (SKU produk per user, produk yang dihapus tetap disimpan)
+CREATE TABLE wishlists
+(
+    id             SERIAL PRIMARY KEY,
+    user_public_id VARCHAR(100) NOT NULL,
+    product_sku    VARCHAR(100) NOT NULL,
+    created_at     TIMESTAMP    DEFAULT NOW(),
+    UNIQUE (user_public_id, product_sku)
+);
+
+-- PRODUCT VERSION (optimistic concurrency untuk PUT / PATCH produk)
+ALTER TABLE products
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- PRODUCT LIFECYCLE (produk yang sudah ada tetap PUBLISHED, produk baru dari API dibuat DRAFT)
+ALTER TABLE products
+    ADD COLUMN status       INT NOT NULL DEFAULT 10,
+    ADD COLUMN publish_at   TIMESTAMP,
+    ADD COLUMN unpublish_at TIMESTAMP;
+CREATE INDEX idx_products_status ON products (status, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_publish_at ON products (publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX idx_products_unpublish_at ON products (unpublish_at) WHERE unpublish_at IS NOT NULL;
+
+-- PRODUCT SKU & SLUG
+-- slug produk lama dibuat dari nama, nama yang sama diberi akhiran -2, -3, ...
+ALTER TABLE products
+    ADD COLUMN slug VARCHAR(120);
+UPDATE products p
+SET slug = s.slug
+FROM (
+    SELECT id, base || CASE WHEN rn > 1 THEN '-' || rn ELSE '' END AS slug
+    FROM (
+        SELECT id, base, ROW_NUMBER() OVER (PARTITION BY base ORDER BY id) AS rn
+        FROM (
+            SELECT id,
+                   COALESCE(NULLIF(TRIM(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), ''), 'product') AS base
+            FROM products
+        ) b
+    ) r
+) s
+WHERE p.id = s.id;
+ALTER TABLE products
+    ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_products_slug ON products (slug);
+CREATE UNIQUE INDEX idx_products_sku ON products (sku) WHERE deleted_at IS NULL;
+
+-- nomor urut untuk app.product.sku_template
+CREATE SEQUENCE product_sku_seq;
+
+-- PRODUCT BUNDLES
+-- type 1 = STANDARD, 2 = BUNDLE. stok bundle tidak dipakai, dihitung dari komponennya
+ALTER TABLE products
+    ADD COLUMN type INT NOT NULL DEFAULT 1;
+
+CREATE TABLE bundle_components
+(
+    bundle_id    INT NOT NULL REFERENCES products (id),
+    component_id INT NOT NULL REFERENCES products (id),
+    quantity     INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (bundle_id, component_id),
+    CHECK (bundle_id <> component_id)
+);
+CREATE INDEX idx_bundle_components_component_id ON bundle_components (component_id);
+
+-- PRODUCT ATTRIBUTES
+-- type 1 = STRING, 2 = NUMBER, 3 = ENUM, 4 = BOOLEAN. options hanya untuk ENUM, unit hanya untuk NUMBER
+CREATE TABLE product_attributes
+(
+    id         SERIAL PRIMARY KEY,
+    code       VARCHAR(50)  NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    type       INT          NOT NULL,
+    unit       VARCHAR(20)  NOT NULL DEFAULT '',
+    options    TEXT[]       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP    NOT NULL,
+    updated_at TIMESTAMP    NOT NULL
+);
+
+-- nilai atribut per produk berdasarkan code, contoh: {"weight": 250, "material": "cotton"}
+ALTER TABLE products
+    ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX idx_products_attributes ON products USING GIN (attributes);
+
+-- DIGITAL PRODUCTS
+-- type 3 = DIGITAL. file disimpan di app.digital.blob_root, file_name adalah nama file untuk pembeli
+ALTER TABLE products
+    ADD COLUMN file_key  VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN file_name VARCHAR(255) NOT NULL DEFAULT '';
+
+-- satu key untuk satu unit, key yang dicabut karena pesanan dibatalkan tidak dijual lagi
+CREATE TABLE license_keys
+(
+    id             SERIAL PRIMARY KEY,
+    product_id     INT          NOT NULL REFERENCES products (id),
+    license_key    VARCHAR(255) NOT NULL,
+    transaction_id INT          NULL REFERENCES transactions (id),
+    assigned_at    TIMESTAMP    NULL,
+    revoked_at     TIMESTAMP    NULL,
+    created_at     TIMESTAMP    NOT NULL,
+    UNIQUE (product_id, license_key)
+);
+CREATE INDEX idx_license_keys_available ON license_keys (product_id) WHERE transaction_id IS NULL;
+CREATE INDEX idx_license_keys_transaction_id ON license_keys (transaction_id);
+
+-- PRODUCT RECOMMENDATIONS
+-- top-N produk yang dibeli oleh pembeli yang sama, diisi ulang oleh batch app.recommendation.refresh_interval
+CREATE TABLE product_relations
+(
+    product_id         INT       NOT NULL REFERENCES products (id),
+    related_product_id INT       NOT NULL REFERENCES products (id),
+    score              INT       NOT NULL,
+    rank               INT       NOT NULL,
+    updated_at         TIMESTAMP NOT NULL,
+    PRIMARY KEY (product_id, related_product_id)
+);
+CREATE INDEX idx_product_relations_rank ON product_relations (product_id, rank);
+
+-- RECENTLY VIEWED
+-- view terakhir per produk untuk user (session_id '') atau session anonim (user_public_id ''),
+-- dibatasi app.product.recently_viewed_limit per user / session
+CREATE TABLE product_views
+(
+    id             SERIAL PRIMARY KEY,
+    user_public_id VARCHAR(100) NOT NULL DEFAULT '',
+    session_id     VARCHAR(100) NOT NULL DEFAULT '',
+    product_id     INT          NOT NULL REFERENCES products (id),
+    viewed_at      TIMESTAMP    NOT NULL,
+    UNIQUE (user_public_id, session_id, product_id)
+);
+CREATE INDEX idx_product_views_viewed_at ON product_views (user_public_id, session_id, viewed_at DESC);
+
+-- AUTH PROFILE
+-- akun yang dihapus dianonimkan (email deleted-<public_id>@deleted.invalid, password kosong),
+-- barisnya tetap ada supaya transaksi dengan user_public_id yang sama tetap utuh
+ALTER TABLE auth ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE auth ADD COLUMN phone VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE auth ADD COLUMN avatar_url VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE auth ADD COLUMN email_verified_at TIMESTAMP NULL;
+ALTER TABLE auth ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE UNIQUE INDEX idx_auth_public_id ON auth (public_id);
+
+-- AUTH TOKENS
+-- token verifikasi email dan reset password, yang disimpan hanya sha256 dari token di link email.
+-- used_at juga diisi ketika token dicabut karena token baru dengan purpose yang sama dibuat
+CREATE TABLE auth_tokens (
+    id SERIAL PRIMARY KEY,
+    auth_public_id VARCHAR(50) NOT NULL,
+    purpose VARCHAR(20) NOT NULL, -- VERIFY_EMAIL, RESET_PASSWORD
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_auth_tokens_auth_purpose ON auth_tokens (auth_public_id, purpose) WHERE used_at IS NULL;
+
+-- akun yang dibuat sebelum verifikasi email dianggap sudah terverifikasi
+UPDATE auth SET email_verified_at = created_at WHERE email_verified_at IS NULL AND deleted_at IS NULL;
+
+-- AUTH LOGIN FAILURES
+-- hitungan login gagal per email (scope ACCOUNT, termasuk email yang tidak terdaftar) dan per IP (scope IP)
+CREATE TABLE auth_login_failures (
+    id SERIAL PRIMARY KEY,
+    scope VARCHAR(10) NOT NULL, -- ACCOUNT, IP
+    subject VARCHAR(255) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP NULL,
+    last_failed_at TIMESTAMP NOT NULL,
+    UNIQUE (scope, subject)
+);
+CREATE INDEX idx_auth_login_failures_last_failed_at ON auth_login_failures (last_failed_at);
+
 -- CHECKING TRANSACTIONS
 SELECT * FROM transactions as t
 JOIN auth AS a ON a.public_id = t.user_public_id
\ No newline at end of file
@@ -0,0 +1,98 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrBlobNotFound   = errors.New("blob not found")
+	ErrBlobKeyInvalid = errors.New("blob key is invalid")
+)
+
+// BlobStore menyimpan file biner seperti file produk digital, key memakai "/" sebagai pemisah folder
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (err error)
+	Open(ctx context.Context, key string) (rc io.ReadCloser, err error)
+	Delete(ctx context.Context, key string) (err error)
+}
+
+// Local menyimpan blob sebagai file di bawah folder Root
+type Local struct {
+	Root string
+}
+
+func NewLocal(root string) Local {
+	return Local{
+		Root: root,
+	}
+}
+
+// path menolak key yang keluar dari Root, contoh: ../config.yaml
+func (l Local) path(key string) (path string, err error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return "", ErrBlobKeyInvalid
+	}
+
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", ErrBlobKeyInvalid
+	}
+	return filepath.Join(l.Root, cleaned), nil
+}
+
+// Put menulis ke file sementara lalu rename, pembaca tidak pernah melihat file yang setengah tertulis
+func (l Local) Put(ctx context.Context, key string, r io.Reader) (err error) {
+	path, err := l.path(key)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		return
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l Local) Open(ctx context.Context, key string) (rc io.ReadCloser, err error) {
+	path, err := l.path(key)
+	if err != nil {
+		return
+	}
+
+	rc, err = os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return
+}
+
+func (l Local) Delete(ctx context.Context, key string) (err error) {
+	path, err := l.path(key)
+	if err != nil {
+		return
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return
+}
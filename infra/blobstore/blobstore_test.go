@@ -0,0 +1,53 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocal(t.TempDir())
+
+	t.Run("put and open", func(t *testing.T) {
+		require.Nil(t, store.Put(ctx, "products/1/ebook.pdf", strings.NewReader("isi ebook")))
+
+		rc, err := store.Open(ctx, "products/1/ebook.pdf")
+		require.Nil(t, err)
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		require.Nil(t, err)
+		require.Equal(t, "isi ebook", string(data))
+	})
+	t.Run("overwrite", func(t *testing.T) {
+		require.Nil(t, store.Put(ctx, "products/1/ebook.pdf", strings.NewReader("versi baru")))
+
+		rc, err := store.Open(ctx, "products/1/ebook.pdf")
+		require.Nil(t, err)
+		defer rc.Close()
+
+		data, _ := io.ReadAll(rc)
+		require.Equal(t, "versi baru", string(data))
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := store.Open(ctx, "products/2/missing.pdf")
+		require.Equal(t, ErrBlobNotFound, err)
+	})
+	t.Run("delete", func(t *testing.T) {
+		require.Nil(t, store.Delete(ctx, "products/1/ebook.pdf"))
+		require.Nil(t, store.Delete(ctx, "products/1/ebook.pdf"))
+
+		_, err := store.Open(ctx, "products/1/ebook.pdf")
+		require.Equal(t, ErrBlobNotFound, err)
+	})
+	t.Run("key outside root", func(t *testing.T) {
+		for _, key := range []string{"", "../secret", "/etc/passwd", "products/../../secret", `products\..\secret`} {
+			require.Equal(t, ErrBlobKeyInvalid, store.Put(ctx, key, strings.NewReader("x")), key)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache key-value dengan tipe nilai V, implementasinya LRU in-memory atau Redis
+type Cache[V any] interface {
+	// ok false berarti key tidak ada atau sudah kedaluwarsa
+	Get(ctx context.Context, key string) (value V, ok bool, err error)
+
+	// ttl 0 berarti entry tidak kedaluwarsa
+	Set(ctx context.Context, key string, value V, ttl time.Duration) (err error)
+
+	Delete(ctx context.Context, keys ...string) (err error)
+}
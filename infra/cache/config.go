@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"Ecommerce-basic/internal"
+	"sync"
+	"time"
+)
+
+const (
+	DRIVER_Memory string = "memory"
+	DRIVER_Redis  string = "redis"
+	DRIVER_None   string = "none"
+
+	// nilai default jika app.cache.ttl tidak diatur
+	defaultTTL = 30 * time.Second
+
+	// prefix semua key aplikasi di Redis
+	redisKeyPrefix = "ecommerce:"
+)
+
+var (
+	redisOnce   sync.Once
+	redisClient *RedisClient
+)
+
+// FromConfig membuat Loader sesuai app.cache, nil jika driver none.
+// name membedakan cache tiap repository, contoh: product:sku
+func FromConfig[V any](name string) *Loader[V] {
+	cfg := config.Cfg.App.Cache
+
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	switch cfg.Driver {
+	case DRIVER_None:
+		return nil
+	case DRIVER_Redis:
+		return NewLoader[V](NewRedis[V](sharedRedisClient(cfg), redisKeyPrefix+name+":"), ttl)
+	default:
+		return NewLoader[V](NewLRU[V](cfg.Capacity), ttl)
+	}
+}
+
+// semua cache memakai satu pool koneksi Redis
+func sharedRedisClient(cfg config.CacheConfig) *RedisClient {
+	redisOnce.Do(func() {
+		redisClient = NewRedisClient(RedisOptions{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+	})
+	return redisClient
+}
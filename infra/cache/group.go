@@ -0,0 +1,57 @@
+package cache
+
+import "sync"
+
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// Group menggabungkan load key yang sama yang sedang berjalan supaya cache miss
+// dari banyak request tidak menjadi banyak query yang sama ke database (cache stampede)
+type Group[V any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[V]
+}
+
+// Do menjalankan fn sekali per key, pemanggil lain menunggu dan mendapat hasil yang sama.
+// shared true berarti hasil juga dipakai pemanggil lain
+func (g *Group[V]) Do(key string, fn func() (V, error)) (value V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*call[V]{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err, true
+	}
+
+	c := &call[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		// key bisa sudah di-Forget dan dipakai load baru
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.value, c.err = fn()
+	return c.value, c.err, false
+}
+
+// Forget pemanggil berikutnya tidak menunggu load key yang sedang berjalan,
+// dipakai ketika data berubah di tengah load
+func (g *Group[V]) Forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.calls, key)
+}
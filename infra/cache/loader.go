@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	infraLog "Ecommerce-basic/internal/log"
+)
+
+// Loader cache read-through: nilai dibaca dari cache, jika tidak ada dimuat sekali lewat Group
+// lalu disimpan. Cache yang error tidak membuat request gagal, nilai langsung dimuat dari sumbernya
+type Loader[V any] struct {
+	cache Cache[V]
+	ttl   time.Duration
+	group Group[V]
+
+	// naik setiap Invalidate, hasil load yang dimulai sebelum Invalidate tidak disimpan
+	generation atomic.Uint64
+}
+
+func NewLoader[V any](cache Cache[V], ttl time.Duration) *Loader[V] {
+	return &Loader[V]{
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+// Load error dari load tidak disimpan, contoh: response.ErrNotFound tetap dicek ke database setiap kali
+func (l *Loader[V]) Load(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (value V, err error) {
+	value, ok, err := l.cache.Get(ctx, key)
+	if err != nil {
+		infraLog.Log.Errorf(ctx, "[Loader, Get] with error detail %v", err.Error())
+	}
+	if ok {
+		return value, nil
+	}
+
+	value, err, _ = l.group.Do(key, func() (value V, err error) {
+		generation := l.generation.Load()
+		if value, err = load(ctx); err != nil {
+			return
+		}
+
+		if generation != l.generation.Load() {
+			return
+		}
+		if err := l.cache.Set(ctx, key, value, l.ttl); err != nil {
+			infraLog.Log.Errorf(ctx, "[Loader, Set] with error detail %v", err.Error())
+		}
+		return
+	})
+	return
+}
+
+// Invalidate menghapus key setelah data di sumbernya berubah
+func (l *Loader[V]) Invalidate(ctx context.Context, keys ...string) (err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	l.generation.Add(1)
+	for _, key := range keys {
+		l.group.Forget(key)
+	}
+	return l.cache.Delete(ctx, keys...)
+}
+
+// Invalidator dipanggil Invalidate untuk tag yang di-Subscribe
+type Invalidator interface {
+	Invalidate(ctx context.Context, keys ...string) (err error)
+}
+
+// tag yang dipakai bersama beberapa modul
+const (
+	// key-nya adalah SKU produk
+	TAG_PRODUCT_SKU string = "product:sku"
+
+	// key-nya adalah id produk, untuk perubahan yang hanya mengubah daftar produk publik
+	// tanpa mengubah data produk itu sendiri, contoh: reservasi stok
+	TAG_PRODUCT_CATALOG string = "product:catalog"
+
+	// key-nya adalah email akun
+	TAG_AUTH_EMAIL string = "auth:email"
+
+	// key-nya adalah public_id akun
+	TAG_AUTH_PUBLIC_ID string = "auth:public_id"
+)
+
+var (
+	subscribersMu sync.RWMutex
+	subscribers   = map[string][]Invalidator{}
+)
+
+// Subscribe mendaftarkan cache supaya ikut dihapus ketika modul lain mengubah data dengan tag yang sama,
+// contoh: perubahan harga di modul pricing menghapus cache produk di modul product dan transaction
+func Subscribe(tag string, invalidator Invalidator) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	subscribers[tag] = append(subscribers[tag], invalidator)
+}
+
+// Invalidate menghapus key di semua cache yang di-Subscribe ke tag, error pertama dikembalikan
+func Invalidate(ctx context.Context, tag string, keys ...string) (err error) {
+	subscribersMu.RLock()
+	invalidators := subscribers[tag]
+	subscribersMu.RUnlock()
+
+	for _, invalidator := range invalidators {
+		if invalidateErr := invalidator.Invalidate(ctx, keys...); invalidateErr != nil && err == nil {
+			err = invalidateErr
+		}
+	}
+	return
+}
+
+// PendingKeys key tag yang berubah di dalam transaksi database, baru dihapus dari cache setelah commit
+// supaya request lain tidak mengisi cache lagi dengan data lama sebelum commit
+type PendingKeys[T comparable] struct {
+	tag string
+
+	mu   sync.Mutex
+	keys map[T][]string
+}
+
+func NewPendingKeys[T comparable](tag string) *PendingKeys[T] {
+	return &PendingKeys[T]{
+		tag:  tag,
+		keys: map[T][]string{},
+	}
+}
+
+func (p *PendingKeys[T]) Add(owner T, keys ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.keys[owner] = append(p.keys[owner], keys...)
+}
+
+// Discard dipanggil saat rollback atau commit gagal
+func (p *PendingKeys[T]) Discard(owner T) {
+	p.take(owner)
+}
+
+// Flush dipanggil setelah commit berhasil, cache yang gagal dihapus hanya dicatat dan hilang setelah TTL
+func (p *PendingKeys[T]) Flush(ctx context.Context, owner T) {
+	if err := Invalidate(ctx, p.tag, p.take(owner)...); err != nil {
+		infraLog.Log.Errorf(ctx, "[PendingKeys, Flush] with error detail %v", err.Error())
+	}
+}
+
+func (p *PendingKeys[T]) take(owner T) (keys []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys = p.keys[owner]
+	delete(p.keys, owner)
+	return
+}
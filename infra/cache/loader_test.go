@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("concurrent calls share one load", func(t *testing.T) {
+		var group Group[int]
+		var loads atomic.Int32
+		release := make(chan struct{})
+
+		var wg sync.WaitGroup
+		results := make([]int, 10)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _, _ = group.Do("a", func() (int, error) {
+					loads.Add(1)
+					<-release
+					return 7, nil
+				})
+			}()
+		}
+
+		// tunggu sampai load pertama berjalan, lalu beri waktu pemanggil lain ikut menunggu
+		require.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int32(1), loads.Load())
+		for _, result := range results {
+			require.Equal(t, 7, result)
+		}
+	})
+	t.Run("forget starts a new load", func(t *testing.T) {
+		var group Group[int]
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		go group.Do("a", func() (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		<-started
+
+		group.Forget("a")
+		value, _, shared := group.Do("a", func() (int, error) { return 2, nil })
+		require.False(t, shared)
+		require.Equal(t, 2, value)
+		close(release)
+	})
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads once then serves from cache", func(t *testing.T) {
+		loader := NewLoader[string](NewLRU[string](10), time.Minute)
+		loads := 0
+		load := func(ctx context.Context) (string, error) {
+			loads++
+			return "produk", nil
+		}
+
+		for range 3 {
+			value, err := loader.Load(ctx, "SKU-1", load)
+			require.Nil(t, err)
+			require.Equal(t, "produk", value)
+		}
+		require.Equal(t, 1, loads)
+
+		require.Nil(t, loader.Invalidate(ctx, "SKU-1"))
+		loader.Load(ctx, "SKU-1", load)
+		require.Equal(t, 2, loads)
+	})
+	t.Run("errors are not cached", func(t *testing.T) {
+		loader := NewLoader[string](NewLRU[string](10), time.Minute)
+		errNotFound := errors.New("not found")
+		loads := 0
+		load := func(ctx context.Context) (string, error) {
+			loads++
+			return "", errNotFound
+		}
+
+		_, err := loader.Load(ctx, "SKU-1", load)
+		require.Equal(t, errNotFound, err)
+		loader.Load(ctx, "SKU-1", load)
+		require.Equal(t, 2, loads)
+	})
+	t.Run("invalidate during load skips set", func(t *testing.T) {
+		loader := NewLoader[string](NewLRU[string](10), time.Minute)
+
+		_, err := loader.Load(ctx, "SKU-1", func(ctx context.Context) (string, error) {
+			// data berubah ketika nilai lama sedang dibaca
+			loader.Invalidate(ctx, "SKU-1")
+			return "lama", nil
+		})
+		require.Nil(t, err)
+
+		value, _ := loader.Load(ctx, "SKU-1", func(ctx context.Context) (string, error) {
+			return "baru", nil
+		})
+		require.Equal(t, "baru", value)
+	})
+	t.Run("broken cache falls back to load", func(t *testing.T) {
+		loader := NewLoader[string](brokenCache[string]{}, time.Minute)
+
+		value, err := loader.Load(ctx, "SKU-1", func(ctx context.Context) (string, error) {
+			return "produk", nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, "produk", value)
+	})
+	t.Run("tag invalidates every subscriber", func(t *testing.T) {
+		first := NewLoader[string](NewLRU[string](10), time.Minute)
+		second := NewLoader[int](NewLRU[int](10), time.Minute)
+		Subscribe("test:sku", first)
+		Subscribe("test:sku", second)
+
+		first.Load(ctx, "SKU-1", func(ctx context.Context) (string, error) { return "produk", nil })
+		second.Load(ctx, "SKU-1", func(ctx context.Context) (int, error) { return 1, nil })
+
+		require.Nil(t, Invalidate(ctx, "test:sku", "SKU-1"))
+
+		_, ok, _ := first.cache.Get(ctx, "SKU-1")
+		require.False(t, ok)
+		_, ok, _ = second.cache.Get(ctx, "SKU-1")
+		require.False(t, ok)
+	})
+}
+
+func TestPendingKeys(t *testing.T) {
+	ctx := context.Background()
+	loader := NewLoader[string](NewLRU[string](10), time.Minute)
+	Subscribe("test:pending", loader)
+	for _, key := range []string{"SKU-1", "SKU-2", "SKU-3"} {
+		loader.cache.Set(ctx, key, key, 0)
+	}
+
+	pending := NewPendingKeys[int]("test:pending")
+	pending.Add(1, "SKU-1")
+	pending.Add(1, "SKU-2")
+	pending.Add(2, "SKU-3")
+
+	// transaksi 1 commit, transaksi 2 rollback
+	pending.Flush(ctx, 1)
+	pending.Discard(2)
+
+	_, ok, _ := loader.cache.Get(ctx, "SKU-1")
+	require.False(t, ok)
+	_, ok, _ = loader.cache.Get(ctx, "SKU-2")
+	require.False(t, ok)
+	_, ok, _ = loader.cache.Get(ctx, "SKU-3")
+	require.True(t, ok)
+	require.Empty(t, pending.take(1))
+	require.Empty(t, pending.take(2))
+}
+
+type brokenCache[V any] struct{}
+
+var errBroken = errors.New("cache down")
+
+func (brokenCache[V]) Get(ctx context.Context, key string) (value V, ok bool, err error) {
+	return value, false, errBroken
+}
+
+func (brokenCache[V]) Set(ctx context.Context, key string, value V, ttl time.Duration) error {
+	return errBroken
+}
+
+func (brokenCache[V]) Delete(ctx context.Context, keys ...string) error {
+	return errBroken
+}
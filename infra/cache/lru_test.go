@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get and set", func(t *testing.T) {
+		c := NewLRU[string](2)
+		require.Nil(t, c.Set(ctx, "a", "satu", 0))
+
+		value, ok, err := c.Get(ctx, "a")
+		require.Nil(t, err)
+		require.True(t, ok)
+		require.Equal(t, "satu", value)
+
+		_, ok, _ = c.Get(ctx, "b")
+		require.False(t, ok)
+	})
+	t.Run("least recently used is evicted", func(t *testing.T) {
+		c := NewLRU[int](2)
+		c.Set(ctx, "a", 1, 0)
+		c.Set(ctx, "b", 2, 0)
+
+		// a dibaca sehingga b yang paling lama tidak dipakai
+		c.Get(ctx, "a")
+		c.Set(ctx, "c", 3, 0)
+
+		_, ok, _ := c.Get(ctx, "b")
+		require.False(t, ok)
+		_, ok, _ = c.Get(ctx, "a")
+		require.True(t, ok)
+		require.Equal(t, 2, c.Len())
+	})
+	t.Run("overwrite keeps size", func(t *testing.T) {
+		c := NewLRU[int](2)
+		c.Set(ctx, "a", 1, 0)
+		c.Set(ctx, "a", 2, 0)
+
+		value, _, _ := c.Get(ctx, "a")
+		require.Equal(t, 2, value)
+		require.Equal(t, 1, c.Len())
+	})
+	t.Run("expired", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := NewLRU[int](2)
+		c.now = func() time.Time { return now }
+
+		c.Set(ctx, "a", 1, time.Minute)
+		now = now.Add(59 * time.Second)
+		_, ok, _ := c.Get(ctx, "a")
+		require.True(t, ok)
+
+		now = now.Add(time.Second)
+		_, ok, _ = c.Get(ctx, "a")
+		require.False(t, ok)
+		require.Equal(t, 0, c.Len())
+	})
+	t.Run("delete", func(t *testing.T) {
+		c := NewLRU[int](2)
+		c.Set(ctx, "a", 1, 0)
+		c.Set(ctx, "b", 2, 0)
+
+		require.Nil(t, c.Delete(ctx, "a", "missing"))
+		_, ok, _ := c.Get(ctx, "a")
+		require.False(t, ok)
+		require.Equal(t, 1, c.Len())
+	})
+}
@@ -0,0 +1,284 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	// nilai default jika RedisOptions tidak diatur
+	defaultRedisPoolSize = 10
+	defaultRedisTimeout  = 3 * time.Second
+)
+
+// RedisError balasan error dari server, contoh: ERR unknown command
+type RedisError string
+
+func (e RedisError) Error() string {
+	return string(e)
+}
+
+var errRedisProtocol = errors.New("redis: invalid reply")
+
+type RedisOptions struct {
+	Addr     string
+	Password string
+	DB       int
+
+	// jumlah koneksi idle yang disimpan dan batas waktu tiap command jika ctx tidak punya deadline
+	PoolSize int
+	Timeout  time.Duration
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// RedisClient klien protokol Redis (RESP2) minimal untuk cache: GET, SET dengan PX, DEL dan PING.
+// Koneksi dipakai ulang lewat pool, koneksi yang error langsung ditutup
+type RedisClient struct {
+	opts RedisOptions
+	pool chan *redisConn
+}
+
+func NewRedisClient(opts RedisOptions) *RedisClient {
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = defaultRedisPoolSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultRedisTimeout
+	}
+	return &RedisClient{
+		opts: opts,
+		pool: make(chan *redisConn, opts.PoolSize),
+	}
+}
+
+// Do mengirim satu command, balasan berupa string, int64, []byte, []interface{} atau nil untuk null bulk string
+func (c *RedisClient) Do(ctx context.Context, args ...string) (reply interface{}, err error) {
+	conn, err := c.get(ctx)
+	if err != nil {
+		return
+	}
+
+	reply, err = conn.do(ctx, c.opts.Timeout, args...)
+	if err != nil {
+		// koneksi masih bisa dipakai jika server hanya membalas error
+		if _, ok := err.(RedisError); !ok {
+			conn.conn.Close()
+			return
+		}
+	}
+
+	c.put(conn)
+	return
+}
+
+func (c *RedisClient) Ping(ctx context.Context) (err error) {
+	_, err = c.Do(ctx, "PING")
+	return
+}
+
+// Close menutup semua koneksi idle
+func (c *RedisClient) Close() error {
+	for {
+		select {
+		case conn := <-c.pool:
+			conn.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *RedisClient) get(ctx context.Context) (conn *redisConn, err error) {
+	select {
+	case conn = <-c.pool:
+		return
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.opts.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.opts.Addr)
+	if err != nil {
+		return
+	}
+	conn = &redisConn{conn: netConn, r: bufio.NewReader(netConn)}
+
+	if c.opts.Password != "" {
+		if _, err = conn.do(ctx, c.opts.Timeout, "AUTH", c.opts.Password); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err = conn.do(ctx, c.opts.Timeout, "SELECT", strconv.Itoa(c.opts.DB)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return
+}
+
+func (c *RedisClient) put(conn *redisConn) {
+	select {
+	case c.pool <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+func (c *redisConn) do(ctx context.Context, timeout time.Duration, args ...string) (reply interface{}, err error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(timeout)
+	}
+	if err = c.conn.SetDeadline(deadline); err != nil {
+		return
+	}
+
+	if _, err = c.conn.Write(encodeCommand(args)); err != nil {
+		return
+	}
+	return readReply(c.r)
+}
+
+// encodeCommand command dikirim sebagai array bulk string
+func encodeCommand(args []string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return buf.Bytes()
+}
+
+func readReply(r *bufio.Reader) (reply interface{}, err error) {
+	line, err := readLine(r)
+	if err != nil {
+		return
+	}
+	if len(line) == 0 {
+		return nil, errRedisProtocol
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errRedisProtocol
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err = io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errRedisProtocol
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, size)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				if _, ok := err.(RedisError); !ok {
+					return nil, err
+				}
+				items[i] = err
+			}
+		}
+		return items, nil
+	}
+	return nil, errRedisProtocol
+}
+
+func readLine(r *bufio.Reader) (line string, err error) {
+	line, err = r.ReadString('\n')
+	if err != nil {
+		return
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errRedisProtocol
+	}
+	return line[:len(line)-2], nil
+}
+
+// Redis cache di server Redis yang bisa dipakai bersama beberapa instance aplikasi,
+// nilai di-encode dengan gob supaya field tanpa tag json (contoh: password hash) tetap tersimpan
+type Redis[V any] struct {
+	client *RedisClient
+	prefix string
+}
+
+// NewRedis prefix ditambahkan ke setiap key, contoh: ecommerce:product:sku:
+func NewRedis[V any](client *RedisClient, prefix string) *Redis[V] {
+	return &Redis[V]{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (c *Redis[V]) Get(ctx context.Context, key string) (value V, ok bool, err error) {
+	reply, err := c.client.Do(ctx, "GET", c.prefix+key)
+	if err != nil || reply == nil {
+		return
+	}
+
+	data, isBytes := reply.([]byte)
+	if !isBytes {
+		return value, false, errRedisProtocol
+	}
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return
+	}
+	return value, true, nil
+}
+
+func (c *Redis[V]) Set(ctx context.Context, key string, value V, ttl time.Duration) (err error) {
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(value); err != nil {
+		return
+	}
+
+	args := []string{"SET", c.prefix + key, buf.String()}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	}
+	_, err = c.client.Do(ctx, args...)
+	return
+}
+
+func (c *Redis[V]) Delete(ctx context.Context, keys ...string) (err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	args := make([]string, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, c.prefix+key)
+	}
+	_, err = c.client.Do(ctx, args...)
+	return
+}
@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis server protokol Redis in-process untuk test, hanya command yang dipakai RedisClient
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	expires  map[string]time.Time
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	server := &fakeRedis{
+		listener: listener,
+		password: password,
+		values:   map[string]string{},
+		expires:  map[string]time.Time{},
+	}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeRedis) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			data, _ := item.([]byte)
+			args[i] = string(data)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		command := strings.ToUpper(args[0])
+		if command == "AUTH" {
+			if len(args) == 2 && args[1] == s.password {
+				authenticated = true
+				fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+			}
+			continue
+		}
+		if !authenticated {
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		conn.Write([]byte(s.exec(command, args[1:])))
+	}
+}
+
+func (s *fakeRedis) exec(command string, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = append(s.commands, command)
+	switch command {
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		value, ok := s.get(args[0])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "SET":
+		s.values[args[0]] = args[1]
+		delete(s.expires, args[0])
+		if len(args) == 4 && strings.ToUpper(args[2]) == "PX" {
+			ms, _ := strconv.Atoi(args[3])
+			s.expires[args[0]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args {
+			if _, ok := s.get(key); ok {
+				deleted++
+			}
+			delete(s.values, key)
+			delete(s.expires, key)
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", command)
+}
+
+func (s *fakeRedis) get(key string) (value string, ok bool) {
+	if expiresAt, ok := s.expires[key]; ok && !time.Now().Before(expiresAt) {
+		delete(s.values, key)
+		delete(s.expires, key)
+	}
+	value, ok = s.values[key]
+	return
+}
+
+func (s *fakeRedis) has(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.get(key)
+	return ok
+}
+
+type cachedAccount struct {
+	Email    string
+	Password string
+	Roles    []string
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis(t, "rahasia")
+	client := NewRedisClient(RedisOptions{Addr: server.Addr(), Password: "rahasia", DB: 1})
+	defer client.Close()
+
+	c := NewRedis[cachedAccount](client, "test:auth:")
+
+	t.Run("ping", func(t *testing.T) {
+		require.Nil(t, client.Ping(ctx))
+	})
+	t.Run("set and get", func(t *testing.T) {
+		account := cachedAccount{Email: "user@mail.com", Password: "hash", Roles: []string{"user"}}
+		require.Nil(t, c.Set(ctx, "user@mail.com", account, time.Minute))
+		require.True(t, server.has("test:auth:user@mail.com"))
+
+		value, ok, err := c.Get(ctx, "user@mail.com")
+		require.Nil(t, err)
+		require.True(t, ok)
+		require.Equal(t, account, value)
+	})
+	t.Run("missing key", func(t *testing.T) {
+		_, ok, err := c.Get(ctx, "missing@mail.com")
+		require.Nil(t, err)
+		require.False(t, ok)
+	})
+	t.Run("ttl", func(t *testing.T) {
+		require.Nil(t, c.Set(ctx, "short@mail.com", cachedAccount{Email: "short@mail.com"}, 20*time.Millisecond))
+		time.Sleep(40 * time.Millisecond)
+
+		_, ok, err := c.Get(ctx, "short@mail.com")
+		require.Nil(t, err)
+		require.False(t, ok)
+	})
+	t.Run("delete", func(t *testing.T) {
+		c.Set(ctx, "a@mail.com", cachedAccount{Email: "a@mail.com"}, 0)
+		c.Set(ctx, "b@mail.com", cachedAccount{Email: "b@mail.com"}, 0)
+
+		require.Nil(t, c.Delete(ctx, "a@mail.com", "b@mail.com"))
+		require.False(t, server.has("test:auth:a@mail.com"))
+		require.False(t, server.has("test:auth:b@mail.com"))
+	})
+	t.Run("server error keeps connection", func(t *testing.T) {
+		_, err := client.Do(ctx, "FLUSHALL")
+		require.Equal(t, RedisError("ERR unknown command 'FLUSHALL'"), err)
+		require.Nil(t, client.Ping(ctx))
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		wrong := NewRedisClient(RedisOptions{Addr: server.Addr(), Password: "salah"})
+		require.Equal(t, RedisError("WRONGPASS invalid password"), wrong.Ping(ctx))
+	})
+	t.Run("server down", func(t *testing.T) {
+		down := NewRedis[cachedAccount](NewRedisClient(RedisOptions{Addr: "127.0.0.1:1", Timeout: 100 * time.Millisecond}), "test:")
+		_, _, err := down.Get(ctx, "a@mail.com")
+		require.NotNil(t, err)
+
+		// Loader tetap mengembalikan data dari sumbernya
+		loader := NewLoader[cachedAccount](down, time.Minute)
+		value, err := loader.Load(ctx, "a@mail.com", func(ctx context.Context) (cachedAccount, error) {
+			return cachedAccount{Email: "a@mail.com"}, nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, "a@mail.com", value.Email)
+	})
+}
+
+func TestReadReply(t *testing.T) {
+	reply, err := readReply(bufio.NewReader(strings.NewReader("*3\r\n:1\r\n$3\r\nabc\r\n$-1\r\n")))
+	require.Nil(t, err)
+	require.Equal(t, []interface{}{int64(1), []byte("abc"), nil}, reply)
+
+	_, err = readReply(bufio.NewReader(strings.NewReader("?\r\n")))
+	require.Equal(t, errRedisProtocol, err)
+}
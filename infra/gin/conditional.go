@@ -0,0 +1,91 @@
+package infragin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Representation adalah response yang sudah di-encode beserta validator untuk conditional GET,
+// bisa disimpan di cache dan dikirim ulang tanpa membangun response lagi
+type Representation struct {
+	HttpCode     int
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+}
+
+// NewRepresentation ETag default adalah strong ETag dari isi body
+func NewRepresentation(r Response, lastModified time.Time) (rep Representation, err error) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+
+	// Last-Modified hanya punya presisi detik
+	if !lastModified.IsZero() {
+		lastModified = lastModified.UTC().Truncate(time.Second)
+	}
+
+	return Representation{
+		HttpCode:     r.HttpCode,
+		Body:         body,
+		ETag:         `"` + ContentHash(body) + `"`,
+		LastModified: lastModified,
+	}, nil
+}
+
+// ContentHash hash isi body yang dipakai sebagai strong ETag
+func ContentHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:16])
+}
+
+// NotModified mengikuti RFC 9110: If-None-Match diperiksa lebih dulu,
+// If-Modified-Since hanya dipakai jika If-None-Match tidak dikirim
+func (r Representation) NotModified(ifNoneMatch string, ifModifiedSince string) bool {
+	if ifNoneMatch = strings.TrimSpace(ifNoneMatch); ifNoneMatch != "" {
+		if ifNoneMatch == "*" {
+			return true
+		}
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			// If-None-Match memakai weak comparison
+			if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == r.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince == "" || r.LastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !r.LastModified.After(since)
+}
+
+// Send mengirim 304 tanpa body jika validator dari client masih cocok
+func (r Representation) Send(c *gin.Context, cacheControl string) {
+	c.Header("ETag", r.ETag)
+	if !r.LastModified.IsZero() {
+		c.Header("Last-Modified", r.LastModified.Format(http.TimeFormat))
+	}
+	if cacheControl != "" {
+		c.Header("Cache-Control", cacheControl)
+	}
+
+	if r.NotModified(c.GetHeader("If-None-Match"), c.GetHeader("If-Modified-Since")) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(r.HttpCode, "application/json; charset=utf-8", r.Body)
+}
@@ -0,0 +1,57 @@
+package infragin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepresentation(t *testing.T) {
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+	rep, err := NewRepresentation(NewResponse(WithHttpCode(http.StatusOK), WithPayload("a")), modified)
+	require.Nil(t, err)
+	require.Equal(t, modified.Truncate(time.Second), rep.LastModified)
+
+	other, err := NewRepresentation(NewResponse(WithHttpCode(http.StatusOK), WithPayload("b")), modified)
+	require.Nil(t, err)
+	require.NotEqual(t, rep.ETag, other.ETag)
+
+	t.Run("if none match", func(t *testing.T) {
+		require.True(t, rep.NotModified(rep.ETag, ""))
+		require.True(t, rep.NotModified(`"x", W/`+rep.ETag, ""))
+		require.True(t, rep.NotModified("*", ""))
+		require.False(t, rep.NotModified(other.ETag, ""))
+
+		// If-Modified-Since diabaikan jika If-None-Match ada
+		require.False(t, rep.NotModified(other.ETag, modified.Add(time.Hour).Format(http.TimeFormat)))
+	})
+	t.Run("if modified since", func(t *testing.T) {
+		require.True(t, rep.NotModified("", modified.Format(http.TimeFormat)))
+		require.False(t, rep.NotModified("", modified.Add(-time.Second).Format(http.TimeFormat)))
+		require.False(t, rep.NotModified("", "bukan tanggal"))
+	})
+	t.Run("send", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		rep.Send(c, "public, max-age=30")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, rep.ETag, w.Header().Get("ETag"))
+		require.Equal(t, "public, max-age=30", w.Header().Get("Cache-Control"))
+		require.Equal(t, string(rep.Body), w.Body.String())
+
+		w = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("If-None-Match", rep.ETag)
+		rep.Send(c, "")
+		require.Equal(t, http.StatusNotModified, w.Code)
+		require.Empty(t, w.Body.String())
+	})
+}
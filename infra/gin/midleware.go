@@ -72,6 +72,51 @@ func Trace() gin.HandlerFunc {
 	}
 }
 
+// SessionChecker memeriksa akun pemilik token, token JWT tetap valid sampai kedaluwarsa
+// sehingga akun yang sudah dihapus hanya bisa ditolak lewat pengecekan ini
+type SessionChecker interface {
+	IsActive(ctx context.Context, publicId string) (active bool, err error)
+}
+
+var sessionChecker SessionChecker
+
+// SetSessionChecker didaftarkan modul auth saat Init, tanpa checker token hanya divalidasi signature-nya
+func SetSessionChecker(checker SessionChecker) {
+	sessionChecker = checker
+}
+
+func isSessionActive(ctx context.Context, publicId string) (active bool, err error) {
+	if sessionChecker == nil {
+		return true, nil
+	}
+	return sessionChecker.IsActive(ctx, publicId)
+}
+
+// OptionalAuth mengisi ROLE dan PUBLIC_ID jika request membawa token yang valid,
+// request tanpa token atau dengan token yang tidak valid tetap diteruskan sebagai anonim
+func OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bearer := strings.Split(c.GetHeader("Authorization"), "Bearer ")
+		if len(bearer) == 2 {
+			publicId, role, err := utility.ValidateToken(bearer[1], config.Cfg.App.Encryption.JWTSecret)
+			if err == nil {
+				active, err := isSessionActive(c.Request.Context(), publicId)
+				if err != nil {
+					infraLog.Log.Errorf(c.Request.Context(), "[OptionalAuth, IsActive] with error detail %v", err.Error())
+				}
+				if !active {
+					c.Next()
+					return
+				}
+				c.Set("ROLE", role)
+				c.Set("PUBLIC_ID", publicId)
+			}
+		}
+
+		c.Next()
+	}
+}
+
 // CheckAuth Middleware
 func CheckAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -111,6 +156,26 @@ func CheckAuth() gin.HandlerFunc {
 			return
 		}
 
+		// Akun yang sudah dihapus tidak bisa memakai token lamanya
+		active, err := isSessionActive(c.Request.Context(), publicId)
+		if err != nil {
+			infraLog.Log.Errorf(c.Request.Context(), "[CheckAuth, IsActive] with error detail %v", err.Error())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success":   false,
+				"error":     response.ErrorGeneral.Message,
+				"errorCode": response.ErrorGeneral.Code,
+			})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"success":   false,
+				"error":     response.ErrorUnauthorized.Message,
+				"errorCode": response.ErrorUnauthorized.Code,
+			})
+			return
+		}
+
 		// Set role and public ID in context
 		c.Set("ROLE", role)
 		c.Set("PUBLIC_ID", publicId)
@@ -0,0 +1,52 @@
+package infragin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Ecommerce-basic/internal"
+	"Ecommerce-basic/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSessionChecker map[string]bool
+
+func (s stubSessionChecker) IsActive(ctx context.Context, publicId string) (bool, error) {
+	return s[publicId], nil
+}
+
+func TestCheckAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.Cfg.App.Encryption.JWTSecret = "secret"
+
+	router := gin.New()
+	router.GET("/me", CheckAuth(), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("PUBLIC_ID"))
+	})
+	request := func(publicId string) *httptest.ResponseRecorder {
+		token, err := utility.GenerateToken(publicId, "user", "secret")
+		require.Nil(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	SetSessionChecker(stubSessionChecker{"active": true, "deleted": false})
+	defer SetSessionChecker(nil)
+
+	t.Run("active account", func(t *testing.T) {
+		rec := request("active")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "active", rec.Body.String())
+	})
+	t.Run("deleted account keeps a valid token", func(t *testing.T) {
+		rec := request("deleted")
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -12,6 +12,7 @@ type Response struct {
 	Message   string      `json:"message"`
 	Payload   interface{} `json:"payload,omitempty"`
 	Query     interface{} `json:"query,omitempty"`
+	Meta      interface{} `json:"meta,omitempty"`
 	Error     string      `json:"error,omitempty"`
 	ErrorCode string      `json:"error_code,omitempty"`
 }
@@ -30,6 +31,7 @@ func NewResponse(params ...func(*Response) *Response) Response {
 
 func WithHttpCode(httpCode int) func(*Response) *Response {
 	return func(r *Response) *Response {
+		r.HttpCode = httpCode
 		return r
 	}
 }
@@ -55,6 +57,13 @@ func WithQuery(query interface{}) func(*Response) *Response {
 	}
 }
 
+func WithMeta(meta interface{}) func(*Response) *Response {
+	return func(r *Response) *Response {
+		r.Meta = meta
+		return r
+	}
+}
+
 func WithError(err error) func(*Response) *Response {
 	return func(r *Response) *Response {
 		r.Success = false
@@ -66,6 +75,11 @@ func WithError(err error) func(*Response) *Response {
 
 		r.Error = myErr.Message
 		r.ErrorCode = myErr.Code
+
+		// http code dari error dipakai jika handler tidak mengatur sendiri
+		if r.HttpCode == 0 {
+			r.HttpCode = myErr.HttpCode
+		}
 		return r
 	}
 }
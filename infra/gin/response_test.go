@@ -0,0 +1,30 @@
+package infragin
+
+import (
+	"Ecommerce-basic/infra/response"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseHttpCode(t *testing.T) {
+	t.Run("with http code", func(t *testing.T) {
+		resp := NewResponse(WithHttpCode(http.StatusCreated))
+
+		require.Equal(t, http.StatusCreated, resp.HttpCode)
+		require.True(t, resp.Success)
+	})
+	t.Run("from error", func(t *testing.T) {
+		resp := NewResponse(WithError(response.ErrorNotFound))
+
+		require.Equal(t, http.StatusNotFound, resp.HttpCode)
+		require.Equal(t, response.ErrorNotFound.Code, resp.ErrorCode)
+		require.False(t, resp.Success)
+	})
+	t.Run("explicit http code wins", func(t *testing.T) {
+		resp := NewResponse(WithHttpCode(http.StatusBadRequest), WithError(response.ErrorGeneral))
+
+		require.Equal(t, http.StatusBadRequest, resp.HttpCode)
+	})
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import "Ecommerce-basic/internal"
+
+const (
+	DRIVER_SMTP   string = "smtp"
+	DRIVER_File   string = "file"
+	DRIVER_Memory string = "memory"
+
+	// nilai default jika app.mail tidak diatur
+	defaultFrom      = "no-reply@localhost"
+	defaultOutboxDir = "./storage/outbox"
+	defaultSMTPPort  = 587
+)
+
+// FromConfig membuat Mailer sesuai app.mail, default outbox file supaya development tidak butuh server SMTP
+func FromConfig() Mailer {
+	cfg := config.Cfg.App.Mail
+
+	from := cfg.From
+	if from == "" {
+		from = defaultFrom
+	}
+
+	switch cfg.Driver {
+	case DRIVER_SMTP:
+		port := cfg.SMTPPort
+		if port <= 0 {
+			port = defaultSMTPPort
+		}
+		return NewSMTP(SMTPOptions{
+			Host:     cfg.SMTPHost,
+			Port:     port,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     from,
+		})
+	case DRIVER_Memory:
+		return NewMemoryOutbox()
+	default:
+		outboxDir := cfg.OutboxDir
+		if outboxDir == "" {
+			outboxDir = defaultOutboxDir
+		}
+		return NewFileOutbox(outboxDir, from)
+	}
+}
@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var ErrMessageInvalid = errors.New("message is invalid")
+
+// Mailer mengirim email keluar, implementasinya SMTP atau outbox file / in-memory untuk development dan test
+type Mailer interface {
+	Send(ctx context.Context, msg Message) (err error)
+}
+
+// Message email dengan isi teks dan HTML, HTML boleh kosong
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Bytes format RFC 5322 dengan multipart/alternative, siap dikirim lewat SMTP atau disimpan sebagai .eml
+func (m Message) Bytes(from string, now time.Time) (data []byte, err error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = w.Write([]byte(normalizeNewlines(part.content))); err != nil {
+			return nil, err
+		}
+	}
+	if err = body.Close(); err != nil {
+		return
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", m.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", messageId(), domainOf(from))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", body.Boundary())
+	msg.Write(buf.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// Validate header tidak boleh berisi baris baru supaya tidak bisa menyisipkan header lain
+func (m Message) Validate() (err error) {
+	if m.To == "" || strings.ContainsAny(m.To+m.Subject, "\r\n") {
+		return ErrMessageInvalid
+	}
+	return
+}
+
+func normalizeNewlines(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.ReplaceAll(content, "\n", "\r\n")
+}
+
+func messageId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func domainOf(address string) string {
+	address = strings.TrimSuffix(strings.TrimSpace(address), ">")
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
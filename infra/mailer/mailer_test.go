@@ -0,0 +1,196 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type templateData struct {
+	Name      string
+	Email     string
+	Link      string
+	ExpiresIn string
+}
+
+func TestRender(t *testing.T) {
+	data := templateData{Email: "user@mail.com", Link: "http://localhost:3000/verify-email?token=abc", ExpiresIn: "24h"}
+
+	for _, name := range []string{"verify_email", "reset_password"} {
+		msg, err := Render(name, "user@mail.com", data)
+		require.Nil(t, err, name)
+		require.Equal(t, "user@mail.com", msg.To)
+		require.NotEmpty(t, msg.Subject)
+		require.NotContains(t, msg.Subject, "\n")
+		require.Contains(t, msg.Text, data.Link)
+		require.Contains(t, msg.Text, "Hi there")
+		require.Contains(t, msg.HTML, `href="http://localhost:3000/verify-email?token=abc"`)
+	}
+
+	t.Run("html is escaped", func(t *testing.T) {
+		msg, err := Render("verify_email", "user@mail.com", templateData{Name: "<script>", Link: "x"})
+		require.Nil(t, err)
+		require.Contains(t, msg.Text, "Hi <script>")
+		require.NotContains(t, msg.HTML, "<script>")
+	})
+	t.Run("unknown template", func(t *testing.T) {
+		_, err := Render("missing", "user@mail.com", data)
+		require.NotNil(t, err)
+	})
+}
+
+func TestMessageBytes(t *testing.T) {
+	msg := Message{To: "user@mail.com", Subject: "Verifikasi é", Text: "teks\nbaris 2", HTML: "<p>html</p>"}
+	data, err := msg.Bytes("Ecommerce <no-reply@example.com>", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Nil(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.Nil(t, err)
+	require.Equal(t, "user@mail.com", parsed.Header.Get("To"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.Nil(t, err)
+	require.Equal(t, "Verifikasi é", subject)
+	require.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@example.com>"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.Nil(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		content, _ := io.ReadAll(part)
+		parts = append(parts, part.Header.Get("Content-Type")+"|"+string(content))
+	}
+	require.Equal(t, []string{
+		"text/plain; charset=utf-8|teks\r\nbaris 2",
+		"text/html; charset=utf-8|<p>html</p>",
+	}, parts)
+}
+
+func TestMessageValidate(t *testing.T) {
+	require.Nil(t, Message{To: "user@mail.com", Subject: "Halo"}.Validate())
+	require.Equal(t, ErrMessageInvalid, Message{Subject: "Halo"}.Validate())
+	require.Equal(t, ErrMessageInvalid, Message{To: "user@mail.com\r\nBcc: other@mail.com", Subject: "Halo"}.Validate())
+	require.Equal(t, ErrMessageInvalid, Message{To: "user@mail.com", Subject: "Halo\nBcc: other@mail.com"}.Validate())
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("memory", func(t *testing.T) {
+		outbox := NewMemoryOutbox()
+		require.Nil(t, outbox.Send(ctx, Message{To: "a@mail.com", Subject: "1"}))
+		require.Nil(t, outbox.Send(ctx, Message{To: "b@mail.com", Subject: "2"}))
+		require.Nil(t, outbox.Send(ctx, Message{To: "a@mail.com", Subject: "3"}))
+
+		require.Len(t, outbox.Messages(), 3)
+		msg, ok := outbox.Last("a@mail.com")
+		require.True(t, ok)
+		require.Equal(t, "3", msg.Subject)
+		_, ok = outbox.Last("c@mail.com")
+		require.False(t, ok)
+	})
+	t.Run("file", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "outbox")
+		outbox := NewFileOutbox(dir, "no-reply@example.com")
+		require.Nil(t, outbox.Send(ctx, Message{To: "a@mail.com", Subject: "1", Text: "isi"}))
+		require.Nil(t, outbox.Send(ctx, Message{To: "a@mail.com", Subject: "2", Text: "isi"}))
+
+		entries, err := os.ReadDir(dir)
+		require.Nil(t, err)
+		require.Len(t, entries, 2)
+
+		data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+		require.Nil(t, err)
+		require.Contains(t, string(data), "To: a@mail.com\r\n")
+	})
+}
+
+// fakeSMTP server SMTP minimal yang menyimpan isi DATA, tanpa STARTTLS dan AUTH
+func fakeSMTP(t *testing.T) (addr string, received chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received = make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "220 localhost ESMTP\r\n")
+		var envelope []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				fmt.Fprint(conn, "250 localhost\r\n")
+			case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+				envelope = append(envelope, strings.TrimSpace(line))
+				fmt.Fprint(conn, "250 OK\r\n")
+			case command == "DATA":
+				fmt.Fprint(conn, "354 End data with <CR><LF>.<CR><LF>\r\n")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- strings.Join(envelope, "\n") + "\n" + data.String()
+				fmt.Fprint(conn, "250 OK\r\n")
+			case command == "QUIT":
+				fmt.Fprint(conn, "221 Bye\r\n")
+				return
+			default:
+				fmt.Fprint(conn, "502 Command not implemented\r\n")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTP(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	var portNumber int
+	fmt.Sscan(port, &portNumber)
+
+	smtp := NewSMTP(SMTPOptions{Host: host, Port: portNumber, From: "Ecommerce <no-reply@example.com>"})
+	require.Nil(t, smtp.Send(context.Background(), Message{To: "user@mail.com", Subject: "Halo", Text: "isi email"}))
+
+	data := <-received
+	require.Contains(t, data, "MAIL FROM:<no-reply@example.com>")
+	require.Contains(t, data, "RCPT TO:<user@mail.com>")
+	require.Contains(t, data, "Subject: Halo\r\n")
+	require.Contains(t, data, "isi email")
+
+	require.Equal(t, ErrMessageInvalid, smtp.Send(context.Background(), Message{Subject: "tanpa penerima"}))
+}
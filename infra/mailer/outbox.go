@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FileOutbox menyimpan setiap email sebagai file .eml di dir, untuk development tanpa server SMTP
+type FileOutbox struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+func NewFileOutbox(dir string, from string) *FileOutbox {
+	return &FileOutbox{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (o *FileOutbox) Send(ctx context.Context, msg Message) (err error) {
+	if err = msg.Validate(); err != nil {
+		return
+	}
+
+	now := time.Now()
+	data, err := msg.Bytes(o.from, now)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(o.dir, 0o755); err != nil {
+		return
+	}
+
+	// nomor urut supaya email yang dikirim di detik yang sama tidak saling menimpa
+	name := fmt.Sprintf("%s-%d.eml", now.UTC().Format("20060102T150405.000000000"), o.seq.Add(1))
+	return os.WriteFile(filepath.Join(o.dir, name), data, 0o600)
+}
+
+// MemoryOutbox menyimpan email di memory, dipakai test untuk membaca isi email yang dikirim
+type MemoryOutbox struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryOutbox() *MemoryOutbox {
+	return &MemoryOutbox{}
+}
+
+func (o *MemoryOutbox) Send(ctx context.Context, msg Message) (err error) {
+	if err = msg.Validate(); err != nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages = append(o.messages, msg)
+	return
+}
+
+func (o *MemoryOutbox) Messages() []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]Message(nil), o.messages...)
+}
+
+// Last email terakhir yang dikirim ke alamat to
+func (o *MemoryOutbox) Last(to string) (msg Message, ok bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		if o.messages[i].To == to {
+			return o.messages[i], true
+		}
+	}
+	return
+}
@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// timeout default satu pengiriman jika ctx tidak punya deadline
+const defaultSMTPTimeout = 10 * time.Second
+
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP mengirim email lewat server SMTP, STARTTLS dipakai jika server mendukung
+type SMTP struct {
+	opts SMTPOptions
+}
+
+func NewSMTP(opts SMTPOptions) *SMTP {
+	return &SMTP{
+		opts: opts,
+	}
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) (err error) {
+	if err = msg.Validate(); err != nil {
+		return
+	}
+
+	now := time.Now()
+	data, err := msg.Bytes(s.opts.From, now)
+	if err != nil {
+		return
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = now.Add(defaultSMTPTimeout)
+	}
+
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port)))
+	if err != nil {
+		return
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return
+	}
+
+	client, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.opts.Host}); err != nil {
+			return
+		}
+	}
+	if s.opts.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)); err != nil {
+			return
+		}
+	}
+
+	if err = client.Mail(addressOf(s.opts.From)); err != nil {
+		return
+	}
+	if err = client.Rcpt(msg.To); err != nil {
+		return
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return
+	}
+	if _, err = w.Write(data); err != nil {
+		return
+	}
+	if err = w.Close(); err != nil {
+		return
+	}
+	return client.Quit()
+}
+
+// addressOf alamat dari format "Nama <alamat@domain>"
+func addressOf(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		return addr.Address
+	}
+	return from
+}
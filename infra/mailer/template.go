@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// setiap email punya templates/<name>.txt dan boleh punya templates/<name>.html,
+// baris pertama hasil .txt adalah subject
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+)
+
+// Render membuat Message dari template yang di-embed, contoh: Render("verify_email", to, data)
+func Render(name string, to string, data any) (msg Message, err error) {
+	var text bytes.Buffer
+	if err = textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return
+	}
+	subject, body, _ := strings.Cut(text.String(), "\n")
+
+	msg = Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject),
+		Text:    strings.TrimLeft(body, "\n"),
+	}
+
+	if htmlTemplates.Lookup(name+".html") != nil {
+		var html bytes.Buffer
+		if err = htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+			return Message{}, err
+		}
+		msg.HTML = html.String()
+	}
+	return
+}
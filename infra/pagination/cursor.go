@@ -0,0 +1,68 @@
+package pagination
+
+import (
+	"Ecommerce-basic/infra/response"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+type Direction string
+
+const (
+	DirectionNext Direction = "next"
+	DirectionPrev Direction = "prev"
+)
+
+// Cursor menyimpan posisi terakhir dari sebuah halaman.
+// Sort ikut disimpan agar cursor tidak bisa dipakai untuk urutan yang berbeda.
+type Cursor struct {
+	Sort      string    `json:"s"`
+	Value     string    `json:"v,omitempty"`
+	Id        int       `json:"i"`
+	Direction Direction `json:"d"`
+}
+
+// Encode menghasilkan cursor opaque dengan format base64(payload).base64(signature)
+func (c Cursor) Encode(secret string) string {
+	payload, _ := json.Marshal(c)
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	signature := base64.RawURLEncoding.EncodeToString(sign(encodedPayload, secret))
+
+	return encodedPayload + "." + signature
+}
+
+func DecodeCursor(token string, secret string) (cursor Cursor, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return Cursor{}, response.ErrCursorInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, sign(parts[0], secret)) {
+		return Cursor{}, response.ErrCursorInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Cursor{}, response.ErrCursorInvalid
+	}
+
+	if err = json.Unmarshal(payload, &cursor); err != nil {
+		return Cursor{}, response.ErrCursorInvalid
+	}
+
+	if cursor.Direction != DirectionNext && cursor.Direction != DirectionPrev {
+		return Cursor{}, response.ErrCursorInvalid
+	}
+	return
+}
+
+func sign(payload string, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
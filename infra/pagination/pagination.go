@@ -0,0 +1,117 @@
+package pagination
+
+import "Ecommerce-basic/infra/response"
+
+const (
+	DefaultSize = 10
+	MaxSize     = 100
+)
+
+type Request struct {
+	Sort   string
+	Cursor *Cursor
+	Size   int
+}
+
+// Key adalah nilai sort dan id dari item yang dipakai untuk membuat cursor
+type Key struct {
+	Value string
+	Id    int
+}
+
+type Meta struct {
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// NewRequest membaca cursor dari query, cursor kosong berarti halaman pertama.
+func NewRequest(token string, size int, sort string, secret string) (req Request, err error) {
+	req = Request{
+		Sort: sort,
+		Size: size,
+	}
+
+	if req.Size <= 0 {
+		req.Size = DefaultSize
+	}
+	if req.Size > MaxSize {
+		req.Size = MaxSize
+	}
+
+	if token == "" {
+		return
+	}
+
+	cursor, err := DecodeCursor(token, secret)
+	if err != nil {
+		return
+	}
+
+	if cursor.Sort != sort {
+		return Request{}, response.ErrCursorInvalid
+	}
+
+	req.Cursor = &cursor
+	return
+}
+
+// Limit mengambil satu item lebih banyak untuk mengetahui apakah masih ada halaman berikutnya
+func (r Request) Limit() int {
+	return r.Size + 1
+}
+
+func (r Request) IsPrev() bool {
+	return r.Cursor != nil && r.Cursor.Direction == DirectionPrev
+}
+
+// Order mengembalikan operator pembanding cursor dan arah ORDER BY.
+// Halaman sebelumnya dibaca dengan arah terbalik lalu dibalik lagi oleh Paginate.
+func (r Request) Order(desc bool) (operator string, direction string) {
+	if desc != r.IsPrev() {
+		return "<", "DESC"
+	}
+	return ">", "ASC"
+}
+
+// Paginate memotong hasil query sesuai ukuran halaman dan menyusun meta cursor.
+// items harus diambil dengan Limit() dan urutan dari Order().
+func Paginate[T any](items []T, req Request, keyOf func(T) Key, secret string) (page []T, meta Meta) {
+	hasMore := len(items) > req.Size
+	if hasMore {
+		items = items[:req.Size]
+	}
+
+	page = items
+	if req.IsPrev() {
+		page = make([]T, len(items))
+		for i, item := range items {
+			page[len(items)-1-i] = item
+		}
+	}
+
+	if len(page) == 0 {
+		return
+	}
+
+	newCursor := func(item T, direction Direction) *string {
+		key := keyOf(item)
+		token := Cursor{
+			Sort:      req.Sort,
+			Value:     key.Value,
+			Id:        key.Id,
+			Direction: direction,
+		}.Encode(secret)
+		return &token
+	}
+
+	// ketika mundur, halaman berikutnya pasti ada karena kita datang dari sana
+	if hasMore || req.IsPrev() {
+		meta.NextCursor = newCursor(page[len(page)-1], DirectionNext)
+		meta.HasMore = true
+	}
+	if (req.Cursor != nil && !req.IsPrev()) || (req.IsPrev() && hasMore) {
+		meta.PrevCursor = newCursor(page[0], DirectionPrev)
+	}
+	return
+}
@@ -0,0 +1,114 @@
+package pagination
+
+import (
+	"Ecommerce-basic/infra/response"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "IniSecret"
+
+func TestCursor(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cursor := Cursor{Sort: "-price", Value: "10000", Id: 7, Direction: DirectionNext}
+
+		decoded, err := DecodeCursor(cursor.Encode(secret), secret)
+		require.Nil(t, err)
+		require.Equal(t, cursor, decoded)
+	})
+	t.Run("tampered signature", func(t *testing.T) {
+		token := Cursor{Id: 7, Direction: DirectionNext}.Encode(secret)
+
+		_, err := DecodeCursor(token, "OtherSecret")
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrCursorInvalid, err)
+	})
+	t.Run("not a cursor", func(t *testing.T) {
+		_, err := DecodeCursor("7", secret)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrCursorInvalid, err)
+	})
+}
+
+func TestNewRequest(t *testing.T) {
+	t.Run("first page with default size", func(t *testing.T) {
+		req, err := NewRequest("", 0, "", secret)
+		require.Nil(t, err)
+		require.Nil(t, req.Cursor)
+		require.Equal(t, DefaultSize, req.Size)
+	})
+	t.Run("size is capped", func(t *testing.T) {
+		req, err := NewRequest("", 1_000, "", secret)
+		require.Nil(t, err)
+		require.Equal(t, MaxSize, req.Size)
+	})
+	t.Run("cursor from other sort", func(t *testing.T) {
+		token := Cursor{Sort: "price", Id: 7, Direction: DirectionNext}.Encode(secret)
+
+		_, err := NewRequest(token, 10, "name", secret)
+		require.NotNil(t, err)
+		require.Equal(t, response.ErrCursorInvalid, err)
+	})
+}
+
+func TestPaginate(t *testing.T) {
+	keyOf := func(id int) Key {
+		return Key{Value: strconv.Itoa(id), Id: id}
+	}
+
+	t.Run("first page has more", func(t *testing.T) {
+		req := Request{Size: 2}
+
+		page, meta := Paginate([]int{1, 2, 3}, req, keyOf, secret)
+		require.Equal(t, []int{1, 2}, page)
+		require.True(t, meta.HasMore)
+		require.NotNil(t, meta.NextCursor)
+		require.Nil(t, meta.PrevCursor)
+
+		next, err := DecodeCursor(*meta.NextCursor, secret)
+		require.Nil(t, err)
+		require.Equal(t, 2, next.Id)
+		require.Equal(t, DirectionNext, next.Direction)
+	})
+	t.Run("last page", func(t *testing.T) {
+		req := Request{Size: 2, Cursor: &Cursor{Id: 2, Direction: DirectionNext}}
+
+		page, meta := Paginate([]int{3}, req, keyOf, secret)
+		require.Equal(t, []int{3}, page)
+		require.False(t, meta.HasMore)
+		require.Nil(t, meta.NextCursor)
+		require.NotNil(t, meta.PrevCursor)
+	})
+	t.Run("prev page is reversed", func(t *testing.T) {
+		req := Request{Size: 2, Cursor: &Cursor{Id: 4, Direction: DirectionPrev}}
+
+		// dibaca dengan urutan terbalik: 3, 2, 1
+		page, meta := Paginate([]int{3, 2, 1}, req, keyOf, secret)
+		require.Equal(t, []int{2, 3}, page)
+		require.True(t, meta.HasMore)
+		require.NotNil(t, meta.NextCursor)
+		require.NotNil(t, meta.PrevCursor)
+
+		prev, err := DecodeCursor(*meta.PrevCursor, secret)
+		require.Nil(t, err)
+		require.Equal(t, 2, prev.Id)
+	})
+	t.Run("empty page", func(t *testing.T) {
+		page, meta := Paginate([]int{}, Request{Size: 2}, keyOf, secret)
+		require.Empty(t, page)
+		require.False(t, meta.HasMore)
+		require.Nil(t, meta.NextCursor)
+	})
+}
+
+func TestOrder(t *testing.T) {
+	operator, direction := Request{}.Order(false)
+	require.Equal(t, ">", operator)
+	require.Equal(t, "ASC", direction)
+
+	operator, direction = Request{Cursor: &Cursor{Direction: DirectionPrev}}.Order(true)
+	require.Equal(t, ">", operator)
+	require.Equal(t, "ASC", direction)
+}
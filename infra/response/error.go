@@ -10,6 +10,7 @@ var (
 	ErrNotFound        = errors.New("not found")
 	ErrUnauthorized    = errors.New("unauthorized")
 	ErrForbiddenAccess = errors.New("forbidden access")
+	ErrCursorInvalid   = errors.New("cursor is invalid")
 )
 
 var (
@@ -21,17 +22,124 @@ var (
 	ErrAuthIsNotExists       = errors.New("auth is not exists")
 	ErrEmailAlreadyUsed      = errors.New("email already used")
 	ErrPasswordNotMatch      = errors.New("password not match")
+	ErrDisplayNameInvalid    = errors.New("display name must have maximum 100 character")
+	ErrPhoneInvalid          = errors.New("phone must have 8-15 digits with optional leading +")
+	ErrAvatarURLInvalid      = errors.New("avatar url must be an http or https url")
+	ErrPasswordUnchanged     = errors.New("new password must be different from current password")
+	ErrTokenInvalid          = errors.New("token is invalid or expired")
+	ErrEmailNotVerified      = errors.New("email is not verified")
+	ErrEmailAlreadyVerified  = errors.New("email already verified")
+	ErrInvalidCredentials    = errors.New("email or password is invalid")
+	ErrTooManyLoginAttempts  = errors.New("too many failed login attempts, try again later")
 
 	// products
-	ErrProductRequired      = errors.New("product is required")
-	ErrProductInvalid       = errors.New("product must have minimum 4 character")
-	ErrStockInvalid         = errors.New("stock must be greater than 0")
-	ErrPriceInvalid         = errors.New("price must be greater than 0")
-	ErrProductAlreadyExists = errors.New("product already exists")
+	ErrProductRequired         = errors.New("product is required")
+	ErrProductInvalid          = errors.New("product must have minimum 4 character")
+	ErrStockInvalid            = errors.New("stock must be greater than 0")
+	ErrPriceInvalid            = errors.New("price must be greater than 0")
+	ErrProductAlreadyExists    = errors.New("product already exists")
+	ErrPriceRangeInvalid       = errors.New("price range is invalid")
+	ErrStockRangeInvalid       = errors.New("stock range is invalid")
+	ErrSortInvalid             = errors.New("sort is invalid")
+	ErrSKUAlreadyExists        = errors.New("sku already exists")
+	ErrSKUInvalid              = errors.New("sku does not match the required format")
+	ErrBundleComponentsInvalid = errors.New("bundle needs at least one component, each listed once with quantity greater than 0")
+	ErrBundleComponentInvalid  = errors.New("bundle component must be an existing product that is not a bundle")
+	ErrProductNotBundle        = errors.New("product is not a bundle")
+	ErrProductInBundle         = errors.New("product is a component of a bundle and cannot be purged")
+	ErrProductHasTransactions  = errors.New("product is referenced by transactions and cannot be purged")
+	ErrProductPatchInvalid     = errors.New("product patch is invalid")
+	ErrProductVersionConflict  = errors.New("product has been modified, reload and try again")
+	ErrProductStatusInvalid    = errors.New("product status must be DRAFT, PUBLISHED or ARCHIVED")
+	ErrProductScheduleInvalid  = errors.New("product publish schedule is invalid")
+	ErrProductNotPublished     = errors.New("product is not available for sale")
+
+	// product attributes
+	ErrAttributeCodeInvalid    = errors.New("attribute code must be 2-50 lowercase letters, numbers or underscores")
+	ErrAttributeNameRequired   = errors.New("attribute name is required")
+	ErrAttributeTypeInvalid    = errors.New("attribute type must be STRING, NUMBER, ENUM or BOOLEAN")
+	ErrAttributeOptionsInvalid = errors.New("enum attribute needs unique options, unit is only for number attributes")
+	ErrAttributeValueInvalid   = errors.New("attribute value is unknown or does not match its definition")
+	ErrAttributeFilterInvalid  = errors.New("attribute filter is invalid")
+	ErrAttributeNotFound       = errors.New("attribute not found")
+	ErrAttributeAlreadyExists  = errors.New("attribute already exists")
+	ErrAttributeInUse          = errors.New("attribute or option is still used by products")
+
+	// digital products
+	ErrProductTypeInvalid    = errors.New("product type must be STANDARD or DIGITAL")
+	ErrProductNotDigital     = errors.New("product is not a digital product")
+	ErrLicenseKeysInvalid    = errors.New("license keys must be 1-1000 unique, non-empty keys of at most 255 characters")
+	ErrLicenseKeysExhausted  = errors.New("no license keys left for this product")
+	ErrDigitalFileRequired   = errors.New("digital file is required")
+	ErrDigitalFileTooLarge   = errors.New("digital file is too large")
+	ErrTransactionNotDigital = errors.New("transaction is not a digital order")
+	ErrDigitalNotShipped     = errors.New("digital orders are not shipped and skip IN_DELIVERY")
+	ErrDownloadLinkInvalid   = errors.New("download link is invalid")
+	ErrDownloadLinkExpired   = errors.New("download link has expired")
+
+	// recently viewed
+	ErrSessionIdInvalid = errors.New("session id is invalid, create one with POST /products/view-sessions")
+
+	// product imports
+	ErrImportFormatInvalid = errors.New("import format must be csv or jsonl")
+	ErrImportModeInvalid   = errors.New("import mode must be create or upsert")
+	ErrImportFileRequired  = errors.New("import file is required")
+	ErrImportFileTooLarge  = errors.New("import file is too large")
+	ErrImportHeaderInvalid = errors.New("csv header must contain name, stock and price")
 
 	// transactions
-	ErrAmountInvalid          = errors.New("invalid amount")
-	ErrAmountGreaterThanStock = errors.New("amount greater than stock")
+	ErrAmountInvalid            = errors.New("invalid amount")
+	ErrAmountGreaterThanStock   = errors.New("amount greater than stock")
+	ErrTransactionStatusInvalid = errors.New("transaction status is invalid")
+	ErrTransactionCancelled     = errors.New("transaction already cancelled")
+	ErrTransactionNotCancelable = errors.New("completed transaction cannot be cancelled")
+	ErrTransactionShipped       = errors.New("transaction already shipped, only admin can change it")
+	ErrTransactionNotPaid       = errors.New("transaction not paid yet")
+	ErrTransactionDelivered     = errors.New("digital order already delivered, only admin can cancel it")
+
+	// inventory
+	ErrReasonCodeInvalid      = errors.New("reason code is invalid")
+	ErrStockAdjustmentInvalid = errors.New("stock adjustment quantity is invalid for the reason code")
+	ErrStockNegative          = errors.New("stock cannot be negative")
+
+	// warehouses
+	ErrWarehouseNotFound         = errors.New("warehouse not found")
+	ErrNoWarehouseAvailable      = errors.New("no warehouse has enough stock for the order")
+	ErrAllocationStrategyInvalid = errors.New("allocation strategy is invalid")
+
+	// reservations
+	ErrReservationNotFound        = errors.New("reservation not found")
+	ErrReservationExpired         = errors.New("reservation has expired")
+	ErrReservationNotActive       = errors.New("reservation is no longer active")
+	ErrReservationAmountMismatch  = errors.New("amount does not match reservation")
+	ErrAmountGreaterThanAvailable = errors.New("amount greater than available stock")
+
+	// stock alerts
+	ErrReorderThresholdInvalid   = errors.New("reorder threshold must not be negative")
+	ErrProductInStock            = errors.New("product is still in stock")
+	ErrStockSubscriptionNotFound = errors.New("stock subscription not found")
+
+	// price schedules
+	ErrPriceScheduleTypeInvalid   = errors.New("price schedule type must be price or sale")
+	ErrPriceScheduleTimeInvalid   = errors.New("price schedule time is invalid")
+	ErrPriceScheduleOverlap       = errors.New("price schedule overlaps with a sale")
+	ErrPriceScheduleNotFound      = errors.New("price schedule not found")
+	ErrPriceScheduleNotCancelable = errors.New("only pending price schedules can be cancelled")
+
+	// reviews
+	ErrRatingInvalid         = errors.New("rating must be between 1 and 5")
+	ErrReviewTextTooLong     = errors.New("review text is too long")
+	ErrReviewStatusInvalid   = errors.New("review status is invalid")
+	ErrReviewNotFound        = errors.New("review not found")
+	ErrReviewAlreadyExists   = errors.New("product already reviewed")
+	ErrReviewNotVerifiedUser = errors.New("only buyers with a completed transaction can review this product")
+
+	// wishlist
+	ErrWishlistItemNotFound = errors.New("wishlist item not found")
+
+	// exports
+	ErrExportFormatInvalid = errors.New("export format must be csv, jsonl or xlsx")
+	ErrDateRangeInvalid    = errors.New("date range is invalid")
 )
 
 type Error struct {
@@ -58,6 +166,7 @@ var (
 	ErrorNotFound        = NewError(ErrNotFound.Error(), "40400", http.StatusNotFound)
 	ErrorUnauthorized    = NewError(ErrUnauthorized.Error(), "40100", http.StatusUnauthorized)
 	ErrorForbiddenAccess = NewError(ErrForbiddenAccess.Error(), "40100", http.StatusForbidden)
+	ErrorCursorInvalid   = NewError(ErrCursorInvalid.Error(), "40013", http.StatusBadRequest)
 )
 
 var (
@@ -71,7 +180,90 @@ var (
 	ErrorStockInvalid          = NewError(ErrStockInvalid.Error(), "40007", http.StatusBadRequest)
 	ErrorPriceInvalid          = NewError(ErrPriceInvalid.Error(), "40008", http.StatusBadRequest)
 	ErrorInvalidAmount         = NewError(ErrAmountInvalid.Error(), "40009", http.StatusBadRequest)
+	ErrorPriceRangeInvalid     = NewError(ErrPriceRangeInvalid.Error(), "40010", http.StatusBadRequest)
+	ErrorStockRangeInvalid     = NewError(ErrStockRangeInvalid.Error(), "40011", http.StatusBadRequest)
+	ErrorSortInvalid           = NewError(ErrSortInvalid.Error(), "40012", http.StatusBadRequest)
+	ErrorImportFormatInvalid   = NewError(ErrImportFormatInvalid.Error(), "40014", http.StatusBadRequest)
+	ErrorImportModeInvalid     = NewError(ErrImportModeInvalid.Error(), "40015", http.StatusBadRequest)
+	ErrorImportFileRequired    = NewError(ErrImportFileRequired.Error(), "40016", http.StatusBadRequest)
+	ErrorImportHeaderInvalid   = NewError(ErrImportHeaderInvalid.Error(), "40017", http.StatusBadRequest)
+	ErrorImportFileTooLarge    = NewError(ErrImportFileTooLarge.Error(), "41300", http.StatusRequestEntityTooLarge)
 	ErrorProductAlreadyExists  = NewError(ErrProductAlreadyExists.Error(), "40902", http.StatusConflict)
+	ErrorSKUAlreadyExists      = NewError(ErrSKUAlreadyExists.Error(), "40903", http.StatusConflict)
+
+	ErrorTransactionStatusInvalid   = NewError(ErrTransactionStatusInvalid.Error(), "40018", http.StatusBadRequest)
+	ErrorExportFormatInvalid        = NewError(ErrExportFormatInvalid.Error(), "40019", http.StatusBadRequest)
+	ErrorDateRangeInvalid           = NewError(ErrDateRangeInvalid.Error(), "40020", http.StatusBadRequest)
+	ErrorReasonCodeInvalid          = NewError(ErrReasonCodeInvalid.Error(), "40021", http.StatusBadRequest)
+	ErrorStockAdjustmentInvalid     = NewError(ErrStockAdjustmentInvalid.Error(), "40022", http.StatusBadRequest)
+	ErrorStockNegative              = NewError(ErrStockNegative.Error(), "40904", http.StatusConflict)
+	ErrorTransactionCancelled       = NewError(ErrTransactionCancelled.Error(), "40905", http.StatusConflict)
+	ErrorTransactionNotCancelable   = NewError(ErrTransactionNotCancelable.Error(), "40906", http.StatusConflict)
+	ErrorTransactionShipped         = NewError(ErrTransactionShipped.Error(), "40926", http.StatusConflict)
+	ErrorTransactionNotPaid         = NewError(ErrTransactionNotPaid.Error(), "40927", http.StatusConflict)
+	ErrorTransactionDelivered       = NewError(ErrTransactionDelivered.Error(), "40928", http.StatusConflict)
+	ErrorNoWarehouseAvailable       = NewError(ErrNoWarehouseAvailable.Error(), "40907", http.StatusConflict)
+	ErrorWarehouseNotFound          = NewError(ErrWarehouseNotFound.Error(), "40402", http.StatusNotFound)
+	ErrorReservationNotFound        = NewError(ErrReservationNotFound.Error(), "40403", http.StatusNotFound)
+	ErrorReservationAmountMismatch  = NewError(ErrReservationAmountMismatch.Error(), "40023", http.StatusBadRequest)
+	ErrorReservationExpired         = NewError(ErrReservationExpired.Error(), "40908", http.StatusConflict)
+	ErrorReservationNotActive       = NewError(ErrReservationNotActive.Error(), "40909", http.StatusConflict)
+	ErrorAmountGreaterThanAvailable = NewError(ErrAmountGreaterThanAvailable.Error(), "40910", http.StatusConflict)
+	ErrorReorderThresholdInvalid    = NewError(ErrReorderThresholdInvalid.Error(), "40024", http.StatusBadRequest)
+	ErrorProductInStock             = NewError(ErrProductInStock.Error(), "40911", http.StatusConflict)
+	ErrorStockSubscriptionNotFound  = NewError(ErrStockSubscriptionNotFound.Error(), "40404", http.StatusNotFound)
+	ErrorPriceScheduleTypeInvalid   = NewError(ErrPriceScheduleTypeInvalid.Error(), "40025", http.StatusBadRequest)
+	ErrorPriceScheduleTimeInvalid   = NewError(ErrPriceScheduleTimeInvalid.Error(), "40026", http.StatusBadRequest)
+	ErrorPriceScheduleOverlap       = NewError(ErrPriceScheduleOverlap.Error(), "40912", http.StatusConflict)
+	ErrorPriceScheduleNotFound      = NewError(ErrPriceScheduleNotFound.Error(), "40405", http.StatusNotFound)
+	ErrorPriceScheduleNotCancelable = NewError(ErrPriceScheduleNotCancelable.Error(), "40913", http.StatusConflict)
+	ErrorRatingInvalid              = NewError(ErrRatingInvalid.Error(), "40027", http.StatusBadRequest)
+	ErrorReviewTextTooLong          = NewError(ErrReviewTextTooLong.Error(), "40028", http.StatusBadRequest)
+	ErrorReviewStatusInvalid        = NewError(ErrReviewStatusInvalid.Error(), "40029", http.StatusBadRequest)
+	ErrorReviewNotFound             = NewError(ErrReviewNotFound.Error(), "40406", http.StatusNotFound)
+	ErrorReviewAlreadyExists        = NewError(ErrReviewAlreadyExists.Error(), "40914", http.StatusConflict)
+	ErrorReviewNotVerifiedUser      = NewError(ErrReviewNotVerifiedUser.Error(), "40301", http.StatusForbidden)
+	ErrorWishlistItemNotFound       = NewError(ErrWishlistItemNotFound.Error(), "40407", http.StatusNotFound)
+	ErrorProductHasTransactions     = NewError(ErrProductHasTransactions.Error(), "40915", http.StatusConflict)
+	ErrorProductPatchInvalid        = NewError(ErrProductPatchInvalid.Error(), "40030", http.StatusBadRequest)
+	ErrorProductVersionConflict     = NewError(ErrProductVersionConflict.Error(), "41200", http.StatusPreconditionFailed)
+	ErrorProductStatusInvalid       = NewError(ErrProductStatusInvalid.Error(), "40031", http.StatusBadRequest)
+	ErrorProductScheduleInvalid     = NewError(ErrProductScheduleInvalid.Error(), "40032", http.StatusBadRequest)
+	ErrorProductNotPublished        = NewError(ErrProductNotPublished.Error(), "40916", http.StatusConflict)
+	ErrorSKUInvalid                 = NewError(ErrSKUInvalid.Error(), "40033", http.StatusBadRequest)
+	ErrorBundleComponentsInvalid    = NewError(ErrBundleComponentsInvalid.Error(), "40034", http.StatusBadRequest)
+	ErrorBundleComponentInvalid     = NewError(ErrBundleComponentInvalid.Error(), "40035", http.StatusBadRequest)
+	ErrorProductNotBundle           = NewError(ErrProductNotBundle.Error(), "40917", http.StatusConflict)
+	ErrorProductInBundle            = NewError(ErrProductInBundle.Error(), "40918", http.StatusConflict)
+	ErrorAttributeCodeInvalid       = NewError(ErrAttributeCodeInvalid.Error(), "40036", http.StatusBadRequest)
+	ErrorAttributeNameRequired      = NewError(ErrAttributeNameRequired.Error(), "40037", http.StatusBadRequest)
+	ErrorAttributeTypeInvalid       = NewError(ErrAttributeTypeInvalid.Error(), "40038", http.StatusBadRequest)
+	ErrorAttributeOptionsInvalid    = NewError(ErrAttributeOptionsInvalid.Error(), "40039", http.StatusBadRequest)
+	ErrorAttributeValueInvalid      = NewError(ErrAttributeValueInvalid.Error(), "40040", http.StatusBadRequest)
+	ErrorAttributeFilterInvalid     = NewError(ErrAttributeFilterInvalid.Error(), "40041", http.StatusBadRequest)
+	ErrorAttributeNotFound          = NewError(ErrAttributeNotFound.Error(), "40408", http.StatusNotFound)
+	ErrorAttributeAlreadyExists     = NewError(ErrAttributeAlreadyExists.Error(), "40919", http.StatusConflict)
+	ErrorAttributeInUse             = NewError(ErrAttributeInUse.Error(), "40920", http.StatusConflict)
+	ErrorProductTypeInvalid         = NewError(ErrProductTypeInvalid.Error(), "40042", http.StatusBadRequest)
+	ErrorLicenseKeysInvalid         = NewError(ErrLicenseKeysInvalid.Error(), "40043", http.StatusBadRequest)
+	ErrorDigitalFileRequired        = NewError(ErrDigitalFileRequired.Error(), "40044", http.StatusBadRequest)
+	ErrorDigitalFileTooLarge        = NewError(ErrDigitalFileTooLarge.Error(), "41301", http.StatusRequestEntityTooLarge)
+	ErrorProductNotDigital          = NewError(ErrProductNotDigital.Error(), "40921", http.StatusConflict)
+	ErrorLicenseKeysExhausted       = NewError(ErrLicenseKeysExhausted.Error(), "40922", http.StatusConflict)
+	ErrorTransactionNotDigital      = NewError(ErrTransactionNotDigital.Error(), "40923", http.StatusConflict)
+	ErrorDigitalNotShipped          = NewError(ErrDigitalNotShipped.Error(), "40924", http.StatusConflict)
+	ErrorDownloadLinkInvalid        = NewError(ErrDownloadLinkInvalid.Error(), "40302", http.StatusForbidden)
+	ErrorDownloadLinkExpired        = NewError(ErrDownloadLinkExpired.Error(), "40303", http.StatusForbidden)
+	ErrorSessionIdInvalid           = NewError(ErrSessionIdInvalid.Error(), "40045", http.StatusBadRequest)
+	ErrorDisplayNameInvalid         = NewError(ErrDisplayNameInvalid.Error(), "40046", http.StatusBadRequest)
+	ErrorPhoneInvalid               = NewError(ErrPhoneInvalid.Error(), "40047", http.StatusBadRequest)
+	ErrorAvatarURLInvalid           = NewError(ErrAvatarURLInvalid.Error(), "40048", http.StatusBadRequest)
+	ErrorPasswordUnchanged          = NewError(ErrPasswordUnchanged.Error(), "40049", http.StatusBadRequest)
+	ErrorTokenInvalid               = NewError(ErrTokenInvalid.Error(), "40050", http.StatusBadRequest)
+	ErrorEmailNotVerified           = NewError(ErrEmailNotVerified.Error(), "40304", http.StatusForbidden)
+	ErrorEmailAlreadyVerified       = NewError(ErrEmailAlreadyVerified.Error(), "40925", http.StatusConflict)
+	ErrorInvalidCredentials         = NewError(ErrInvalidCredentials.Error(), "40102", http.StatusUnauthorized)
+	ErrorTooManyLoginAttempts       = NewError(ErrTooManyLoginAttempts.Error(), "42901", http.StatusTooManyRequests)
 
 	ErrorAuthIsNotExists  = NewError(ErrAuthIsNotExists.Error(), "40401", http.StatusNotFound)
 	ErrorEmailAlreadyUsed = NewError(ErrEmailAlreadyUsed.Error(), "40901", http.StatusConflict)
@@ -90,6 +282,90 @@ var (
 		ErrPasswordNotMatch.Error():      ErrorPasswordNotMatch,
 		ErrUnauthorized.Error():          ErrorUnauthorized,
 		ErrForbiddenAccess.Error():       ErrorForbiddenAccess,
+		ErrCursorInvalid.Error():         ErrorCursorInvalid,
 		ErrProductAlreadyExists.Error():  ErrorProductAlreadyExists,
+		ErrPriceRangeInvalid.Error():     ErrorPriceRangeInvalid,
+		ErrStockRangeInvalid.Error():     ErrorStockRangeInvalid,
+		ErrSortInvalid.Error():           ErrorSortInvalid,
+		ErrSKUAlreadyExists.Error():      ErrorSKUAlreadyExists,
+		ErrImportFormatInvalid.Error():   ErrorImportFormatInvalid,
+		ErrImportModeInvalid.Error():     ErrorImportModeInvalid,
+		ErrImportFileRequired.Error():    ErrorImportFileRequired,
+		ErrImportFileTooLarge.Error():    ErrorImportFileTooLarge,
+		ErrImportHeaderInvalid.Error():   ErrorImportHeaderInvalid,
+
+		ErrTransactionStatusInvalid.Error():   ErrorTransactionStatusInvalid,
+		ErrExportFormatInvalid.Error():        ErrorExportFormatInvalid,
+		ErrDateRangeInvalid.Error():           ErrorDateRangeInvalid,
+		ErrReasonCodeInvalid.Error():          ErrorReasonCodeInvalid,
+		ErrStockAdjustmentInvalid.Error():     ErrorStockAdjustmentInvalid,
+		ErrStockNegative.Error():              ErrorStockNegative,
+		ErrTransactionCancelled.Error():       ErrorTransactionCancelled,
+		ErrTransactionNotCancelable.Error():   ErrorTransactionNotCancelable,
+		ErrTransactionShipped.Error():         ErrorTransactionShipped,
+		ErrTransactionNotPaid.Error():         ErrorTransactionNotPaid,
+		ErrTransactionDelivered.Error():       ErrorTransactionDelivered,
+		ErrNoWarehouseAvailable.Error():       ErrorNoWarehouseAvailable,
+		ErrWarehouseNotFound.Error():          ErrorWarehouseNotFound,
+		ErrReservationNotFound.Error():        ErrorReservationNotFound,
+		ErrReservationExpired.Error():         ErrorReservationExpired,
+		ErrReservationNotActive.Error():       ErrorReservationNotActive,
+		ErrReservationAmountMismatch.Error():  ErrorReservationAmountMismatch,
+		ErrAmountGreaterThanAvailable.Error(): ErrorAmountGreaterThanAvailable,
+		ErrReorderThresholdInvalid.Error():    ErrorReorderThresholdInvalid,
+		ErrProductInStock.Error():             ErrorProductInStock,
+		ErrStockSubscriptionNotFound.Error():  ErrorStockSubscriptionNotFound,
+		ErrPriceScheduleTypeInvalid.Error():   ErrorPriceScheduleTypeInvalid,
+		ErrPriceScheduleTimeInvalid.Error():   ErrorPriceScheduleTimeInvalid,
+		ErrPriceScheduleOverlap.Error():       ErrorPriceScheduleOverlap,
+		ErrPriceScheduleNotFound.Error():      ErrorPriceScheduleNotFound,
+		ErrPriceScheduleNotCancelable.Error(): ErrorPriceScheduleNotCancelable,
+		ErrRatingInvalid.Error():              ErrorRatingInvalid,
+		ErrReviewTextTooLong.Error():          ErrorReviewTextTooLong,
+		ErrReviewStatusInvalid.Error():        ErrorReviewStatusInvalid,
+		ErrReviewNotFound.Error():             ErrorReviewNotFound,
+		ErrReviewAlreadyExists.Error():        ErrorReviewAlreadyExists,
+		ErrReviewNotVerifiedUser.Error():      ErrorReviewNotVerifiedUser,
+		ErrWishlistItemNotFound.Error():       ErrorWishlistItemNotFound,
+		ErrProductHasTransactions.Error():     ErrorProductHasTransactions,
+		ErrProductPatchInvalid.Error():        ErrorProductPatchInvalid,
+		ErrProductVersionConflict.Error():     ErrorProductVersionConflict,
+		ErrProductStatusInvalid.Error():       ErrorProductStatusInvalid,
+		ErrProductScheduleInvalid.Error():     ErrorProductScheduleInvalid,
+		ErrProductNotPublished.Error():        ErrorProductNotPublished,
+		ErrSKUInvalid.Error():                 ErrorSKUInvalid,
+		ErrBundleComponentsInvalid.Error():    ErrorBundleComponentsInvalid,
+		ErrBundleComponentInvalid.Error():     ErrorBundleComponentInvalid,
+		ErrProductNotBundle.Error():           ErrorProductNotBundle,
+		ErrProductInBundle.Error():            ErrorProductInBundle,
+		ErrAttributeCodeInvalid.Error():       ErrorAttributeCodeInvalid,
+		ErrAttributeNameRequired.Error():      ErrorAttributeNameRequired,
+		ErrAttributeTypeInvalid.Error():       ErrorAttributeTypeInvalid,
+		ErrAttributeOptionsInvalid.Error():    ErrorAttributeOptionsInvalid,
+		ErrAttributeValueInvalid.Error():      ErrorAttributeValueInvalid,
+		ErrAttributeFilterInvalid.Error():     ErrorAttributeFilterInvalid,
+		ErrAttributeNotFound.Error():          ErrorAttributeNotFound,
+		ErrAttributeAlreadyExists.Error():     ErrorAttributeAlreadyExists,
+		ErrAttributeInUse.Error():             ErrorAttributeInUse,
+		ErrProductTypeInvalid.Error():         ErrorProductTypeInvalid,
+		ErrLicenseKeysInvalid.Error():         ErrorLicenseKeysInvalid,
+		ErrDigitalFileRequired.Error():        ErrorDigitalFileRequired,
+		ErrDigitalFileTooLarge.Error():        ErrorDigitalFileTooLarge,
+		ErrProductNotDigital.Error():          ErrorProductNotDigital,
+		ErrLicenseKeysExhausted.Error():       ErrorLicenseKeysExhausted,
+		ErrTransactionNotDigital.Error():      ErrorTransactionNotDigital,
+		ErrDigitalNotShipped.Error():          ErrorDigitalNotShipped,
+		ErrDownloadLinkInvalid.Error():        ErrorDownloadLinkInvalid,
+		ErrDownloadLinkExpired.Error():        ErrorDownloadLinkExpired,
+		ErrSessionIdInvalid.Error():           ErrorSessionIdInvalid,
+		ErrDisplayNameInvalid.Error():         ErrorDisplayNameInvalid,
+		ErrPhoneInvalid.Error():               ErrorPhoneInvalid,
+		ErrAvatarURLInvalid.Error():           ErrorAvatarURLInvalid,
+		ErrPasswordUnchanged.Error():          ErrorPasswordUnchanged,
+		ErrTokenInvalid.Error():               ErrorTokenInvalid,
+		ErrEmailNotVerified.Error():           ErrorEmailNotVerified,
+		ErrEmailAlreadyVerified.Error():       ErrorEmailAlreadyVerified,
+		ErrInvalidCredentials.Error():         ErrorInvalidCredentials,
+		ErrTooManyLoginAttempts.Error():       ErrorTooManyLoginAttempts,
 	}
 )
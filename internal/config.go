@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type Config struct {
@@ -14,11 +15,135 @@ type AppConfig struct {
 	Name       string           `mapstructure:"name"`
 	Port       string           `mapstructure:"port"`
 	Encryption EncryptionConfig `mapstructure:"encryption"`
+	Inventory  InventoryConfig  `mapstructure:"inventory"`
+	Pricing    PricingConfig    `mapstructure:"pricing"`
+	Product    ProductConfig    `mapstructure:"product"`
+	Digital    DigitalConfig    `mapstructure:"digital"`
+	Cache      CacheConfig      `mapstructure:"cache"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+	Mail       MailConfig       `mapstructure:"mail"`
+
+	Recommendation RecommendationConfig `mapstructure:"recommendation"`
+
+	// reverse proxy yang header X-Forwarded-For-nya dipercaya untuk IP klien, kosong berarti tidak ada
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type EncryptionConfig struct {
-	Salt      uint8  `mapstructure:"salt"`
-	JWTSecret string `mapstructure:"jwt_secret"`
+	Salt         uint8  `mapstructure:"salt"`
+	JWTSecret    string `mapstructure:"jwt_secret"`
+	CursorSecret string `mapstructure:"cursor_secret"`
+}
+
+type InventoryConfig struct {
+	// nearest, most_stock atau priority
+	AllocationStrategy string `mapstructure:"allocation_strategy"`
+
+	// lama stok ditahan untuk checkout dan interval sweeper, contoh: 15m
+	ReservationTTL           time.Duration `mapstructure:"reservation_ttl"`
+	ReservationSweepInterval time.Duration `mapstructure:"reservation_sweep_interval"`
+
+	// interval pengiriman alert stok menipis dan notifikasi restock
+	AlertDispatchInterval time.Duration `mapstructure:"alert_dispatch_interval"`
+}
+
+type PricingConfig struct {
+	// interval scheduler perubahan harga dan sale, contoh: 1m
+	ScheduleInterval time.Duration `mapstructure:"schedule_interval"`
+}
+
+type ProductConfig struct {
+	// interval scheduler publish / unpublish produk, contoh: 1m
+	LifecycleInterval time.Duration `mapstructure:"lifecycle_interval"`
+
+	// regex untuk SKU yang dikirim admin, contoh: ^[A-Z0-9][A-Z0-9-]{2,49}$
+	SKUPattern string `mapstructure:"sku_pattern"`
+
+	// template SKU otomatis, YYYY / YY / MM diganti tanggal dan # diganti nomor urut.
+	// kosong berarti UUID, contoh: PRD-YYYY-#####
+	SKUTemplate string `mapstructure:"sku_template"`
+
+	// ukuran antrean pencatatan view detail produk dan jumlah produk yang disimpan per user / session
+	ViewQueueSize       int `mapstructure:"view_queue_size"`
+	RecentlyViewedLimit int `mapstructure:"recently_viewed_limit"`
+
+	// cache in-process untuk GET /products dan detail produk publik, contoh: 30s.
+	// stok dari checkout atau reservasi bisa terlambat paling lama cache_ttl
+	CacheTTL        time.Duration `mapstructure:"cache_ttl"`
+	CacheMaxEntries int           `mapstructure:"cache_max_entries"`
+
+	// header Cache-Control untuk endpoint produk publik, contoh: public, max-age=30
+	CacheControl string `mapstructure:"cache_control"`
+}
+
+type DigitalConfig struct {
+	// folder penyimpanan file produk digital
+	BlobRoot string `mapstructure:"blob_root"`
+
+	// masa berlaku link download dan secret untuk menandatanganinya, contoh: 15m
+	DownloadTTL    time.Duration `mapstructure:"download_ttl"`
+	DownloadSecret string        `mapstructure:"download_secret"`
+}
+
+type RecommendationConfig struct {
+	// interval batch perhitungan produk yang sering dibeli bersama, contoh: 1h
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// jumlah produk terkait yang disimpan per produk
+	TopN int `mapstructure:"top_n"`
+}
+
+type CacheConfig struct {
+	// memory, redis atau none untuk mematikan cache repository
+	Driver string `mapstructure:"driver"`
+
+	// masa berlaku entry cache repository dan jumlah entry maksimal per cache driver memory, contoh: 30s
+	TTL      time.Duration `mapstructure:"ttl"`
+	Capacity int           `mapstructure:"capacity"`
+
+	// server Redis atau server lain yang memakai protokol Redis, contoh: localhost:6379
+	RedisAddr     string `mapstructure:"redis_addr"`
+	RedisPassword string `mapstructure:"redis_password"`
+	RedisDB       int    `mapstructure:"redis_db"`
+}
+
+type AuthConfig struct {
+	// masa berlaku token verifikasi email dan reset password, contoh: 24h
+	VerifyTokenTTL time.Duration `mapstructure:"verify_token_ttl"`
+	ResetTokenTTL  time.Duration `mapstructure:"reset_token_ttl"`
+
+	// login ditolak sampai email diverifikasi
+	RequireVerifiedEmail bool `mapstructure:"require_verified_email"`
+
+	// alamat frontend untuk link di email, contoh: http://localhost:3000
+	LinkBaseURL string `mapstructure:"link_base_url"`
+
+	// jumlah login gagal sebelum dikunci, per email dan per IP
+	MaxLoginAttempts      int `mapstructure:"max_login_attempts"`
+	MaxLoginAttemptsPerIP int `mapstructure:"max_login_attempts_per_ip"`
+
+	// lama kunci pertama, berlipat dua setiap gagal lagi sampai lockout_max
+	LockoutBase time.Duration `mapstructure:"lockout_base"`
+	LockoutMax  time.Duration `mapstructure:"lockout_max"`
+
+	// hitungan gagal mulai dari nol jika tidak ada login gagal selama failure_window setelah kunci terakhir
+	FailureWindow time.Duration `mapstructure:"failure_window"`
+}
+
+type MailConfig struct {
+	// smtp, file atau memory
+	Driver string `mapstructure:"driver"`
+
+	// pengirim, contoh: Ecommerce <no-reply@example.com>
+	From string `mapstructure:"from"`
+
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     int    `mapstructure:"smtp_port"`
+	SMTPUsername string `mapstructure:"smtp_username"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+
+	// folder file .eml untuk driver file
+	OutboxDir string `mapstructure:"outbox_dir"`
 }
 
 type DBConfig struct {
@@ -48,12 +173,20 @@ func LoadConfig(filename string) error {
 
 	// Secara eksplisit bind environment variable
 	envVars := map[string]string{
-		"app.encryption.jwt_secret": "JWT_SECRET",
-		"db.host":                   "PGHOST",
-		"db.port":                   "PGPORT",
-		"db.user":                   "PGUSER",
-		"db.password":               "PGPASSWORD",
-		"db.name":                   "PGDATABASE",
+		"app.encryption.jwt_secret":         "JWT_SECRET",
+		"app.encryption.cursor_secret":      "CURSOR_SECRET",
+		"app.inventory.allocation_strategy": "ALLOCATION_STRATEGY",
+		"app.inventory.reservation_ttl":     "RESERVATION_TTL",
+		"app.digital.download_secret":       "DOWNLOAD_SECRET",
+		"app.cache.redis_addr":              "REDIS_ADDR",
+		"app.cache.redis_password":          "REDIS_PASSWORD",
+		"app.mail.smtp_username":            "SMTP_USERNAME",
+		"app.mail.smtp_password":            "SMTP_PASSWORD",
+		"db.host":                           "PGHOST",
+		"db.port":                           "PGPORT",
+		"db.user":                           "PGUSER",
+		"db.password":                       "PGPASSWORD",
+		"db.name":                           "PGDATABASE",
 	}
 
 	// Loop untuk bind environment variables